@@ -1,23 +1,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/jwt"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	sharedlogger "github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	authpb "github.com/toxictoast/toxictoastgo/shared/proto/auth"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
-	authpb "toxictoast/services/auth-service/api/proto"
 	"toxictoast/services/auth-service/internal/command"
 	grpchandler "toxictoast/services/auth-service/internal/handler/grpc"
 	"toxictoast/services/auth-service/internal/query"
@@ -26,6 +32,9 @@ import (
 	"toxictoast/services/auth-service/pkg/config"
 )
 
+// jwksPath serves the public keys validators fetch to check tokens
+const jwksPath = "/.well-known/jwks.json"
+
 func main() {
 	// Load configuration
 	cfg, err := config.LoadConfig()
@@ -33,6 +42,18 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Initialize structured logging
+	if err := sharedlogger.Setup(sharedconfig.LoadLoggingConfig(cfg.ServiceName)); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig(cfg.ServiceName))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize logger
 	logger := sharedlogger.NewLogger(cfg.ServiceName)
 	logger.Info("Starting Auth Service")
@@ -62,14 +83,16 @@ func main() {
 	logger.Info("Database migration completed")
 
 	// Initialize Kafka producer
-	kafkaProducer, err := kafka.NewProducer(cfg.Kafka.Brokers)
+	// Handlers get a nil Publisher when Kafka is unavailable
+	var kafkaProducer kafka.Publisher
+	producer, err := kafka.NewProducerWithOptions(cfg.Kafka.Brokers, kafka.ProducerOptions{Source: cfg.ServiceName})
 	if err != nil {
 		logger.Info(fmt.Sprintf("Warning: Failed to initialize Kafka producer: %v", err))
 		logger.Info("Service will continue without event publishing")
-		kafkaProducer = nil
 	} else {
 		logger.Info("Kafka producer connected successfully")
-		defer kafkaProducer.Close()
+		kafkaProducer = producer
+		defer producer.Close()
 	}
 
 	// Initialize repositories
@@ -79,16 +102,31 @@ func main() {
 	rolePermissionRepo := impl.NewRolePermissionRepository(db)
 
 	// Initialize JWT helper
-	jwtHelper := jwt.NewJWTHelper(
-		cfg.JWT.SecretKey,
-		cfg.JWT.AccessTokenDuration,
-		cfg.JWT.RefreshTokenDuration,
-	)
+	// With a signing key, tokens are signed asymmetrically and the secret only validates legacy tokens
+	keyRing, err := loadKeyRing(cfg.JWT)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to load JWT signing keys: %v", err))
+	}
+	jwtHelper := jwt.NewJWTHelperWithOptions(jwt.Options{
+		SecretKey:            cfg.JWT.SecretKey,
+		KeyRing:              keyRing,
+		AccessTokenDuration:  cfg.JWT.AccessTokenDuration,
+		RefreshTokenDuration: cfg.JWT.RefreshTokenDuration,
+	})
+	if keyRing != nil {
+		signingKey, _ := keyRing.SigningKey()
+		logger.Info(fmt.Sprintf("JWT signing with %s key %s (%d retiring)", signingKey.Algorithm, signingKey.ID, len(cfg.JWT.RetiringKeyFiles)))
+	} else {
+		logger.Info("JWT signing with shared HS256 secret")
+	}
 
 	// ============================================
 	// CQRS SETUP
 	// ============================================
 
+	// Metrics of the buses and gRPC calls, served on the HTTP port
+	serviceMetrics := metrics.New(cfg.ServiceName)
+
 	// Initialize Command Bus
 	commandBus := cqrs.NewCommandBus()
 
@@ -118,6 +156,12 @@ func main() {
 	// Initialize Query Bus
 	queryBus := cqrs.NewQueryBus()
 
+	// Install the shared command/query middleware pipeline
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: cfg.ServiceName,
+		Metrics:     serviceMetrics,
+	})
+
 	// Register Query Handlers - Role
 	queryBus.RegisterHandler("get_role", query.NewGetRoleHandler(roleRepo))
 	queryBus.RegisterHandler("list_roles", query.NewListRolesHandler(roleRepo))
@@ -147,8 +191,13 @@ func main() {
 		kafkaProducer,
 	)
 
-	// Create gRPC server
-	server := grpc.NewServer()
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:    cfg.ServiceName,
+		DefaultTimeout: cfg.Server.GRPCTimeout,
+		MaxTimeout:     cfg.Server.GRPCMaxTimeout,
+		Metrics:        serviceMetrics,
+	})
 	authpb.RegisterAuthServiceServer(server, authHandler)
 
 	// Register reflection service (for grpcurl)
@@ -170,12 +219,76 @@ func main() {
 		}
 	}()
 
+	// Start HTTP server (JWKS, health and metrics)
+	httpServer := setupHTTPServer(cfg, keyRing, serviceMetrics)
+	go func() {
+		logger.Info(fmt.Sprintf("Starting HTTP server on port %d", cfg.Port))
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal(fmt.Sprintf("Failed to serve HTTP: %v", err))
+		}
+	}()
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("Shutting down Auth Service...")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		logger.Error(fmt.Sprintf("HTTP server shutdown error: %v", err))
+	}
 	server.GracefulStop()
 	logger.Info("Auth Service stopped")
 }
+
+// loadKeyRing loads the signing key and retiring keys; nil without a signing key
+func loadKeyRing(cfg config.JWTConfig) (*jwt.KeyRing, error) {
+	if cfg.SigningKeyFile == "" {
+		return nil, nil
+	}
+
+	ring := jwt.NewKeyRing()
+	for _, path := range cfg.RetiringKeyFiles {
+		key, err := jwt.LoadSigningKeyFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ring.Add(key, jwt.KeyRetiring)
+	}
+
+	signingKey, err := jwt.LoadSigningKeyFile(cfg.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.SigningKeyFile, err)
+	}
+	if signingKey.PrivateKey == nil {
+		return nil, fmt.Errorf("%s: signing key must be a private key", cfg.SigningKeyFile)
+	}
+	ring.Add(signingKey, jwt.KeyActive)
+
+	return ring, nil
+}
+
+// setupHTTPServer serves the JWKS endpoint, a health check, metrics and the log level endpoint
+// Without a key ring the JWKS is empty, since HS256 secrets are never published
+func setupHTTPServer(cfg *config.Config, keyRing *jwt.KeyRing, serviceMetrics *metrics.Metrics) *http.Server {
+	if keyRing == nil {
+		keyRing = jwt.NewKeyRing()
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET "+jwksPath, jwt.JWKSHandler(keyRing))
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	mux.Handle("GET /metrics", serviceMetrics.Handler())
+	mux.Handle("/admin/log-level", sharedlogger.LevelHandler())
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/jwt"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	"toxictoast/services/auth-service/internal/repository/interfaces"
@@ -71,8 +72,9 @@ type RefreshTokenCommand struct {
 	cqrs.BaseCommand
 	RefreshToken string `json:"refresh_token"`
 	// Populated by handler
-	Email    string `json:"-"`
-	Username string `json:"-"`
+	Email     string `json:"-"`
+	Username  string `json:"-"`
+	SessionID string `json:"-"`
 }
 
 func (c *RefreshTokenCommand) CommandName() string {
@@ -102,7 +104,7 @@ type RegisterHandler struct {
 	rolePermissionRepo interfaces.RolePermissionRepository
 	jwtHelper          *jwt.JWTHelper
 	userServiceAddr    string
-	kafkaProducer      *kafka.Producer
+	kafkaProducer      kafka.Publisher
 }
 
 func NewRegisterHandler(
@@ -110,7 +112,7 @@ func NewRegisterHandler(
 	rolePermissionRepo interfaces.RolePermissionRepository,
 	jwtHelper *jwt.JWTHelper,
 	userServiceAddr string,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *RegisterHandler {
 	return &RegisterHandler{
 		userRoleRepo:       userRoleRepo,
@@ -125,7 +127,7 @@ func (h *RegisterHandler) Handle(ctx context.Context, cmd cqrs.Command) error {
 	registerCmd := cmd.(*RegisterCommand)
 
 	// Connect to user-service
-	conn, err := grpc.NewClient(h.userServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dialUserService(h.userServiceAddr)
 	if err != nil {
 		return fmt.Errorf("failed to connect to user-service: %w", err)
 	}
@@ -177,7 +179,7 @@ func (h *LoginHandler) Handle(ctx context.Context, cmd cqrs.Command) error {
 	loginCmd := cmd.(*LoginCommand)
 
 	// Connect to user-service
-	conn, err := grpc.NewClient(h.userServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dialUserService(h.userServiceAddr)
 	if err != nil {
 		return fmt.Errorf("failed to connect to user-service: %w", err)
 	}
@@ -245,7 +247,7 @@ func (h *RefreshTokenHandler) Handle(ctx context.Context, cmd cqrs.Command) erro
 	}
 
 	// Connect to user-service to get fresh user data
-	conn, err := grpc.NewClient(h.userServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dialUserService(h.userServiceAddr)
 	if err != nil {
 		return fmt.Errorf("failed to connect to user-service: %w", err)
 	}
@@ -261,6 +263,13 @@ func (h *RefreshTokenHandler) Handle(ctx context.Context, cmd cqrs.Command) erro
 	refreshCmd.AggregateID = userResp.User.Id
 	refreshCmd.Email = userResp.User.Email
 	refreshCmd.Username = userResp.User.Username
+	refreshCmd.SessionID = claims.SessionID
 
 	return nil
 }
+
+// dialUserService connects to user-service, propagating trace context and request IDs
+func dialUserService(addr string) (*grpc.ClientConn, error) {
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, sharedgrpc.ClientOptions()...)
+	return grpc.NewClient(addr, opts...)
+}
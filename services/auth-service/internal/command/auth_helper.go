@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/uuid"
+
 	"github.com/toxictoast/toxictoastgo/shared/jwt"
 	"toxictoast/services/auth-service/internal/repository/interfaces"
 )
@@ -29,7 +31,12 @@ func NewTokenHelper(
 }
 
 // GenerateTokens generates access and refresh tokens for a user
-func (h *TokenHelper) GenerateTokens(ctx context.Context, userID, email, username string) (string, string, int64, error) {
+// Both tokens belong to the given session; an empty sessionID starts a new one
+func (h *TokenHelper) GenerateTokens(ctx context.Context, sessionID, userID, email, username string) (string, string, int64, error) {
+	if sessionID == "" {
+		sessionID = uuid.NewString()
+	}
+
 	// Get user roles
 	roles, err := h.userRoleRepo.GetUserRoles(ctx, userID)
 	if err != nil {
@@ -53,13 +60,13 @@ func (h *TokenHelper) GenerateTokens(ctx context.Context, userID, email, usernam
 	}
 
 	// Generate access token
-	accessToken, err := h.jwtHelper.GenerateAccessToken(userID, email, username, roleNames, permissionStrings)
+	accessToken, err := h.jwtHelper.GenerateSessionAccessToken(sessionID, userID, email, username, roleNames, permissionStrings)
 	if err != nil {
 		return "", "", 0, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generate refresh token
-	refreshToken, err := h.jwtHelper.GenerateRefreshToken(userID)
+	refreshToken, err := h.jwtHelper.GenerateSessionRefreshToken(sessionID, userID)
 	if err != nil {
 		return "", "", 0, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
@@ -11,6 +11,14 @@ import (
 	"toxictoast/services/auth-service/internal/repository/interfaces"
 )
 
+var (
+	// ErrRoleAlreadyAssigned is returned when assigning a role the user already has
+	ErrRoleAlreadyAssigned = errors.New("user already has this role")
+
+	// ErrRoleNotAssigned is returned when revoking a role the user doesn't have
+	ErrRoleNotAssigned = errors.New("user does not have this role")
+)
+
 // AssignRoleCommand assigns a role to a user
 type AssignRoleCommand struct {
 	cqrs.BaseCommand
@@ -128,7 +136,7 @@ func (h *AssignRoleHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 		return fmt.Errorf("failed to check role assignment: %w", err)
 	}
 	if hasRole {
-		return fmt.Errorf("user already has this role")
+		return ErrRoleAlreadyAssigned
 	}
 
 	// Assign role
@@ -159,7 +167,7 @@ func (h *RevokeRoleHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 		return fmt.Errorf("failed to check role assignment: %w", err)
 	}
 	if !hasRole {
-		return fmt.Errorf("user does not have this role")
+		return ErrRoleNotAssigned
 	}
 
 	return h.userRoleRepo.RevokeRole(ctx, revokeCmd.UserID, revokeCmd.RoleID)
@@ -10,7 +10,7 @@ import (
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/jwt"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
-	authpb "toxictoast/services/auth-service/api/proto"
+	authpb "github.com/toxictoast/toxictoastgo/shared/proto/auth"
 	"toxictoast/services/auth-service/internal/command"
 	"toxictoast/services/auth-service/internal/query"
 	"toxictoast/services/auth-service/internal/repository/interfaces"
@@ -22,7 +22,7 @@ type AuthHandler struct {
 	commandBus    *cqrs.CommandBus
 	queryBus      *cqrs.QueryBus
 	tokenHelper   *command.TokenHelper
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 // NewAuthHandler creates a new auth handler with CQRS support
@@ -32,7 +32,7 @@ func NewAuthHandler(
 	jwtHelper *jwt.JWTHelper,
 	userRoleRepo interfaces.UserRoleRepository,
 	rolePermissionRepo interfaces.RolePermissionRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *AuthHandler {
 	return &AuthHandler{
 		commandBus:    commandBus,
@@ -73,7 +73,7 @@ func (h *AuthHandler) Register(ctx context.Context, req *authpb.RegisterRequest)
 	userID := cmd.AggregateID
 
 	// Generate tokens
-	accessToken, refreshToken, expiresIn, err := h.tokenHelper.GenerateTokens(ctx, userID, req.Email, req.Username)
+	accessToken, refreshToken, expiresIn, err := h.tokenHelper.GenerateTokens(ctx, "", userID, req.Email, req.Username)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate tokens: %v", err)
 	}
@@ -124,7 +124,7 @@ func (h *AuthHandler) Login(ctx context.Context, req *authpb.LoginRequest) (*aut
 	username := cmd.Username
 
 	// Generate tokens
-	accessToken, refreshToken, expiresIn, err := h.tokenHelper.GenerateTokens(ctx, userID, req.Email, username)
+	accessToken, refreshToken, expiresIn, err := h.tokenHelper.GenerateTokens(ctx, "", userID, req.Email, username)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate tokens: %v", err)
 	}
@@ -214,8 +214,8 @@ func (h *AuthHandler) RefreshToken(ctx context.Context, req *authpb.RefreshToken
 	email := cmd.Email
 	username := cmd.Username
 
-	// Generate new tokens
-	accessToken, refreshToken, expiresIn, err := h.tokenHelper.GenerateTokens(ctx, userID, email, username)
+	// Generate new tokens, keeping the session so it can still be revoked as a whole
+	accessToken, refreshToken, expiresIn, err := h.tokenHelper.GenerateTokens(ctx, cmd.SessionID, userID, email, username)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate tokens: %v", err)
 	}
@@ -2,12 +2,13 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
-	authpb "toxictoast/services/auth-service/api/proto"
+	authpb "github.com/toxictoast/toxictoastgo/shared/proto/auth"
 	"toxictoast/services/auth-service/internal/command"
 	"toxictoast/services/auth-service/internal/domain"
 	"toxictoast/services/auth-service/internal/query"
@@ -22,6 +23,9 @@ func (h *AuthHandler) AssignRole(ctx context.Context, req *authpb.AssignRoleRequ
 	}
 
 	if err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		if errors.Is(err, command.ErrRoleAlreadyAssigned) {
+			return nil, status.Errorf(codes.AlreadyExists, "failed to assign role: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to assign role: %v", err)
 	}
 
@@ -40,6 +44,9 @@ func (h *AuthHandler) RevokeRole(ctx context.Context, req *authpb.RevokeRoleRequ
 	}
 
 	if err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		if errors.Is(err, command.ErrRoleNotAssigned) {
+			return nil, status.Errorf(codes.NotFound, "failed to revoke role: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to revoke role: %v", err)
 	}
 
@@ -8,7 +8,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
-	authpb "toxictoast/services/auth-service/api/proto"
+	authpb "github.com/toxictoast/toxictoastgo/shared/proto/auth"
 	"toxictoast/services/auth-service/internal/command"
 	"toxictoast/services/auth-service/internal/domain"
 	"toxictoast/services/auth-service/internal/query"
@@ -12,6 +12,7 @@ type Config struct {
 	Port            int
 	GRPCPort        int
 	Database        sharedconfig.DatabaseConfig
+	Server          sharedconfig.ServerConfig
 	JWT             JWTConfig
 	Kafka           sharedconfig.KafkaConfig
 	UserServiceAddr string
@@ -19,7 +20,16 @@ type Config struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	SecretKey            string
+	SecretKey string
+
+	// SigningKeyFile is a PEM RSA or Ed25519 private key; tokens are signed with
+	// RS256/EdDSA instead of SecretKey when set
+	SigningKeyFile string
+
+	// RetiringKeyFiles are previous signing keys (private or public PEM) that still
+	// validate tokens issued before a rotation
+	RetiringKeyFiles []string
+
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
 }
@@ -33,8 +43,11 @@ func LoadConfig() (*Config, error) {
 		Port:        sharedconfig.GetEnvAsInt("PORT", 8080),
 		GRPCPort:    sharedconfig.GetEnvAsInt("GRPC_PORT", 9090),
 		Database:    sharedconfig.LoadDatabaseConfig(),
+		Server:      sharedconfig.LoadServerConfig(),
 		JWT: JWTConfig{
 			SecretKey:            sharedconfig.GetEnv("JWT_SECRET", "your-secret-key-change-me"),
+			SigningKeyFile:       sharedconfig.GetEnv("JWT_SIGNING_KEY_FILE", ""),
+			RetiringKeyFiles:     sharedconfig.GetEnvAsSlice("JWT_RETIRING_KEY_FILES", ""),
 			AccessTokenDuration:  sharedconfig.GetEnvAsDuration("JWT_ACCESS_DURATION", "15m"),
 			RefreshTokenDuration: sharedconfig.GetEnvAsDuration("JWT_REFRESH_DURATION", "168h"),
 		},
@@ -19,11 +19,14 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/toxictoast/toxictoastgo/shared/auth"
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	pb "toxictoast/services/blog-service/api/proto"
 	"toxictoast/services/blog-service/internal/command"
@@ -56,6 +59,18 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("blog-service")); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("blog-service"))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize logger
 	logger.Init()
 
@@ -63,7 +78,6 @@ func main() {
 	log.Printf("Environment: %s", cfg.Environment)
 
 	// Connect to database with retry
-	var err error
 	db, err = database.Connect(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -84,20 +98,24 @@ func main() {
 	log.Printf("Database schema is up to date (using blog_ table prefix)")
 
 	// Initialize Kafka producer
-	kafkaProducer, err := kafka.NewProducer(cfg.Kafka.Brokers)
+	// Handlers get a nil Publisher when Kafka is unavailable
+	var kafkaProducer kafka.Publisher
+	producer, err := kafka.NewProducerWithOptions(cfg.Kafka.Brokers, kafka.ProducerOptions{Source: "blog-service"})
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Kafka producer: %v", err)
 		log.Printf("Service will continue without event publishing")
-		kafkaProducer = nil
 	} else {
 		log.Printf("Kafka producer connected successfully")
-		defer kafkaProducer.Close()
+		kafkaProducer = producer
+		defer producer.Close()
 	}
 
 	// Initialize Keycloak auth
 	var keycloakAuth *auth.KeycloakAuth
 	if cfg.AuthEnabled {
-		keycloakAuth, err = auth.NewKeycloakAuth(&cfg.Keycloak)
+		keycloakAuth, err = auth.NewKeycloakAuthWithOptions(&cfg.Keycloak, auth.KeycloakOptions{
+			PublicMethods: publicMethods,
+		})
 		if err != nil {
 			log.Printf("Warning: Failed to initialize Keycloak auth: %v", err)
 			log.Printf("Service will continue without authentication")
@@ -160,6 +178,13 @@ func main() {
 	// Initialize Query Bus
 	queryBus := cqrs.NewQueryBus()
 
+	// Install the shared command/query middleware pipeline
+	serviceMetrics := metrics.New("blog-service")
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: "blog-service",
+		Metrics:     serviceMetrics,
+	})
+
 	// Register Query Handlers - Post (3 queries)
 	queryBus.RegisterHandler("get_post_by_id", query.NewGetPostByIDHandler(postRepo))
 	queryBus.RegisterHandler("get_post_by_slug", query.NewGetPostBySlugHandler(postRepo))
@@ -210,7 +235,7 @@ func main() {
 	blogHandler := grpcHandler.NewBlogHandler(postHandler, categoryHandler, tagHandler, commentHandler, mediaHandler)
 
 	// Setup gRPC server
-	grpcServer := setupGRPCServer(cfg, keycloakAuth, blogHandler)
+	grpcServer := setupGRPCServer(cfg, serviceMetrics, keycloakAuth, blogHandler)
 
 	// Start gRPC server
 	go func() {
@@ -225,7 +250,7 @@ func main() {
 	}()
 
 	// Setup HTTP server for health checks
-	httpServer := setupHTTPServer(cfg)
+	httpServer := setupHTTPServer(cfg, serviceMetrics)
 
 	// Start HTTP server
 	go func() {
@@ -261,32 +286,36 @@ func main() {
 	log.Println("Servers stopped")
 }
 
-func setupGRPCServer(cfg *config.Config, keycloakAuth *auth.KeycloakAuth, blogHandler *grpcHandler.BlogHandler) *grpc.Server {
-	// Setup interceptors
-	var opts []grpc.ServerOption
-
-	// Always add auth interceptor (extracts user from metadata)
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
+// publicMethods are the read-only endpoints available without a token
+var publicMethods = []string{
+	"/blog.BlogService/ListPosts",
+	"/blog.BlogService/GetPost",
+	"/blog.BlogService/ListCategories",
+	"/blog.BlogService/GetCategory",
+	"/blog.BlogService/ListTags",
+	"/blog.BlogService/GetTag",
+	"/blog.BlogService/ListComments",
+	"/blog.BlogService/GetComment",
+}
 
-	// Add Keycloak interceptor if enabled
+func setupGRPCServer(cfg *config.Config, serviceMetrics *metrics.Metrics, keycloakAuth *auth.KeycloakAuth, blogHandler *grpcHandler.BlogHandler) *grpc.Server {
+	// Keycloak authentication runs after the user was extracted from the metadata
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
 	if keycloakAuth != nil {
 		unaryInterceptors = append(unaryInterceptors, keycloakAuth.UnaryInterceptor())
 		streamInterceptors = append(streamInterceptors, keycloakAuth.StreamInterceptor())
 	}
 
-	// Chain interceptors
-	opts = append(opts,
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	)
-
-	// Create gRPC server
-	server := grpc.NewServer(opts...)
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:        "blog-service",
+		Metrics:            serviceMetrics,
+		DefaultTimeout:     cfg.Server.GRPCTimeout,
+		MaxTimeout:         cfg.Server.GRPCMaxTimeout,
+		UnaryInterceptors:  unaryInterceptors,
+		StreamInterceptors: streamInterceptors,
+	})
 
 	// Register services
 	pb.RegisterBlogServiceServer(server, blogHandler)
@@ -297,7 +326,7 @@ func setupGRPCServer(cfg *config.Config, keycloakAuth *auth.KeycloakAuth, blogHa
 	return server
 }
 
-func setupHTTPServer(cfg *config.Config) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceMetrics *metrics.Metrics) *http.Server {
 	router := mux.NewRouter()
 
 	// Health check endpoints
@@ -305,6 +334,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	router.HandleFunc("/health/ready", readinessHandler).Methods("GET")
 	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
 
+	// Prometheus metrics endpoint
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
@@ -80,12 +80,12 @@ func (c *DeleteCategoryCommand) Validate() error {
 // CreateCategoryHandler handles category creation
 type CreateCategoryHandler struct {
 	categoryRepo  repository.CategoryRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateCategoryHandler(
 	categoryRepo repository.CategoryRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateCategoryHandler {
 	return &CreateCategoryHandler{
 		categoryRepo:  categoryRepo,
@@ -160,12 +160,12 @@ func (h *CreateCategoryHandler) generateUniqueSlug(ctx context.Context, name str
 // UpdateCategoryHandler handles category updates
 type UpdateCategoryHandler struct {
 	categoryRepo  repository.CategoryRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewUpdateCategoryHandler(
 	categoryRepo repository.CategoryRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *UpdateCategoryHandler {
 	return &UpdateCategoryHandler{
 		categoryRepo:  categoryRepo,
@@ -247,12 +247,12 @@ func (h *UpdateCategoryHandler) generateUniqueSlug(ctx context.Context, name str
 // DeleteCategoryHandler handles category deletion
 type DeleteCategoryHandler struct {
 	categoryRepo  repository.CategoryRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteCategoryHandler(
 	categoryRepo repository.CategoryRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteCategoryHandler {
 	return &DeleteCategoryHandler{
 		categoryRepo:  categoryRepo,
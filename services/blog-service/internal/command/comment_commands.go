@@ -122,13 +122,13 @@ func (c *ModerateCommentCommand) Validate() error {
 type CreateCommentHandler struct {
 	commentRepo   repository.CommentRepository
 	postRepo      repository.PostRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateCommentHandler(
 	commentRepo repository.CommentRepository,
 	postRepo repository.PostRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateCommentHandler {
 	return &CreateCommentHandler{
 		commentRepo:   commentRepo,
@@ -236,12 +236,12 @@ func (h *UpdateCommentHandler) Handle(ctx context.Context, cmd cqrs.Command) err
 // DeleteCommentHandler handles comment deletion
 type DeleteCommentHandler struct {
 	commentRepo   repository.CommentRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteCommentHandler(
 	commentRepo repository.CommentRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteCommentHandler {
 	return &DeleteCommentHandler{
 		commentRepo:   commentRepo,
@@ -291,12 +291,12 @@ func (h *DeleteCommentHandler) Handle(ctx context.Context, cmd cqrs.Command) err
 // ModerateCommentHandler handles comment moderation (status changes)
 type ModerateCommentHandler struct {
 	commentRepo   repository.CommentRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewModerateCommentHandler(
 	commentRepo repository.CommentRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *ModerateCommentHandler {
 	return &ModerateCommentHandler{
 		commentRepo:   commentRepo,
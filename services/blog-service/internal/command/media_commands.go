@@ -76,14 +76,14 @@ func (c *DeleteMediaCommand) Validate() error {
 type UploadMediaHandler struct {
 	mediaRepo     repository.MediaRepository
 	storage       *storage.Storage
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 	config        *config.Config
 	baseURL       string
 }
 
 func NewUploadMediaHandler(
 	mediaRepo repository.MediaRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 	cfg *config.Config,
 ) (*UploadMediaHandler, error) {
 	// Initialize storage
@@ -284,13 +284,13 @@ func (h *UploadMediaHandler) resizeLargeImage(data []byte) ([]byte, error) {
 type DeleteMediaHandler struct {
 	mediaRepo     repository.MediaRepository
 	storage       *storage.Storage
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 	config        *config.Config
 }
 
 func NewDeleteMediaHandler(
 	mediaRepo repository.MediaRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 	cfg *config.Config,
 ) (*DeleteMediaHandler, error) {
 	// Initialize storage
@@ -159,14 +159,14 @@ type CreatePostHandler struct {
 	postRepo      repository.PostRepository
 	categoryRepo  repository.CategoryRepository
 	tagRepo       repository.TagRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreatePostHandler(
 	postRepo repository.PostRepository,
 	categoryRepo repository.CategoryRepository,
 	tagRepo repository.TagRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreatePostHandler {
 	return &CreatePostHandler{
 		postRepo:      postRepo,
@@ -294,14 +294,14 @@ type UpdatePostHandler struct {
 	postRepo      repository.PostRepository
 	categoryRepo  repository.CategoryRepository
 	tagRepo       repository.TagRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewUpdatePostHandler(
 	postRepo repository.PostRepository,
 	categoryRepo repository.CategoryRepository,
 	tagRepo repository.TagRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *UpdatePostHandler {
 	return &UpdatePostHandler{
 		postRepo:      postRepo,
@@ -423,12 +423,12 @@ func (h *UpdatePostHandler) getCategoriesByIDs(ctx context.Context, ids []string
 // DeletePostHandler handles post deletion
 type DeletePostHandler struct {
 	postRepo      repository.PostRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeletePostHandler(
 	postRepo repository.PostRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeletePostHandler {
 	return &DeletePostHandler{
 		postRepo:      postRepo,
@@ -467,12 +467,12 @@ func (h *DeletePostHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 // PublishPostHandler handles post publishing
 type PublishPostHandler struct {
 	postRepo      repository.PostRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewPublishPostHandler(
 	postRepo repository.PostRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *PublishPostHandler {
 	return &PublishPostHandler{
 		postRepo:      postRepo,
@@ -548,12 +548,12 @@ func (h *IncrementPostViewCountHandler) Handle(ctx context.Context, cmd cqrs.Com
 // PublishScheduledPostHandler handles scheduled post publishing
 type PublishScheduledPostHandler struct {
 	postRepo      repository.PostRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewPublishScheduledPostHandler(
 	postRepo repository.PostRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *PublishScheduledPostHandler {
 	return &PublishScheduledPostHandler{
 		postRepo:      postRepo,
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
+	"github.com/toxictoast/toxictoastgo/shared/kafka/kafkatest"
 
 	"toxictoast/services/blog-service/internal/domain"
 	"toxictoast/services/blog-service/internal/repository"
@@ -350,8 +352,9 @@ func TestCreatePostHandler_Handle(t *testing.T) {
 
 		mockPostRepo.On("SlugExists", ctx, mock.AnythingOfType("string")).Return(false, nil)
 		mockPostRepo.On("Create", ctx, mock.AnythingOfType("*domain.Post")).Return(nil)
+		broker := kafka.NewMemoryBroker()
 
-		handler := NewCreatePostHandler(mockPostRepo, mockCategoryRepo, mockTagRepo, nil)
+		handler := NewCreatePostHandler(mockPostRepo, mockCategoryRepo, mockTagRepo, broker)
 
 		cmd := &CreatePostCommand{
 			Title:    "Test Post",
@@ -365,6 +368,13 @@ func TestCreatePostHandler_Handle(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotEmpty(t, cmd.AggregateID)
 		mockPostRepo.AssertExpectations(t)
+
+		event := kafkatest.AssertEvent(t, broker, "blog.post.created", func(e *kafka.PostCreatedEvent) bool {
+			return e.PostID == cmd.AggregateID
+		})
+		assert.Equal(t, "Test Post", event.Title)
+		assert.Equal(t, "author-123", event.AuthorID)
+		assert.Equal(t, string(domain.PostStatusDraft), event.Status)
 	})
 
 	t.Run("repository error during creation", func(t *testing.T) {
@@ -374,8 +384,9 @@ func TestCreatePostHandler_Handle(t *testing.T) {
 
 		mockPostRepo.On("SlugExists", ctx, mock.AnythingOfType("string")).Return(false, nil)
 		mockPostRepo.On("Create", ctx, mock.AnythingOfType("*domain.Post")).Return(errors.New("database error"))
+		broker := kafka.NewMemoryBroker()
 
-		handler := NewCreatePostHandler(mockPostRepo, mockCategoryRepo, mockTagRepo, nil)
+		handler := NewCreatePostHandler(mockPostRepo, mockCategoryRepo, mockTagRepo, broker)
 
 		cmd := &CreatePostCommand{
 			Title:    "Test Post",
@@ -388,6 +399,7 @@ func TestCreatePostHandler_Handle(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create post")
+		kafkatest.AssertPublishedCount(t, broker, "blog.post.created", 0)
 	})
 
 	t.Run("create post with categories and tags", func(t *testing.T) {
@@ -484,8 +496,9 @@ func TestPublishPostHandler_Handle(t *testing.T) {
 		mockPostRepo.On("Update", ctx, mock.MatchedBy(func(post *domain.Post) bool {
 			return post.ID == "post-123" && post.Status == domain.PostStatusPublished && post.PublishedAt != nil
 		})).Return(nil)
+		broker := kafka.NewMemoryBroker()
 
-		handler := NewPublishPostHandler(mockPostRepo, nil)
+		handler := NewPublishPostHandler(mockPostRepo, broker)
 
 		cmd := &PublishPostCommand{
 			BaseCommand: cqrs.BaseCommand{AggregateID: "post-123"},
@@ -495,6 +508,12 @@ func TestPublishPostHandler_Handle(t *testing.T) {
 
 		assert.NoError(t, err)
 		mockPostRepo.AssertExpectations(t)
+
+		event := kafkatest.AssertEvent(t, broker, "blog.post.published", func(e *kafka.PostPublishedEvent) bool {
+			return e.PostID == "post-123"
+		})
+		assert.Equal(t, "Test Post", event.Title)
+		assert.False(t, event.PublishedAt.IsZero())
 	})
 
 	t.Run("already published post", func(t *testing.T) {
@@ -509,8 +528,9 @@ func TestPublishPostHandler_Handle(t *testing.T) {
 		}
 
 		mockPostRepo.On("GetByID", ctx, "post-123").Return(existingPost, nil)
+		broker := kafka.NewMemoryBroker()
 
-		handler := NewPublishPostHandler(mockPostRepo, nil)
+		handler := NewPublishPostHandler(mockPostRepo, broker)
 
 		cmd := &PublishPostCommand{
 			BaseCommand: cqrs.BaseCommand{AggregateID: "post-123"},
@@ -521,6 +541,7 @@ func TestPublishPostHandler_Handle(t *testing.T) {
 		assert.NoError(t, err)
 		mockPostRepo.AssertExpectations(t)
 		mockPostRepo.AssertNotCalled(t, "Update")
+		kafkatest.AssertPublishedCount(t, broker, "blog.post.published", 0)
 	})
 }
 
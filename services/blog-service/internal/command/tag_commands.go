@@ -79,12 +79,12 @@ func (c *DeleteTagCommand) Validate() error {
 // CreateTagHandler handles tag creation
 type CreateTagHandler struct {
 	tagRepo       repository.TagRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateTagHandler(
 	tagRepo repository.TagRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateTagHandler {
 	return &CreateTagHandler{
 		tagRepo:       tagRepo,
@@ -147,12 +147,12 @@ func (h *CreateTagHandler) generateUniqueSlug(ctx context.Context, name string)
 // UpdateTagHandler handles tag updates
 type UpdateTagHandler struct {
 	tagRepo       repository.TagRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewUpdateTagHandler(
 	tagRepo repository.TagRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *UpdateTagHandler {
 	return &UpdateTagHandler{
 		tagRepo:       tagRepo,
@@ -209,12 +209,12 @@ func (h *UpdateTagHandler) generateUniqueSlug(ctx context.Context, name string)
 // DeleteTagHandler handles tag deletion
 type DeleteTagHandler struct {
 	tagRepo       repository.TagRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteTagHandler(
 	tagRepo repository.TagRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteTagHandler {
 	return &DeleteTagHandler{
 		tagRepo:       tagRepo,
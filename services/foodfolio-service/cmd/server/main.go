@@ -17,14 +17,17 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/toxictoast/toxictoastgo/shared/auth"
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
 
 	"toxictoast/services/foodfolio-service/pkg/config"
 
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	// Repository layer
 	"toxictoast/services/foodfolio-service/internal/repository/entity"
@@ -57,6 +60,18 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("foodfolio-service")); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("foodfolio-service"))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize logger
 	logger.Init()
 
@@ -64,7 +79,6 @@ func main() {
 	log.Printf("Environment: %s", cfg.Environment)
 
 	// Connect to database with retry
-	var err error
 	db, err = database.Connect(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -93,14 +107,16 @@ func main() {
 	log.Printf("Database schema is up to date (using foodfolio_ table prefix)")
 
 	// Initialize Kafka producer
-	kafkaProducer, err := kafka.NewProducer(cfg.Kafka.Brokers)
+	// Handlers get a nil Publisher when Kafka is unavailable
+	var kafkaProducer kafka.Publisher
+	producer, err := kafka.NewProducerWithOptions(cfg.Kafka.Brokers, kafka.ProducerOptions{Source: "foodfolio-service"})
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Kafka producer: %v", err)
 		log.Printf("Service will continue without event publishing")
-		kafkaProducer = nil
 	} else {
 		log.Printf("Kafka producer connected successfully")
-		defer kafkaProducer.Close()
+		kafkaProducer = producer
+		defer producer.Close()
 	}
 
 	// Initialize Keycloak auth
@@ -214,6 +230,13 @@ func main() {
 	log.Println("Initializing Query Bus...")
 	queryBus := cqrs.NewQueryBus()
 
+	// Install the shared command/query middleware pipeline
+	serviceMetrics := metrics.New("foodfolio-service")
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: "foodfolio-service",
+		Metrics:     serviceMetrics,
+	})
+
 	// Register Category Query Handlers
 	queryBus.RegisterHandler("get_category_by_id", query.NewGetCategoryByIDHandler(categoryRepo))
 	queryBus.RegisterHandler("get_category_by_slug", query.NewGetCategoryBySlugHandler(categoryRepo))
@@ -320,6 +343,7 @@ func main() {
 	// Setup gRPC server
 	grpcServer := setupGRPCServer(
 		cfg,
+		serviceMetrics,
 		keycloakAuth,
 		categoryHandler,
 		companyHandler,
@@ -347,7 +371,7 @@ func main() {
 	}()
 
 	// Setup HTTP server for health checks
-	httpServer := setupHTTPServer(cfg)
+	httpServer := setupHTTPServer(cfg, serviceMetrics)
 
 	// Start HTTP server
 	go func() {
@@ -386,6 +410,7 @@ func main() {
 
 func setupGRPCServer(
 	cfg *config.Config,
+	serviceMetrics *metrics.Metrics,
 	keycloakAuth *auth.KeycloakAuth,
 	categoryHandler *grpcHandler.CategoryHandler,
 	companyHandler *grpcHandler.CompanyHandler,
@@ -399,28 +424,23 @@ func setupGRPCServer(
 	shoppinglistHandler *grpcHandler.ShoppinglistHandler,
 	receiptHandler *grpcHandler.ReceiptHandler,
 ) *grpc.Server {
-	// Setup interceptors - always add auth interceptor to extract user from metadata
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
-
-	// Add Keycloak interceptor if enabled
+	// Keycloak authentication runs after the user was extracted from the metadata
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
 	if keycloakAuth != nil {
 		unaryInterceptors = append(unaryInterceptors, keycloakAuth.UnaryInterceptor())
 		streamInterceptors = append(streamInterceptors, keycloakAuth.StreamInterceptor())
 	}
 
-	// Create server options with chained interceptors
-	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	}
-
-	// Create gRPC server with interceptors
-	server := grpc.NewServer(opts...)
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:        "foodfolio-service",
+		Metrics:            serviceMetrics,
+		DefaultTimeout:     cfg.Server.GRPCTimeout,
+		MaxTimeout:         cfg.Server.GRPCMaxTimeout,
+		UnaryInterceptors:  unaryInterceptors,
+		StreamInterceptors: streamInterceptors,
+	})
 
 	// Register all services
 	log.Println("Registering gRPC services...")
@@ -443,7 +463,7 @@ func setupGRPCServer(
 	return server
 }
 
-func setupHTTPServer(cfg *config.Config) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceMetrics *metrics.Metrics) *http.Server {
 	router := mux.NewRouter()
 
 	// Health check endpoints
@@ -451,6 +471,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	router.HandleFunc("/health/ready", readinessHandler).Methods("GET")
 	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
 
+	// Prometheus metrics endpoint
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
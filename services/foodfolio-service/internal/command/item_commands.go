@@ -88,7 +88,7 @@ type CreateItemHandler struct {
 	categoryRepo  interfaces.CategoryRepository
 	companyRepo   interfaces.CompanyRepository
 	typeRepo      interfaces.TypeRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateItemHandler(
@@ -96,7 +96,7 @@ func NewCreateItemHandler(
 	categoryRepo interfaces.CategoryRepository,
 	companyRepo interfaces.CompanyRepository,
 	typeRepo interfaces.TypeRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateItemHandler {
 	return &CreateItemHandler{
 		itemRepo:      itemRepo,
@@ -174,7 +174,7 @@ type UpdateItemHandler struct {
 	categoryRepo  interfaces.CategoryRepository
 	companyRepo   interfaces.CompanyRepository
 	typeRepo      interfaces.TypeRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewUpdateItemHandler(
@@ -182,7 +182,7 @@ func NewUpdateItemHandler(
 	categoryRepo interfaces.CategoryRepository,
 	companyRepo interfaces.CompanyRepository,
 	typeRepo interfaces.TypeRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *UpdateItemHandler {
 	return &UpdateItemHandler{
 		itemRepo:      itemRepo,
@@ -269,12 +269,12 @@ func (h *UpdateItemHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 
 type DeleteItemHandler struct {
 	itemRepo      interfaces.ItemRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteItemHandler(
 	itemRepo interfaces.ItemRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteItemHandler {
 	return &DeleteItemHandler{
 		itemRepo:      itemRepo,
@@ -153,7 +153,7 @@ type CreateItemDetailHandler struct {
 	variantRepo   interfaces.ItemVariantRepository
 	warehouseRepo interfaces.WarehouseRepository
 	locationRepo  interfaces.LocationRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateItemDetailHandler(
@@ -161,7 +161,7 @@ func NewCreateItemDetailHandler(
 	variantRepo interfaces.ItemVariantRepository,
 	warehouseRepo interfaces.WarehouseRepository,
 	locationRepo interfaces.LocationRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateItemDetailHandler {
 	return &CreateItemDetailHandler{
 		detailRepo:    detailRepo,
@@ -230,10 +230,10 @@ func (h *CreateItemDetailHandler) Handle(ctx context.Context, cmd cqrs.Command)
 
 type OpenItemHandler struct {
 	detailRepo    interfaces.ItemDetailRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
-func NewOpenItemHandler(detailRepo interfaces.ItemDetailRepository, kafkaProducer *kafka.Producer) *OpenItemHandler {
+func NewOpenItemHandler(detailRepo interfaces.ItemDetailRepository, kafkaProducer kafka.Publisher) *OpenItemHandler {
 	return &OpenItemHandler{
 		detailRepo:    detailRepo,
 		kafkaProducer: kafkaProducer,
@@ -278,13 +278,13 @@ func (h *OpenItemHandler) Handle(ctx context.Context, cmd cqrs.Command) error {
 type UpdateItemDetailHandler struct {
 	detailRepo    interfaces.ItemDetailRepository
 	locationRepo  interfaces.LocationRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewUpdateItemDetailHandler(
 	detailRepo interfaces.ItemDetailRepository,
 	locationRepo interfaces.LocationRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *UpdateItemDetailHandler {
 	return &UpdateItemDetailHandler{
 		detailRepo:    detailRepo,
@@ -350,10 +350,10 @@ func (h *UpdateItemDetailHandler) Handle(ctx context.Context, cmd cqrs.Command)
 
 type DeleteItemDetailHandler struct {
 	detailRepo    interfaces.ItemDetailRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
-func NewDeleteItemDetailHandler(detailRepo interfaces.ItemDetailRepository, kafkaProducer *kafka.Producer) *DeleteItemDetailHandler {
+func NewDeleteItemDetailHandler(detailRepo interfaces.ItemDetailRepository, kafkaProducer kafka.Publisher) *DeleteItemDetailHandler {
 	return &DeleteItemDetailHandler{
 		detailRepo:    detailRepo,
 		kafkaProducer: kafkaProducer,
@@ -393,7 +393,7 @@ type BatchCreateItemDetailsHandler struct {
 	variantRepo   interfaces.ItemVariantRepository
 	warehouseRepo interfaces.WarehouseRepository
 	locationRepo  interfaces.LocationRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewBatchCreateItemDetailsHandler(
@@ -401,7 +401,7 @@ func NewBatchCreateItemDetailsHandler(
 	variantRepo interfaces.ItemVariantRepository,
 	warehouseRepo interfaces.WarehouseRepository,
 	locationRepo interfaces.LocationRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *BatchCreateItemDetailsHandler {
 	return &BatchCreateItemDetailsHandler{
 		detailRepo:    detailRepo,
@@ -486,13 +486,13 @@ func (h *BatchCreateItemDetailsHandler) Handle(ctx context.Context, cmd cqrs.Com
 type MoveItemsHandler struct {
 	detailRepo    interfaces.ItemDetailRepository
 	locationRepo  interfaces.LocationRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewMoveItemsHandler(
 	detailRepo interfaces.ItemDetailRepository,
 	locationRepo interfaces.LocationRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *MoveItemsHandler {
 	return &MoveItemsHandler{
 		detailRepo:    detailRepo,
@@ -97,14 +97,14 @@ type CreateItemVariantHandler struct {
 	variantRepo   interfaces.ItemVariantRepository
 	itemRepo      interfaces.ItemRepository
 	sizeRepo      interfaces.SizeRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateItemVariantHandler(
 	variantRepo interfaces.ItemVariantRepository,
 	itemRepo interfaces.ItemRepository,
 	sizeRepo interfaces.SizeRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateItemVariantHandler {
 	return &CreateItemVariantHandler{
 		variantRepo:   variantRepo,
@@ -185,12 +185,12 @@ func (h *CreateItemVariantHandler) Handle(ctx context.Context, cmd cqrs.Command)
 
 type UpdateItemVariantHandler struct {
 	variantRepo   interfaces.ItemVariantRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewUpdateItemVariantHandler(
 	variantRepo interfaces.ItemVariantRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *UpdateItemVariantHandler {
 	return &UpdateItemVariantHandler{
 		variantRepo:   variantRepo,
@@ -296,12 +296,12 @@ func (h *UpdateItemVariantHandler) Handle(ctx context.Context, cmd cqrs.Command)
 
 type DeleteItemVariantHandler struct {
 	variantRepo   interfaces.ItemVariantRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteItemVariantHandler(
 	variantRepo interfaces.ItemVariantRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteItemVariantHandler {
 	return &DeleteItemVariantHandler{
 		variantRepo:   variantRepo,
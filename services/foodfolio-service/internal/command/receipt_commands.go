@@ -225,13 +225,13 @@ func (c *CreateInventoryFromReceiptCommand) Validate() error {
 type CreateReceiptHandler struct {
 	receiptRepo   interfaces.ReceiptRepository
 	warehouseRepo interfaces.WarehouseRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateReceiptHandler(
 	receiptRepo interfaces.ReceiptRepository,
 	warehouseRepo interfaces.WarehouseRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateReceiptHandler {
 	return &CreateReceiptHandler{
 		receiptRepo:   receiptRepo,
@@ -374,12 +374,12 @@ func (h *UpdateReceiptOCRDataHandler) Handle(ctx context.Context, cmd cqrs.Comma
 
 type DeleteReceiptHandler struct {
 	receiptRepo   interfaces.ReceiptRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteReceiptHandler(
 	receiptRepo interfaces.ReceiptRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteReceiptHandler {
 	return &DeleteReceiptHandler{
 		receiptRepo:   receiptRepo,
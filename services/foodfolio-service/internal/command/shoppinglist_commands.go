@@ -202,12 +202,12 @@ func (c *ClearPurchasedItemsCommand) Validate() error {
 
 type CreateShoppinglistHandler struct {
 	shoppinglistRepo interfaces.ShoppinglistRepository
-	kafkaProducer    *kafka.Producer
+	kafkaProducer    kafka.Publisher
 }
 
 func NewCreateShoppinglistHandler(
 	shoppinglistRepo interfaces.ShoppinglistRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateShoppinglistHandler {
 	return &CreateShoppinglistHandler{
 		shoppinglistRepo: shoppinglistRepo,
@@ -245,12 +245,12 @@ func (h *CreateShoppinglistHandler) Handle(ctx context.Context, cmd cqrs.Command
 
 type UpdateShoppinglistHandler struct {
 	shoppinglistRepo interfaces.ShoppinglistRepository
-	kafkaProducer    *kafka.Producer
+	kafkaProducer    kafka.Publisher
 }
 
 func NewUpdateShoppinglistHandler(
 	shoppinglistRepo interfaces.ShoppinglistRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *UpdateShoppinglistHandler {
 	return &UpdateShoppinglistHandler{
 		shoppinglistRepo: shoppinglistRepo,
@@ -292,12 +292,12 @@ func (h *UpdateShoppinglistHandler) Handle(ctx context.Context, cmd cqrs.Command
 
 type DeleteShoppinglistHandler struct {
 	shoppinglistRepo interfaces.ShoppinglistRepository
-	kafkaProducer    *kafka.Producer
+	kafkaProducer    kafka.Publisher
 }
 
 func NewDeleteShoppinglistHandler(
 	shoppinglistRepo interfaces.ShoppinglistRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteShoppinglistHandler {
 	return &DeleteShoppinglistHandler{
 		shoppinglistRepo: shoppinglistRepo,
@@ -406,13 +406,13 @@ func (h *GenerateFromLowStockHandler) Handle(ctx context.Context, cmd cqrs.Comma
 type AddItemToShoppinglistHandler struct {
 	shoppinglistRepo interfaces.ShoppinglistRepository
 	variantRepo      interfaces.ItemVariantRepository
-	kafkaProducer    *kafka.Producer
+	kafkaProducer    kafka.Publisher
 }
 
 func NewAddItemToShoppinglistHandler(
 	shoppinglistRepo interfaces.ShoppinglistRepository,
 	variantRepo interfaces.ItemVariantRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *AddItemToShoppinglistHandler {
 	return &AddItemToShoppinglistHandler{
 		shoppinglistRepo: shoppinglistRepo,
@@ -471,12 +471,12 @@ func (h *AddItemToShoppinglistHandler) Handle(ctx context.Context, cmd cqrs.Comm
 
 type RemoveItemFromShoppinglistHandler struct {
 	shoppinglistRepo interfaces.ShoppinglistRepository
-	kafkaProducer    *kafka.Producer
+	kafkaProducer    kafka.Publisher
 }
 
 func NewRemoveItemFromShoppinglistHandler(
 	shoppinglistRepo interfaces.ShoppinglistRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *RemoveItemFromShoppinglistHandler {
 	return &RemoveItemFromShoppinglistHandler{
 		shoppinglistRepo: shoppinglistRepo,
@@ -544,12 +544,12 @@ func (h *UpdateShoppinglistItemHandler) Handle(ctx context.Context, cmd cqrs.Com
 
 type MarkItemPurchasedHandler struct {
 	shoppinglistRepo interfaces.ShoppinglistRepository
-	kafkaProducer    *kafka.Producer
+	kafkaProducer    kafka.Publisher
 }
 
 func NewMarkItemPurchasedHandler(
 	shoppinglistRepo interfaces.ShoppinglistRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *MarkItemPurchasedHandler {
 	return &MarkItemPurchasedHandler{
 		shoppinglistRepo: shoppinglistRepo,
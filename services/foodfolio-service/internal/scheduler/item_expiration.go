@@ -11,7 +11,7 @@ import (
 )
 
 type ItemExpirationScheduler struct {
-	kafkaProducer  *kafka.Producer
+	kafkaProducer  kafka.Publisher
 	itemDetailRepo interfaces.ItemDetailRepository
 	interval       time.Duration
 	enabled        bool
@@ -19,7 +19,7 @@ type ItemExpirationScheduler struct {
 }
 
 func NewItemExpirationScheduler(
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 	itemDetailRepo interfaces.ItemDetailRepository,
 	interval time.Duration,
 	enabled bool,
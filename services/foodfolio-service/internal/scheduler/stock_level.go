@@ -11,7 +11,7 @@ import (
 )
 
 type StockLevelScheduler struct {
-	kafkaProducer   *kafka.Producer
+	kafkaProducer   kafka.Publisher
 	itemVariantRepo interfaces.ItemVariantRepository
 	interval        time.Duration
 	enabled         bool
@@ -19,7 +19,7 @@ type StockLevelScheduler struct {
 }
 
 func NewStockLevelScheduler(
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 	itemVariantRepo interfaces.ItemVariantRepository,
 	interval time.Duration,
 	enabled bool,
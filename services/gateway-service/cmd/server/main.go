@@ -6,14 +6,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
 	"github.com/toxictoast/toxictoastgo/shared/auth"
 	"github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/jwt"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
 	sharedmiddleware "github.com/toxictoast/toxictoastgo/shared/middleware"
+	"github.com/toxictoast/toxictoastgo/shared/ratelimit"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	"toxictoast/services/gateway-service/internal/metrics"
 	"toxictoast/services/gateway-service/internal/middleware"
@@ -29,21 +34,77 @@ func main() {
 	}
 
 	// Initialize logger
+	if err := logger.Setup(config.LoadLoggingConfig("gateway-service")); err != nil {
+		panic(fmt.Sprintf("Failed to initialize logging: %v", err))
+	}
 	logger.Init()
 	logger.Info("Starting Gateway Service")
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), config.LoadTracingConfig("gateway-service"))
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize tracing: %v", err))
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize metrics
 	m := metrics.NewMetrics()
 	logger.Info("Prometheus metrics initialized")
 
+	// Connect to Redis (optional - shares token revocations and rate limits between replicas)
+	var redisClient *redis.Client
+	if cfg.RedisEnabled {
+		redisClient, err = newRedisClient(cfg)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to connect to Redis: %v", err))
+		}
+		defer redisClient.Close()
+		logger.Info(fmt.Sprintf("Connected to Redis at %s:%s", cfg.RedisHost, cfg.RedisPort))
+	}
+
 	// Initialize JWT helper and auth middleware
-	jwtHelper := jwt.NewJWTHelper(cfg.JWTSecret, 15*time.Minute, 7*24*time.Hour)
-	authMiddleware := sharedmiddleware.NewAuthMiddleware(jwtHelper)
+	jwtOptions := jwt.Options{
+		SecretKey:            cfg.JWTSecret,
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 7 * 24 * time.Hour,
+	}
+	if cfg.JWTJWKSURL != "" {
+		jwks := jwt.NewJWKSClient(cfg.JWTJWKSURL)
+		if err := jwks.Refresh(); err != nil {
+			// Keys are fetched again on the first token with an unknown kid
+			logger.Error(fmt.Sprintf("Failed to fetch JWKS: %v", err))
+		}
+		jwtOptions.KeySource = jwks
+		logger.Info(fmt.Sprintf("JWT validation with public keys from %s", cfg.JWTJWKSURL))
+	}
+	jwtHelper := jwt.NewJWTHelperWithOptions(jwtOptions)
+	var revocationStore auth.RevocationStore
+	if redisClient != nil {
+		revocationStore = auth.NewRedisRevocationStore(redisClient)
+		logger.Info("Token revocations shared through Redis")
+	} else {
+		revocationStore = auth.NewMemoryRevocationStore()
+		logger.Info("Token revocations in-memory (per replica)")
+	}
+	authMiddleware := sharedmiddleware.NewAuthMiddlewareWithOptions(jwtHelper, sharedmiddleware.AuthOptions{
+		RevocationStore: revocationStore,
+	})
 	logger.Info("JWT authentication middleware initialized")
 
-	// Initialize rate limiter (5 requests per minute for auth endpoints)
-	rateLimiter := sharedmiddleware.NewRateLimiter(5, 1*time.Minute)
-	logger.Info("Rate limiter initialized (5 req/min for auth endpoints)")
+	// Initialize rate limiting
+	// Policies from RATE_LIMIT_CONFIG replace the auth endpoint limiter
+	var rateLimiter *sharedmiddleware.RateLimiter
+	var policyLimiter *ratelimit.RateLimiter
+	if cfg.RateLimitConfig != "" {
+		policyLimiter, err = newPolicyLimiter(cfg, redisClient, jwtHelper)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to initialize rate limiting: %v", err))
+		}
+	} else {
+		// 5 requests per minute for auth endpoints
+		rateLimiter = sharedmiddleware.NewRateLimiter(5, 1*time.Minute)
+		logger.Info("Rate limiter initialized (5 req/min for auth endpoints)")
+	}
 
 	// Initialize Keycloak auth (optional - only if configured)
 	if cfg.KeycloakURL != "" && cfg.KeycloakRealm != "" {
@@ -113,6 +174,11 @@ func main() {
 	// Apply middleware in order (innermost to outermost)
 	var finalHandler http.Handler = handler
 
+	// Rate limit policies (inside metrics, so rejected requests are counted)
+	if policyLimiter != nil {
+		finalHandler = policyLimiter.Limit(finalHandler)
+	}
+
 	// Metrics middleware (should be innermost to capture all metrics)
 	finalHandler = middleware.Metrics(m)(finalHandler)
 	logger.Info("Metrics middleware enabled")
@@ -122,9 +188,15 @@ func main() {
 		logger.Info("CORS middleware enabled")
 	}
 
-	// Logging middleware (should be outermost)
+	// Logging middleware (inside tracing and request ID, so request logs carry both)
 	finalHandler = middleware.Logging(finalHandler)
 
+	// Request ID middleware (forwarded to the backends by the gRPC clients)
+	finalHandler = middleware.RequestID(finalHandler)
+
+	// Tracing middleware (should be outermost)
+	finalHandler = middleware.Tracing(finalHandler)
+
 	// Optional: Add authentication middleware for protected routes
 	// This would require more sophisticated routing setup
 
@@ -161,3 +233,58 @@ func main() {
 
 	logger.Info("Gateway service stopped")
 }
+
+// newRedisClient connects to Redis
+func newRedisClient(cfg *gwconfig.Config) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisHost + ":" + cfg.RedisPort,
+		Password: cfg.RedisPassword,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// newPolicyLimiter creates the policy rate limiter, sharing its state through Redis when connected
+func newPolicyLimiter(cfg *gwconfig.Config, redisClient *redis.Client, jwtHelper *jwt.JWTHelper) (*ratelimit.RateLimiter, error) {
+	policies, err := ratelimit.LoadConfig(cfg.RateLimitConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := ratelimit.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	var limiter ratelimit.Limiter
+	if redisClient != nil {
+		limiter = ratelimit.NewRedisLimiter(redisClient)
+		logger.Info(fmt.Sprintf("Rate limiting with %d policies shared through Redis", len(policies.Policies)))
+	} else {
+		limiter = ratelimit.NewMemoryLimiter()
+		logger.Info(fmt.Sprintf("Rate limiting with %d policies (in-memory, per replica)", len(policies.Policies)))
+	}
+
+	// Authentication runs per route, so the limiter reads the user from the token itself
+	return ratelimit.NewWithOptions(limiter, policies, ratelimit.Options{
+		HTTPUserID: func(r *http.Request) string {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				return ""
+			}
+			claims, err := jwtHelper.ValidateToken(token)
+			if err != nil {
+				return ""
+			}
+			return claims.UserID
+		},
+		TrustedProxies: trustedProxies,
+	}), nil
+}
@@ -3,15 +3,16 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
-	"strings"
-	"time"
 
-	authpb "toxictoast/services/auth-service/api/proto"
 	userpb "toxictoast/services/user-service/api/proto"
 
 	"github.com/gorilla/mux"
+	"github.com/toxictoast/toxictoastgo/shared/auth"
 	sharedmiddleware "github.com/toxictoast/toxictoastgo/shared/middleware"
+	authpb "github.com/toxictoast/toxictoastgo/shared/proto/auth"
 	"google.golang.org/grpc"
 )
 
@@ -194,37 +195,25 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // Logout handles POST /auth/logout
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	// Extract token from Authorization header
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Authorization header required", http.StatusUnauthorized)
+	// Claims were validated by the auth middleware
+	claims := sharedmiddleware.GetClaims(r.Context())
+	if claims == nil {
+		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
 		return
 	}
 
-	// Check for Bearer token format
-	parts := strings.SplitN(authHeader, " ", 2)
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		http.Error(w, "Invalid authorization header format. Expected: Bearer <token>", http.StatusUnauthorized)
-		return
+	// ?all=true logs the user out on every device, otherwise only this session is revoked
+	var err error
+	if r.URL.Query().Get("all") == "true" {
+		err = h.authMiddleware.RevokeUser(r.Context(), claims.UserID)
+	} else {
+		err = h.authMiddleware.RevokeSession(r.Context(), claims)
 	}
-
-	tokenString := parts[1]
-
-	// Validate token to ensure it's valid before blacklisting
-	_, err := h.authClient.ValidateToken(context.Background(), &authpb.ValidateTokenRequest{
-		Token: tokenString,
-	})
 	if err != nil {
-		// Token is already invalid, no need to blacklist
-		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+		http.Error(w, "Logout failed: "+err.Error(), http.StatusServiceUnavailable)
 		return
 	}
 
-	// Add token to blacklist with standard access token duration (15 minutes from now)
-	// The token will remain blacklisted until it would have expired naturally
-	expiresAt := time.Now().Add(15 * time.Minute)
-	h.authMiddleware.GetTokenBlacklist().Revoke(tokenString, expiresAt)
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -243,6 +232,19 @@ func (h *AuthHandler) ValidateToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The auth service only checks the signature and expiry, revoked tokens must not validate
+	if _, err := h.authMiddleware.ValidateToken(r.Context(), req.Token); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrTokenRevoked):
+			http.Error(w, "Token validation failed: "+err.Error(), http.StatusUnauthorized)
+			return
+		case errors.Is(err, auth.ErrRevocationUnavailable):
+			log.Printf("Failed to check token revocation: %v", err)
+			http.Error(w, "Token revocation check unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
 	pbReq := &authpb.ValidateTokenRequest{
 		Token: req.Token,
 	}
@@ -268,6 +270,20 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Refresh tokens of revoked sessions and users must not issue new access tokens
+	// Fail closed: a revoked token must not be refreshed while the store is unavailable
+	if _, err := h.authMiddleware.ValidateToken(r.Context(), req.RefreshToken); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrTokenRevoked):
+			http.Error(w, "Token refresh failed: "+err.Error(), http.StatusUnauthorized)
+			return
+		case errors.Is(err, auth.ErrRevocationUnavailable):
+			log.Printf("Failed to check token revocation: %v", err)
+			http.Error(w, "Token revocation check unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
 	pbReq := &authpb.RefreshTokenRequest{
 		RefreshToken: req.RefreshToken,
 	}
@@ -42,7 +42,7 @@ func Logging(next http.Handler) http.Handler {
 
 		// Log request details
 		duration := time.Since(start)
-		logger.Info(fmt.Sprintf(
+		logger.InfoContext(r.Context(), fmt.Sprintf(
 			"[%s] %s - Status: %d - Duration: %dms - Bytes: %d - IP: %s - UA: %s",
 			r.Method,
 			r.URL.Path,
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/toxictoast/toxictoastgo/shared/logger"
+)
+
+// RequestIDHeader carries the request ID from clients and back in responses
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID middleware takes the client's request ID or generates one
+// The ID is added to the log records of the request and forwarded to the backends
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
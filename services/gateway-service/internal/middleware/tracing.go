@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Tracing middleware starts a server span for every HTTP request
+// Incoming traceparent headers are continued, so the gateway joins the caller's trace
+func Tracing(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "gateway",
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return r.Method + " " + normalizePath(r.URL.Path)
+		}),
+	)
+}
@@ -6,6 +6,8 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 )
 
 // ServiceClients holds gRPC client connections to all backend services
@@ -29,11 +31,16 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 
 	var err error
 
+	// Propagate trace context and request IDs to the backends
+	dialOptions := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, sharedgrpc.ClientOptions()...)
+
 	// Connect to blog service
 	if config.BlogURL != "" {
 		clients.BlogConn, err = grpc.NewClient(
 			config.BlogURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to blog service: %w", err)
@@ -44,7 +51,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.LinkURL != "" {
 		clients.LinkConn, err = grpc.NewClient(
 			config.LinkURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to link service: %w", err)
@@ -55,7 +62,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.FoodfolioURL != "" {
 		clients.FoodfolioConn, err = grpc.NewClient(
 			config.FoodfolioURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to foodfolio service: %w", err)
@@ -66,7 +73,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.NotificationURL != "" {
 		clients.NotificationConn, err = grpc.NewClient(
 			config.NotificationURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to notification service: %w", err)
@@ -77,7 +84,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.SSEURL != "" {
 		clients.SSEConn, err = grpc.NewClient(
 			config.SSEURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to SSE service: %w", err)
@@ -88,7 +95,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.TwitchBotURL != "" {
 		clients.TwitchBotConn, err = grpc.NewClient(
 			config.TwitchBotURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to twitchbot service: %w", err)
@@ -99,7 +106,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.WebhookURL != "" {
 		clients.WebhookConn, err = grpc.NewClient(
 			config.WebhookURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to webhook service: %w", err)
@@ -110,7 +117,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.WarcraftURL != "" {
 		clients.WarcraftConn, err = grpc.NewClient(
 			config.WarcraftURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to warcraft service: %w", err)
@@ -121,7 +128,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.WeatherURL != "" {
 		clients.WeatherConn, err = grpc.NewClient(
 			config.WeatherURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to weather service: %w", err)
@@ -132,7 +139,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.AuthURL != "" {
 		clients.AuthConn, err = grpc.NewClient(
 			config.AuthURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to auth service: %w", err)
@@ -143,7 +150,7 @@ func NewServiceClients(ctx context.Context, config ServiceURLs) (*ServiceClients
 	if config.UserURL != "" {
 		clients.UserConn, err = grpc.NewClient(
 			config.UserURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			dialOptions...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to user service: %w", err)
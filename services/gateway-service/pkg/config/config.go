@@ -18,8 +18,23 @@ type Config struct {
 	RateLimitBurst int
 	DevMode        bool
 
+	// Rate limit policies file; when set it replaces the auth endpoint limiter
+	RateLimitConfig string
+
+	// Proxies whose X-Forwarded-For headers identify the client; empty trusts none
+	TrustedProxies []string
+
+	// Redis shares token revocations and rate limits between gateway replicas
+	RedisEnabled  bool
+	RedisHost     string
+	RedisPort     string
+	RedisPassword string
+
 	// JWT configuration
-	JWTSecret string
+	// With JWTJWKSURL tokens are validated with auth-service's public keys; JWTSecret
+	// then only validates legacy HS256 tokens and defaults to empty
+	JWTSecret  string
+	JWTJWKSURL string
 
 	// Service endpoints
 	BlogServiceURL         string
@@ -56,8 +71,16 @@ func Load() (*Config, error) {
 		RateLimitBurst: config.GetEnvAsInt("RATE_LIMIT_BURST", 200),
 		DevMode:        config.GetEnvAsBool("DEV_MODE", false),
 
+		// Rate limiting
+		RateLimitConfig: config.GetEnv("RATE_LIMIT_CONFIG", ""),
+		TrustedProxies:  config.GetEnvAsSlice("TRUSTED_PROXIES", ""),
+		RedisEnabled:    config.GetEnvAsBool("REDIS_ENABLED", false),
+		RedisHost:       config.GetEnv("REDIS_HOST", "localhost"),
+		RedisPort:       config.GetEnv("REDIS_PORT", "6379"),
+		RedisPassword:   config.GetEnv("REDIS_PASSWORD", ""),
+
 		// JWT
-		JWTSecret: config.GetEnv("JWT_SECRET", "your-secret-key-please-change-in-production"),
+		JWTJWKSURL: config.GetEnv("JWT_JWKS_URL", ""),
 
 		// Service URLs
 		BlogServiceURL:         config.GetEnv("BLOG_SERVICE_URL", "localhost:9091"),
@@ -73,5 +96,11 @@ func Load() (*Config, error) {
 		UserServiceURL:         config.GetEnv("USER_SERVICE_URL", "localhost:11012"),
 	}
 
+	if cfg.JWTJWKSURL == "" {
+		cfg.JWTSecret = config.GetEnv("JWT_SECRET", "your-secret-key-please-change-in-production")
+	} else {
+		cfg.JWTSecret = config.GetEnv("JWT_SECRET", "")
+	}
+
 	return cfg, nil
 }
@@ -19,11 +19,14 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/toxictoast/toxictoastgo/shared/auth"
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	pb "toxictoast/services/link-service/api/proto"
 	"toxictoast/services/link-service/internal/command"
@@ -56,6 +59,18 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("link-service")); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("link-service"))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize logger
 	logger.Init()
 
@@ -63,7 +78,6 @@ func main() {
 	log.Printf("Environment: %s", cfg.Environment)
 
 	// Connect to database with retry
-	var err error
 	db, err = database.Connect(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -80,15 +94,24 @@ func main() {
 	}
 	log.Printf("Database schema is up to date (using link_ table prefix)")
 
+	serviceMetrics := metrics.New("link-service")
+
 	// Initialize Kafka producer
-	kafkaProducer, err := kafka.NewProducer(cfg.Kafka.Brokers)
+	// Handlers get a nil Publisher when Kafka is unavailable
+	// The async mode batches high-volume events, Close flushes them on shutdown
+	var kafkaProducer kafka.Publisher
+	producer, err := kafka.NewProducerWithOptions(cfg.Kafka.Brokers, kafka.ProducerOptions{
+		Source:  "link-service",
+		Async:   kafka.AsyncOptions{Enabled: true},
+		Metrics: serviceMetrics,
+	})
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Kafka producer: %v", err)
 		log.Printf("Service will continue without event publishing")
-		kafkaProducer = nil
 	} else {
 		log.Printf("Kafka producer connected successfully")
-		defer kafkaProducer.Close()
+		kafkaProducer = producer
+		defer producer.Close()
 	}
 
 	// Initialize Keycloak auth (optional)
@@ -128,6 +151,12 @@ func main() {
 	// Initialize Query Bus
 	queryBus := cqrs.NewQueryBus()
 
+	// Install the shared command/query middleware pipeline
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: "link-service",
+		Metrics:     serviceMetrics,
+	})
+
 	// Register Link Query Handlers (4 queries)
 	queryBus.RegisterHandler("get_link_by_id", query.NewGetLinkByIDHandler(linkRepo))
 	queryBus.RegisterHandler("get_link_by_short_code", query.NewGetLinkByShortCodeHandler(linkRepo))
@@ -157,7 +186,7 @@ func main() {
 	linkHandler := grpcHandler.NewLinkHandler(commandBus, queryBus, cfg)
 
 	// Setup gRPC server
-	grpcServer := setupGRPCServer(cfg, keycloakAuth, linkHandler)
+	grpcServer := setupGRPCServer(cfg, serviceMetrics, keycloakAuth, linkHandler)
 
 	// Start gRPC server
 	go func() {
@@ -172,7 +201,7 @@ func main() {
 	}()
 
 	// Setup HTTP server for health checks
-	httpServer := setupHTTPServer(cfg)
+	httpServer := setupHTTPServer(cfg, serviceMetrics)
 
 	// Start HTTP server
 	go func() {
@@ -208,32 +237,24 @@ func main() {
 	log.Println("Servers stopped")
 }
 
-func setupGRPCServer(cfg *config.Config, keycloakAuth *auth.KeycloakAuth, linkHandler *grpcHandler.LinkHandler) *grpc.Server {
-	// Setup interceptors
-	var opts []grpc.ServerOption
-
-	// Always add auth interceptor (extracts user from metadata)
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
-
-	// Add Keycloak interceptor if enabled
+func setupGRPCServer(cfg *config.Config, serviceMetrics *metrics.Metrics, keycloakAuth *auth.KeycloakAuth, linkHandler *grpcHandler.LinkHandler) *grpc.Server {
+	// Keycloak authentication runs after the user was extracted from the metadata
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
 	if keycloakAuth != nil {
 		unaryInterceptors = append(unaryInterceptors, keycloakAuth.UnaryInterceptor())
 		streamInterceptors = append(streamInterceptors, keycloakAuth.StreamInterceptor())
 	}
 
-	// Chain interceptors
-	opts = append(opts,
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	)
-
-	// Create gRPC server
-	server := grpc.NewServer(opts...)
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:        "link-service",
+		Metrics:            serviceMetrics,
+		DefaultTimeout:     cfg.Server.GRPCTimeout,
+		MaxTimeout:         cfg.Server.GRPCMaxTimeout,
+		UnaryInterceptors:  unaryInterceptors,
+		StreamInterceptors: streamInterceptors,
+	})
 
 	// Register services
 	pb.RegisterLinkServiceServer(server, linkHandler)
@@ -244,7 +265,7 @@ func setupGRPCServer(cfg *config.Config, keycloakAuth *auth.KeycloakAuth, linkHa
 	return server
 }
 
-func setupHTTPServer(cfg *config.Config) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceMetrics *metrics.Metrics) *http.Server {
 	router := mux.NewRouter()
 
 	// Health check endpoints
@@ -252,6 +273,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	router.HandleFunc("/health/ready", readinessHandler).Methods("GET")
 	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
 
+	// Prometheus metrics endpoint
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
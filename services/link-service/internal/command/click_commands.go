@@ -55,10 +55,10 @@ func (c *RecordClickCommand) Validate() error {
 type RecordClickHandler struct {
 	clickRepo     repository.ClickRepository
 	linkRepo      repository.LinkRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
-func NewRecordClickHandler(clickRepo repository.ClickRepository, linkRepo repository.LinkRepository, kafkaProducer *kafka.Producer) *RecordClickHandler {
+func NewRecordClickHandler(clickRepo repository.ClickRepository, linkRepo repository.LinkRepository, kafkaProducer kafka.Publisher) *RecordClickHandler {
 	return &RecordClickHandler{
 		clickRepo:     clickRepo,
 		linkRepo:      linkRepo,
@@ -117,7 +117,8 @@ func (h *RecordClickHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 			ClickedAt:  click.ClickedAt,
 		}
 		topic := "link.clicked"
-		if err := h.kafkaProducer.PublishLinkClicked(topic, event); err != nil {
+		// Clicks are published asynchronously, so redirects don't wait for the brokers
+		if err := kafka.Async(h.kafkaProducer).PublishLinkClicked(topic, event); err != nil {
 			log.Printf("Warning: Failed to publish link clicked event: %v", err)
 		}
 	}
@@ -131,11 +131,11 @@ func (c *DeactivateExpiredLinkCommand) Validate() error {
 // CreateLinkHandler handles link creation
 type CreateLinkHandler struct {
 	linkRepo      repository.LinkRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 	config        *config.Config
 }
 
-func NewCreateLinkHandler(linkRepo repository.LinkRepository, kafkaProducer *kafka.Producer, cfg *config.Config) *CreateLinkHandler {
+func NewCreateLinkHandler(linkRepo repository.LinkRepository, kafkaProducer kafka.Publisher, cfg *config.Config) *CreateLinkHandler {
 	return &CreateLinkHandler{
 		linkRepo:      linkRepo,
 		kafkaProducer: kafkaProducer,
@@ -242,10 +242,10 @@ func (h *CreateLinkHandler) generateUniqueShortCode(ctx context.Context) (string
 // UpdateLinkHandler handles link updates
 type UpdateLinkHandler struct {
 	linkRepo      repository.LinkRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
-func NewUpdateLinkHandler(linkRepo repository.LinkRepository, kafkaProducer *kafka.Producer) *UpdateLinkHandler {
+func NewUpdateLinkHandler(linkRepo repository.LinkRepository, kafkaProducer kafka.Publisher) *UpdateLinkHandler {
 	return &UpdateLinkHandler{
 		linkRepo:      linkRepo,
 		kafkaProducer: kafkaProducer,
@@ -358,10 +358,10 @@ func (h *UpdateLinkHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 // DeleteLinkHandler handles link deletion
 type DeleteLinkHandler struct {
 	linkRepo      repository.LinkRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
-func NewDeleteLinkHandler(linkRepo repository.LinkRepository, kafkaProducer *kafka.Producer) *DeleteLinkHandler {
+func NewDeleteLinkHandler(linkRepo repository.LinkRepository, kafkaProducer kafka.Publisher) *DeleteLinkHandler {
 	return &DeleteLinkHandler{
 		linkRepo:      linkRepo,
 		kafkaProducer: kafkaProducer,
@@ -440,10 +440,10 @@ func (h *IncrementClickHandler) Handle(ctx context.Context, cmd cqrs.Command) er
 // DeactivateExpiredLinkHandler deactivates an expired link
 type DeactivateExpiredLinkHandler struct {
 	linkRepo      repository.LinkRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
-func NewDeactivateExpiredLinkHandler(linkRepo repository.LinkRepository, kafkaProducer *kafka.Producer) *DeactivateExpiredLinkHandler {
+func NewDeactivateExpiredLinkHandler(linkRepo repository.LinkRepository, kafkaProducer kafka.Publisher) *DeactivateExpiredLinkHandler {
 	return &DeactivateExpiredLinkHandler{
 		linkRepo:      linkRepo,
 		kafkaProducer: kafkaProducer,
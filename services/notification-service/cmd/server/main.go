@@ -17,10 +17,13 @@ import (
 	"google.golang.org/grpc/reflection"
 	"gorm.io/gorm"
 
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	pb "toxictoast/services/notification-service/api/proto"
 	"toxictoast/services/notification-service/internal/command"
@@ -60,8 +63,21 @@ func main() {
 	cfg := config.Load()
 	logger.Info(fmt.Sprintf("Loaded configuration: gRPC port %s, HTTP port %s, %d Kafka topics", cfg.GRPCPort, cfg.Port, len(cfg.Kafka.Topics)))
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("notification-service")); err != nil {
+		logger.Error(fmt.Sprintf("Failed to initialize logging: %v", err))
+		os.Exit(1)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("notification-service"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to initialize tracing: %v", err))
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize database
-	var err error
 	db, err = database.Connect(cfg.Database)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to database: %v", err))
@@ -92,6 +108,13 @@ func main() {
 	// Initialize CQRS buses
 	commandBus := cqrs.NewCommandBus()
 	queryBus := cqrs.NewQueryBus()
+
+	// Install the shared command/query middleware pipeline
+	serviceMetrics := metrics.New("notification-service")
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: "notification-service",
+		Metrics:     serviceMetrics,
+	})
 	logger.Info("CQRS buses initialized")
 
 	// Register command handlers
@@ -117,7 +140,7 @@ func main() {
 	logger.Info("Query handlers registered")
 
 	// Initialize Kafka consumer
-	kafkaConsumer := consumer.NewKafkaConsumer(
+	kafkaConsumer, err := consumer.NewKafkaConsumer(
 		consumer.Config{
 			Brokers:     cfg.Kafka.Brokers,
 			Topics:      cfg.Kafka.Topics,
@@ -126,6 +149,10 @@ func main() {
 		},
 		commandBus,
 	)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create Kafka consumer: %v", err))
+		os.Exit(1)
+	}
 	logger.Info("Kafka consumer created")
 
 	// Start Kafka consumer
@@ -162,7 +189,7 @@ func main() {
 	logger.Info("gRPC handlers initialized")
 
 	// Setup gRPC server
-	grpcServer := setupGRPCServer(channelHandler, notificationHandler)
+	grpcServer := setupGRPCServer(cfg, serviceMetrics, channelHandler, notificationHandler)
 
 	// Start gRPC server
 	go func() {
@@ -180,7 +207,7 @@ func main() {
 	}()
 
 	// Setup HTTP server for health checks
-	httpServer := setupHTTPServer(cfg)
+	httpServer := setupHTTPServer(cfg, serviceMetrics)
 
 	// Start HTTP server
 	go func() {
@@ -229,22 +256,14 @@ func main() {
 	logger.Info("Notification Service stopped")
 }
 
-func setupGRPCServer(channelHandler *grpcHandler.ChannelHandler, notificationHandler *grpcHandler.NotificationHandler) *grpc.Server {
-	// Setup auth interceptors
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
-
-	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	}
-
-	// Create gRPC server with auth interceptors
-	server := grpc.NewServer(opts...)
+func setupGRPCServer(cfg *config.Config, serviceMetrics *metrics.Metrics, channelHandler *grpcHandler.ChannelHandler, notificationHandler *grpcHandler.NotificationHandler) *grpc.Server {
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:    "notification-service",
+		Metrics:        serviceMetrics,
+		DefaultTimeout: cfg.Server.GRPCTimeout,
+		MaxTimeout:     cfg.Server.GRPCMaxTimeout,
+	})
 
 	// Register services
 	pb.RegisterChannelManagementServiceServer(server, channelHandler)
@@ -256,7 +275,7 @@ func setupGRPCServer(channelHandler *grpcHandler.ChannelHandler, notificationHan
 	return server
 }
 
-func setupHTTPServer(cfg *config.Config) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceMetrics *metrics.Metrics) *http.Server {
 	router := mux.NewRouter()
 
 	// Health check endpoints
@@ -264,6 +283,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	router.HandleFunc("/health/ready", readinessHandler).Methods("GET")
 	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
 
+	// Prometheus metrics endpoint
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
@@ -3,11 +3,11 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"sync"
 
-	"github.com/segmentio/kafka-go"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
 
 	"toxictoast/services/notification-service/internal/command"
@@ -15,13 +15,8 @@ import (
 )
 
 type KafkaConsumer struct {
-	reader         *kafka.Reader
-	commandBus     *cqrs.CommandBus
-	wg             sync.WaitGroup
-	ctx            context.Context
-	cancel         context.CancelFunc
-	messageChannel chan kafka.Message
-	workerCount    int
+	consumer   *kafka.Consumer
+	commandBus *cqrs.CommandBus
 }
 
 type Config struct {
@@ -34,133 +29,50 @@ type Config struct {
 func NewKafkaConsumer(
 	config Config,
 	commandBus *cqrs.CommandBus,
-) *KafkaConsumer {
-	if config.WorkerCount == 0 {
-		config.WorkerCount = 5
+) (*KafkaConsumer, error) {
+	c := &KafkaConsumer{
+		commandBus: commandBus,
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:     config.Brokers,
-		GroupTopics: config.Topics,
-		GroupID:     config.GroupID,
-		MinBytes:    10e3,
-		MaxBytes:    10e6,
-		StartOffset: kafka.LastOffset,
-	})
-
-	return &KafkaConsumer{
-		reader:         reader,
-		commandBus:     commandBus,
-		ctx:            ctx,
-		cancel:         cancel,
-		messageChannel: make(chan kafka.Message, 100),
-		workerCount:    config.WorkerCount,
+	consumer, err := kafka.NewConsumer(kafka.ConsumerConfig{
+		Brokers: config.Brokers,
+		Topics:  config.Topics,
+		GroupID: config.GroupID,
+		Workers: config.WorkerCount,
+	}, kafka.HandlerFunc(c.processMessage))
+	if err != nil {
+		return nil, err
 	}
+	c.consumer = consumer
+
+	return c, nil
 }
 
 // Start starts the Kafka consumer
 func (c *KafkaConsumer) Start() error {
-	logger.Info(fmt.Sprintf("Starting Kafka consumer with %d workers", c.workerCount))
-
-	// Start message reader
-	c.wg.Add(1)
-	go c.readMessages()
-
-	// Start worker pool
-	for i := 0; i < c.workerCount; i++ {
-		c.wg.Add(1)
-		go c.worker(i)
-	}
-
+	c.consumer.Start()
 	logger.Info("Kafka consumer started successfully")
 	return nil
 }
 
-// Stop gracefully stops the Kafka consumer
+// Stop gracefully stops the Kafka consumer, draining in-flight messages
 func (c *KafkaConsumer) Stop() error {
-	logger.Info("Stopping Kafka consumer...")
-	c.cancel()
-	close(c.messageChannel)
-	c.wg.Wait()
-
-	if err := c.reader.Close(); err != nil {
-		logger.Error(fmt.Sprintf("Error closing Kafka reader: %v", err))
+	if err := c.consumer.Stop(); err != nil {
+		logger.Error(fmt.Sprintf("Error stopping Kafka consumer: %v", err))
 		return err
 	}
-
-	logger.Info("Kafka consumer stopped")
 	return nil
 }
 
-// readMessages reads messages from Kafka and sends them to the message channel
-func (c *KafkaConsumer) readMessages() {
-	defer c.wg.Done()
-
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		default:
-			msg, err := c.reader.FetchMessage(c.ctx)
-			if err != nil {
-				if err == context.Canceled {
-					return
-				}
-				logger.Error(fmt.Sprintf("Error fetching message from Kafka: %v", err))
-				continue
-			}
-
-			// Send to workers
-			select {
-			case c.messageChannel <- msg:
-			case <-c.ctx.Done():
-				return
-			}
-		}
-	}
-}
-
-// worker processes messages from the message channel
-func (c *KafkaConsumer) worker(id int) {
-	defer c.wg.Done()
-	logger.Info(fmt.Sprintf("Kafka worker %d started", id))
-
-	for {
-		select {
-		case <-c.ctx.Done():
-			logger.Info(fmt.Sprintf("Kafka worker %d stopped", id))
-			return
-		case msg, ok := <-c.messageChannel:
-			if !ok {
-				logger.Info(fmt.Sprintf("Kafka worker %d stopped (channel closed)", id))
-				return
-			}
-
-			if err := c.processMessage(msg); err != nil {
-				logger.Error(fmt.Sprintf("Worker %d failed to process message: %v", id, err))
-			} else {
-				// Commit message after successful processing
-				if err := c.reader.CommitMessages(c.ctx, msg); err != nil {
-					logger.Error(fmt.Sprintf("Failed to commit message: %v", err))
-				}
-			}
-		}
-	}
-}
-
 // processMessage processes a single Kafka message
-func (c *KafkaConsumer) processMessage(msg kafka.Message) error {
-	logger.Info(fmt.Sprintf("Processing message from topic %s (partition %d, offset %d)",
-		msg.Topic, msg.Partition, msg.Offset))
-
-	// Parse event from message
-	var event domain.Event
-	if err := json.Unmarshal(msg.Value, &event); err != nil {
-		logger.Error(fmt.Sprintf("Failed to unmarshal event: %v", err))
-		// Still commit the message to avoid reprocessing invalid JSON
-		return nil
+// Failed events are retried through the retry topics, invalid JSON goes to the dead-letter topic
+func (c *KafkaConsumer) processMessage(ctx context.Context, msg *kafka.Message) error {
+	logger.Info(fmt.Sprintf("Processing message from topic %s (partition %d, offset %d, attempt %d)",
+		msg.Topic, msg.Partition, msg.Offset, msg.Attempt))
+
+	event, err := decodeEvent(msg)
+	if err != nil {
+		return kafka.Permanent(err)
 	}
 
 	// Set event type from topic if not present
@@ -176,13 +88,39 @@ func (c *KafkaConsumer) processMessage(msg kafka.Message) error {
 	// Process event and send notifications
 	cmd := &command.ProcessEventCommand{
 		BaseCommand: cqrs.BaseCommand{},
-		Event:       &event,
+		Event:       event,
 	}
 
-	if err := c.commandBus.Dispatch(c.ctx, cmd); err != nil {
+	if err := c.commandBus.Dispatch(ctx, cmd); err != nil {
 		return fmt.Errorf("failed to process event: %w", err)
 	}
 
 	logger.Info(fmt.Sprintf("Successfully processed event %s (type: %s)", event.ID, event.Type))
 	return nil
 }
+
+// decodeEvent decodes a CloudEvent, or a legacy JSON event for producers not sending CloudEvents yet
+func decodeEvent(msg *kafka.Message) (*domain.Event, error) {
+	cloudEvent, err := msg.CloudEvent()
+	if errors.Is(err, kafka.ErrNotCloudEvent) {
+		var event domain.Event
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		return &event, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event := &domain.Event{
+		ID:        cloudEvent.ID,
+		Type:      cloudEvent.Type,
+		Source:    cloudEvent.Source,
+		Timestamp: cloudEvent.Time,
+	}
+	if err := cloudEvent.DecodeData(&event.Data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
 	"toxictoast/services/notification-service/internal/domain"
 
 	"github.com/toxictoast/toxictoastgo/shared/logger"
@@ -24,6 +26,8 @@ func NewClient() *Client {
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
+			// Client spans continue the trace of the consumed event and send traceparent to Discord
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 	}
 }
@@ -15,7 +15,10 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
+	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 	"toxictoast/services/sse-service/internal/broker"
 	"toxictoast/services/sse-service/internal/consumer"
 	grpcHandler "toxictoast/services/sse-service/internal/handler/grpc"
@@ -37,6 +40,18 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("sse-service")); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("sse-service"))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	log.Printf("Starting SSE Service v%s (built: %s)", Version, BuildTime)
 	log.Printf("Environment: %s", cfg.Environment)
 
@@ -45,13 +60,11 @@ func main() {
 	sseBroker.Start()
 
 	// Create Kafka consumer
-	ctx, cancel := context.WithCancel(context.Background())
-
 	kafkaConsumer, err := consumer.NewKafkaConsumer(&cfg.Kafka, sseBroker)
 	if err != nil {
 		log.Fatalf("Failed to create Kafka consumer: %v", err)
 	}
-	kafkaConsumer.Start(ctx)
+	kafkaConsumer.Start()
 
 	// Initialize background job schedulers
 	clientCleanupScheduler := scheduler.NewClientCleanupScheduler(
@@ -123,29 +136,25 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
-	// 1. Cancel main context to stop Kafka consumer goroutine
-	cancel()
-	log.Println("   Context cancelled")
-
-	// 2. Stop Kafka consumer (this closes the consumer group)
+	// 1. Stop Kafka consumer (this drains in-flight events and closes the consumer group)
 	if err := kafkaConsumer.Stop(); err != nil {
 		log.Printf("   Kafka consumer shutdown error: %v", err)
 	}
 
-	// 3. Shutdown HTTP server (this stops new connections)
+	// 2. Shutdown HTTP server (this stops new connections)
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
 		log.Printf("   HTTP server shutdown error: %v", err)
 	}
 
-	// 4. Shutdown gRPC server
+	// 3. Shutdown gRPC server
 	grpcServer.GracefulStop()
 	log.Println("   gRPC server stopped")
 
-	// 5. Stop background jobs
+	// 4. Stop background jobs
 	clientCleanupScheduler.Stop()
 	log.Println("   Background jobs stopped")
 
-	// 6. Stop SSE broker last (this closes all client connections)
+	// 5. Stop SSE broker last (this closes all client connections)
 	sseBroker.Stop()
 
 	log.Println("✅ All servers stopped gracefully")
@@ -161,6 +170,9 @@ func setupHTTPServer(cfg *config.Config, sseHandler *httpHandler.SSEHandler, rat
 	router.HandleFunc("/health", sseHandler.HandleHealth).Methods("GET")
 	router.HandleFunc("/stats", sseHandler.HandleStats).Methods("GET")
 
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
@@ -171,21 +183,12 @@ func setupHTTPServer(cfg *config.Config, sseHandler *httpHandler.SSEHandler, rat
 }
 
 func setupGRPCServer(cfg *config.Config, managementHandler *grpcHandler.ManagementHandler) *grpc.Server {
-	// Setup auth interceptors
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
-
-	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	}
-
-	// Create gRPC server with auth interceptors
-	server := grpc.NewServer(opts...)
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:    "sse-service",
+		DefaultTimeout: cfg.Server.GRPCTimeout,
+		MaxTimeout:     cfg.Server.GRPCMaxTimeout,
+	})
 
 	// Register services
 	log.Println("Registering gRPC services...")
@@ -2,10 +2,10 @@ package consumer
 
 import (
 	"context"
+	"errors"
 	"log"
-	"time"
 
-	"github.com/IBM/sarama"
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
 
 	"toxictoast/services/sse-service/internal/broker"
 	"toxictoast/services/sse-service/internal/domain"
@@ -14,127 +14,86 @@ import (
 
 // KafkaConsumer consumes events from Kafka and forwards them to the SSE broker
 type KafkaConsumer struct {
-	config       *config.KafkaConfig
-	consumerGroup sarama.ConsumerGroup
-	broker       *broker.Broker
-	stopChan     chan struct{}
+	config   *config.KafkaConfig
+	consumer *kafka.Consumer
+	broker   *broker.Broker
 }
 
 // NewKafkaConsumer creates a new Kafka consumer
 func NewKafkaConsumer(cfg *config.KafkaConfig, b *broker.Broker) (*KafkaConsumer, error) {
-	config := sarama.NewConfig()
-	config.Version = sarama.V2_6_0_0
-	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
-	config.Consumer.Offsets.Initial = sarama.OffsetNewest
-
-	if cfg.AutoOffsetReset == "earliest" {
-		config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	kc := &KafkaConsumer{
+		config: cfg,
+		broker: b,
 	}
 
-	consumerGroup, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, config)
+	consumer, err := kafka.NewConsumer(kafka.ConsumerConfig{
+		Brokers:       cfg.Brokers,
+		GroupID:       cfg.GroupID,
+		Topics:        cfg.Topics,
+		InitialOffset: cfg.AutoOffsetReset,
+	}, kafka.HandlerFunc(kc.handleMessage))
 	if err != nil {
 		return nil, err
 	}
+	kc.consumer = consumer
 
-	return &KafkaConsumer{
-		config:       cfg,
-		consumerGroup: consumerGroup,
-		broker:       b,
-		stopChan:     make(chan struct{}),
-	}, nil
+	return kc, nil
 }
 
 // Start starts consuming Kafka messages
-func (kc *KafkaConsumer) Start(ctx context.Context) {
+func (kc *KafkaConsumer) Start() {
 	log.Printf("🎧 Kafka consumer started")
 	log.Printf("   Brokers: %v", kc.config.Brokers)
 	log.Printf("   Topics: %v", kc.config.Topics)
 	log.Printf("   Group ID: %s", kc.config.GroupID)
 
-	handler := &consumerGroupHandler{
-		broker: kc.broker,
-	}
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("🛑 Kafka consumer context cancelled")
-				return
-			case <-kc.stopChan:
-				log.Println("🛑 Kafka consumer stopped")
-				return
-			default:
-				// Consume messages
-				if err := kc.consumerGroup.Consume(ctx, kc.config.Topics, handler); err != nil {
-					log.Printf("❌ Error from consumer: %v", err)
-					time.Sleep(5 * time.Second) // Wait before retry
-				}
-			}
-		}
-	}()
+	kc.consumer.Start()
 }
 
-// Stop stops the Kafka consumer
+// Stop stops the Kafka consumer once in-flight events are published
 func (kc *KafkaConsumer) Stop() error {
 	log.Println("🛑 Stopping Kafka consumer...")
 
-	// Close consumer group first (this will trigger context cancellation)
-	if err := kc.consumerGroup.Close(); err != nil {
+	if err := kc.consumer.Stop(); err != nil {
 		log.Printf("⚠️  Error closing consumer group: %v", err)
-	}
-
-	// Signal stop and close channel
-	select {
-	case <-kc.stopChan:
-		// Already closed
-	default:
-		close(kc.stopChan)
+		return err
 	}
 
 	log.Println("✅ Kafka consumer stopped")
 	return nil
 }
 
-// consumerGroupHandler implements sarama.ConsumerGroupHandler
-type consumerGroupHandler struct {
-	broker *broker.Broker
-}
+// handleMessage parses an event and publishes it to the SSE broker
+// Events that can't be parsed are moved to the dead-letter topic
+func (kc *KafkaConsumer) handleMessage(ctx context.Context, msg *kafka.Message) error {
+	event, err := parseEvent(msg)
+	if err != nil {
+		log.Printf("⚠️  Failed to parse event from topic %s: %v", msg.Topic, err)
+		return kafka.Permanent(err)
+	}
 
-func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
-	log.Println("✅ Kafka consumer group session setup")
+	kc.broker.PublishEvent(event)
 	return nil
 }
 
-func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
-	log.Println("🧹 Kafka consumer group session cleanup")
-	return nil
-}
+// parseEvent parses a CloudEvent, or infers the event attributes for producers not sending CloudEvents yet
+func parseEvent(msg *kafka.Message) (*domain.Event, error) {
+	cloudEvent, err := msg.CloudEvent()
+	if errors.Is(err, kafka.ErrNotCloudEvent) {
+		return domain.ParseEvent(msg.Value, msg.Topic)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	for {
-		select {
-		case message := <-claim.Messages():
-			if message == nil {
-				return nil
-			}
-
-			// Parse event from Kafka message
-			event, err := domain.ParseEvent(message.Value, message.Topic)
-			if err != nil {
-				log.Printf("⚠️  Failed to parse event from topic %s: %v", message.Topic, err)
-				session.MarkMessage(message, "")
-				continue
-			}
-
-			// Publish event to SSE broker
-			h.broker.PublishEvent(event)
-
-			// Mark message as processed
-			session.MarkMessage(message, "")
-
-		case <-session.Context().Done():
-			return nil
-		}
+	event := &domain.Event{
+		ID:        cloudEvent.ID,
+		Type:      cloudEvent.Type,
+		Source:    cloudEvent.Source,
+		Timestamp: cloudEvent.Time,
+	}
+	if err := cloudEvent.DecodeData(&event.Data); err != nil {
+		return nil, err
 	}
+	return event, nil
 }
@@ -37,6 +37,10 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	// GRPCTimeout bounds gRPC calls without a client deadline; GRPCMaxTimeout caps client deadlines
+	GRPCTimeout    time.Duration
+	GRPCMaxTimeout time.Duration
 }
 
 // SSEConfig holds SSE-specific configuration
@@ -131,6 +135,9 @@ func Load() *Config {
 			ReadTimeout:  time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 15)) * time.Second,
 			WriteTimeout: time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 15)) * time.Second,
 			IdleTimeout:  time.Duration(getEnvAsInt("SERVER_IDLE_TIMEOUT", 60)) * time.Second,
+
+			GRPCTimeout:    time.Duration(getEnvAsInt("GRPC_REQUEST_TIMEOUT", 30)) * time.Second,
+			GRPCMaxTimeout: time.Duration(getEnvAsInt("GRPC_MAX_REQUEST_TIMEOUT", 300)) * time.Second,
 		},
 		SSE: SSEConfig{
 			MaxClients:                   getEnvAsInt("SSE_MAX_CLIENTS", 1000),
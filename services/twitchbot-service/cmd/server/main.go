@@ -17,11 +17,14 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/toxictoast/toxictoastgo/shared/auth"
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	"toxictoast/services/twitchbot-service/pkg/bot"
 	"toxictoast/services/twitchbot-service/pkg/config"
@@ -61,6 +64,18 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("twitchbot-service")); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("twitchbot-service"))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize logger
 	logger.Init()
 
@@ -68,7 +83,6 @@ func main() {
 	log.Printf("Environment: %s", cfg.Environment)
 
 	// Connect to database with retry
-	var err error
 	db, err = database.Connect(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -95,15 +109,24 @@ func main() {
 		log.Printf("Offline messages may not be logged correctly")
 	}
 
+	serviceMetrics := metrics.New("twitchbot-service")
+
 	// Initialize Kafka producer
-	kafkaProducer, err := kafka.NewProducer(cfg.Kafka.Brokers)
+	// Handlers get a nil Publisher when Kafka is unavailable
+	// The async mode batches high-volume events, Close flushes them on shutdown
+	var kafkaProducer kafka.Publisher
+	producer, err := kafka.NewProducerWithOptions(cfg.Kafka.Brokers, kafka.ProducerOptions{
+		Source:  "twitchbot-service",
+		Async:   kafka.AsyncOptions{Enabled: true},
+		Metrics: serviceMetrics,
+	})
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Kafka producer: %v", err)
 		log.Printf("Service will continue without event publishing")
-		kafkaProducer = nil
 	} else {
 		log.Printf("Kafka producer connected successfully")
-		defer kafkaProducer.Close()
+		kafkaProducer = producer
+		defer producer.Close()
 	}
 
 	// Initialize Keycloak auth
@@ -136,6 +159,12 @@ func main() {
 	commandBus := cqrs.NewCommandBus()
 	queryBus := cqrs.NewQueryBus()
 
+	// Install the shared command/query middleware pipeline
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: "twitchbot-service",
+		Metrics:     serviceMetrics,
+	})
+
 	// Register stream handlers
 	commandBus.RegisterHandler("create_stream", command.NewCreateStreamHandler(streamRepo))
 	commandBus.RegisterHandler("update_stream", command.NewUpdateStreamHandler(streamRepo))
@@ -249,6 +278,7 @@ func main() {
 	// Setup gRPC server
 	grpcServer := setupGRPCServer(
 		cfg,
+		serviceMetrics,
 		keycloakAuth,
 		streamHandler,
 		messageHandler,
@@ -272,7 +302,7 @@ func main() {
 	}()
 
 	// Setup HTTP server for health checks
-	httpServer := setupHTTPServer(cfg)
+	httpServer := setupHTTPServer(cfg, serviceMetrics)
 
 	// Start HTTP server
 	go func() {
@@ -318,6 +348,7 @@ func main() {
 
 func setupGRPCServer(
 	cfg *config.Config,
+	serviceMetrics *metrics.Metrics,
 	keycloakAuth *auth.KeycloakAuth,
 	streamHandler *grpcHandler.StreamHandler,
 	messageHandler *grpcHandler.MessageHandler,
@@ -327,28 +358,23 @@ func setupGRPCServer(
 	botHandler *grpcHandler.BotHandler,
 	channelViewerHandler *grpcHandler.ChannelViewerHandler,
 ) *grpc.Server {
-	// Setup interceptors - always add auth interceptor to extract user from metadata
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
-
-	// Add Keycloak interceptor if enabled
+	// Keycloak authentication runs after the user was extracted from the metadata
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
 	if keycloakAuth != nil {
 		unaryInterceptors = append(unaryInterceptors, keycloakAuth.UnaryInterceptor())
 		streamInterceptors = append(streamInterceptors, keycloakAuth.StreamInterceptor())
 	}
 
-	// Create server options with chained interceptors
-	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	}
-
-	// Create gRPC server with interceptors
-	server := grpc.NewServer(opts...)
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:        "twitchbot-service",
+		Metrics:            serviceMetrics,
+		DefaultTimeout:     cfg.Server.GRPCTimeout,
+		MaxTimeout:         cfg.Server.GRPCMaxTimeout,
+		UnaryInterceptors:  unaryInterceptors,
+		StreamInterceptors: streamInterceptors,
+	})
 
 	// Register all services
 	log.Println("Registering gRPC services...")
@@ -367,7 +393,7 @@ func setupGRPCServer(
 	return server
 }
 
-func setupHTTPServer(cfg *config.Config) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceMetrics *metrics.Metrics) *http.Server {
 	router := mux.NewRouter()
 
 	// Health check endpoints
@@ -375,6 +401,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	router.HandleFunc("/health/ready", readinessHandler).Methods("GET")
 	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
 
+	// Prometheus metrics endpoint
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/toxictoast/toxictoastgo/shared/logger"
+
 	"toxictoast/services/twitchbot-service/internal/usecase"
 	"toxictoast/services/twitchbot-service/pkg/config"
 	"toxictoast/services/twitchbot-service/pkg/events"
@@ -32,8 +35,12 @@ type Manager struct {
 	broadcasterCache map[string]string // channel -> broadcasterID cache
 	cacheMu          sync.RWMutex      // Mutex for broadcasterCache
 	stopChan         chan struct{}
+	chatLog          *slog.Logger // Sampled logger of the chat ingestion hot path
 }
 
+// chatLogSampling limits chat ingestion logs during busy streams
+var chatLogSampling = logger.Sampling{First: 20, Thereafter: 100, Tick: time.Second}
+
 // NewManager creates a new bot manager
 func NewManager(
 	cfg *config.Config,
@@ -57,6 +64,7 @@ func NewManager(
 		activeStreamIDs:  make(map[string]string),
 		broadcasterCache: make(map[string]string),
 		stopChan:         make(chan struct{}),
+		chatLog:          logger.Sampled(logger.Component("twitchbot.chat"), chatLogSampling),
 	}
 }
 
@@ -296,6 +304,7 @@ func (m *Manager) handleChatMessage(channel, username, displayName, userID, mess
 
 	// Remove # prefix from channel if present
 	channel = strings.TrimPrefix(channel, "#")
+	m.chatLog.Debug("chat message received", "channel", channel, "user", username)
 
 	// Get or find active stream for this channel
 	m.streamIDsMu.RLock()
@@ -353,7 +362,7 @@ func (m *Manager) handleChatMessage(channel, username, displayName, userID, mess
 			isVIP,
 		)
 		if err != nil {
-			log.Printf("⚠️  Failed to track viewer %s in channel %s: %v", username, channel, err)
+			m.chatLog.Warn("failed to track viewer", "channel", channel, "user", username, "error", err)
 		}
 	}
 
@@ -372,14 +381,14 @@ func (m *Manager) handleChatMessage(channel, username, displayName, userID, mess
 	)
 
 	if err != nil {
-		log.Printf("Error creating message: %v", err)
+		m.chatLog.Error("failed to create message", "channel", channel, "error", err)
 		return
 	}
 
 	// Publish event
 	if m.eventPublisher != nil {
 		if err := m.eventPublisher.PublishMessageReceived(msg.ID, msg.UserID, msg.Username, msg.Message); err != nil {
-			log.Printf("Failed to publish message received event: %v", err)
+			m.chatLog.Error("failed to publish message received event", "channel", channel, "error", err)
 		}
 	}
 }
@@ -9,13 +9,13 @@ import (
 
 // EventPublisher handles publishing events to Kafka using shared event types
 type EventPublisher struct {
-	producer    *kafka.Producer
+	producer    kafka.Publisher
 	channelID   string
 	channelName string
 }
 
 // NewEventPublisher creates a new event publisher
-func NewEventPublisher(producer *kafka.Producer) *EventPublisher {
+func NewEventPublisher(producer kafka.Publisher) *EventPublisher {
 	return &EventPublisher{
 		producer: producer,
 	}
@@ -92,6 +92,7 @@ func (p *EventPublisher) PublishStreamUpdated(streamID, title, gameName string,
 }
 
 // PublishMessageReceived publishes a message received event
+// Published asynchronously, as chat traffic must not wait for the brokers
 func (p *EventPublisher) PublishMessageReceived(messageID, userID, username, message string) error {
 	if p.producer == nil {
 		return nil
@@ -107,7 +108,7 @@ func (p *EventPublisher) PublishMessageReceived(messageID, userID, username, mes
 		ReceivedAt:  time.Now(),
 	}
 
-	if err := p.producer.PublishTwitchbotMessageReceived("twitchbot.message.received", event); err != nil {
+	if err := kafka.Async(p.producer).PublishTwitchbotMessageReceived("twitchbot.message.received", event); err != nil {
 		return fmt.Errorf("failed to publish message received event: %w", err)
 	}
 	return nil
@@ -156,6 +157,7 @@ func (p *EventPublisher) PublishMessageTimeout(userID, username, reason string,
 }
 
 // PublishViewerJoined publishes a viewer joined event
+// Published asynchronously, as chat traffic must not wait for the brokers
 func (p *EventPublisher) PublishViewerJoined(userID, username string) error {
 	if p.producer == nil {
 		return nil
@@ -169,13 +171,14 @@ func (p *EventPublisher) PublishViewerJoined(userID, username string) error {
 		JoinedAt:    time.Now(),
 	}
 
-	if err := p.producer.PublishTwitchbotViewerJoined("twitchbot.viewer.joined", event); err != nil {
+	if err := kafka.Async(p.producer).PublishTwitchbotViewerJoined("twitchbot.viewer.joined", event); err != nil {
 		return fmt.Errorf("failed to publish viewer joined event: %w", err)
 	}
 	return nil
 }
 
 // PublishViewerLeft publishes a viewer left event
+// Published asynchronously, as chat traffic must not wait for the brokers
 func (p *EventPublisher) PublishViewerLeft(userID, username string) error {
 	if p.producer == nil {
 		return nil
@@ -189,7 +192,7 @@ func (p *EventPublisher) PublishViewerLeft(userID, username string) error {
 		LeftAt:      time.Now(),
 	}
 
-	if err := p.producer.PublishTwitchbotViewerLeft("twitchbot.viewer.left", event); err != nil {
+	if err := kafka.Async(p.producer).PublishTwitchbotViewerLeft("twitchbot.viewer.left", event); err != nil {
 		return fmt.Errorf("failed to publish viewer left event: %w", err)
 	}
 	return nil
@@ -1089,6 +1089,584 @@ func (x *DeleteResponse) GetMessage() string {
 	return ""
 }
 
+type UserEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType     string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	SchemaVersion int32                  `protobuf:"varint,4,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Data          string                 `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`                                                                                   // JSON payload, secrets redacted
+	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // e.g. correlation_id, actor, command
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserEvent) Reset() {
+	*x = UserEvent{}
+	mi := &file_api_proto_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserEvent) ProtoMessage() {}
+
+func (x *UserEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserEvent.ProtoReflect.Descriptor instead.
+func (*UserEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UserEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *UserEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *UserEvent) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UserEvent) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *UserEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *UserEvent) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+func (x *UserEvent) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type GetUserHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page, empty for the first page
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserHistoryRequest) Reset() {
+	*x = GetUserHistoryRequest{}
+	mi := &file_api_proto_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserHistoryRequest) ProtoMessage() {}
+
+func (x *GetUserHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetUserHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetUserHistoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetUserHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GetUserHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*UserEvent           `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // empty when there are no more events
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserHistoryResponse) Reset() {
+	*x = GetUserHistoryResponse{}
+	mi := &file_api_proto_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserHistoryResponse) ProtoMessage() {}
+
+func (x *GetUserHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetUserHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetUserHistoryResponse) GetEvents() []*UserEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *GetUserHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetUserAsOfRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Types that are valid to be assigned to Point:
+	//
+	//	*GetUserAsOfRequest_Version
+	//	*GetUserAsOfRequest_AsOf
+	Point         isGetUserAsOfRequest_Point `protobuf_oneof:"point"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserAsOfRequest) Reset() {
+	*x = GetUserAsOfRequest{}
+	mi := &file_api_proto_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserAsOfRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserAsOfRequest) ProtoMessage() {}
+
+func (x *GetUserAsOfRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserAsOfRequest.ProtoReflect.Descriptor instead.
+func (*GetUserAsOfRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetUserAsOfRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserAsOfRequest) GetPoint() isGetUserAsOfRequest_Point {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *GetUserAsOfRequest) GetVersion() int64 {
+	if x != nil {
+		if x, ok := x.Point.(*GetUserAsOfRequest_Version); ok {
+			return x.Version
+		}
+	}
+	return 0
+}
+
+func (x *GetUserAsOfRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.Point.(*GetUserAsOfRequest_AsOf); ok {
+			return x.AsOf
+		}
+	}
+	return nil
+}
+
+type isGetUserAsOfRequest_Point interface {
+	isGetUserAsOfRequest_Point()
+}
+
+type GetUserAsOfRequest_Version struct {
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3,oneof"`
+}
+
+type GetUserAsOfRequest_AsOf struct {
+	AsOf *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=as_of,json=asOf,proto3,oneof"`
+}
+
+func (*GetUserAsOfRequest_Version) isGetUserAsOfRequest_Point() {}
+
+func (*GetUserAsOfRequest_AsOf) isGetUserAsOfRequest_Point() {}
+
+type GetUserAsOfResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // version of the last event applied
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserAsOfResponse) Reset() {
+	*x = GetUserAsOfResponse{}
+	mi := &file_api_proto_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserAsOfResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserAsOfResponse) ProtoMessage() {}
+
+func (x *GetUserAsOfResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserAsOfResponse.ProtoReflect.Descriptor instead.
+func (*GetUserAsOfResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetUserAsOfResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *GetUserAsOfResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type EraseUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserRequest) Reset() {
+	*x = EraseUserRequest{}
+	mi := &file_api_proto_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserRequest) ProtoMessage() {}
+
+func (x *EraseUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserRequest.ProtoReflect.Descriptor instead.
+func (*EraseUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *EraseUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RebuildReadModelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildReadModelRequest) Reset() {
+	*x = RebuildReadModelRequest{}
+	mi := &file_api_proto_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildReadModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildReadModelRequest) ProtoMessage() {}
+
+func (x *RebuildReadModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildReadModelRequest.ProtoReflect.Descriptor instead.
+func (*RebuildReadModelRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{23}
+}
+
+type GetRebuildStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRebuildStatusRequest) Reset() {
+	*x = GetRebuildStatusRequest{}
+	mi := &file_api_proto_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRebuildStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRebuildStatusRequest) ProtoMessage() {}
+
+func (x *GetRebuildStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRebuildStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetRebuildStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{24}
+}
+
+type RebuildStatusResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Projector       string                 `protobuf:"bytes,1,opt,name=projector,proto3" json:"projector,omitempty"`
+	Version         int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // running, completed or failed
+	Position        int64                  `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	TargetPosition  int64                  `protobuf:"varint,5,opt,name=target_position,json=targetPosition,proto3" json:"target_position,omitempty"`
+	EventsProjected int64                  `protobuf:"varint,6,opt,name=events_projected,json=eventsProjected,proto3" json:"events_projected,omitempty"`
+	LastError       string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	StartedAt       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	FinishedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RebuildStatusResponse) Reset() {
+	*x = RebuildStatusResponse{}
+	mi := &file_api_proto_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildStatusResponse) ProtoMessage() {}
+
+func (x *RebuildStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildStatusResponse.ProtoReflect.Descriptor instead.
+func (*RebuildStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RebuildStatusResponse) GetProjector() string {
+	if x != nil {
+		return x.Projector
+	}
+	return ""
+}
+
+func (x *RebuildStatusResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RebuildStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RebuildStatusResponse) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *RebuildStatusResponse) GetTargetPosition() int64 {
+	if x != nil {
+		return x.TargetPosition
+	}
+	return 0
+}
+
+func (x *RebuildStatusResponse) GetEventsProjected() int64 {
+	if x != nil {
+		return x.EventsProjected
+	}
+	return 0
+}
+
+func (x *RebuildStatusResponse) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *RebuildStatusResponse) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *RebuildStatusResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *RebuildStatusResponse) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
 var File_api_proto_user_proto protoreflect.FileDescriptor
 
 const file_api_proto_user_proto_rawDesc = "" +
@@ -1187,7 +1765,56 @@ const file_api_proto_user_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"D\n" +
 	"\x0eDeleteResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage*\x8f\x01\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xcc\x02\n" +
+	"\tUserEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12%\n" +
+	"\x0eschema_version\x18\x04 \x01(\x05R\rschemaVersion\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04data\x18\x06 \x01(\tR\x04data\x129\n" +
+	"\bmetadata\x18\a \x03(\v2\x1d.user.UserEvent.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"e\n" +
+	"\x15GetUserHistoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"b\n" +
+	"\x16GetUserHistoryResponse\x12'\n" +
+	"\x06events\x18\x01 \x03(\v2\x0f.user.UserEventR\x06events\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x85\x01\n" +
+	"\x12GetUserAsOfRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\aversion\x18\x02 \x01(\x03H\x00R\aversion\x121\n" +
+	"\x05as_of\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x04asOfB\a\n" +
+	"\x05point\"O\n" +
+	"\x13GetUserAsOfResponse\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".user.UserR\x04user\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"+\n" +
+	"\x10EraseUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x19\n" +
+	"\x17RebuildReadModelRequest\"\x19\n" +
+	"\x17GetRebuildStatusRequest\"\xa9\x03\n" +
+	"\x15RebuildStatusResponse\x12\x1c\n" +
+	"\tprojector\x18\x01 \x01(\tR\tprojector\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\x03R\bposition\x12'\n" +
+	"\x0ftarget_position\x18\x05 \x01(\x03R\x0etargetPosition\x12)\n" +
+	"\x10events_projected\x18\x06 \x01(\x03R\x0feventsProjected\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"started_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\vfinished_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt*\x8f\x01\n" +
 	"\n" +
 	"UserStatus\x12\x1b\n" +
 	"\x17USER_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
@@ -1209,7 +1836,13 @@ const file_api_proto_user_proto_rawDesc = "" +
 	"\x0eUpdatePassword\x12\x1b.user.UpdatePasswordRequest\x1a\x1c.user.UpdatePasswordResponse\x12K\n" +
 	"\x0eVerifyPassword\x12\x1b.user.VerifyPasswordRequest\x1a\x1c.user.VerifyPasswordResponse\x12=\n" +
 	"\fActivateUser\x12\x19.user.ActivateUserRequest\x1a\x12.user.UserResponse\x12A\n" +
-	"\x0eDeactivateUser\x12\x1b.user.DeactivateUserRequest\x1a\x12.user.UserResponseB,Z*toxictoast/services/user-service/api/protob\x06proto3"
+	"\x0eDeactivateUser\x12\x1b.user.DeactivateUserRequest\x1a\x12.user.UserResponse2\xfe\x02\n" +
+	"\x10UserAdminService\x12K\n" +
+	"\x0eGetUserHistory\x12\x1b.user.GetUserHistoryRequest\x1a\x1c.user.GetUserHistoryResponse\x12B\n" +
+	"\vGetUserAsOf\x12\x18.user.GetUserAsOfRequest\x1a\x19.user.GetUserAsOfResponse\x129\n" +
+	"\tEraseUser\x12\x16.user.EraseUserRequest\x1a\x14.user.DeleteResponse\x12N\n" +
+	"\x10RebuildReadModel\x12\x1d.user.RebuildReadModelRequest\x1a\x1b.user.RebuildStatusResponse\x12N\n" +
+	"\x10GetRebuildStatus\x12\x1d.user.GetRebuildStatusRequest\x1a\x1b.user.RebuildStatusResponseB,Z*toxictoast/services/user-service/api/protob\x06proto3"
 
 var (
 	file_api_proto_user_proto_rawDescOnce sync.Once
@@ -1224,7 +1857,7 @@ func file_api_proto_user_proto_rawDescGZIP() []byte {
 }
 
 var file_api_proto_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_api_proto_user_proto_goTypes = []any{
 	(UserStatus)(0),                  // 0: user.UserStatus
 	(*User)(nil),                     // 1: user.User
@@ -1244,43 +1877,71 @@ var file_api_proto_user_proto_goTypes = []any{
 	(*ActivateUserRequest)(nil),      // 15: user.ActivateUserRequest
 	(*DeactivateUserRequest)(nil),    // 16: user.DeactivateUserRequest
 	(*DeleteResponse)(nil),           // 17: user.DeleteResponse
-	(*timestamppb.Timestamp)(nil),    // 18: google.protobuf.Timestamp
+	(*UserEvent)(nil),                // 18: user.UserEvent
+	(*GetUserHistoryRequest)(nil),    // 19: user.GetUserHistoryRequest
+	(*GetUserHistoryResponse)(nil),   // 20: user.GetUserHistoryResponse
+	(*GetUserAsOfRequest)(nil),       // 21: user.GetUserAsOfRequest
+	(*GetUserAsOfResponse)(nil),      // 22: user.GetUserAsOfResponse
+	(*EraseUserRequest)(nil),         // 23: user.EraseUserRequest
+	(*RebuildReadModelRequest)(nil),  // 24: user.RebuildReadModelRequest
+	(*GetRebuildStatusRequest)(nil),  // 25: user.GetRebuildStatusRequest
+	(*RebuildStatusResponse)(nil),    // 26: user.RebuildStatusResponse
+	nil,                              // 27: user.UserEvent.MetadataEntry
+	(*timestamppb.Timestamp)(nil),    // 28: google.protobuf.Timestamp
 }
 var file_api_proto_user_proto_depIdxs = []int32{
 	0,  // 0: user.User.status:type_name -> user.UserStatus
-	18, // 1: user.User.created_at:type_name -> google.protobuf.Timestamp
-	18, // 2: user.User.updated_at:type_name -> google.protobuf.Timestamp
-	18, // 3: user.User.last_login:type_name -> google.protobuf.Timestamp
+	28, // 1: user.User.created_at:type_name -> google.protobuf.Timestamp
+	28, // 2: user.User.updated_at:type_name -> google.protobuf.Timestamp
+	28, // 3: user.User.last_login:type_name -> google.protobuf.Timestamp
 	0,  // 4: user.ListUsersRequest.status:type_name -> user.UserStatus
 	1,  // 5: user.UserResponse.user:type_name -> user.User
 	1,  // 6: user.ListUsersResponse.users:type_name -> user.User
-	2,  // 7: user.UserService.CreateUser:input_type -> user.CreateUserRequest
-	4,  // 8: user.UserService.GetUser:input_type -> user.GetUserRequest
-	5,  // 9: user.UserService.GetUserByEmail:input_type -> user.GetUserByEmailRequest
-	6,  // 10: user.UserService.GetUserByUsername:input_type -> user.GetUserByUsernameRequest
-	3,  // 11: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
-	7,  // 12: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
-	8,  // 13: user.UserService.ListUsers:input_type -> user.ListUsersRequest
-	11, // 14: user.UserService.UpdatePassword:input_type -> user.UpdatePasswordRequest
-	13, // 15: user.UserService.VerifyPassword:input_type -> user.VerifyPasswordRequest
-	15, // 16: user.UserService.ActivateUser:input_type -> user.ActivateUserRequest
-	16, // 17: user.UserService.DeactivateUser:input_type -> user.DeactivateUserRequest
-	9,  // 18: user.UserService.CreateUser:output_type -> user.UserResponse
-	9,  // 19: user.UserService.GetUser:output_type -> user.UserResponse
-	9,  // 20: user.UserService.GetUserByEmail:output_type -> user.UserResponse
-	9,  // 21: user.UserService.GetUserByUsername:output_type -> user.UserResponse
-	9,  // 22: user.UserService.UpdateUser:output_type -> user.UserResponse
-	17, // 23: user.UserService.DeleteUser:output_type -> user.DeleteResponse
-	10, // 24: user.UserService.ListUsers:output_type -> user.ListUsersResponse
-	12, // 25: user.UserService.UpdatePassword:output_type -> user.UpdatePasswordResponse
-	14, // 26: user.UserService.VerifyPassword:output_type -> user.VerifyPasswordResponse
-	9,  // 27: user.UserService.ActivateUser:output_type -> user.UserResponse
-	9,  // 28: user.UserService.DeactivateUser:output_type -> user.UserResponse
-	18, // [18:29] is the sub-list for method output_type
-	7,  // [7:18] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	28, // 7: user.UserEvent.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 8: user.UserEvent.metadata:type_name -> user.UserEvent.MetadataEntry
+	18, // 9: user.GetUserHistoryResponse.events:type_name -> user.UserEvent
+	28, // 10: user.GetUserAsOfRequest.as_of:type_name -> google.protobuf.Timestamp
+	1,  // 11: user.GetUserAsOfResponse.user:type_name -> user.User
+	28, // 12: user.RebuildStatusResponse.started_at:type_name -> google.protobuf.Timestamp
+	28, // 13: user.RebuildStatusResponse.updated_at:type_name -> google.protobuf.Timestamp
+	28, // 14: user.RebuildStatusResponse.finished_at:type_name -> google.protobuf.Timestamp
+	2,  // 15: user.UserService.CreateUser:input_type -> user.CreateUserRequest
+	4,  // 16: user.UserService.GetUser:input_type -> user.GetUserRequest
+	5,  // 17: user.UserService.GetUserByEmail:input_type -> user.GetUserByEmailRequest
+	6,  // 18: user.UserService.GetUserByUsername:input_type -> user.GetUserByUsernameRequest
+	3,  // 19: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
+	7,  // 20: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
+	8,  // 21: user.UserService.ListUsers:input_type -> user.ListUsersRequest
+	11, // 22: user.UserService.UpdatePassword:input_type -> user.UpdatePasswordRequest
+	13, // 23: user.UserService.VerifyPassword:input_type -> user.VerifyPasswordRequest
+	15, // 24: user.UserService.ActivateUser:input_type -> user.ActivateUserRequest
+	16, // 25: user.UserService.DeactivateUser:input_type -> user.DeactivateUserRequest
+	19, // 26: user.UserAdminService.GetUserHistory:input_type -> user.GetUserHistoryRequest
+	21, // 27: user.UserAdminService.GetUserAsOf:input_type -> user.GetUserAsOfRequest
+	23, // 28: user.UserAdminService.EraseUser:input_type -> user.EraseUserRequest
+	24, // 29: user.UserAdminService.RebuildReadModel:input_type -> user.RebuildReadModelRequest
+	25, // 30: user.UserAdminService.GetRebuildStatus:input_type -> user.GetRebuildStatusRequest
+	9,  // 31: user.UserService.CreateUser:output_type -> user.UserResponse
+	9,  // 32: user.UserService.GetUser:output_type -> user.UserResponse
+	9,  // 33: user.UserService.GetUserByEmail:output_type -> user.UserResponse
+	9,  // 34: user.UserService.GetUserByUsername:output_type -> user.UserResponse
+	9,  // 35: user.UserService.UpdateUser:output_type -> user.UserResponse
+	17, // 36: user.UserService.DeleteUser:output_type -> user.DeleteResponse
+	10, // 37: user.UserService.ListUsers:output_type -> user.ListUsersResponse
+	12, // 38: user.UserService.UpdatePassword:output_type -> user.UpdatePasswordResponse
+	14, // 39: user.UserService.VerifyPassword:output_type -> user.VerifyPasswordResponse
+	9,  // 40: user.UserService.ActivateUser:output_type -> user.UserResponse
+	9,  // 41: user.UserService.DeactivateUser:output_type -> user.UserResponse
+	20, // 42: user.UserAdminService.GetUserHistory:output_type -> user.GetUserHistoryResponse
+	22, // 43: user.UserAdminService.GetUserAsOf:output_type -> user.GetUserAsOfResponse
+	17, // 44: user.UserAdminService.EraseUser:output_type -> user.DeleteResponse
+	26, // 45: user.UserAdminService.RebuildReadModel:output_type -> user.RebuildStatusResponse
+	26, // 46: user.UserAdminService.GetRebuildStatus:output_type -> user.RebuildStatusResponse
+	31, // [31:47] is the sub-list for method output_type
+	15, // [15:31] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_user_proto_init() }
@@ -1291,15 +1952,19 @@ func file_api_proto_user_proto_init() {
 	file_api_proto_user_proto_msgTypes[1].OneofWrappers = []any{}
 	file_api_proto_user_proto_msgTypes[2].OneofWrappers = []any{}
 	file_api_proto_user_proto_msgTypes[7].OneofWrappers = []any{}
+	file_api_proto_user_proto_msgTypes[20].OneofWrappers = []any{
+		(*GetUserAsOfRequest_Version)(nil),
+		(*GetUserAsOfRequest_AsOf)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_user_proto_rawDesc), len(file_api_proto_user_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   17,
+			NumMessages:   27,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_api_proto_user_proto_goTypes,
 		DependencyIndexes: file_api_proto_user_proto_depIdxs,
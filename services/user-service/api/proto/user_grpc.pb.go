@@ -509,3 +509,271 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/user.proto",
 }
+
+const (
+	UserAdminService_GetUserHistory_FullMethodName   = "/user.UserAdminService/GetUserHistory"
+	UserAdminService_GetUserAsOf_FullMethodName      = "/user.UserAdminService/GetUserAsOf"
+	UserAdminService_EraseUser_FullMethodName        = "/user.UserAdminService/EraseUser"
+	UserAdminService_RebuildReadModel_FullMethodName = "/user.UserAdminService/RebuildReadModel"
+	UserAdminService_GetRebuildStatus_FullMethodName = "/user.UserAdminService/GetRebuildStatus"
+)
+
+// UserAdminServiceClient is the client API for UserAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// User Admin Service - Event history and erasure (internal, not routed through the gateway)
+type UserAdminServiceClient interface {
+	// Page through the events recorded for a user
+	GetUserHistory(ctx context.Context, in *GetUserHistoryRequest, opts ...grpc.CallOption) (*GetUserHistoryResponse, error)
+	// Rebuild a user as it was at a version or point in time
+	GetUserAsOf(ctx context.Context, in *GetUserAsOfRequest, opts ...grpc.CallOption) (*GetUserAsOfResponse, error)
+	// Permanently erase a user's personal data (crypto-shredding)
+	EraseUser(ctx context.Context, in *EraseUserRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Rebuild the user read model in a shadow table and swap it in once caught up
+	RebuildReadModel(ctx context.Context, in *RebuildReadModelRequest, opts ...grpc.CallOption) (*RebuildStatusResponse, error)
+	// Get the progress of the latest read model rebuild
+	GetRebuildStatus(ctx context.Context, in *GetRebuildStatusRequest, opts ...grpc.CallOption) (*RebuildStatusResponse, error)
+}
+
+type userAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserAdminServiceClient(cc grpc.ClientConnInterface) UserAdminServiceClient {
+	return &userAdminServiceClient{cc}
+}
+
+func (c *userAdminServiceClient) GetUserHistory(ctx context.Context, in *GetUserHistoryRequest, opts ...grpc.CallOption) (*GetUserHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserHistoryResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_GetUserHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) GetUserAsOf(ctx context.Context, in *GetUserAsOfRequest, opts ...grpc.CallOption) (*GetUserAsOfResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserAsOfResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_GetUserAsOf_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) EraseUser(ctx context.Context, in *EraseUserRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_EraseUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) RebuildReadModel(ctx context.Context, in *RebuildReadModelRequest, opts ...grpc.CallOption) (*RebuildStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RebuildStatusResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_RebuildReadModel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) GetRebuildStatus(ctx context.Context, in *GetRebuildStatusRequest, opts ...grpc.CallOption) (*RebuildStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RebuildStatusResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_GetRebuildStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserAdminServiceServer is the server API for UserAdminService service.
+// All implementations must embed UnimplementedUserAdminServiceServer
+// for forward compatibility.
+//
+// User Admin Service - Event history and erasure (internal, not routed through the gateway)
+type UserAdminServiceServer interface {
+	// Page through the events recorded for a user
+	GetUserHistory(context.Context, *GetUserHistoryRequest) (*GetUserHistoryResponse, error)
+	// Rebuild a user as it was at a version or point in time
+	GetUserAsOf(context.Context, *GetUserAsOfRequest) (*GetUserAsOfResponse, error)
+	// Permanently erase a user's personal data (crypto-shredding)
+	EraseUser(context.Context, *EraseUserRequest) (*DeleteResponse, error)
+	// Rebuild the user read model in a shadow table and swap it in once caught up
+	RebuildReadModel(context.Context, *RebuildReadModelRequest) (*RebuildStatusResponse, error)
+	// Get the progress of the latest read model rebuild
+	GetRebuildStatus(context.Context, *GetRebuildStatusRequest) (*RebuildStatusResponse, error)
+	mustEmbedUnimplementedUserAdminServiceServer()
+}
+
+// UnimplementedUserAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserAdminServiceServer struct{}
+
+func (UnimplementedUserAdminServiceServer) GetUserHistory(context.Context, *GetUserHistoryRequest) (*GetUserHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserHistory not implemented")
+}
+func (UnimplementedUserAdminServiceServer) GetUserAsOf(context.Context, *GetUserAsOfRequest) (*GetUserAsOfResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserAsOf not implemented")
+}
+func (UnimplementedUserAdminServiceServer) EraseUser(context.Context, *EraseUserRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseUser not implemented")
+}
+func (UnimplementedUserAdminServiceServer) RebuildReadModel(context.Context, *RebuildReadModelRequest) (*RebuildStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebuildReadModel not implemented")
+}
+func (UnimplementedUserAdminServiceServer) GetRebuildStatus(context.Context, *GetRebuildStatusRequest) (*RebuildStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRebuildStatus not implemented")
+}
+func (UnimplementedUserAdminServiceServer) mustEmbedUnimplementedUserAdminServiceServer() {}
+func (UnimplementedUserAdminServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeUserAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserAdminServiceServer will
+// result in compilation errors.
+type UnsafeUserAdminServiceServer interface {
+	mustEmbedUnimplementedUserAdminServiceServer()
+}
+
+func RegisterUserAdminServiceServer(s grpc.ServiceRegistrar, srv UserAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserAdminService_ServiceDesc, srv)
+}
+
+func _UserAdminService_GetUserHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).GetUserHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_GetUserHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).GetUserHistory(ctx, req.(*GetUserHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_GetUserAsOf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserAsOfRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).GetUserAsOf(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_GetUserAsOf_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).GetUserAsOf(ctx, req.(*GetUserAsOfRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_EraseUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).EraseUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_EraseUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).EraseUser(ctx, req.(*EraseUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_RebuildReadModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebuildReadModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).RebuildReadModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_RebuildReadModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).RebuildReadModel(ctx, req.(*RebuildReadModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_GetRebuildStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRebuildStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).GetRebuildStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_GetRebuildStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).GetRebuildStatus(ctx, req.(*GetRebuildStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserAdminService_ServiceDesc is the grpc.ServiceDesc for UserAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "user.UserAdminService",
+	HandlerType: (*UserAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUserHistory",
+			Handler:    _UserAdminService_GetUserHistory_Handler,
+		},
+		{
+			MethodName: "GetUserAsOf",
+			Handler:    _UserAdminService_GetUserAsOf_Handler,
+		},
+		{
+			MethodName: "EraseUser",
+			Handler:    _UserAdminService_EraseUser_Handler,
+		},
+		{
+			MethodName: "RebuildReadModel",
+			Handler:    _UserAdminService_RebuildReadModel_Handler,
+		},
+		{
+			MethodName: "GetRebuildStatus",
+			Handler:    _UserAdminService_GetRebuildStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/proto/user.proto",
+}
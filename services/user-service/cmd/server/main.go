@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,22 +14,43 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/toxictoast/toxictoastgo/shared/auth"
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	sharedlogger "github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/outbox"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	userpb "toxictoast/services/user-service/api/proto"
+	"toxictoast/services/user-service/internal/aggregate"
+	"toxictoast/services/user-service/internal/client"
 	"toxictoast/services/user-service/internal/command"
 	grpchandler "toxictoast/services/user-service/internal/handler/grpc"
 	"toxictoast/services/user-service/internal/projection"
 	"toxictoast/services/user-service/internal/query"
 	"toxictoast/services/user-service/internal/repository/entity"
 	"toxictoast/services/user-service/internal/repository/impl"
+	"toxictoast/services/user-service/internal/saga"
 	"toxictoast/services/user-service/pkg/config"
 )
 
+// publicMethods don't require a Keycloak token
+var publicMethods = []string{
+	"/user.UserService/*",
+}
+
+// permissions restrict the admin RPCs to administrators
+var permissions = sharedgrpc.PermissionRegistry{
+	"/user.UserAdminService/*":                {Roles: []string{"admin"}, Permissions: []string{"user:admin"}},
+	"/user.UserAdminService/EraseUser":        {Roles: []string{"admin"}, Permissions: []string{"user:erase"}},
+	"/user.UserAdminService/RebuildReadModel": {Roles: []string{"admin"}, Permissions: []string{"user:rebuild"}},
+}
+
 func main() {
 	// Load configuration
 	cfg, err := config.LoadConfig()
@@ -35,6 +58,18 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Initialize structured logging
+	if err := sharedlogger.Setup(sharedconfig.LoadLoggingConfig(cfg.ServiceName)); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig(cfg.ServiceName))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize logger
 	logger := sharedlogger.NewLogger(cfg.ServiceName)
 	logger.Info("Starting User Service")
@@ -73,18 +108,74 @@ func main() {
 	}
 	logger.Info("Event Store initialized")
 
-	// Initialize Aggregate Repository
-	aggRepo := eventstore.NewAggregateRepository(eventStore)
+	// Upcast old event payloads to their current schema on read
+	upcasters := eventstore.NewUpcasterRegistry()
+	aggregate.RegisterUpcasters(upcasters)
+
+	// Personal data fields of the user events, encrypted when PII_MASTER_KEY is set
+	piiRegistry := eventstore.NewPIIRegistry()
+	if err := aggregate.RegisterPII(piiRegistry); err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to register personal data fields: %v", err))
+	}
+
+	// Record every appended event in the outbox within the same transaction
+	// Published events carry neither credential hashes nor personal data, encrypted or not:
+	// consumers look users up through user-service, so erasure doesn't depend on Kafka retention
+	if err := outbox.CreateTable(sqlDB); err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to create outbox table: %v", err))
+	}
+	eventStore.AddAppendHook(outbox.EventStoreHookWithOptions(outbox.HookOptions{
+		Router: outbox.EventTypeTopic,
+		Mapper: outbox.ChainMappers(outbox.OmitFields(aggregate.SecretFields...), outbox.OmitPII(piiRegistry)),
+	}))
+
+	// Encrypt personal data with per-user keys so it can be erased by shredding the key
+	var store eventstore.EventStore = eventStore
+	var shredder command.DataShredder
+	if cfg.PII.MasterKey != "" {
+		masterKey, err := base64.StdEncoding.DecodeString(cfg.PII.MasterKey)
+		if err != nil {
+			logger.Fatal(fmt.Sprintf("Failed to decode PII master key: %v", err))
+		}
+		keyStore, err := eventstore.NewPostgresKeyStore(sqlDB, masterKey)
+		if err != nil {
+			logger.Fatal(fmt.Sprintf("Failed to initialize key store: %v", err))
+		}
+
+		// Upcasters run after decryption, since ciphertexts are bound to their stored field names
+		encryptingStore := eventstore.NewEncryptingEventStore(eventStore, keyStore, piiRegistry)
+		encryptingStore.SetUpcasterRegistry(upcasters)
+		store = encryptingStore
+		shredder = encryptingStore
+		logger.Info("Personal data encryption enabled")
+	} else {
+		eventStore.SetUpcasterRegistry(upcasters)
+		logger.Info("Warning: PII_MASTER_KEY not set, personal data is stored unencrypted and can't be erased")
+	}
+
+	// Initialize Snapshot Store
+	snapshotStore, err := eventstore.NewPostgresSnapshotStore(sqlDB)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to initialize snapshot store: %v", err))
+	}
+
+	// Initialize Aggregate Repository (aggregates may override the default snapshot policy)
+	aggRepo := eventstore.NewSnapshotAggregateRepository(store, snapshotStore, eventstore.EveryNEvents(100))
+
+	// Initialize metrics
+	serviceMetrics := metrics.New(cfg.ServiceName)
 
 	// Initialize Kafka producer
-	kafkaProducer, err := kafka.NewProducer(cfg.Kafka.Brokers)
+	// Handlers get a nil Publisher when Kafka is unavailable
+	var kafkaProducer kafka.Publisher
+	producer, err := kafka.NewProducerWithOptions(cfg.Kafka.Brokers, kafka.ProducerOptions{Source: cfg.ServiceName})
 	if err != nil {
 		logger.Info(fmt.Sprintf("Warning: Failed to initialize Kafka producer: %v", err))
 		logger.Info("Service will continue without event publishing")
-		kafkaProducer = nil
 	} else {
 		logger.Info("Kafka producer connected successfully")
-		defer kafkaProducer.Close()
+		kafkaProducer = producer
+		defer producer.Close()
 	}
 
 	// Initialize legacy repository (for command handlers validation)
@@ -106,7 +197,17 @@ func main() {
 	commandBus.RegisterHandler("activate_user", command.NewActivateUserHandler(aggRepo))
 	commandBus.RegisterHandler("deactivate_user", command.NewDeactivateUserHandler(aggRepo))
 	commandBus.RegisterHandler("delete_user", command.NewDeleteUserHandler(aggRepo))
-	logger.Info("Command Bus initialized with 8 command handlers")
+	commandBus.RegisterHandler("erase_user", command.NewEraseUserHandler(aggRepo, shredder, snapshotStore))
+
+	// Role assignments live in auth-service
+	authClient, err := client.NewAuthClient(cfg.AuthServiceAddr)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to create auth service client: %v", err))
+	}
+	defer authClient.Close()
+	commandBus.RegisterHandler("revoke_user_roles", command.NewRevokeUserRolesHandler(authClient))
+	commandBus.RegisterHandler("restore_user_roles", command.NewRestoreUserRolesHandler(authClient))
+	logger.Info("Command Bus initialized with 11 command handlers")
 
 	// Initialize Read Model Repository
 	readModelRepo := projection.NewUserReadModelRepository(sqlDB)
@@ -120,36 +221,138 @@ func main() {
 	// Initialize Query Bus
 	queryBus := cqrs.NewQueryBus()
 
+	// Install the shared command/query middleware pipeline
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: cfg.ServiceName,
+		Metrics:     serviceMetrics,
+	})
+
 	// Register Query Handlers
 	queryBus.RegisterHandler("get_user_by_id", query.NewGetUserByIDHandler(readModelRepo))
 	queryBus.RegisterHandler("get_user_by_email", query.NewGetUserByEmailHandler(readModelRepo))
 	queryBus.RegisterHandler("get_user_by_username", query.NewGetUserByUsernameHandler(readModelRepo))
 	queryBus.RegisterHandler("list_users", query.NewListUsersHandler(readModelRepo))
-	queryBus.RegisterHandler("get_user_password_hash", query.NewGetUserPasswordHashHandler(eventStore))
-	logger.Info("Query Bus initialized with 5 query handlers")
+	queryBus.RegisterHandler("get_user_password_hash", query.NewGetUserPasswordHashHandler(aggRepo))
+	queryBus.RegisterHandler("get_user_history", query.NewGetUserHistoryHandler(aggRepo))
+	queryBus.RegisterHandler("get_user_as_of", query.NewGetUserAsOfHandler(aggRepo))
+	logger.Info("Query Bus initialized with 7 query handlers")
 
 	// Initialize Projector
 	userProjector := projection.NewUserProjector(readModelRepo)
 
-	// Initialize Projector Manager
-	projectorManager := cqrs.NewProjectorManager(eventStore)
+	// Initialize Projector Manager with persistent checkpoints
+	checkpointStore, err := eventstore.NewPostgresCheckpointStore(sqlDB)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to initialize checkpoint store: %v", err))
+	}
+
+	projectorManager := cqrs.NewProjectorManager(store)
+	projectorManager.SetCheckpointStore(checkpointStore)
 	projectorManager.RegisterProjector(userProjector)
 	logger.Info("Projector Manager initialized")
 
-	// Optional: Rebuild projections from history
-	ctx := context.Background()
-	if err := projectorManager.RebuildProjections(ctx, eventstore.AggregateTypeUser); err != nil {
-		logger.Info(fmt.Sprintf("Warning: Failed to rebuild projections: %v", err))
+	// Wake the projection on every append via LISTEN/NOTIFY, polling stays as a fallback
+	subscriptionOpts := cqrs.SubscriptionOptions{PollInterval: cfg.Projection.PollInterval}
+	if cfg.Projection.ListenNotify {
+		notifier, err := eventstore.NewPostgresEventNotifier(cfg.Database.GetDatabaseURL())
+		if err != nil {
+			logger.Info(fmt.Sprintf("Warning: Failed to start event notifier, falling back to polling: %v", err))
+		} else {
+			defer notifier.Close()
+			subscriptionOpts.Notifier = notifier
+		}
+	}
+
+	// Resume the user projection from its last checkpoint
+	ctx, cancelProjections := context.WithCancel(context.Background())
+	defer cancelProjections()
+	if _, err := projectorManager.Subscribe(ctx, userProjector, subscriptionOpts); err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to start user projection: %v", err))
+	}
+	logger.Info("User projection subscribed to event stream")
+
+	// Rebuild the user read model into shadow tables on demand, resuming interrupted rebuilds
+	rebuildStore, err := cqrs.NewPostgresRebuildStore(sqlDB)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to initialize rebuild store: %v", err))
+	}
+	rebuilder := cqrs.NewProjectionRebuilder(projectorManager, rebuildStore, cqrs.RebuildOptions{
+		Metrics:     serviceMetrics,
+		ServiceName: cfg.ServiceName,
+	})
+	rebuilder.Register(userProjector)
+	rebuilder.Start(ctx)
+	commandBus.RegisterHandler("rebuild_read_model", command.NewRebuildReadModelHandler(rebuilder))
+	queryBus.RegisterHandler("get_rebuild_status", query.NewGetRebuildStatusHandler(rebuilder))
+
+	// Run the user deletion saga; it only reacts to users deleted from now on
+	sagaStore, err := cqrs.NewPostgresSagaStore(sqlDB)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to initialize saga store: %v", err))
+	}
+	sagaManager := cqrs.NewSagaManager(commandBus, sagaStore, cqrs.SagaOptions{
+		MaxAttempts:  cfg.Saga.MaxAttempts,
+		RetryDelay:   cfg.Saga.RetryDelay,
+		PollInterval: cfg.Saga.PollInterval,
+	})
+	if err := sagaManager.Register(saga.NewUserDeletionSaga(authClient, shredder != nil)); err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to register user deletion saga: %v", err))
+	}
+
+	sagaOpts := subscriptionOpts
+	sagaOpts.StartAtEnd = true
+	if _, err := projectorManager.Subscribe(ctx, sagaManager, sagaOpts); err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to start saga manager: %v", err))
+	}
+	sagaManager.Start(ctx)
+	logger.Info("Saga manager started")
+
+	// Relay outbox messages to Kafka; without a producer they stay pending until the next start
+	if kafkaProducer != nil {
+		relay := outbox.NewRelay(sqlDB, kafkaProducer, outbox.RelayOptions{
+			Metrics:     serviceMetrics,
+			ServiceName: cfg.ServiceName,
+		})
+		relay.Start(ctx)
+		logger.Info("Outbox relay started")
 	} else {
-		logger.Info("Projections rebuilt successfully")
+		logger.Info("Outbox relay disabled, events will be published once Kafka is available")
 	}
 
 	// Initialize gRPC handler with CQRS components
 	userHandler := grpchandler.NewUserHandler(commandBus, queryBus, readModelRepo)
 
-	// Create gRPC server
-	grpcServer := grpc.NewServer()
+	// Admin RPCs require a Keycloak token; the user API is called by other services
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if cfg.AuthEnabled {
+		keycloakAuth, err := auth.NewKeycloakAuthWithOptions(&cfg.Keycloak, auth.KeycloakOptions{
+			PublicMethods: publicMethods,
+		})
+		if err != nil {
+			logger.Info(fmt.Sprintf("Warning: Failed to initialize Keycloak auth, admin RPCs are unavailable: %v", err))
+		} else {
+			defer keycloakAuth.Close()
+			unaryInterceptors = append(unaryInterceptors, keycloakAuth.UnaryInterceptor())
+			streamInterceptors = append(streamInterceptors, keycloakAuth.StreamInterceptor())
+			logger.Info("Keycloak authentication initialized")
+		}
+	} else {
+		logger.Info("Authentication is DISABLED (AUTH_ENABLED=false), admin RPCs are unavailable")
+	}
+
+	// Create gRPC server with the standard interceptor chain
+	grpcServer := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:        cfg.ServiceName,
+		Metrics:            serviceMetrics,
+		DefaultTimeout:     cfg.Server.GRPCTimeout,
+		MaxTimeout:         cfg.Server.GRPCMaxTimeout,
+		Permissions:        permissions,
+		UnaryInterceptors:  unaryInterceptors,
+		StreamInterceptors: streamInterceptors,
+	})
 	userpb.RegisterUserServiceServer(grpcServer, userHandler)
+	userpb.RegisterUserAdminServiceServer(grpcServer, grpchandler.NewAdminHandler(commandBus, queryBus))
 
 	// Register reflection service (for grpcurl)
 	reflection.Register(grpcServer)
@@ -163,6 +366,20 @@ func main() {
 
 	logger.Info(fmt.Sprintf("Starting gRPC server on port %d", cfg.GRPCPort))
 
+	// Expose Prometheus metrics
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/metrics", serviceMetrics.Handler())
+	httpMux.Handle("/admin/log-level", sharedlogger.LevelHandler())
+	httpServer := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Port),
+		Handler: httpMux,
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Info(fmt.Sprintf("Warning: metrics server failed: %v", err))
+		}
+	}()
+
 	// Handle graceful shutdown
 	go func() {
 		if err := grpcServer.Serve(listener); err != nil {
@@ -176,6 +393,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down User Service...")
+	httpServer.Shutdown(context.Background())
 	grpcServer.GracefulStop()
 	logger.Info("User Service stopped")
 }
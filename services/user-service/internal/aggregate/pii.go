@@ -0,0 +1,27 @@
+package aggregate
+
+import (
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+// RegisterPII registers the personal data fields of user event payloads
+// The fields are marked with pii struct tags and encrypted with the user's data key
+func RegisterPII(registry *eventstore.PIIRegistry) error {
+	payloads := []struct {
+		eventType string
+		payload   interface{}
+	}{
+		{eventstore.EventTypeUserCreated, UserCreatedEvent{}},
+		{eventstore.EventTypeUserUpdated, UserEmailChangedEvent{}},
+		{eventstore.EventTypeUserUpdated, UserPasswordChangedEvent{}},
+		{eventstore.EventTypeUserUpdated, UserProfileUpdatedEvent{}},
+	}
+
+	for _, p := range payloads {
+		if err := registry.Register(p.eventType, p.payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
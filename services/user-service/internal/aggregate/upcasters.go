@@ -0,0 +1,15 @@
+package aggregate
+
+import (
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+// RegisterUpcasters registers the upcasters for user event payloads
+// Whenever a user event struct changes shape, bump its schema by registering
+// an upcaster from the previous version, e.g.
+//
+//	registry.Register(eventstore.EventTypeUserCreated, 1, eventstore.RenameField("avatar_url", "avatar"))
+//
+// All user events are still at eventstore.DefaultSchemaVersion
+func RegisterUpcasters(registry *eventstore.UpcasterRegistry) {
+}
@@ -1,6 +1,7 @@
 package aggregate
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -18,6 +19,7 @@ var (
 	ErrUserNotActive         = errors.New("user is not active")
 	ErrUserAlreadyActive     = errors.New("user is already active")
 	ErrUserAlreadyDeleted    = errors.New("user is already deleted")
+	ErrUserErased            = errors.New("user personal data has been erased")
 )
 
 // UserAggregate represents the user aggregate root
@@ -35,8 +37,12 @@ type UserAggregate struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	DeletedAt    *time.Time
+	ErasedAt     *time.Time
 }
 
+// UserSnapshotInterval is the number of events between two user snapshots
+const UserSnapshotInterval = 20
+
 // NewUserAggregate creates a new user aggregate
 func NewUserAggregate(id string) *UserAggregate {
 	return &UserAggregate{
@@ -44,40 +50,43 @@ func NewUserAggregate(id string) *UserAggregate {
 	}
 }
 
+// SecretFields are event payload fields that are never published outside user-service
+var SecretFields = []string{"password_hash", "new_password_hash"}
+
 // UserCreatedEvent represents a user creation event
 type UserCreatedEvent struct {
-	UserID       string             `json:"user_id"`
-	Email        string             `json:"email"`
-	Username     string             `json:"username"`
-	PasswordHash string             `json:"password_hash"`
-	FirstName    string             `json:"first_name,omitempty"`
-	LastName     string             `json:"last_name,omitempty"`
-	AvatarURL    string             `json:"avatar_url,omitempty"`
-	Status       domain.UserStatus  `json:"status"`
-	CreatedAt    time.Time          `json:"created_at"`
+	UserID       string            `json:"user_id"`
+	Email        string            `json:"email" pii:"email"`
+	Username     string            `json:"username" pii:"token"`
+	PasswordHash string            `json:"password_hash" pii:"token"`
+	FirstName    string            `json:"first_name,omitempty" pii:"empty"`
+	LastName     string            `json:"last_name,omitempty" pii:"empty"`
+	AvatarURL    string            `json:"avatar_url,omitempty" pii:"empty"`
+	Status       domain.UserStatus `json:"status"`
+	CreatedAt    time.Time         `json:"created_at"`
 }
 
 // UserEmailChangedEvent represents an email change event
 type UserEmailChangedEvent struct {
-	UserID   string    `json:"user_id"`
-	OldEmail string    `json:"old_email"`
-	NewEmail string    `json:"new_email"`
+	UserID    string    `json:"user_id"`
+	OldEmail  string    `json:"old_email" pii:"email"`
+	NewEmail  string    `json:"new_email" pii:"email"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // UserPasswordChangedEvent represents a password change event
 type UserPasswordChangedEvent struct {
 	UserID          string    `json:"user_id"`
-	NewPasswordHash string    `json:"new_password_hash"`
+	NewPasswordHash string    `json:"new_password_hash" pii:"token"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // UserProfileUpdatedEvent represents a profile update event
 type UserProfileUpdatedEvent struct {
 	UserID    string    `json:"user_id"`
-	FirstName string    `json:"first_name,omitempty"`
-	LastName  string    `json:"last_name,omitempty"`
-	AvatarURL string    `json:"avatar_url,omitempty"`
+	FirstName string    `json:"first_name,omitempty" pii:"empty"`
+	LastName  string    `json:"last_name,omitempty" pii:"empty"`
+	AvatarURL string    `json:"avatar_url,omitempty" pii:"empty"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
@@ -99,6 +108,13 @@ type UserDeletedEvent struct {
 	DeletedAt time.Time `json:"deleted_at"`
 }
 
+// UserErasedEvent represents the erasure of the user's personal data
+// The event itself carries none; the data key is shredded once it was saved
+type UserErasedEvent struct {
+	UserID   string    `json:"user_id"`
+	ErasedAt time.Time `json:"erased_at"`
+}
+
 // CreateUser creates a new user
 func (a *UserAggregate) CreateUser(email, username, passwordHash string, firstName, lastName, avatarURL *string) error {
 	// Validation
@@ -139,6 +155,10 @@ func (a *UserAggregate) CreateUser(email, username, passwordHash string, firstNa
 
 // ChangeEmail changes the user's email
 func (a *UserAggregate) ChangeEmail(newEmail string) error {
+	if a.ErasedAt != nil {
+		return ErrUserErased
+	}
+
 	if newEmail == "" {
 		return ErrInvalidEmail
 	}
@@ -161,6 +181,10 @@ func (a *UserAggregate) ChangeEmail(newEmail string) error {
 
 // ChangePassword changes the user's password
 func (a *UserAggregate) ChangePassword(newPasswordHash string) error {
+	if a.ErasedAt != nil {
+		return ErrUserErased
+	}
+
 	if newPasswordHash == "" {
 		return ErrInvalidPassword
 	}
@@ -178,6 +202,10 @@ func (a *UserAggregate) ChangePassword(newPasswordHash string) error {
 
 // UpdateProfile updates the user's profile information
 func (a *UserAggregate) UpdateProfile(firstName, lastName, avatarURL *string) error {
+	if a.ErasedAt != nil {
+		return ErrUserErased
+	}
+
 	event := UserProfileUpdatedEvent{
 		UserID:    a.ID,
 		UpdatedAt: time.Now().UTC(),
@@ -246,6 +274,23 @@ func (a *UserAggregate) Delete() error {
 	})
 }
 
+// Erase anonymizes the user's personal data
+// Erasing an already erased user is a no-op, so the key can be shredded again after a failure
+func (a *UserAggregate) Erase() error {
+	if a.ErasedAt != nil {
+		return nil
+	}
+
+	event := UserErasedEvent{
+		UserID:   a.ID,
+		ErasedAt: time.Now().UTC(),
+	}
+
+	return a.RaiseEvent(eventstore.EventTypeUserErased, event, func(e *eventstore.EventEnvelope) error {
+		return a.applyUserErased(e)
+	})
+}
+
 // LoadFromHistory reconstructs the aggregate from events
 func (a *UserAggregate) LoadFromHistory(events []*eventstore.EventEnvelope) error {
 	return a.BaseAggregate.LoadFromHistory(events, func(e *eventstore.EventEnvelope) error {
@@ -261,12 +306,74 @@ func (a *UserAggregate) LoadFromHistory(events []*eventstore.EventEnvelope) erro
 			return a.applyUserDeactivated(e)
 		case eventstore.EventTypeUserDeleted:
 			return a.applyUserDeleted(e)
+		case eventstore.EventTypeUserErased:
+			return a.applyUserErased(e)
 		default:
 			return fmt.Errorf("unknown event type: %s", e.EventType)
 		}
 	})
 }
 
+// userSnapshotState is the serialized form of the aggregate state
+type userSnapshotState struct {
+	Email        string            `json:"email"`
+	Username     string            `json:"username"`
+	PasswordHash string            `json:"password_hash"`
+	FirstName    string            `json:"first_name,omitempty"`
+	LastName     string            `json:"last_name,omitempty"`
+	AvatarURL    string            `json:"avatar_url,omitempty"`
+	Status       domain.UserStatus `json:"status"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"`
+	ErasedAt     *time.Time        `json:"erased_at,omitempty"`
+}
+
+// SnapshotPolicy snapshots users every UserSnapshotInterval events
+func (a *UserAggregate) SnapshotPolicy() eventstore.SnapshotPolicy {
+	return eventstore.EveryNEvents(UserSnapshotInterval)
+}
+
+// SnapshotState serializes the current user state
+func (a *UserAggregate) SnapshotState() ([]byte, error) {
+	return json.Marshal(userSnapshotState{
+		Email:        a.Email,
+		Username:     a.Username,
+		PasswordHash: a.PasswordHash,
+		FirstName:    a.FirstName,
+		LastName:     a.LastName,
+		AvatarURL:    a.AvatarURL,
+		Status:       a.Status,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+		DeletedAt:    a.DeletedAt,
+		ErasedAt:     a.ErasedAt,
+	})
+}
+
+// RestoreSnapshot restores the user state from a snapshot
+func (a *UserAggregate) RestoreSnapshot(snapshot *eventstore.Snapshot) error {
+	var state userSnapshotState
+	if err := json.Unmarshal(snapshot.State, &state); err != nil {
+		return fmt.Errorf("failed to unmarshal user snapshot: %w", err)
+	}
+
+	a.Email = state.Email
+	a.Username = state.Username
+	a.PasswordHash = state.PasswordHash
+	a.FirstName = state.FirstName
+	a.LastName = state.LastName
+	a.AvatarURL = state.AvatarURL
+	a.Status = state.Status
+	a.CreatedAt = state.CreatedAt
+	a.UpdatedAt = state.UpdatedAt
+	a.DeletedAt = state.DeletedAt
+	a.ErasedAt = state.ErasedAt
+	a.Version = snapshot.Version
+
+	return nil
+}
+
 // Event application methods
 func (a *UserAggregate) applyUserCreated(e *eventstore.EventEnvelope) error {
 	var event UserCreatedEvent
@@ -386,3 +493,22 @@ func (a *UserAggregate) applyUserDeleted(e *eventstore.EventEnvelope) error {
 
 	return nil
 }
+
+func (a *UserAggregate) applyUserErased(e *eventstore.EventEnvelope) error {
+	var event UserErasedEvent
+	if err := e.UnmarshalData(&event); err != nil {
+		return err
+	}
+
+	// Same values the event store substitutes once the data key is shredded
+	a.Email = eventstore.AnonymizedValue(eventstore.PIIModeEmail, a.ID)
+	a.Username = eventstore.AnonymizedValue(eventstore.PIIModeToken, a.ID)
+	a.PasswordHash = eventstore.AnonymizedValue(eventstore.PIIModeToken, a.ID)
+	a.FirstName = ""
+	a.LastName = ""
+	a.AvatarURL = ""
+	a.ErasedAt = &event.ErasedAt
+	a.UpdatedAt = event.ErasedAt
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package aggregate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	"toxictoast/services/user-service/internal/domain"
+)
+
+func newTestRepository() (*eventstore.AggregateRepository, *eventstore.MemorySnapshotStore) {
+	snapshots := eventstore.NewMemorySnapshotStore()
+	repo := eventstore.NewSnapshotAggregateRepository(eventstore.NewMemoryEventStore(), snapshots, eventstore.NeverSnapshot())
+	return repo, snapshots
+}
+
+func createTestUser(t *testing.T, repo *eventstore.AggregateRepository) string {
+	t.Helper()
+
+	id := uuid.New().String()
+	user := NewUserAggregate(id)
+	if err := user.CreateUser("john@example.com", "johndoe", "hash", nil, nil, nil); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if err := repo.Save(context.Background(), user); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	return id
+}
+
+func TestUserAggregate_SaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepository()
+	id := createTestUser(t, repo)
+
+	user := NewUserAggregate(id)
+	if err := repo.Load(ctx, user); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := user.ChangeEmail("jane@example.com"); err != nil {
+		t.Fatalf("ChangeEmail failed: %v", err)
+	}
+	if err := user.Deactivate(); err != nil {
+		t.Fatalf("Deactivate failed: %v", err)
+	}
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := NewUserAggregate(id)
+	if err := repo.Load(ctx, loaded); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.Email != "jane@example.com" {
+		t.Errorf("Expected email jane@example.com, got %s", loaded.Email)
+	}
+	if loaded.Status != domain.UserStatusInactive {
+		t.Errorf("Expected status %s, got %s", domain.UserStatusInactive, loaded.Status)
+	}
+	if loaded.GetVersion() != 2 {
+		t.Errorf("Expected version 2, got %d", loaded.GetVersion())
+	}
+}
+
+func TestUserAggregate_ConcurrentModification(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepository()
+	id := createTestUser(t, repo)
+
+	first := NewUserAggregate(id)
+	second := NewUserAggregate(id)
+	if err := repo.Load(ctx, first); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := repo.Load(ctx, second); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if err := first.ChangeEmail("first@example.com"); err != nil {
+		t.Fatalf("ChangeEmail failed: %v", err)
+	}
+	if err := second.ChangeEmail("second@example.com"); err != nil {
+		t.Fatalf("ChangeEmail failed: %v", err)
+	}
+
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := repo.Save(ctx, second); !errors.Is(err, eventstore.ErrConcurrencyConflict) {
+		t.Errorf("Expected ErrConcurrencyConflict, got %v", err)
+	}
+}
+
+func TestUserAggregate_LoadFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	repo, snapshots := newTestRepository()
+	id := createTestUser(t, repo)
+
+	// Cross the snapshot interval, then append a few more events after the snapshot
+	for i := 0; i < UserSnapshotInterval+2; i++ {
+		user := NewUserAggregate(id)
+		if err := repo.Load(ctx, user); err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if err := user.ChangeEmail(fmt.Sprintf("user%d@example.com", i)); err != nil {
+			t.Fatalf("ChangeEmail failed: %v", err)
+		}
+		if err := repo.Save(ctx, user); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	snapshot, err := snapshots.GetSnapshot(ctx, eventstore.AggregateTypeUser, id)
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if snapshot == nil {
+		t.Fatal("Expected a snapshot, got nil")
+	}
+
+	loaded := NewUserAggregate(id)
+	if err := repo.Load(ctx, loaded); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	expectedEmail := fmt.Sprintf("user%d@example.com", UserSnapshotInterval+1)
+	if loaded.Email != expectedEmail {
+		t.Errorf("Expected email %s, got %s", expectedEmail, loaded.Email)
+	}
+	if loaded.GetVersion() != UserSnapshotInterval+2 {
+		t.Errorf("Expected version %d, got %d", UserSnapshotInterval+2, loaded.GetVersion())
+	}
+	if loaded.GetVersion() <= snapshot.Version {
+		t.Errorf("Expected events after snapshot version %d to be replayed", snapshot.Version)
+	}
+}
+
+func TestUserAggregate_LoadAtVersion(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepository()
+	id := createTestUser(t, repo)
+
+	user := NewUserAggregate(id)
+	if err := repo.Load(ctx, user); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := user.ChangeEmail("jane@example.com"); err != nil {
+		t.Fatalf("ChangeEmail failed: %v", err)
+	}
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	past := NewUserAggregate(id)
+	if err := repo.LoadAtVersion(ctx, past, 0); err != nil {
+		t.Fatalf("LoadAtVersion failed: %v", err)
+	}
+	if past.Email != "john@example.com" {
+		t.Errorf("Expected email john@example.com at version 0, got %s", past.Email)
+	}
+
+	beforeCreation := NewUserAggregate(id)
+	err := repo.LoadAsOf(ctx, beforeCreation, past.CreatedAt.Add(-time.Hour))
+	if !errors.Is(err, eventstore.ErrAggregateNotFound) {
+		t.Errorf("Expected ErrAggregateNotFound before creation, got %v", err)
+	}
+}
+
+func TestUserAggregate_Erase(t *testing.T) {
+	ctx := context.Background()
+
+	registry := eventstore.NewPIIRegistry()
+	if err := RegisterPII(registry); err != nil {
+		t.Fatalf("RegisterPII failed: %v", err)
+	}
+	store := eventstore.NewEncryptingEventStore(eventstore.NewMemoryEventStore(), eventstore.NewMemoryKeyStore(), registry)
+	repo := eventstore.NewAggregateRepository(store)
+	id := createTestUser(t, repo)
+
+	user := NewUserAggregate(id)
+	if err := repo.Load(ctx, user); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if user.Email != "john@example.com" {
+		t.Fatalf("Expected decrypted email john@example.com, got %s", user.Email)
+	}
+	if err := user.Erase(); err != nil {
+		t.Fatalf("Erase failed: %v", err)
+	}
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := store.ShredAggregate(ctx, id); err != nil {
+		t.Fatalf("ShredAggregate failed: %v", err)
+	}
+
+	// Replaying the shredded history yields the same state the erasure produced
+	replayed := NewUserAggregate(id)
+	if err := repo.LoadAtVersion(ctx, replayed, 0); err != nil {
+		t.Fatalf("LoadAtVersion failed: %v", err)
+	}
+	expectedEmail := eventstore.AnonymizedValue(eventstore.PIIModeEmail, id)
+	if replayed.Email != expectedEmail || replayed.Email != user.Email {
+		t.Errorf("Expected email %s, got %s", expectedEmail, replayed.Email)
+	}
+	if replayed.Username != user.Username || replayed.PasswordHash != user.PasswordHash {
+		t.Errorf("Expected anonymized username and password hash, got %s and %s", replayed.Username, replayed.PasswordHash)
+	}
+
+	erased := NewUserAggregate(id)
+	if err := repo.Load(ctx, erased); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if erased.ErasedAt == nil {
+		t.Error("Expected the user to be erased")
+	}
+	if err := erased.ChangeEmail("jane@example.com"); !errors.Is(err, ErrUserErased) {
+		t.Errorf("Expected ErrUserErased, got %v", err)
+	}
+}
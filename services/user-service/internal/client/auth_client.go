@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
+	authpb "github.com/toxictoast/toxictoastgo/shared/proto/auth"
+)
+
+// AuthClient manages a user's role assignments in auth-service
+// Assigning and revoking roles is idempotent, so saga steps can be retried
+type AuthClient struct {
+	conn   *grpc.ClientConn
+	client authpb.AuthServiceClient
+}
+
+// NewAuthClient creates a new auth-service client
+// The connection is established lazily on the first call and held until Close
+func NewAuthClient(addr string) (*AuthClient, error) {
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, sharedgrpc.ClientOptions()...)
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to auth service: %w", err)
+	}
+
+	return &AuthClient{
+		conn:   conn,
+		client: authpb.NewAuthServiceClient(conn),
+	}, nil
+}
+
+// Close closes the connection to auth-service
+func (c *AuthClient) Close() error {
+	return c.conn.Close()
+}
+
+// ListUserRoleIDs returns the IDs of the roles assigned to a user
+func (c *AuthClient) ListUserRoleIDs(ctx context.Context, userID string) ([]string, error) {
+	resp, err := c.client.ListUserRoles(ctx, &authpb.ListUserRolesRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user roles: %w", err)
+	}
+
+	roleIDs := make([]string, 0, len(resp.Roles))
+	for _, role := range resp.Roles {
+		roleIDs = append(roleIDs, role.Id)
+	}
+	return roleIDs, nil
+}
+
+// RevokeRoles revokes roles from a user; roles the user doesn't have are skipped
+func (c *AuthClient) RevokeRoles(ctx context.Context, userID string, roleIDs []string) error {
+	for _, roleID := range roleIDs {
+		_, err := c.client.RevokeRole(ctx, &authpb.RevokeRoleRequest{UserId: userID, RoleId: roleID})
+		if err != nil && !alreadyApplied(err, codes.NotFound, "user does not have this role") {
+			return fmt.Errorf("failed to revoke role %s: %w", roleID, err)
+		}
+	}
+	return nil
+}
+
+// AssignRoles assigns roles to a user; roles the user already has are skipped
+func (c *AuthClient) AssignRoles(ctx context.Context, userID string, roleIDs []string) error {
+	for _, roleID := range roleIDs {
+		_, err := c.client.AssignRole(ctx, &authpb.AssignRoleRequest{UserId: userID, RoleId: roleID})
+		if err != nil && !alreadyApplied(err, codes.AlreadyExists, "user already has this role") {
+			return fmt.Errorf("failed to assign role %s: %w", roleID, err)
+		}
+	}
+	return nil
+}
+
+// alreadyApplied reports whether auth-service rejected a change because it's already in place
+// Older auth-service versions report it as an internal error, recognizable by its message
+func alreadyApplied(err error, code codes.Code, message string) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	return st.Code() == code || strings.Contains(st.Message(), message)
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+)
+
+// RebuildReadModelCommand starts rebuilding a read model in the background
+type RebuildReadModelCommand struct {
+	cqrs.BaseCommand
+	Projector string `json:"projector"`
+}
+
+func (c *RebuildReadModelCommand) CommandName() string {
+	return "rebuild_read_model"
+}
+
+func (c *RebuildReadModelCommand) Validate() error {
+	if c.Projector == "" {
+		return errors.New("projector is required")
+	}
+	return nil
+}
+
+// RebuildReadModelHandler handles read model rebuilds
+type RebuildReadModelHandler struct {
+	rebuilder *cqrs.ProjectionRebuilder
+}
+
+func NewRebuildReadModelHandler(rebuilder *cqrs.ProjectionRebuilder) *RebuildReadModelHandler {
+	return &RebuildReadModelHandler{rebuilder: rebuilder}
+}
+
+func (h *RebuildReadModelHandler) Handle(ctx context.Context, cmd cqrs.Command) error {
+	rebuildCmd := cmd.(*RebuildReadModelCommand)
+
+	if _, err := h.rebuilder.StartRebuild(rebuildCmd.Projector); err != nil {
+		return fmt.Errorf("failed to start rebuild: %w", err)
+	}
+
+	return nil
+}
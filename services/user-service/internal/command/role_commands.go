@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+)
+
+// RoleManager manages a user's role assignments, which live in auth-service
+type RoleManager interface {
+	RevokeRoles(ctx context.Context, userID string, roleIDs []string) error
+	AssignRoles(ctx context.Context, userID string, roleIDs []string) error
+}
+
+// RevokeUserRolesCommand revokes roles from a user
+type RevokeUserRolesCommand struct {
+	cqrs.BaseCommand
+	RoleIDs []string `json:"role_ids"`
+}
+
+func (c *RevokeUserRolesCommand) CommandName() string {
+	return "revoke_user_roles"
+}
+
+func (c *RevokeUserRolesCommand) Validate() error {
+	if c.AggregateID == "" {
+		return errors.New("user_id is required")
+	}
+	return nil
+}
+
+// RestoreUserRolesCommand assigns previously revoked roles to a user again
+type RestoreUserRolesCommand struct {
+	cqrs.BaseCommand
+	RoleIDs []string `json:"role_ids"`
+}
+
+func (c *RestoreUserRolesCommand) CommandName() string {
+	return "restore_user_roles"
+}
+
+func (c *RestoreUserRolesCommand) Validate() error {
+	if c.AggregateID == "" {
+		return errors.New("user_id is required")
+	}
+	return nil
+}
+
+// RevokeUserRolesHandler handles role revocation
+type RevokeUserRolesHandler struct {
+	roles RoleManager
+}
+
+func NewRevokeUserRolesHandler(roles RoleManager) *RevokeUserRolesHandler {
+	return &RevokeUserRolesHandler{roles: roles}
+}
+
+func (h *RevokeUserRolesHandler) Handle(ctx context.Context, cmd cqrs.Command) error {
+	revokeCmd := cmd.(*RevokeUserRolesCommand)
+
+	if err := h.roles.RevokeRoles(ctx, revokeCmd.AggregateID, revokeCmd.RoleIDs); err != nil {
+		return fmt.Errorf("failed to revoke user roles: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreUserRolesHandler handles role restoration
+type RestoreUserRolesHandler struct {
+	roles RoleManager
+}
+
+func NewRestoreUserRolesHandler(roles RoleManager) *RestoreUserRolesHandler {
+	return &RestoreUserRolesHandler{roles: roles}
+}
+
+func (h *RestoreUserRolesHandler) Handle(ctx context.Context, cmd cqrs.Command) error {
+	restoreCmd := cmd.(*RestoreUserRolesCommand)
+
+	if err := h.roles.AssignRoles(ctx, restoreCmd.AggregateID, restoreCmd.RoleIDs); err != nil {
+		return fmt.Errorf("failed to restore user roles: %w", err)
+	}
+
+	return nil
+}
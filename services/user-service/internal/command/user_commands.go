@@ -171,6 +171,22 @@ func (c *DeleteUserCommand) Validate() error {
 	return nil
 }
 
+// EraseUserCommand erases a user's personal data by shredding the user's data key
+type EraseUserCommand struct {
+	cqrs.BaseCommand
+}
+
+func (c *EraseUserCommand) CommandName() string {
+	return "erase_user"
+}
+
+func (c *EraseUserCommand) Validate() error {
+	if c.AggregateID == "" {
+		return errors.New("user_id is required")
+	}
+	return nil
+}
+
 // Command Handlers
 
 // CreateUserHandler handles user creation
@@ -487,3 +503,64 @@ func (h *DeleteUserHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 
 	return nil
 }
+
+// ErrErasureNotConfigured is returned by EraseUserHandler when crypto-shredding is disabled
+var ErrErasureNotConfigured = errors.New("personal data erasure is not configured")
+
+// DataShredder permanently deletes an aggregate's data key
+type DataShredder interface {
+	ShredAggregate(ctx context.Context, aggregateID string) error
+}
+
+// EraseUserHandler handles personal data erasure
+type EraseUserHandler struct {
+	aggRepo   *eventstore.AggregateRepository
+	shredder  DataShredder
+	snapshots eventstore.SnapshotStore
+}
+
+// NewEraseUserHandler creates an erase handler; a nil shredder disables erasure
+func NewEraseUserHandler(aggRepo *eventstore.AggregateRepository, shredder DataShredder, snapshots eventstore.SnapshotStore) *EraseUserHandler {
+	return &EraseUserHandler{
+		aggRepo:   aggRepo,
+		shredder:  shredder,
+		snapshots: snapshots,
+	}
+}
+
+func (h *EraseUserHandler) Handle(ctx context.Context, cmd cqrs.Command) error {
+	eraseCmd := cmd.(*EraseUserCommand)
+
+	if h.shredder == nil {
+		return ErrErasureNotConfigured
+	}
+
+	// Load aggregate
+	user := aggregate.NewUserAggregate(eraseCmd.AggregateID)
+	if err := h.aggRepo.Load(ctx, user); err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+
+	// Erase, so projections and later snapshots only see anonymized state
+	if err := user.Erase(); err != nil {
+		return fmt.Errorf("failed to erase user: %w", err)
+	}
+
+	// Save events
+	if err := h.aggRepo.Save(ctx, user); err != nil {
+		return fmt.Errorf("failed to save user events: %w", err)
+	}
+
+	// Snapshots hold decrypted state
+	if h.snapshots != nil {
+		if err := h.snapshots.DeleteSnapshot(ctx, eventstore.AggregateTypeUser, user.ID); err != nil {
+			return fmt.Errorf("failed to delete user snapshots: %w", err)
+		}
+	}
+
+	if err := h.shredder.ShredAggregate(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to shred user data key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	userpb "toxictoast/services/user-service/api/proto"
+	"toxictoast/services/user-service/internal/aggregate"
+	"toxictoast/services/user-service/internal/command"
+	"toxictoast/services/user-service/internal/domain"
+	"toxictoast/services/user-service/internal/projection"
+	"toxictoast/services/user-service/internal/query"
+)
+
+// redactedValue replaces secrets in event payloads returned by the admin API
+const redactedValue = "[REDACTED]"
+
+// AdminHandler implements the gRPC user admin service
+type AdminHandler struct {
+	userpb.UnimplementedUserAdminServiceServer
+	commandBus *cqrs.CommandBus
+	queryBus   *cqrs.QueryBus
+}
+
+// NewAdminHandler creates a new user admin handler
+func NewAdminHandler(commandBus *cqrs.CommandBus, queryBus *cqrs.QueryBus) *AdminHandler {
+	return &AdminHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+	}
+}
+
+// GetUserHistory pages through the events recorded for a user
+func (h *AdminHandler) GetUserHistory(ctx context.Context, req *userpb.GetUserHistoryRequest) (*userpb.GetUserHistoryResponse, error) {
+	qry := &query.GetUserHistoryQuery{
+		BaseQuery: cqrs.BaseQuery{},
+		UserID:    req.UserId,
+		Cursor:    req.Cursor,
+		PageSize:  int(req.PageSize),
+	}
+
+	result, err := h.queryBus.Dispatch(ctx, qry)
+	if err != nil {
+		if errors.Is(err, cqrs.ErrQueryValidation) || errors.Is(err, eventstore.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user history: %v", err)
+	}
+
+	page := result.(*eventstore.EventPage)
+
+	events := make([]*userpb.UserEvent, 0, len(page.Events))
+	for _, event := range page.Events {
+		events = append(events, eventToProto(event))
+	}
+
+	return &userpb.GetUserHistoryResponse{
+		Events:     events,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// GetUserAsOf rebuilds a user as it was at a version or point in time
+func (h *AdminHandler) GetUserAsOf(ctx context.Context, req *userpb.GetUserAsOfRequest) (*userpb.GetUserAsOfResponse, error) {
+	qry := &query.GetUserAsOfQuery{
+		BaseQuery: cqrs.BaseQuery{},
+		UserID:    req.UserId,
+	}
+
+	switch point := req.Point.(type) {
+	case *userpb.GetUserAsOfRequest_Version:
+		qry.Version = &point.Version
+	case *userpb.GetUserAsOfRequest_AsOf:
+		asOf := point.AsOf.AsTime()
+		qry.AsOf = &asOf
+	}
+
+	result, err := h.queryBus.Dispatch(ctx, qry)
+	if err != nil {
+		if errors.Is(err, cqrs.ErrQueryValidation) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+		}
+		if errors.Is(err, query.ErrUserNotFoundAtPoint) {
+			return nil, status.Error(codes.NotFound, "user not found at the requested point")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+
+	user := result.(*aggregate.UserAggregate)
+	return &userpb.GetUserAsOfResponse{
+		User:    domainUserToProto(aggregateToDomain(user)),
+		Version: user.GetVersion(),
+	}, nil
+}
+
+// EraseUser permanently erases a user's personal data
+func (h *AdminHandler) EraseUser(ctx context.Context, req *userpb.EraseUserRequest) (*userpb.DeleteResponse, error) {
+	cmd := &command.EraseUserCommand{
+		BaseCommand: cqrs.BaseCommand{AggregateID: req.UserId},
+	}
+
+	if err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		if errors.Is(err, cqrs.ErrCommandValidation) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+		}
+		if errors.Is(err, command.ErrErasureNotConfigured) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, eventstore.ErrAggregateNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to erase user: %v", err)
+	}
+
+	return &userpb.DeleteResponse{
+		Success: true,
+		Message: "User personal data erased successfully",
+	}, nil
+}
+
+// RebuildReadModel starts rebuilding the user read model in a shadow table
+func (h *AdminHandler) RebuildReadModel(ctx context.Context, req *userpb.RebuildReadModelRequest) (*userpb.RebuildStatusResponse, error) {
+	cmd := &command.RebuildReadModelCommand{
+		BaseCommand: cqrs.BaseCommand{},
+		Projector:   projection.UserProjectorName,
+	}
+
+	if err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		if errors.Is(err, cqrs.ErrRebuildRunning) {
+			return nil, status.Error(codes.FailedPrecondition, "a rebuild is already running")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to start rebuild: %v", err)
+	}
+
+	return h.GetRebuildStatus(ctx, &userpb.GetRebuildStatusRequest{})
+}
+
+// GetRebuildStatus returns the progress of the latest user read model rebuild
+func (h *AdminHandler) GetRebuildStatus(ctx context.Context, req *userpb.GetRebuildStatusRequest) (*userpb.RebuildStatusResponse, error) {
+	qry := &query.GetRebuildStatusQuery{
+		BaseQuery: cqrs.BaseQuery{},
+		Projector: projection.UserProjectorName,
+	}
+
+	result, err := h.queryBus.Dispatch(ctx, qry)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get rebuild status: %v", err)
+	}
+
+	state := result.(*cqrs.RebuildState)
+	if state == nil {
+		return nil, status.Error(codes.NotFound, "the read model was never rebuilt")
+	}
+
+	return rebuildStateToProto(state), nil
+}
+
+// rebuildStateToProto converts cqrs.RebuildState to userpb.RebuildStatusResponse
+func rebuildStateToProto(state *cqrs.RebuildState) *userpb.RebuildStatusResponse {
+	resp := &userpb.RebuildStatusResponse{
+		Projector:       state.ProjectorName,
+		Version:         state.Version,
+		Status:          string(state.Status),
+		Position:        state.Position,
+		TargetPosition:  state.TargetPosition,
+		EventsProjected: state.EventsProjected,
+		LastError:       state.LastError,
+		StartedAt:       timestamppb.New(state.StartedAt),
+		UpdatedAt:       timestamppb.New(state.UpdatedAt),
+	}
+
+	if state.FinishedAt != nil {
+		resp.FinishedAt = timestamppb.New(*state.FinishedAt)
+	}
+
+	return resp
+}
+
+// eventToProto converts an event envelope to userpb.UserEvent
+func eventToProto(event *eventstore.EventEnvelope) *userpb.UserEvent {
+	metadata := make(map[string]string, len(event.Metadata))
+	for key, value := range event.Metadata {
+		metadata[key] = fmt.Sprint(value)
+	}
+
+	return &userpb.UserEvent{
+		EventId:       event.EventID,
+		EventType:     event.EventType,
+		Version:       event.Version,
+		SchemaVersion: int32(event.SchemaVersion),
+		Timestamp:     timestamppb.New(event.Timestamp),
+		Data:          string(redactEventData(event.Data)),
+		Metadata:      metadata,
+	}
+}
+
+// redactEventData masks password hashes in an event payload
+func redactEventData(data json.RawMessage) json.RawMessage {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return data
+	}
+
+	redacted := false
+	for key := range payload {
+		if strings.Contains(key, "password") {
+			payload[key] = json.RawMessage(`"` + redactedValue + `"`)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return data
+	}
+
+	result, err := json.Marshal(payload)
+	if err != nil {
+		return data
+	}
+	return result
+}
+
+// aggregateToDomain converts aggregate.UserAggregate to domain.User
+func aggregateToDomain(user *aggregate.UserAggregate) *domain.User {
+	var deletedAt *time.Time
+	if user.DeletedAt != nil {
+		t := *user.DeletedAt
+		deletedAt = &t
+	}
+
+	return &domain.User{
+		ID:        user.ID,
+		Email:     user.Email,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		AvatarURL: user.AvatarURL,
+		Status:    user.Status,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		DeletedAt: deletedAt,
+	}
+}
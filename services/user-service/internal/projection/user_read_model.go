@@ -25,20 +25,33 @@ type UserReadModel struct {
 	DeletedAt  *time.Time        `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
+// userReadModelTable is the live user read model table
+const userReadModelTable = "user_read_model"
+
+// userReadModelIndexes are the indexed columns of the user read model
+var userReadModelIndexes = []string{"email", "username", "status", "created_at"}
+
 // UserReadModelRepository manages user read models
 type UserReadModelRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	table string
 }
 
 // NewUserReadModelRepository creates a new repository
 func NewUserReadModelRepository(db *sql.DB) *UserReadModelRepository {
-	return &UserReadModelRepository{db: db}
+	return &UserReadModelRepository{db: db, table: userReadModelTable}
 }
 
 // CreateTable creates the read model table
 func (r *UserReadModelRepository) CreateTable() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS user_read_model (
+	_, err := r.db.Exec(userReadModelDDL(r.table))
+	return err
+}
+
+// userReadModelDDL returns the statements creating a user read model table and its indexes
+func userReadModelDDL(table string) string {
+	query := fmt.Sprintf(`
+	CREATE TABLE IF NOT EXISTS %[1]s (
 		id VARCHAR(36) PRIMARY KEY,
 		email VARCHAR(255) UNIQUE NOT NULL,
 		username VARCHAR(255) UNIQUE NOT NULL,
@@ -51,27 +64,80 @@ func (r *UserReadModelRepository) CreateTable() error {
 		deleted_at TIMESTAMP,
 		CONSTRAINT chk_status CHECK (status IN ('active', 'inactive', 'suspended'))
 	);
+	`, table)
+
+	for _, column := range userReadModelIndexes {
+		query += fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%[1]s_%[2]s ON %[1]s(%[2]s);\n", table, column)
+	}
 
-	CREATE INDEX IF NOT EXISTS idx_user_read_model_email ON user_read_model(email);
-	CREATE INDEX IF NOT EXISTS idx_user_read_model_username ON user_read_model(username);
-	CREATE INDEX IF NOT EXISTS idx_user_read_model_status ON user_read_model(status);
-	CREATE INDEX IF NOT EXISTS idx_user_read_model_created_at ON user_read_model(created_at);
-	`
+	return query
+}
 
-	_, err := r.db.Exec(query)
-	return err
+// shadowTable returns the table a rebuild of a version projects into
+func shadowTable(version int64) string {
+	return fmt.Sprintf("%s_v%d", userReadModelTable, version)
+}
+
+// withTable returns a repository reading and writing another user read model table
+func (r *UserReadModelRepository) withTable(table string) *UserReadModelRepository {
+	return &UserReadModelRepository{db: r.db, table: table}
+}
+
+// createShadowTable creates the shadow table of a rebuild version if it doesn't exist
+func (r *UserReadModelRepository) createShadowTable(ctx context.Context, version int64) error {
+	if _, err := r.db.ExecContext(ctx, userReadModelDDL(shadowTable(version))); err != nil {
+		return fmt.Errorf("failed to create shadow table: %w", err)
+	}
+	return nil
+}
+
+// swapShadowTable atomically replaces the live table with the shadow table of a version
+// Constraints and indexes are renamed, so the table looks as if created by CreateTable
+func (r *UserReadModelRepository) swapShadowTable(ctx context.Context, version int64) error {
+	shadow := shadowTable(version)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", userReadModelTable),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", shadow, userReadModelTable),
+		fmt.Sprintf("ALTER INDEX %s_pkey RENAME TO %s_pkey", shadow, userReadModelTable),
+		fmt.Sprintf("ALTER INDEX %s_email_key RENAME TO %s_email_key", shadow, userReadModelTable),
+		fmt.Sprintf("ALTER INDEX %s_username_key RENAME TO %s_username_key", shadow, userReadModelTable),
+	}
+	for _, column := range userReadModelIndexes {
+		statements = append(statements,
+			fmt.Sprintf("ALTER INDEX idx_%s_%s RENAME TO idx_%s_%s", shadow, column, userReadModelTable, column))
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to swap shadow table: %w", err)
+		}
+	}
+
+	return tx.Commit()
 }
 
 // FindByID finds a user by ID
 func (r *UserReadModelRepository) FindByID(ctx context.Context, id string) (*UserReadModel, error) {
+	return r.findByID(ctx, id, false)
+}
+
+// findByID finds a user by ID, optionally including deleted users
+func (r *UserReadModelRepository) findByID(ctx context.Context, id string, includeDeleted bool) (*UserReadModel, error) {
 	var user UserReadModel
 	user.BaseReadModel = &cqrs.BaseReadModel{}
 
 	err := r.db.QueryRowContext(ctx, `
 		SELECT id, email, username, first_name, last_name, avatar_url, status, created_at, last_updated, deleted_at
-		FROM user_read_model
-		WHERE id = $1 AND deleted_at IS NULL
-	`, id).Scan(
+		FROM `+r.table+`
+		WHERE id = $1 AND (deleted_at IS NULL OR $2)
+	`, id, includeDeleted).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
@@ -101,7 +167,7 @@ func (r *UserReadModelRepository) FindByEmail(ctx context.Context, email string)
 
 	err := r.db.QueryRowContext(ctx, `
 		SELECT id, email, username, first_name, last_name, avatar_url, status, created_at, last_updated, deleted_at
-		FROM user_read_model
+		FROM `+r.table+`
 		WHERE email = $1 AND deleted_at IS NULL
 	`, email).Scan(
 		&user.ID,
@@ -133,7 +199,7 @@ func (r *UserReadModelRepository) FindByUsername(ctx context.Context, username s
 
 	err := r.db.QueryRowContext(ctx, `
 		SELECT id, email, username, first_name, last_name, avatar_url, status, created_at, last_updated, deleted_at
-		FROM user_read_model
+		FROM `+r.table+`
 		WHERE username = $1 AND deleted_at IS NULL
 	`, username).Scan(
 		&user.ID,
@@ -162,7 +228,7 @@ func (r *UserReadModelRepository) FindByUsername(ctx context.Context, username s
 func (r *UserReadModelRepository) FindAll(ctx context.Context, limit, offset int) ([]*UserReadModel, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, email, username, first_name, last_name, avatar_url, status, created_at, last_updated, deleted_at
-		FROM user_read_model
+		FROM `+r.table+`
 		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -204,7 +270,7 @@ func (r *UserReadModelRepository) FindAll(ctx context.Context, limit, offset int
 // Save upserts a user read model
 func (r *UserReadModelRepository) Save(ctx context.Context, user *UserReadModel) error {
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO user_read_model (
+		INSERT INTO `+r.table+` (
 			id, email, username, first_name, last_name, avatar_url, status, created_at, last_updated, deleted_at
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (id) DO UPDATE SET
@@ -238,13 +304,16 @@ func (r *UserReadModelRepository) Save(ctx context.Context, user *UserReadModel)
 
 // Delete removes a user read model
 func (r *UserReadModelRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM user_read_model WHERE id = $1`, id)
+	_, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE id = $1`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user read model: %w", err)
 	}
 	return nil
 }
 
+// UserProjectorName is the name the user projector checkpoints and rebuilds under
+const UserProjectorName = "user_projector"
+
 // UserProjector projects user events into read models
 type UserProjector struct {
 	repo *UserReadModelRepository
@@ -257,7 +326,22 @@ func NewUserProjector(repo *UserReadModelRepository) *UserProjector {
 
 // GetProjectorName returns the projector name
 func (p *UserProjector) GetProjectorName() string {
-	return "user_projector"
+	return UserProjectorName
+}
+
+// PrepareShadow creates the shadow table of a rebuild version
+func (p *UserProjector) PrepareShadow(ctx context.Context, version int64) error {
+	return p.repo.createShadowTable(ctx, version)
+}
+
+// Shadow returns a projector writing into the shadow table of a rebuild version
+func (p *UserProjector) Shadow(version int64) cqrs.Projector {
+	return NewUserProjector(p.repo.withTable(shadowTable(version)))
+}
+
+// SwapShadow replaces the live read model table with the shadow table of a rebuild version
+func (p *UserProjector) SwapShadow(ctx context.Context, version int64) error {
+	return p.repo.swapShadowTable(ctx, version)
 }
 
 // GetEventTypes returns the event types this projector handles
@@ -268,6 +352,7 @@ func (p *UserProjector) GetEventTypes() []string {
 		eventstore.EventTypeUserActivated,
 		eventstore.EventTypeUserDeactivated,
 		eventstore.EventTypeUserDeleted,
+		eventstore.EventTypeUserErased,
 	}
 }
 
@@ -284,6 +369,8 @@ func (p *UserProjector) ProjectEvent(ctx context.Context, event *eventstore.Even
 		return p.projectUserDeactivated(ctx, event)
 	case eventstore.EventTypeUserDeleted:
 		return p.projectUserDeleted(ctx, event)
+	case eventstore.EventTypeUserErased:
+		return p.projectUserErased(ctx, event)
 	default:
 		return fmt.Errorf("unknown event type: %s", event.EventType)
 	}
@@ -310,8 +397,9 @@ func (p *UserProjector) projectUserCreated(ctx context.Context, event *eventstor
 }
 
 func (p *UserProjector) projectUserUpdated(ctx context.Context, event *eventstore.EventEnvelope) error {
-	// Load existing read model
-	user, err := p.repo.FindByID(ctx, event.AggregateID)
+	// Load existing read model, including deleted users: events recorded before the
+	// deletion may be redelivered after it, and must not stop the subscription
+	user, err := p.repo.findByID(ctx, event.AggregateID, true)
 	if err != nil {
 		return err
 	}
@@ -347,7 +435,8 @@ func (p *UserProjector) projectUserUpdated(ctx context.Context, event *eventstor
 }
 
 func (p *UserProjector) projectUserActivated(ctx context.Context, event *eventstore.EventEnvelope) error {
-	user, err := p.repo.FindByID(ctx, event.AggregateID)
+	// Include deleted users, so a redelivered event is projected again instead of failing
+	user, err := p.repo.findByID(ctx, event.AggregateID, true)
 	if err != nil {
 		return err
 	}
@@ -362,7 +451,8 @@ func (p *UserProjector) projectUserActivated(ctx context.Context, event *eventst
 }
 
 func (p *UserProjector) projectUserDeactivated(ctx context.Context, event *eventstore.EventEnvelope) error {
-	user, err := p.repo.FindByID(ctx, event.AggregateID)
+	// Include deleted users, so a redelivered event is projected again instead of failing
+	user, err := p.repo.findByID(ctx, event.AggregateID, true)
 	if err != nil {
 		return err
 	}
@@ -382,7 +472,8 @@ func (p *UserProjector) projectUserDeleted(ctx context.Context, event *eventstor
 		return err
 	}
 
-	user, err := p.repo.FindByID(ctx, event.AggregateID)
+	// Include deleted users, so a redelivered event is projected again instead of failing
+	user, err := p.repo.findByID(ctx, event.AggregateID, true)
 	if err != nil {
 		return err
 	}
@@ -395,3 +486,24 @@ func (p *UserProjector) projectUserDeleted(ctx context.Context, event *eventstor
 
 	return p.repo.Save(ctx, user)
 }
+
+func (p *UserProjector) projectUserErased(ctx context.Context, event *eventstore.EventEnvelope) error {
+	// Users are usually deleted before their personal data is erased
+	user, err := p.repo.findByID(ctx, event.AggregateID, true)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user not found: %s", event.AggregateID)
+	}
+
+	// Live projections see the erasure before a rebuild would replay the anonymized events
+	user.Email = eventstore.AnonymizedValue(eventstore.PIIModeEmail, event.AggregateID)
+	user.Username = eventstore.AnonymizedValue(eventstore.PIIModeToken, event.AggregateID)
+	user.FirstName = ""
+	user.LastName = ""
+	user.AvatarURL = ""
+	user.UpdateTimestamp()
+
+	return p.repo.Save(ctx, user)
+}
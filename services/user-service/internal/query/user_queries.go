@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/eventstore"
@@ -11,6 +12,9 @@ import (
 	"toxictoast/services/user-service/internal/projection"
 )
 
+// ErrUserNotFoundAtPoint is returned when a user did not exist at the requested version or time
+var ErrUserNotFoundAtPoint = errors.New("user did not exist at the requested point")
+
 // GetUserByIDQuery retrieves a user by ID
 type GetUserByIDQuery struct {
 	cqrs.BaseQuery
@@ -160,11 +164,11 @@ func (q *GetUserPasswordHashQuery) Validate() error {
 
 // GetUserPasswordHashHandler handles password hash retrieval
 type GetUserPasswordHashHandler struct {
-	eventStore eventstore.EventStore
+	aggRepo *eventstore.AggregateRepository
 }
 
-func NewGetUserPasswordHashHandler(eventStore eventstore.EventStore) *GetUserPasswordHashHandler {
-	return &GetUserPasswordHashHandler{eventStore: eventStore}
+func NewGetUserPasswordHashHandler(aggRepo *eventstore.AggregateRepository) *GetUserPasswordHashHandler {
+	return &GetUserPasswordHashHandler{aggRepo: aggRepo}
 }
 
 // PasswordHashResult contains the password hash
@@ -175,25 +179,143 @@ type PasswordHashResult struct {
 func (h *GetUserPasswordHashHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
 	q := query.(*GetUserPasswordHashQuery)
 
-	// Load user aggregate from event store to get password hash
+	// Load user aggregate (from its latest snapshot when available) to get password hash
 	user := aggregate.NewUserAggregate(q.UserID)
+	if err := h.aggRepo.Load(ctx, user); err != nil {
+		if errors.Is(err, eventstore.ErrAggregateNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	return &PasswordHashResult{
+		PasswordHash: user.PasswordHash,
+	}, nil
+}
+
+// GetUserHistoryQuery pages through the events recorded for a user
+type GetUserHistoryQuery struct {
+	cqrs.BaseQuery
+	UserID   string `json:"user_id"`
+	Cursor   string `json:"cursor"`
+	PageSize int    `json:"page_size"`
+}
+
+func (q *GetUserHistoryQuery) QueryName() string {
+	return "get_user_history"
+}
+
+func (q *GetUserHistoryQuery) Validate() error {
+	if q.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	return nil
+}
+
+// GetUserHistoryHandler handles user history retrieval
+type GetUserHistoryHandler struct {
+	aggRepo *eventstore.AggregateRepository
+}
+
+func NewGetUserHistoryHandler(aggRepo *eventstore.AggregateRepository) *GetUserHistoryHandler {
+	return &GetUserHistoryHandler{aggRepo: aggRepo}
+}
+
+func (h *GetUserHistoryHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
+	q := query.(*GetUserHistoryQuery)
+	return h.aggRepo.GetHistory(ctx, eventstore.AggregateTypeUser, q.UserID, q.Cursor, q.PageSize)
+}
 
-	// Get events from event store
-	events, err := h.eventStore.GetEvents(ctx, eventstore.AggregateTypeUser, q.UserID)
+// GetUserAsOfQuery rebuilds a user as it was at a version or point in time
+// Exactly one of Version and AsOf must be set
+type GetUserAsOfQuery struct {
+	cqrs.BaseQuery
+	UserID  string     `json:"user_id"`
+	Version *int64     `json:"version,omitempty"`
+	AsOf    *time.Time `json:"as_of,omitempty"`
+}
+
+func (q *GetUserAsOfQuery) QueryName() string {
+	return "get_user_as_of"
+}
+
+func (q *GetUserAsOfQuery) Validate() error {
+	if q.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	if (q.Version == nil) == (q.AsOf == nil) {
+		return errors.New("exactly one of version or as_of is required")
+	}
+	if q.Version != nil && *q.Version < 0 {
+		return errors.New("version must not be negative")
+	}
+	return nil
+}
+
+// GetUserAsOfHandler handles point-in-time user retrieval
+type GetUserAsOfHandler struct {
+	aggRepo *eventstore.AggregateRepository
+}
+
+func NewGetUserAsOfHandler(aggRepo *eventstore.AggregateRepository) *GetUserAsOfHandler {
+	return &GetUserAsOfHandler{aggRepo: aggRepo}
+}
+
+func (h *GetUserAsOfHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
+	q := query.(*GetUserAsOfQuery)
+
+	user := aggregate.NewUserAggregate(q.UserID)
+
+	var err error
+	if q.Version != nil {
+		err = h.aggRepo.LoadAtVersion(ctx, user, *q.Version)
+	} else {
+		err = h.aggRepo.LoadAsOf(ctx, user, *q.AsOf)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
+		if errors.Is(err, eventstore.ErrAggregateNotFound) {
+			return nil, ErrUserNotFoundAtPoint
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
 	}
 
-	if len(events) == 0 {
-		return nil, errors.New("user not found")
+	return user, nil
+}
+
+// GetRebuildStatusQuery retrieves the progress of a read model's latest rebuild
+type GetRebuildStatusQuery struct {
+	cqrs.BaseQuery
+	Projector string `json:"projector"`
+}
+
+func (q *GetRebuildStatusQuery) QueryName() string {
+	return "get_rebuild_status"
+}
+
+func (q *GetRebuildStatusQuery) Validate() error {
+	if q.Projector == "" {
+		return errors.New("projector is required")
 	}
+	return nil
+}
+
+// GetRebuildStatusHandler handles rebuild status retrieval
+type GetRebuildStatusHandler struct {
+	rebuilder *cqrs.ProjectionRebuilder
+}
 
-	// Reconstruct aggregate from events
-	if err := user.LoadFromHistory(events); err != nil {
-		return nil, fmt.Errorf("failed to load user from history: %w", err)
+func NewGetRebuildStatusHandler(rebuilder *cqrs.ProjectionRebuilder) *GetRebuildStatusHandler {
+	return &GetRebuildStatusHandler{rebuilder: rebuilder}
+}
+
+// Handle returns the latest *cqrs.RebuildState, or nil if the read model was never rebuilt
+func (h *GetRebuildStatusHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
+	q := query.(*GetRebuildStatusQuery)
+
+	state, err := h.rebuilder.Status(ctx, q.Projector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rebuild status: %w", err)
 	}
 
-	return &PasswordHashResult{
-		PasswordHash: user.PasswordHash,
-	}, nil
+	return state, nil
 }
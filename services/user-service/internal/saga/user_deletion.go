@@ -0,0 +1,88 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	"toxictoast/services/user-service/internal/command"
+)
+
+// UserDeletionSagaType identifies the user deletion saga
+const UserDeletionSagaType = "user_deletion"
+
+// eraseTimeout bounds how long the saga waits for the user's personal data to be erased
+const eraseTimeout = 10 * time.Minute
+
+// RoleLister lists the roles assigned to a user
+type RoleLister interface {
+	ListUserRoleIDs(ctx context.Context, userID string) ([]string, error)
+}
+
+// NewUserDeletionSaga creates the saga cleaning up after a deleted user
+//
+// It revokes the user's roles in auth-service and, when erasure is enabled, erases the
+// user's personal data. If erasure fails or times out the revoked roles are restored
+//
+// Blog comments and foodfolio data are out of scope: comments only carry a free-text
+// author name and email, and foodfolio items have no owner, so neither service stores
+// anything keyed by user ID. They get a step once they reference users
+func NewUserDeletionSaga(roles RoleLister, eraseEnabled bool) *cqrs.SagaDefinition {
+	steps := []cqrs.SagaStep{
+		{
+			Name: "revoke_roles",
+			Command: func(ctx context.Context, saga *cqrs.SagaState) (cqrs.Command, error) {
+				// Remember the roles on the first attempt, so they can be restored
+				var roleIDs []string
+				found, err := saga.GetData("role_ids", &roleIDs)
+				if err != nil {
+					return nil, err
+				}
+				if !found {
+					if roleIDs, err = roles.ListUserRoleIDs(ctx, saga.CorrelationID); err != nil {
+						return nil, err
+					}
+					if err := saga.SetData("role_ids", roleIDs); err != nil {
+						return nil, err
+					}
+				}
+
+				return &command.RevokeUserRolesCommand{
+					BaseCommand: cqrs.BaseCommand{AggregateID: saga.CorrelationID},
+					RoleIDs:     roleIDs,
+				}, nil
+			},
+			Compensation: func(ctx context.Context, saga *cqrs.SagaState) (cqrs.Command, error) {
+				var roleIDs []string
+				if _, err := saga.GetData("role_ids", &roleIDs); err != nil {
+					return nil, err
+				}
+
+				return &command.RestoreUserRolesCommand{
+					BaseCommand: cqrs.BaseCommand{AggregateID: saga.CorrelationID},
+					RoleIDs:     roleIDs,
+				}, nil
+			},
+		},
+	}
+
+	if eraseEnabled {
+		steps = append(steps, cqrs.SagaStep{
+			Name: "erase_personal_data",
+			Command: func(ctx context.Context, saga *cqrs.SagaState) (cqrs.Command, error) {
+				return &command.EraseUserCommand{
+					BaseCommand: cqrs.BaseCommand{AggregateID: saga.CorrelationID},
+				}, nil
+			},
+			CompletedBy: eventstore.EventTypeUserErased,
+			Timeout:     eraseTimeout,
+		})
+	}
+
+	return &cqrs.SagaDefinition{
+		Type:      UserDeletionSagaType,
+		StartedBy: eventstore.EventTypeUserDeleted,
+		Steps:     steps,
+	}
+}
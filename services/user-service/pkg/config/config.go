@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 )
 
@@ -10,7 +12,36 @@ type Config struct {
 	Port        int
 	GRPCPort    int
 	Database    sharedconfig.DatabaseConfig
+	Server      sharedconfig.ServerConfig
 	Kafka       sharedconfig.KafkaConfig
+	Keycloak    sharedconfig.KeycloakConfig
+	AuthEnabled bool
+	Projection  ProjectionConfig
+	PII         PIIConfig
+	Saga        SagaConfig
+
+	// AuthServiceAddr is the auth-service gRPC address used by the user deletion saga
+	AuthServiceAddr string
+}
+
+// ProjectionConfig holds read model projection settings
+type ProjectionConfig struct {
+	PollInterval time.Duration
+	ListenNotify bool
+}
+
+// PIIConfig holds personal data encryption settings
+type PIIConfig struct {
+	// MasterKey is the base64 encoded 32 byte key wrapping the per-user data keys
+	// Personal data is stored unencrypted and can't be erased when it is empty
+	MasterKey string
+}
+
+// SagaConfig holds saga manager settings
+type SagaConfig struct {
+	MaxAttempts  int
+	RetryDelay   time.Duration
+	PollInterval time.Duration
 }
 
 // LoadConfig loads configuration from environment variables
@@ -22,7 +53,23 @@ func LoadConfig() (*Config, error) {
 		Port:        sharedconfig.GetEnvAsInt("PORT", 8080),
 		GRPCPort:    sharedconfig.GetEnvAsInt("GRPC_PORT", 9090),
 		Database:    sharedconfig.LoadDatabaseConfig(),
+		Server:      sharedconfig.LoadServerConfig(),
 		Kafka:       sharedconfig.LoadKafkaConfig(),
+		Keycloak:    sharedconfig.LoadKeycloakConfig(),
+		AuthEnabled: sharedconfig.GetEnvAsBool("AUTH_ENABLED", true),
+		Projection: ProjectionConfig{
+			PollInterval: sharedconfig.GetEnvAsDuration("PROJECTION_POLL_INTERVAL", "1s"),
+			ListenNotify: sharedconfig.GetEnvAsBool("PROJECTION_LISTEN_NOTIFY", true),
+		},
+		PII: PIIConfig{
+			MasterKey: sharedconfig.GetEnv("PII_MASTER_KEY", ""),
+		},
+		Saga: SagaConfig{
+			MaxAttempts:  sharedconfig.GetEnvAsInt("SAGA_MAX_ATTEMPTS", 5),
+			RetryDelay:   sharedconfig.GetEnvAsDuration("SAGA_RETRY_DELAY", "30s"),
+			PollInterval: sharedconfig.GetEnvAsDuration("SAGA_POLL_INTERVAL", "5s"),
+		},
+		AuthServiceAddr: sharedconfig.GetEnv("AUTH_SERVICE_ADDR", "auth-service:9090"),
 	}
 
 	return cfg, nil
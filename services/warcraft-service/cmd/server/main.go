@@ -18,11 +18,14 @@ import (
 	"google.golang.org/grpc/reflection"
 	"gorm.io/gorm"
 
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/kafka"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	pb "toxictoast/services/warcraft-service/api/proto"
 	"toxictoast/services/warcraft-service/internal/command"
@@ -59,6 +62,18 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("warcraft-service")); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("warcraft-service"))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize logger
 	logger.Init()
 
@@ -101,16 +116,16 @@ func main() {
 	}
 
 	// Initialize Kafka producer
-	var kafkaProducer *kafka.Producer
+	var kafkaProducer kafka.Publisher
 	if len(cfg.KafkaBrokers) > 0 && cfg.KafkaBrokers[0] != "" {
-		var err error
-		kafkaProducer, err = kafka.NewProducer(cfg.KafkaBrokers)
+		producer, err := kafka.NewProducerWithOptions(cfg.KafkaBrokers, kafka.ProducerOptions{Source: "warcraft-service"})
 		if err != nil {
 			log.Printf("Warning: Failed to initialize Kafka producer: %v", err)
 			log.Printf("Service will continue without event publishing")
 		} else {
 			log.Printf("Kafka producer initialized with brokers: %v", cfg.KafkaBrokers)
-			defer kafkaProducer.Close()
+			kafkaProducer = producer
+			defer producer.Close()
 		}
 	} else {
 		log.Printf("Kafka brokers not configured - event publishing disabled")
@@ -183,6 +198,13 @@ func main() {
 	// Initialize Query Bus
 	queryBus := cqrs.NewQueryBus()
 
+	// Install the shared command/query middleware pipeline
+	serviceMetrics := metrics.New("warcraft-service")
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: "warcraft-service",
+		Metrics:     serviceMetrics,
+	})
+
 	// Register Character Query Handlers (4 queries)
 	queryBus.RegisterHandler("get_character", query.NewGetCharacterHandler(characterRepo))
 	queryBus.RegisterHandler("list_characters", query.NewListCharactersHandler(characterRepo))
@@ -230,7 +252,7 @@ func main() {
 	log.Printf("Background jobs initialized")
 
 	// Setup gRPC server
-	grpcServer := setupGRPCServer(characterHandler, guildHandler)
+	grpcServer := setupGRPCServer(cfg, serviceMetrics, characterHandler, guildHandler)
 
 	// Start gRPC server
 	go func() {
@@ -245,7 +267,7 @@ func main() {
 	}()
 
 	// Setup HTTP server for health checks
-	httpServer := setupHTTPServer(cfg)
+	httpServer := setupHTTPServer(cfg, serviceMetrics)
 
 	// Start HTTP server
 	go func() {
@@ -282,23 +304,14 @@ func main() {
 	log.Println("Servers stopped")
 }
 
-func setupGRPCServer(characterHandler *grpcHandler.CharacterHandler, guildHandler *grpcHandler.GuildHandler) *grpc.Server {
-	// Setup interceptors
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
-
-	// Create server options with chained interceptors
-	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	}
-
-	// Create gRPC server with interceptors
-	server := grpc.NewServer(opts...)
+func setupGRPCServer(cfg *config.Config, serviceMetrics *metrics.Metrics, characterHandler *grpcHandler.CharacterHandler, guildHandler *grpcHandler.GuildHandler) *grpc.Server {
+	// Create gRPC server with the standard interceptor chain
+	server := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:    "warcraft-service",
+		Metrics:        serviceMetrics,
+		DefaultTimeout: cfg.Server.GRPCTimeout,
+		MaxTimeout:     cfg.Server.GRPCMaxTimeout,
+	})
 
 	// Register services
 	pb.RegisterCharacterServiceServer(server, characterHandler)
@@ -310,7 +323,7 @@ func setupGRPCServer(characterHandler *grpcHandler.CharacterHandler, guildHandle
 	return server
 }
 
-func setupHTTPServer(cfg *config.Config) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceMetrics *metrics.Metrics) *http.Server {
 	router := mux.NewRouter()
 
 	// Health check endpoints
@@ -318,6 +331,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	router.HandleFunc("/health/ready", readinessHandler).Methods("GET")
 	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
 
+	// Prometheus metrics endpoint
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
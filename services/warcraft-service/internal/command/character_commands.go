@@ -108,7 +108,7 @@ type CreateCharacterHandler struct {
 	factionRepo   repository.FactionRepository
 	guildRepo     repository.GuildRepository
 	blizzardClient *blizzard.Client
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewCreateCharacterHandler(
@@ -119,7 +119,7 @@ func NewCreateCharacterHandler(
 	factionRepo repository.FactionRepository,
 	guildRepo repository.GuildRepository,
 	blizzardClient *blizzard.Client,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateCharacterHandler {
 	return &CreateCharacterHandler{
 		repo:          repo,
@@ -367,7 +367,7 @@ type DeleteCharacterHandler struct {
 	detailsRepo   repository.CharacterDetailsRepository
 	equipmentRepo repository.CharacterEquipmentRepository
 	statsRepo     repository.CharacterStatsRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteCharacterHandler(
@@ -375,7 +375,7 @@ func NewDeleteCharacterHandler(
 	detailsRepo repository.CharacterDetailsRepository,
 	equipmentRepo repository.CharacterEquipmentRepository,
 	statsRepo repository.CharacterStatsRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteCharacterHandler {
 	return &DeleteCharacterHandler{
 		repo:          repo,
@@ -431,7 +431,7 @@ type RefreshCharacterHandler struct {
 	factionRepo   repository.FactionRepository
 	guildRepo     repository.GuildRepository
 	blizzardClient *blizzard.Client
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewRefreshCharacterHandler(
@@ -442,7 +442,7 @@ func NewRefreshCharacterHandler(
 	factionRepo repository.FactionRepository,
 	guildRepo repository.GuildRepository,
 	blizzardClient *blizzard.Client,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *RefreshCharacterHandler {
 	return &RefreshCharacterHandler{
 		repo:          repo,
@@ -103,14 +103,14 @@ type CreateGuildHandler struct {
 	repo           repository.GuildRepository
 	factionRepo    repository.FactionRepository
 	blizzardClient *blizzard.Client
-	kafkaProducer  *kafka.Producer
+	kafkaProducer  kafka.Publisher
 }
 
 func NewCreateGuildHandler(
 	repo repository.GuildRepository,
 	factionRepo repository.FactionRepository,
 	blizzardClient *blizzard.Client,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *CreateGuildHandler {
 	return &CreateGuildHandler{
 		repo:           repo,
@@ -227,12 +227,12 @@ func (h *UpdateGuildHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 // DeleteGuildHandler handles guild deletion
 type DeleteGuildHandler struct {
 	repo          repository.GuildRepository
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewDeleteGuildHandler(
 	repo repository.GuildRepository,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *DeleteGuildHandler {
 	return &DeleteGuildHandler{
 		repo:          repo,
@@ -276,14 +276,14 @@ type RefreshGuildHandler struct {
 	repo           repository.GuildRepository
 	factionRepo    repository.FactionRepository
 	blizzardClient *blizzard.Client
-	kafkaProducer  *kafka.Producer
+	kafkaProducer  kafka.Publisher
 }
 
 func NewRefreshGuildHandler(
 	repo repository.GuildRepository,
 	factionRepo repository.FactionRepository,
 	blizzardClient *blizzard.Client,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *RefreshGuildHandler {
 	return &RefreshGuildHandler{
 		repo:           repo,
@@ -180,14 +180,14 @@ type GetCharacterEquipmentHandler struct {
 	characterRepo repository.CharacterRepository
 	equipmentRepo repository.CharacterEquipmentRepository
 	blizzardClient *blizzard.Client
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewGetCharacterEquipmentHandler(
 	characterRepo repository.CharacterRepository,
 	equipmentRepo repository.CharacterEquipmentRepository,
 	blizzardClient *blizzard.Client,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *GetCharacterEquipmentHandler {
 	return &GetCharacterEquipmentHandler{
 		characterRepo: characterRepo,
@@ -244,14 +244,14 @@ type GetCharacterStatsHandler struct {
 	characterRepo repository.CharacterRepository
 	statsRepo     repository.CharacterStatsRepository
 	blizzardClient *blizzard.Client
-	kafkaProducer *kafka.Producer
+	kafkaProducer kafka.Publisher
 }
 
 func NewGetCharacterStatsHandler(
 	characterRepo repository.CharacterRepository,
 	statsRepo repository.CharacterStatsRepository,
 	blizzardClient *blizzard.Client,
-	kafkaProducer *kafka.Producer,
+	kafkaProducer kafka.Publisher,
 ) *GetCharacterStatsHandler {
 	return &GetCharacterStatsHandler{
 		characterRepo: characterRepo,
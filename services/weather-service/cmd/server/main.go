@@ -10,11 +10,15 @@ import (
 	"syscall"
 	"time"
 
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 	pb "toxictoast/services/weather-service/api/proto"
 	grpcHandler "toxictoast/services/weather-service/internal/handler/grpc"
 	"toxictoast/services/weather-service/internal/query"
@@ -29,6 +33,18 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig(cfg.ServiceName)); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig(cfg.ServiceName))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
 	log.Printf("Environment: %s", cfg.Environment)
 
 	// Initialize OpenMeteo client
@@ -37,6 +53,11 @@ func main() {
 	// Initialize CQRS buses
 	log.Println("Initializing CQRS buses...")
 	queryBus := cqrs.NewQueryBus()
+	serviceMetrics := metrics.New(cfg.ServiceName)
+	cqrs.UseDefaultPipeline(nil, queryBus, cqrs.PipelineConfig{
+		ServiceName: cfg.ServiceName,
+		Metrics:     serviceMetrics,
+	})
 	log.Println("CQRS buses initialized")
 
 	// Register query handlers
@@ -50,8 +71,13 @@ func main() {
 	weatherHandler := grpcHandler.NewWeatherHandler(queryBus, version)
 	log.Println("gRPC handlers initialized")
 
-	// Create gRPC server
-	grpcServer := grpc.NewServer()
+	// Create gRPC server with the standard interceptor chain
+	grpcServer := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:    cfg.ServiceName,
+		Metrics:        serviceMetrics,
+		DefaultTimeout: cfg.Server.GRPCTimeout,
+		MaxTimeout:     cfg.Server.GRPCMaxTimeout,
+	})
 
 	// Register services
 	log.Println("Registering gRPC services...")
@@ -70,6 +96,8 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
+	httpMux.Handle("/metrics", serviceMetrics.Handler())
+	httpMux.Handle("/admin/log-level", logger.LevelHandler())
 
 	httpServer := &http.Server{
 		Addr:    ":" + cfg.Port,
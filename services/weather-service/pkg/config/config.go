@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/joho/godotenv"
+
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 )
 
 type Config struct {
@@ -12,6 +14,7 @@ type Config struct {
 	Environment string
 	Port        string
 	GRPCPort    string
+	Server      sharedconfig.ServerConfig
 }
 
 func Load() *Config {
@@ -25,6 +28,7 @@ func Load() *Config {
 		Environment: getEnv("ENVIRONMENT", "development"),
 		Port:        getEnv("PORT", "8080"),
 		GRPCPort:    getEnv("GRPC_PORT", "9090"),
+		Server:      sharedconfig.LoadServerConfig(),
 	}
 
 	return cfg
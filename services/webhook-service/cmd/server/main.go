@@ -13,14 +13,16 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"gorm.io/gorm"
 
+	sharedconfig "github.com/toxictoast/toxictoastgo/shared/config"
 	"github.com/toxictoast/toxictoastgo/shared/cqrs"
 	"github.com/toxictoast/toxictoastgo/shared/database"
 	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
 	"github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 
 	pb "toxictoast/services/webhook-service/api/proto"
 	"toxictoast/services/webhook-service/internal/command"
@@ -60,8 +62,21 @@ func main() {
 	cfg := config.Load()
 	logger.Info(fmt.Sprintf("Loaded configuration: gRPC port %s, HTTP port %s, %d Kafka topics", cfg.GRPCPort, cfg.Port, len(cfg.Kafka.Topics)))
 
+	// Initialize structured logging
+	if err := logger.Setup(sharedconfig.LoadLoggingConfig("webhook-service")); err != nil {
+		logger.Error(fmt.Sprintf("Failed to initialize logging: %v", err))
+		os.Exit(1)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), sharedconfig.LoadTracingConfig("webhook-service"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to initialize tracing: %v", err))
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize database
-	var err error
 	db, err = database.Connect(cfg.Database)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to database: %v", err))
@@ -117,6 +132,13 @@ func main() {
 	// Initialize CQRS buses
 	commandBus := cqrs.NewCommandBus()
 	queryBus := cqrs.NewQueryBus()
+
+	// Install the shared command/query middleware pipeline
+	serviceMetrics := metrics.New("webhook-service")
+	cqrs.UseDefaultPipeline(commandBus, queryBus, cqrs.PipelineConfig{
+		ServiceName: "webhook-service",
+		Metrics:     serviceMetrics,
+	})
 	logger.Info("CQRS buses initialized")
 
 	// Register command handlers
@@ -144,7 +166,7 @@ func main() {
 	logger.Info("Query handlers registered")
 
 	// Initialize Kafka consumer
-	kafkaConsumer := consumer.NewKafkaConsumer(
+	kafkaConsumer, err := consumer.NewKafkaConsumer(
 		consumer.Config{
 			Brokers:     cfg.Kafka.Brokers,
 			Topics:      cfg.Kafka.Topics,
@@ -153,6 +175,10 @@ func main() {
 		},
 		commandBus,
 	)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create Kafka consumer: %v", err))
+		os.Exit(1)
+	}
 	logger.Info("Kafka consumer created")
 
 	// Start Kafka consumer
@@ -187,20 +213,13 @@ func main() {
 	deliveryHandler := grpcHandler.NewDeliveryHandler(commandBus, queryBus)
 	logger.Info("gRPC handlers initialized")
 
-	// Create gRPC server with auth interceptors
-	unaryInterceptors := []grpc.UnaryServerInterceptor{
-		sharedgrpc.AuthInterceptor,
-	}
-	streamInterceptors := []grpc.StreamServerInterceptor{
-		sharedgrpc.StreamAuthInterceptor,
-	}
-
-	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(unaryInterceptors...),
-		grpc.ChainStreamInterceptor(streamInterceptors...),
-	}
-
-	grpcServer := grpc.NewServer(opts...)
+	// Create gRPC server with the standard interceptor chain
+	grpcServer := sharedgrpc.NewServer(sharedgrpc.ServerConfig{
+		ServiceName:    "webhook-service",
+		Metrics:        serviceMetrics,
+		DefaultTimeout: cfg.Server.GRPCTimeout,
+		MaxTimeout:     cfg.Server.GRPCMaxTimeout,
+	})
 	pb.RegisterWebhookManagementServiceServer(grpcServer, webhookHandler)
 	pb.RegisterDeliveryServiceServer(grpcServer, deliveryHandler)
 	reflection.Register(grpcServer)
@@ -223,7 +242,7 @@ func main() {
 	}()
 
 	// Setup HTTP server for health checks
-	httpServer := setupHTTPServer(cfg)
+	httpServer := setupHTTPServer(cfg, serviceMetrics)
 
 	// Start HTTP server
 	go func() {
@@ -275,7 +294,7 @@ func main() {
 	logger.Info("Webhook Service stopped")
 }
 
-func setupHTTPServer(cfg *config.Config) *http.Server {
+func setupHTTPServer(cfg *config.Config, serviceMetrics *metrics.Metrics) *http.Server {
 	router := mux.NewRouter()
 
 	// Health check endpoints
@@ -283,6 +302,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	router.HandleFunc("/health/ready", readinessHandler).Methods("GET")
 	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
 
+	// Prometheus metrics endpoint
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+
+	// Log levels per component
+	router.Handle("/admin/log-level", logger.LevelHandler()).Methods("GET", "PUT", "POST", "DELETE")
+
 	return &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
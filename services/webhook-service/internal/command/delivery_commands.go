@@ -148,7 +148,7 @@ func (h *ProcessEventHandler) Handle(ctx context.Context, cmd cqrs.Command) erro
 		}
 
 		// Queue for delivery
-		if err := h.deliveryPool.QueueDelivery(del, webhook); err != nil {
+		if err := h.deliveryPool.QueueDelivery(ctx, del, webhook); err != nil {
 			logger.Error(fmt.Sprintf("Failed to queue delivery %s: %v", del.ID, err))
 			// Mark as failed in database
 			del.Status = domain.DeliveryStatusFailed
@@ -313,7 +313,7 @@ func (h *TestWebhookHandler) Handle(ctx context.Context, cmd cqrs.Command) error
 	}
 
 	// Queue for delivery
-	if err := h.deliveryPool.QueueDelivery(del, webhook); err != nil {
+	if err := h.deliveryPool.QueueDelivery(ctx, del, webhook); err != nil {
 		return fmt.Errorf("failed to queue test delivery: %w", err)
 	}
 
@@ -6,12 +6,19 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"toxictoast/services/webhook-service/internal/domain"
 	"toxictoast/services/webhook-service/internal/repository/interfaces"
 
 	"github.com/toxictoast/toxictoastgo/shared/logger"
 )
 
+var tracer = otel.Tracer("toxictoast/services/webhook-service/internal/delivery")
+
 // Pool manages a pool of delivery workers
 type Pool struct {
 	worker           *Worker
@@ -29,6 +36,8 @@ type Pool struct {
 type QueuedDelivery struct {
 	Delivery *domain.Delivery
 	Webhook  *domain.Webhook
+	// SpanContext is the span of the event that triggered the delivery
+	SpanContext trace.SpanContext
 }
 
 type PoolConfig struct {
@@ -106,10 +115,11 @@ func (p *Pool) Stop() {
 	logger.Info("Delivery pool stopped")
 }
 
-// QueueDelivery queues a delivery for processing
-func (p *Pool) QueueDelivery(delivery *domain.Delivery, webhook *domain.Webhook) error {
+// QueueDelivery queues a delivery for processing, continuing the trace in ctx
+func (p *Pool) QueueDelivery(ctx context.Context, delivery *domain.Delivery, webhook *domain.Webhook) error {
+	queued := &QueuedDelivery{Delivery: delivery, Webhook: webhook, SpanContext: trace.SpanContextFromContext(ctx)}
 	select {
-	case p.deliveryQueue <- &QueuedDelivery{Delivery: delivery, Webhook: webhook}:
+	case p.deliveryQueue <- queued:
 		logger.Info(fmt.Sprintf("Queued delivery %s for webhook %s", delivery.ID, webhook.ID))
 		return nil
 	case <-p.ctx.Done():
@@ -142,7 +152,9 @@ func (p *Pool) deliveryWorker(id int) {
 			logger.Info(fmt.Sprintf("Worker %d processing delivery %s", id, queued.Delivery.ID))
 
 			// Process delivery
-			err := p.worker.DeliverWebhook(p.ctx, queued.Delivery, queued.Webhook)
+			ctx, span := startDeliverySpan(p.ctx, queued)
+			err := p.worker.DeliverWebhook(ctx, queued.Delivery, queued.Webhook)
+			endDeliverySpan(span, err)
 
 			// Update webhook statistics
 			success := err == nil
@@ -182,7 +194,9 @@ func (p *Pool) retryWorker(id int) {
 			logger.Info(fmt.Sprintf("Retry worker %d processing delivery %s", id, queued.Delivery.ID))
 
 			// Process retry
-			err := p.worker.ProcessRetry(p.ctx, queued.Delivery)
+			ctx, span := startDeliverySpan(p.ctx, queued)
+			err := p.worker.ProcessRetry(ctx, queued.Delivery)
+			endDeliverySpan(span, err)
 
 			// Update webhook statistics
 			success := err == nil
@@ -199,6 +213,30 @@ func (p *Pool) retryWorker(id int) {
 	}
 }
 
+// startDeliverySpan starts the span of a delivery as a child of the event that triggered it
+// The pool context is kept as the parent context, so stopping the pool still cancels the delivery
+func startDeliverySpan(ctx context.Context, queued *QueuedDelivery) (context.Context, trace.Span) {
+	if queued.SpanContext.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, queued.SpanContext)
+	}
+	return tracer.Start(ctx, "deliver "+queued.Delivery.EventType,
+		trace.WithAttributes(
+			attribute.String("webhook.id", queued.Webhook.ID),
+			attribute.String("webhook.delivery_id", queued.Delivery.ID),
+			attribute.String("webhook.event_type", queued.Delivery.EventType),
+		),
+	)
+}
+
+// endDeliverySpan records the outcome of a delivery and ends its span
+func endDeliverySpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // retryChecker periodically checks for deliveries that need retry
 func (p *Pool) retryChecker() {
 	defer p.wg.Done()
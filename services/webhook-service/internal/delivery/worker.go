@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
 	"toxictoast/services/webhook-service/internal/domain"
 	"toxictoast/services/webhook-service/internal/repository/interfaces"
 
@@ -52,6 +54,8 @@ func NewWorker(
 	return &Worker{
 		httpClient: &http.Client{
 			Timeout: config.Timeout,
+			// Client spans continue the trace of the delivery and send traceparent to the webhook
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 		deliveryRepo:      deliveryRepo,
 		maxRetries:        config.MaxRetries,
@@ -2,13 +2,11 @@ package auth
 
 import (
 	"context"
+	"crypto"
+	"crypto/ed25519"
 	"crypto/rsa"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"log"
-	"math/big"
-	"net/http"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -18,11 +16,32 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/toxictoast/toxictoastgo/shared/config"
+	sharedjwt "github.com/toxictoast/toxictoastgo/shared/jwt"
+	"github.com/toxictoast/toxictoastgo/shared/logger"
 )
 
+// defaultPublicMethods are reachable without a token in every service
+var defaultPublicMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// KeycloakOptions configures token validation beyond the Keycloak connection
+type KeycloakOptions struct {
+	// PublicMethods are gRPC full methods that don't require a token, in addition to
+	// the health checks; a trailing "*" matches a prefix, e.g. "/blog.BlogService/List*"
+	PublicMethods []string
+
+	// JWKS configures caching and refreshing of the realm's signing keys
+	JWKS sharedjwt.JWKSOptions
+}
+
 type KeycloakAuth struct {
-	config    *config.KeycloakConfig
-	publicKey *rsa.PublicKey
+	config        *config.KeycloakConfig
+	keys          sharedjwt.KeySource
+	issuer        string
+	publicMethods []string
+	cancel        context.CancelFunc
 }
 
 type KeycloakClaims struct {
@@ -42,9 +61,23 @@ type UserContext struct {
 	Roles    []string
 }
 
+// NewKeycloakAuth creates Keycloak authentication with only the health checks public
 func NewKeycloakAuth(cfg *config.KeycloakConfig) (*KeycloakAuth, error) {
+	return NewKeycloakAuthWithOptions(cfg, KeycloakOptions{})
+}
+
+// NewKeycloakAuthWithOptions creates Keycloak authentication with service-specific public methods
+// Signing keys are looked up by kid in the realm's JWKS, which is refreshed in the
+// background and refetched (rate limited) when a token carries an unknown kid, so
+// Keycloak key rollovers don't require a restart. A configured public key pins a single key instead
+func NewKeycloakAuthWithOptions(cfg *config.KeycloakConfig, opts KeycloakOptions) (*KeycloakAuth, error) {
 	auth := &KeycloakAuth{
-		config: cfg,
+		config:        cfg,
+		issuer:        cfg.Issuer,
+		publicMethods: append(append([]string{}, defaultPublicMethods...), opts.PublicMethods...),
+	}
+	if auth.issuer == "" {
+		auth.issuer = fmt.Sprintf("%s/realms/%s", strings.TrimSuffix(cfg.URL, "/"), cfg.Realm)
 	}
 
 	// If public key is provided in config, use it
@@ -53,75 +86,53 @@ func NewKeycloakAuth(cfg *config.KeycloakConfig) (*KeycloakAuth, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse public key: %w", err)
 		}
-		auth.publicKey = publicKey
+		auth.keys = staticKey{key: publicKey}
 		log.Println("Keycloak auth initialized with provided public key")
-	} else {
-		// Fetch public key from Keycloak
-		publicKey, err := auth.fetchPublicKey()
-		if err != nil {
-			log.Printf("Warning: Failed to fetch Keycloak public key: %v", err)
-			log.Println("Authentication will fail until public key is available")
-		} else {
-			auth.publicKey = publicKey
-			log.Println("Keycloak auth initialized with fetched public key")
-		}
-	}
-
-	return auth, nil
-}
-
-// fetchPublicKey fetches the public key from Keycloak's JWKS endpoint
-func (k *KeycloakAuth) fetchPublicKey() (*rsa.PublicKey, error) {
-	url := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/certs", k.config.URL, k.config.Realm)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+		return auth, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	// Fetch public keys from Keycloak's JWKS endpoint
+	jwks := sharedjwt.NewJWKSClientWithOptions(auth.jwksURL(), opts.JWKS)
+	if err := jwks.Refresh(); err != nil {
+		log.Printf("Warning: Failed to fetch Keycloak public keys: %v", err)
+		log.Println("Keys will be fetched again with the next token")
+	} else {
+		log.Println("Keycloak auth initialized with fetched public keys")
 	}
 
-	var jwks struct {
-		Keys []struct {
-			Kid string `json:"kid"`
-			Kty string `json:"kty"`
-			Alg string `json:"alg"`
-			Use string `json:"use"`
-			N   string `json:"n"`
-			E   string `json:"e"`
-		} `json:"keys"`
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	jwks.StartRefresh(ctx)
+	auth.keys = jwks
+	auth.cancel = cancel
 
-	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
-		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
-	}
+	return auth, nil
+}
 
-	if len(jwks.Keys) == 0 {
-		return nil, fmt.Errorf("no keys found in JWKS")
+// Close stops the background key refresh
+func (k *KeycloakAuth) Close() {
+	if k.cancel != nil {
+		k.cancel()
 	}
+}
 
-	// Use the first RSA key
-	key := jwks.Keys[0]
-
-	return parseRSAPublicKey(key.N, key.E)
+// jwksURL returns the realm's JWKS endpoint
+func (k *KeycloakAuth) jwksURL() string {
+	return fmt.Sprintf("%s/realms/%s/protocol/openid-connect/certs", strings.TrimSuffix(k.config.URL, "/"), k.config.Realm)
 }
 
 // ValidateToken validates a JWT token and extracts user information
+// Besides the signature, the issuer and (when configured) the audience must match
 func (k *KeycloakAuth) ValidateToken(tokenString string) (*UserContext, error) {
-	if k.publicKey == nil {
-		return nil, fmt.Errorf("public key not available")
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "EdDSA"}),
+		jwt.WithIssuer(k.issuer),
+		jwt.WithExpirationRequired(),
+	}
+	if k.config.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(k.config.Audience))
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &KeycloakClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return k.publicKey, nil
-	})
-
+	token, err := jwt.ParseWithClaims(tokenString, &KeycloakClaims{}, k.keyFunc, parserOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
@@ -146,11 +157,56 @@ func (k *KeycloakAuth) ValidateToken(tokenString string) (*UserContext, error) {
 	}, nil
 }
 
+// keyFunc returns the realm key matching the token's kid and algorithm
+func (k *KeycloakAuth) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, err := k.keys.PublicKey(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+	case *jwt.SigningMethodEd25519:
+		if edKey, ok := key.(ed25519.PublicKey); ok {
+			return edKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+}
+
+// isPublicMethod reports whether a gRPC method may be called without a token
+func (k *KeycloakAuth) isPublicMethod(method string) bool {
+	for _, public := range k.publicMethods {
+		if prefix, ok := strings.CutSuffix(public, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		} else if method == public {
+			return true
+		}
+	}
+	return false
+}
+
+// staticKey is a KeySource with a single configured key
+type staticKey struct {
+	key crypto.PublicKey
+}
+
+// PublicKey returns the configured key for every kid
+func (s staticKey) PublicKey(kid string) (crypto.PublicKey, error) {
+	return s.key, nil
+}
+
 // UnaryInterceptor is a gRPC unary interceptor for JWT authentication
 func (k *KeycloakAuth) UnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// Skip authentication for health checks and public endpoints
-		if isPublicEndpoint(info.FullMethod) {
+		if k.isPublicMethod(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
@@ -171,7 +227,7 @@ func (k *KeycloakAuth) UnaryInterceptor() grpc.UnaryServerInterceptor {
 		}
 
 		// Add user context to the request context
-		ctx = context.WithValue(ctx, userContextKey, userContext)
+		ctx = WithUserContext(ctx, userContext)
 
 		return handler(ctx, req)
 	}
@@ -181,7 +237,7 @@ func (k *KeycloakAuth) UnaryInterceptor() grpc.UnaryServerInterceptor {
 func (k *KeycloakAuth) StreamInterceptor() grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		// Skip authentication for public endpoints
-		if isPublicEndpoint(info.FullMethod) {
+		if k.isPublicMethod(info.FullMethod) {
 			return handler(srv, ss)
 		}
 
@@ -202,7 +258,7 @@ func (k *KeycloakAuth) StreamInterceptor() grpc.StreamServerInterceptor {
 		}
 
 		// Create a new context with user information
-		ctx := context.WithValue(ss.Context(), userContextKey, userContext)
+		ctx := WithUserContext(ss.Context(), userContext)
 		wrappedStream := &wrappedServerStream{ServerStream: ss, ctx: ctx}
 
 		return handler(srv, wrappedStream)
@@ -215,6 +271,12 @@ type contextKey string
 
 const userContextKey contextKey = "user"
 
+// WithUserContext stores a user verified by Keycloak in ctx and adds its ID to log records
+func WithUserContext(ctx context.Context, user *UserContext) context.Context {
+	ctx = logger.WithUserID(ctx, user.UserID)
+	return context.WithValue(ctx, userContextKey, user)
+}
+
 func GetUserContext(ctx context.Context) (*UserContext, error) {
 	user, ok := ctx.Value(userContextKey).(*UserContext)
 	if !ok {
@@ -251,52 +313,17 @@ func extractRoles(claims *KeycloakClaims) []string {
 	return roles
 }
 
-func isPublicEndpoint(method string) bool {
-	publicEndpoints := []string{
-		"/grpc.health.v1.Health/Check",
-		"/blog.BlogService/ListPosts",
-		"/blog.BlogService/GetPost",
-		"/blog.BlogService/ListCategories",
-		"/blog.BlogService/GetCategory",
-		"/blog.BlogService/ListTags",
-		"/blog.BlogService/GetTag",
-		"/blog.BlogService/ListComments",
-		"/blog.BlogService/GetComment",
-	}
-
-	for _, endpoint := range publicEndpoints {
-		if method == endpoint {
-			return true
-		}
+// parsePublicKey parses a PEM public key, or the base64 DER key shown in the Keycloak realm settings
+func parsePublicKey(keyStr string) (crypto.PublicKey, error) {
+	if !strings.Contains(keyStr, "-----BEGIN") {
+		keyStr = "-----BEGIN PUBLIC KEY-----\n" + keyStr + "\n-----END PUBLIC KEY-----"
 	}
-	return false
-}
 
-func parsePublicKey(keyStr string) (*rsa.PublicKey, error) {
-	// Try to parse as PEM format first
-	// If that fails, try base64 decode and parse as raw key
-	// This is a simplified implementation - you may need to adjust based on your key format
-	return nil, fmt.Errorf("public key parsing not implemented - please provide via JWKS endpoint")
-}
-
-func parseRSAPublicKey(nStr, eStr string) (*rsa.PublicKey, error) {
-	nBytes, err := base64.RawURLEncoding.DecodeString(nStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode N: %w", err)
-	}
-
-	eBytes, err := base64.RawURLEncoding.DecodeString(eStr)
+	key, err := sharedjwt.ParseSigningKeyPEM([]byte(keyStr))
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode E: %w", err)
+		return nil, err
 	}
-
-	n := new(big.Int).SetBytes(nBytes)
-	e := new(big.Int).SetBytes(eBytes).Int64()
-
-	return &rsa.PublicKey{
-		N: n,
-		E: int(e),
-	}, nil
+	return key.PublicKey, nil
 }
 
 // wrappedServerStream wraps a grpc.ServerStream with a custom context
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/toxictoast/toxictoastgo/shared/config"
+	sharedjwt "github.com/toxictoast/toxictoastgo/shared/jwt"
+)
+
+// newTestRealm serves a key ring as the JWKS of realm "test"
+func newTestRealm(t *testing.T, ring *sharedjwt.KeyRing) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/realms/test/protocol/openid-connect/certs", sharedjwt.JWKSHandler(ring))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signTestToken(t *testing.T, key *sharedjwt.SigningKey, issuer string, audience string) string {
+	method := jwt.SigningMethod(jwt.SigningMethodRS256)
+	if key.Algorithm == sharedjwt.AlgorithmEdDSA {
+		method = jwt.SigningMethodEdDSA
+	}
+
+	token := jwt.NewWithClaims(method, &KeycloakClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		PreferredUsername: "toast",
+	})
+	token.Header["kid"] = key.ID
+
+	signed, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestKeycloakValidateToken(t *testing.T) {
+	key, _ := sharedjwt.GenerateSigningKey(sharedjwt.AlgorithmRS256)
+	ring := sharedjwt.NewKeyRing(key)
+	server := newTestRealm(t, ring)
+
+	keycloak, err := NewKeycloakAuthWithOptions(
+		&config.KeycloakConfig{URL: server.URL, Realm: "test", Audience: "toxictoast"},
+		KeycloakOptions{JWKS: sharedjwt.JWKSOptions{MinRefreshInterval: time.Nanosecond}},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create Keycloak auth: %v", err)
+	}
+	defer keycloak.Close()
+
+	issuer := server.URL + "/realms/test"
+
+	// Keycloak rolls its key over after startup
+	rotated, _ := sharedjwt.GenerateSigningKey(sharedjwt.AlgorithmRS256)
+	ring.Rotate(rotated)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid token", signTestToken(t, key, issuer, "toxictoast"), false},
+		{"key after rollover", signTestToken(t, rotated, issuer, "toxictoast"), false},
+		{"wrong issuer", signTestToken(t, key, "http://evil.example/realms/test", "toxictoast"), true},
+		{"wrong audience", signTestToken(t, key, issuer, "other-client"), true},
+		{"unknown key", func() string {
+			unknown, _ := sharedjwt.GenerateSigningKey(sharedjwt.AlgorithmRS256)
+			return signTestToken(t, unknown, issuer, "toxictoast")
+		}(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := keycloak.ValidateToken(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got user %+v", user)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if user.UserID != "user-1" || user.Username != "toast" {
+				t.Errorf("Expected user-1/toast, got %s/%s", user.UserID, user.Username)
+			}
+		})
+	}
+}
+
+func TestKeycloakPublicMethods(t *testing.T) {
+	keycloak := &KeycloakAuth{publicMethods: append(defaultPublicMethods, "/blog.BlogService/GetPost", "/blog.BlogService/List*")}
+
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"/grpc.health.v1.Health/Check", true},
+		{"/blog.BlogService/GetPost", true},
+		{"/blog.BlogService/ListTags", true},
+		{"/blog.BlogService/GetPostBySlug", false},
+		{"/blog.BlogService/DeletePost", false},
+	}
+
+	for _, tt := range tests {
+		if got := keycloak.isPublicMethod(tt.method); got != tt.want {
+			t.Errorf("Expected public %v for %s, got %v", tt.want, tt.method, got)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/logger"
+)
+
+// PostgresRevocationStore implements RevocationStore using PostgreSQL
+type PostgresRevocationStore struct {
+	db *sql.DB
+}
+
+// NewPostgresRevocationStore creates a new PostgreSQL revocation store
+func NewPostgresRevocationStore(db *sql.DB) (*PostgresRevocationStore, error) {
+	store := &PostgresRevocationStore{db: db}
+
+	if err := store.createTables(); err != nil {
+		return nil, fmt.Errorf("failed to create revocation tables: %w", err)
+	}
+
+	// Start cleanup goroutine to remove expired revocations
+	go store.cleanupLoop()
+
+	return store, nil
+}
+
+// createTables creates the revocation table
+// kind is "token", "session" or "user"; not_before is only set for users
+func (s *PostgresRevocationStore) createTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS token_revocations (
+		kind VARCHAR(16) NOT NULL,
+		subject VARCHAR(255) NOT NULL,
+		not_before TIMESTAMPTZ,
+		expires_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (kind, subject)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_token_revocations_expires_at ON token_revocations(expires_at);
+	`
+
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// RevokeToken revokes a single token by its jti until it expires
+func (s *PostgresRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	if err := s.revoke(ctx, "token", tokenID, nil, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession revokes all tokens of a login session
+func (s *PostgresRevocationStore) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	if err := s.revoke(ctx, "session", sessionID, nil, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser revokes all tokens of a user issued before notBefore
+func (s *PostgresRevocationStore) RevokeUser(ctx context.Context, userID string, notBefore time.Time, expiresAt time.Time) error {
+	if err := s.revoke(ctx, "user", userID, &notBefore, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke user: %w", err)
+	}
+	return nil
+}
+
+// revoke upserts a revocation without shortening an existing one
+func (s *PostgresRevocationStore) revoke(ctx context.Context, kind, subject string, notBefore *time.Time, expiresAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO token_revocations (kind, subject, not_before, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (kind, subject) DO UPDATE SET
+			not_before = GREATEST(token_revocations.not_before, EXCLUDED.not_before),
+			expires_at = GREATEST(token_revocations.expires_at, EXCLUDED.expires_at)
+	`, kind, subject, notBefore, expiresAt)
+	return err
+}
+
+// IsRevoked checks the token, its session and its user with a single query
+func (s *PostgresRevocationStore) IsRevoked(ctx context.Context, token TokenRef) (bool, error) {
+	var revoked bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM token_revocations
+			WHERE expires_at > NOW() AND (
+				(kind = 'token' AND subject = $1 AND $1 <> '') OR
+				(kind = 'session' AND subject = $2 AND $2 <> '') OR
+				(kind = 'user' AND subject = $3 AND $3 <> '' AND not_before > $4)
+			)
+		)
+	`, token.ID, token.SessionID, token.UserID, token.IssuedAt).Scan(&revoked)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// DeleteExpired removes revocations whose tokens have all expired
+func (s *PostgresRevocationStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM token_revocations WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired revocations: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// cleanupLoop periodically removes expired revocations
+func (s *PostgresRevocationStore) cleanupLoop() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := s.DeleteExpired(context.Background()); err != nil {
+			logger.Component("auth").Warn("failed to delete expired revocations", "error", err)
+		}
+	}
+}
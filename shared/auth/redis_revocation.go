@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// revokeScript stores a revocation without shortening an existing one
+// KEYS[1] is the revocation key
+// ARGV: not-before in unix microseconds (0 for tokens and sessions) and TTL in milliseconds
+var revokeScript = redis.NewScript(`
+local value = ARGV[1]
+local current = redis.call("GET", KEYS[1])
+if current and tonumber(current) > tonumber(value) then
+	value = current
+end
+
+local ttl = tonumber(ARGV[2])
+local remaining = redis.call("PTTL", KEYS[1])
+if remaining > ttl then
+	ttl = remaining
+end
+
+redis.call("SET", KEYS[1], value, "PX", string.format("%.0f", ttl))
+return 1
+`)
+
+// RedisRevocationStore is a RevocationStore shared between replicas through Redis
+// Revocations are stored under "revoked:token:", "revoked:session:" and "revoked:user:"
+// keys that expire together with the tokens they revoke
+type RedisRevocationStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisRevocationStore creates a new Redis revocation store
+func NewRedisRevocationStore(client *redis.Client) *RedisRevocationStore {
+	return &RedisRevocationStore{client: client, prefix: "revoked:"}
+}
+
+// RevokeToken revokes a single token by its jti until it expires
+func (s *RedisRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	if err := s.revoke(ctx, s.prefix+"token:"+tokenID, 0, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession revokes all tokens of a login session
+func (s *RedisRevocationStore) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	if err := s.revoke(ctx, s.prefix+"session:"+sessionID, 0, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser revokes all tokens of a user issued before notBefore
+func (s *RedisRevocationStore) RevokeUser(ctx context.Context, userID string, notBefore time.Time, expiresAt time.Time) error {
+	if err := s.revoke(ctx, s.prefix+"user:"+userID, notBefore.UnixMicro(), expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke user: %w", err)
+	}
+	return nil
+}
+
+// revoke stores a revocation key; revocations that already expired are skipped
+func (s *RedisRevocationStore) revoke(ctx context.Context, key string, notBefore int64, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt).Milliseconds()
+	if ttl <= 0 {
+		return nil
+	}
+	return revokeScript.Run(ctx, s.client, []string{key}, notBefore, ttl).Err()
+}
+
+// IsRevoked checks the token, its session and its user with a single MGET
+func (s *RedisRevocationStore) IsRevoked(ctx context.Context, token TokenRef) (bool, error) {
+	var keys []string
+	userIndex := -1
+
+	if token.ID != "" {
+		keys = append(keys, s.prefix+"token:"+token.ID)
+	}
+	if token.SessionID != "" {
+		keys = append(keys, s.prefix+"session:"+token.SessionID)
+	}
+	if token.UserID != "" {
+		userIndex = len(keys)
+		keys = append(keys, s.prefix+"user:"+token.UserID)
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		if i != userIndex {
+			return true, nil
+		}
+
+		raw, _ := value.(string)
+		notBefore, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("failed to check token revocation: invalid not-before %q", raw)
+		}
+		if token.IssuedAt.Before(time.UnixMicro(notBefore)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTokenRevoked is returned for tokens that have been revoked
+	ErrTokenRevoked = errors.New("token has been revoked")
+
+	// ErrRevocationUnavailable is returned when the revocation store can't be checked
+	// Callers must fail closed, since the token may have been revoked
+	ErrRevocationUnavailable = errors.New("token revocation check unavailable")
+)
+
+// TokenRef identifies a token for revocation checks
+type TokenRef struct {
+	// ID is the token's jti claim
+	ID string
+
+	UserID    string
+	SessionID string
+
+	// IssuedAt is the token's iat claim, compared with the user's not-before time
+	IssuedAt time.Time
+}
+
+// RevocationStore records revoked tokens, sessions and users
+// Entries only need to be kept until the tokens they revoke have expired
+type RevocationStore interface {
+	// RevokeToken revokes a single token by its jti until it expires
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+
+	// RevokeSession revokes all tokens of a login session
+	RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error
+
+	// RevokeUser revokes all tokens of a user issued before notBefore ("log out everywhere")
+	RevokeUser(ctx context.Context, userID string, notBefore time.Time, expiresAt time.Time) error
+
+	// IsRevoked checks the token, its session and its user in a single lookup
+	IsRevoked(ctx context.Context, token TokenRef) (bool, error)
+}
+
+// revocation is a revoked token, session or user
+type revocation struct {
+	notBefore time.Time
+	expiresAt time.Time
+}
+
+// MemoryRevocationStore is a process-local RevocationStore intended for tests and single-replica setups
+type MemoryRevocationStore struct {
+	mu       sync.RWMutex
+	tokens   map[string]time.Time
+	sessions map[string]time.Time
+	users    map[string]revocation
+}
+
+// NewMemoryRevocationStore creates a new in-memory revocation store
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	s := &MemoryRevocationStore{
+		tokens:   make(map[string]time.Time),
+		sessions: make(map[string]time.Time),
+		users:    make(map[string]revocation),
+	}
+
+	// Start cleanup goroutine to remove expired revocations
+	go s.cleanupLoop()
+
+	return s
+}
+
+// RevokeToken revokes a single token by its jti until it expires
+func (s *MemoryRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[tokenID] = later(s.tokens[tokenID], expiresAt)
+	return nil
+}
+
+// RevokeSession revokes all tokens of a login session
+func (s *MemoryRevocationStore) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID] = later(s.sessions[sessionID], expiresAt)
+	return nil
+}
+
+// RevokeUser revokes all tokens of a user issued before notBefore
+func (s *MemoryRevocationStore) RevokeUser(ctx context.Context, userID string, notBefore time.Time, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.users[userID]
+	s.users[userID] = revocation{
+		notBefore: later(current.notBefore, notBefore),
+		expiresAt: later(current.expiresAt, expiresAt),
+	}
+	return nil
+}
+
+// IsRevoked checks the token, its session and its user
+func (s *MemoryRevocationStore) IsRevoked(ctx context.Context, token TokenRef) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	if expiresAt, exists := s.tokens[token.ID]; token.ID != "" && exists && now.Before(expiresAt) {
+		return true, nil
+	}
+	if expiresAt, exists := s.sessions[token.SessionID]; token.SessionID != "" && exists && now.Before(expiresAt) {
+		return true, nil
+	}
+	if user, exists := s.users[token.UserID]; token.UserID != "" && exists && now.Before(user.expiresAt) {
+		return token.IssuedAt.Before(user.notBefore), nil
+	}
+	return false, nil
+}
+
+// Size returns the number of revocations currently stored
+func (s *MemoryRevocationStore) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tokens) + len(s.sessions) + len(s.users)
+}
+
+// cleanupLoop periodically removes expired revocations
+func (s *MemoryRevocationStore) cleanupLoop() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.cleanup()
+	}
+}
+
+// cleanup removes expired revocations
+func (s *MemoryRevocationStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, expiresAt := range s.tokens {
+		if now.After(expiresAt) {
+			delete(s.tokens, id)
+		}
+	}
+	for id, expiresAt := range s.sessions {
+		if now.After(expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	for id, user := range s.users {
+		if now.After(user.expiresAt) {
+			delete(s.users, id)
+		}
+	}
+}
+
+// later returns the later of two times
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
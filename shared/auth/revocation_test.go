@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryRevocationStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewMemoryRevocationStore()
+	store.RevokeToken(ctx, "revoked-token", now.Add(time.Hour))
+	store.RevokeToken(ctx, "expired-token", now.Add(-time.Second))
+	store.RevokeSession(ctx, "revoked-session", now.Add(time.Hour))
+	store.RevokeUser(ctx, "revoked-user", now, now.Add(time.Hour))
+
+	tests := []struct {
+		name  string
+		token TokenRef
+		want  bool
+	}{
+		{"unknown token", TokenRef{ID: "other", UserID: "user", SessionID: "session", IssuedAt: now}, false},
+		{"revoked token", TokenRef{ID: "revoked-token", UserID: "user", IssuedAt: now}, true},
+		{"expired revocation", TokenRef{ID: "expired-token", UserID: "user", IssuedAt: now}, false},
+		{"revoked session", TokenRef{ID: "other", SessionID: "revoked-session", IssuedAt: now}, true},
+		{"user token issued before", TokenRef{ID: "other", UserID: "revoked-user", IssuedAt: now.Add(-time.Minute)}, true},
+		{"user token issued after", TokenRef{ID: "other", UserID: "revoked-user", IssuedAt: now.Add(time.Minute)}, false},
+		{"token without identifiers", TokenRef{IssuedAt: now}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := store.IsRevoked(ctx, tt.token)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if revoked != tt.want {
+				t.Errorf("Expected revoked %v, got %v", tt.want, revoked)
+			}
+		})
+	}
+}
+
+func TestMemoryRevocationStoreKeepsLongestRevocation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewMemoryRevocationStore()
+	store.RevokeUser(ctx, "user", now, now.Add(time.Hour))
+	store.RevokeUser(ctx, "user", now.Add(-time.Hour), now.Add(-time.Minute))
+
+	revoked, _ := store.IsRevoked(ctx, TokenRef{UserID: "user", IssuedAt: now.Add(-time.Second)})
+	if !revoked {
+		t.Errorf("Expected an older revocation not to shorten the current one")
+	}
+}
@@ -28,8 +28,11 @@ type Cache interface {
 
 // New creates a new cache instance based on the config
 func New(config *Config) (Cache, error) {
-	if config.Type == "redis" {
+	switch config.Type {
+	case "redis":
 		return NewRedisCache(config)
+	case "tiered":
+		return NewTieredCache(config)
 	}
 	return NewMemoryCache(config), nil
 }
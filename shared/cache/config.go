@@ -1,10 +1,14 @@
 package cache
 
-import "time"
+import (
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+)
 
 // Config holds the configuration for the cache
 type Config struct {
-	// Type is the cache type ("redis" or "memory")
+	// Type is the cache type ("redis", "memory" or "tiered")
 	Type string
 
 	// Redis configuration
@@ -13,10 +17,25 @@ type Config struct {
 	RedisDB       int
 
 	// Memory cache configuration
-	MaxSize int // Maximum number of items (0 = unlimited)
+	MaxSize           int                   // Maximum number of items (0 = unlimited)
+	MaxBytes          int64                 // Maximum size of keys and values in bytes (0 = unlimited)
+	EvictionPolicy    string                // "lru" (default), "lfu" or "size"
+	NewEvictionPolicy func() EvictionPolicy // Custom eviction policy, overrides EvictionPolicy
+	Shards            int                   // Number of independently locked shards (default 16)
 
 	// Default TTL for cache entries (0 = no expiration)
 	DefaultTTL time.Duration
+
+	// Tiered cache configuration
+	L1TTL               time.Duration // Maximum lifetime of L1 entries (default 1m)
+	InvalidationChannel string        // Redis pub/sub channel for invalidations (default "cache:invalidate")
+
+	// Metrics enables eviction and memory usage metrics of the memory cache and
+	// per-tier hit/miss metrics of the tiered cache when set
+	// Several caches can share one Metrics; Name tells them apart (default "default")
+	Metrics     *metrics.Metrics
+	ServiceName string
+	Name        string
 }
 
 // DefaultConfig returns a config with sensible defaults
@@ -36,3 +55,14 @@ func RedisConfig(addr string) *Config {
 		DefaultTTL: 5 * time.Minute,
 	}
 }
+
+// TieredConfig returns a config for a memory cache in front of a Redis cache
+func TieredConfig(addr string) *Config {
+	return &Config{
+		Type:       "tiered",
+		RedisAddr:  addr,
+		MaxSize:    1000,
+		DefaultTTL: 5 * time.Minute,
+		L1TTL:      time.Minute,
+	}
+}
@@ -0,0 +1,202 @@
+package cache
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// Eviction policy names for Config.EvictionPolicy
+const (
+	EvictionLRU  = "lru"
+	EvictionLFU  = "lfu"
+	EvictionSize = "size"
+)
+
+// EvictionPolicy decides which item a full MemoryCache shard evicts
+// Calls are serialized by the shard lock, implementations don't need to be thread-safe
+type EvictionPolicy interface {
+	// Added is called when a key is stored or overwritten with a value of size bytes
+	Added(key string, size int)
+
+	// Accessed is called on every read of a key
+	Accessed(key string)
+
+	// Removed is called when a key is deleted, expired or evicted
+	Removed(key string)
+
+	// Victim returns the key to evict next
+	Victim() (string, bool)
+}
+
+// newEvictionPolicy returns the policy configured for a shard
+func newEvictionPolicy(config *Config) EvictionPolicy {
+	if config.NewEvictionPolicy != nil {
+		return config.NewEvictionPolicy()
+	}
+
+	switch config.EvictionPolicy {
+	case EvictionLFU:
+		return NewLFUPolicy()
+	case EvictionSize:
+		return NewSizePolicy()
+	default:
+		return NewLRUPolicy()
+	}
+}
+
+// lruPolicy evicts the least recently used key
+type lruPolicy struct {
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+// NewLRUPolicy creates a policy evicting the least recently used key
+func NewLRUPolicy() EvictionPolicy {
+	return &lruPolicy{
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+	}
+}
+
+func (p *lruPolicy) Added(key string, size int) {
+	if element, ok := p.elements[key]; ok {
+		p.order.MoveToFront(element)
+		return
+	}
+	p.elements[key] = p.order.PushFront(key)
+}
+
+func (p *lruPolicy) Accessed(key string) {
+	if element, ok := p.elements[key]; ok {
+		p.order.MoveToFront(element)
+	}
+}
+
+func (p *lruPolicy) Removed(key string) {
+	if element, ok := p.elements[key]; ok {
+		p.order.Remove(element)
+		delete(p.elements, key)
+	}
+}
+
+func (p *lruPolicy) Victim() (string, bool) {
+	element := p.order.Back()
+	if element == nil {
+		return "", false
+	}
+	return element.Value.(string), true
+}
+
+// NewLFUPolicy creates a policy evicting the least frequently used key
+// Ties are broken by evicting the least recently used key
+func NewLFUPolicy() EvictionPolicy {
+	return newHeapPolicy(func(a, b *heapItem) bool {
+		if a.hits != b.hits {
+			return a.hits < b.hits
+		}
+		return a.touched < b.touched
+	})
+}
+
+// NewSizePolicy creates a policy evicting the largest key first, which frees a byte
+// budget with the fewest evictions; ties are broken by evicting the least recently used key
+func NewSizePolicy() EvictionPolicy {
+	return newHeapPolicy(func(a, b *heapItem) bool {
+		if a.size != b.size {
+			return a.size > b.size
+		}
+		return a.touched < b.touched
+	})
+}
+
+// heapItem tracks the usage of a key
+type heapItem struct {
+	key     string
+	size    int
+	hits    int
+	touched uint64
+	index   int
+}
+
+// heapPolicy evicts the smallest item according to less
+type heapPolicy struct {
+	items map[string]*heapItem
+	heap  itemHeap
+	clock uint64
+}
+
+func newHeapPolicy(less func(a, b *heapItem) bool) *heapPolicy {
+	return &heapPolicy{
+		items: make(map[string]*heapItem),
+		heap:  itemHeap{less: less},
+	}
+}
+
+func (p *heapPolicy) Added(key string, size int) {
+	p.clock++
+	if item, ok := p.items[key]; ok {
+		item.size = size
+		item.hits++
+		item.touched = p.clock
+		heap.Fix(&p.heap, item.index)
+		return
+	}
+
+	item := &heapItem{key: key, size: size, hits: 1, touched: p.clock}
+	p.items[key] = item
+	heap.Push(&p.heap, item)
+}
+
+func (p *heapPolicy) Accessed(key string) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+	p.clock++
+	item.hits++
+	item.touched = p.clock
+	heap.Fix(&p.heap, item.index)
+}
+
+func (p *heapPolicy) Removed(key string) {
+	if item, ok := p.items[key]; ok {
+		heap.Remove(&p.heap, item.index)
+		delete(p.items, key)
+	}
+}
+
+func (p *heapPolicy) Victim() (string, bool) {
+	if len(p.heap.items) == 0 {
+		return "", false
+	}
+	return p.heap.items[0].key, true
+}
+
+// itemHeap implements heap.Interface for heapPolicy
+type itemHeap struct {
+	items []*heapItem
+	less  func(a, b *heapItem) bool
+}
+
+func (h itemHeap) Len() int           { return len(h.items) }
+func (h itemHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+
+func (h itemHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *itemHeap) Push(x interface{}) {
+	item := x.(*heapItem)
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *itemHeap) Pop() interface{} {
+	last := len(h.items) - 1
+	item := h.items[last]
+	h.items[last] = nil
+	h.items = h.items[:last]
+	return item
+}
@@ -3,29 +3,62 @@ package cache
 import (
 	"context"
 	"errors"
+	"hash/fnv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const defaultShards = 16
+
 var (
 	ErrNotFound = errors.New("key not found")
 	ErrMaxSize  = errors.New("cache is full")
 )
 
 type cacheItem struct {
-	value      []byte
-	expiresAt  time.Time
-	hasExpiry  bool
-	accessedAt time.Time
+	value     []byte
+	size      int
+	expiresAt time.Time
+	hasExpiry bool
+}
+
+// expired reports whether the item expired at now
+func (item *cacheItem) expired(now time.Time) bool {
+	return item.hasExpiry && now.After(item.expiresAt)
+}
+
+// shard holds a part of the keys behind its own lock
+type shard struct {
+	mu       sync.Mutex
+	items    map[string]*cacheItem
+	policy   EvictionPolicy
+	bytes    int64
+	maxItems int
+	maxBytes int64
 }
 
 // MemoryCache is an in-memory cache implementation
+// Keys are spread over shards with their own lock and eviction policy; MaxSize and
+// MaxBytes are split evenly between the shards
 type MemoryCache struct {
-	items   map[string]*cacheItem
-	mu      sync.RWMutex
+	shards  []*shard
 	config  *Config
 	cleanup *time.Ticker
 	done    chan bool
+
+	items     atomic.Int64
+	bytes     atomic.Int64
+	evictions atomic.Int64
+
+	metrics *collectors
+}
+
+// MemoryStats is a snapshot of a MemoryCache's usage
+type MemoryStats struct {
+	Items     int64
+	Bytes     int64
+	Evictions int64
 }
 
 // NewMemoryCache creates a new in-memory cache
@@ -34,12 +67,30 @@ func NewMemoryCache(config *Config) *MemoryCache {
 		config = DefaultConfig()
 	}
 
+	shardCount := config.Shards
+	if shardCount <= 0 {
+		shardCount = defaultShards
+	}
+
 	mc := &MemoryCache{
-		items:  make(map[string]*cacheItem),
+		shards: make([]*shard, shardCount),
 		config: config,
 		done:   make(chan bool),
 	}
 
+	for i := range mc.shards {
+		mc.shards[i] = &shard{
+			items:    make(map[string]*cacheItem),
+			policy:   newEvictionPolicy(config),
+			maxItems: perShard(config.MaxSize, shardCount),
+			maxBytes: int64(perShard(int(config.MaxBytes), shardCount)),
+		}
+	}
+
+	if config.Metrics != nil {
+		mc.metrics = collectorsFor(config.Metrics)
+	}
+
 	// Start cleanup goroutine
 	mc.cleanup = time.NewTicker(1 * time.Minute)
 	go mc.cleanupExpired()
@@ -47,46 +98,65 @@ func NewMemoryCache(config *Config) *MemoryCache {
 	return mc
 }
 
+// perShard splits a limit between shards, rounding up so the total isn't undercut
+func perShard(limit int, shards int) int {
+	if limit <= 0 {
+		return 0
+	}
+	return (limit + shards - 1) / shards
+}
+
+// shardFor returns the shard holding a key
+func (mc *MemoryCache) shardFor(key string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return mc.shards[h.Sum32()%uint32(len(mc.shards))]
+}
+
 // Get retrieves a value from the cache
 func (mc *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	s := mc.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	item, exists := mc.items[key]
+	item, exists := s.items[key]
 	if !exists {
 		return nil, ErrNotFound
 	}
 
 	// Check expiry
-	if item.hasExpiry && time.Now().After(item.expiresAt) {
+	if item.expired(time.Now()) {
+		mc.remove(s, key, "expired")
 		return nil, ErrNotFound
 	}
 
-	// Update access time
-	item.accessedAt = time.Now()
+	s.policy.Accessed(key)
 
 	return item.value, nil
 }
 
 // Set stores a value in the cache
+// Other items are evicted when the shard exceeds MaxSize or MaxBytes; values larger
+// than a shard's byte budget fail with ErrMaxSize
 func (mc *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
-	mc.mu.Lock()
-	defer mc.mu.Unlock()
+	size := len(key) + len(value)
 
-	// Check max size
-	if mc.config.MaxSize > 0 && len(mc.items) >= mc.config.MaxSize {
-		// Evict least recently used
-		mc.evictLRU()
+	s := mc.shardFor(key)
+	if s.maxBytes > 0 && int64(size) > s.maxBytes {
+		return ErrMaxSize
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Use default TTL if not specified
 	if ttl == 0 {
 		ttl = mc.config.DefaultTTL
 	}
 
 	item := &cacheItem{
-		value:      value,
-		accessedAt: time.Now(),
+		value: value,
+		size:  size,
 	}
 
 	if ttl > 0 {
@@ -94,31 +164,42 @@ func (mc *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl ti
 		item.hasExpiry = true
 	}
 
-	mc.items[key] = item
+	if previous, exists := s.items[key]; exists {
+		mc.addUsage(s, -1, -int64(previous.size))
+	}
+	s.items[key] = item
+	s.policy.Added(key, size)
+	mc.addUsage(s, 1, int64(size))
+
+	mc.evict(s, key)
 	return nil
 }
 
 // Delete removes a value from the cache
 func (mc *MemoryCache) Delete(ctx context.Context, key string) error {
-	mc.mu.Lock()
-	defer mc.mu.Unlock()
+	s := mc.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	delete(mc.items, key)
+	if _, exists := s.items[key]; exists {
+		mc.remove(s, key, "")
+	}
 	return nil
 }
 
 // Exists checks if a key exists in the cache
 func (mc *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	s := mc.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	item, exists := mc.items[key]
+	item, exists := s.items[key]
 	if !exists {
 		return false, nil
 	}
 
 	// Check expiry
-	if item.hasExpiry && time.Now().After(item.expiresAt) {
+	if item.expired(time.Now()) {
 		return false, nil
 	}
 
@@ -127,10 +208,13 @@ func (mc *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
 
 // Clear removes all values from the cache
 func (mc *MemoryCache) Clear(ctx context.Context) error {
-	mc.mu.Lock()
-	defer mc.mu.Unlock()
-
-	mc.items = make(map[string]*cacheItem)
+	for _, s := range mc.shards {
+		s.mu.Lock()
+		mc.addUsage(s, -len(s.items), -s.bytes)
+		s.items = make(map[string]*cacheItem)
+		s.policy = newEvictionPolicy(mc.config)
+		s.mu.Unlock()
+	}
 	return nil
 }
 
@@ -141,38 +225,87 @@ func (mc *MemoryCache) Close() error {
 	return nil
 }
 
+// Stats returns the current number of items, their approximate size and the number of evictions
+func (mc *MemoryCache) Stats() MemoryStats {
+	return MemoryStats{
+		Items:     mc.items.Load(),
+		Bytes:     mc.bytes.Load(),
+		Evictions: mc.evictions.Load(),
+	}
+}
+
 // cleanupExpired removes expired items from the cache
 func (mc *MemoryCache) cleanupExpired() {
 	for {
 		select {
 		case <-mc.cleanup.C:
-			mc.mu.Lock()
 			now := time.Now()
-			for key, item := range mc.items {
-				if item.hasExpiry && now.After(item.expiresAt) {
-					delete(mc.items, key)
+			for _, s := range mc.shards {
+				s.mu.Lock()
+				for key, item := range s.items {
+					if item.expired(now) {
+						mc.remove(s, key, "expired")
+					}
 				}
+				s.mu.Unlock()
 			}
-			mc.mu.Unlock()
 		case <-mc.done:
 			return
 		}
 	}
 }
 
-// evictLRU removes the least recently used item
-func (mc *MemoryCache) evictLRU() {
-	var oldestKey string
-	var oldestTime time.Time
-
-	for key, item := range mc.items {
-		if oldestKey == "" || item.accessedAt.Before(oldestTime) {
-			oldestKey = key
-			oldestTime = item.accessedAt
+// evict removes the policy's victims until the shard is within its limits
+// The key just written is only evicted when nothing else is left
+func (mc *MemoryCache) evict(s *shard, written string) {
+	for (s.maxItems > 0 && len(s.items) > s.maxItems) || (s.maxBytes > 0 && s.bytes > s.maxBytes) {
+		victim, ok := s.policy.Victim()
+		if !ok {
+			return
+		}
+		if victim == written && len(s.items) > 1 {
+			// Keep the new item, evict the next candidate instead
+			s.policy.Removed(written)
+			victim, ok = s.policy.Victim()
+			s.policy.Added(written, s.items[written].size)
+			if !ok {
+				return
+			}
 		}
+		mc.remove(s, victim, "capacity")
 	}
+}
+
+// remove deletes a key from a locked shard; a non-empty reason counts as eviction
+func (mc *MemoryCache) remove(s *shard, key string, reason string) {
+	item, exists := s.items[key]
+	if !exists {
+		return
+	}
+
+	delete(s.items, key)
+	s.policy.Removed(key)
+	mc.addUsage(s, -1, -int64(item.size))
+
+	if reason == "" {
+		return
+	}
+	if reason == "capacity" {
+		mc.evictions.Add(1)
+	}
+	if mc.metrics != nil {
+		mc.metrics.evicted.WithLabelValues(mc.config.ServiceName, mc.config.cacheName(), reason).Inc()
+	}
+}
+
+// addUsage tracks item count and size of a locked shard and the whole cache
+func (mc *MemoryCache) addUsage(s *shard, items int, bytes int64) {
+	s.bytes += bytes
 
-	if oldestKey != "" {
-		delete(mc.items, oldestKey)
+	totalItems := mc.items.Add(int64(items))
+	totalBytes := mc.bytes.Add(bytes)
+	if mc.metrics != nil {
+		mc.metrics.items.WithLabelValues(mc.config.ServiceName, mc.config.cacheName()).Set(float64(totalItems))
+		mc.metrics.memory.WithLabelValues(mc.config.ServiceName, mc.config.cacheName()).Set(float64(totalBytes))
 	}
 }
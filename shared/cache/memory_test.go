@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+)
+
+func TestMemoryCacheEviction(t *testing.T) {
+	type op struct {
+		set   string
+		get   string
+		value string
+	}
+
+	tests := []struct {
+		name        string
+		config      *Config
+		ops         []op
+		wantEvicted []string
+		wantKept    []string
+	}{
+		{
+			name:        "lru evicts least recently used",
+			config:      &Config{MaxSize: 2, EvictionPolicy: EvictionLRU, Shards: 1},
+			ops:         []op{{set: "a"}, {get: "a"}, {get: "a"}, {set: "b"}, {set: "c"}},
+			wantEvicted: []string{"a"},
+			wantKept:    []string{"b", "c"},
+		},
+		{
+			name:        "lfu evicts least frequently used",
+			config:      &Config{MaxSize: 2, EvictionPolicy: EvictionLFU, Shards: 1},
+			ops:         []op{{set: "a"}, {get: "a"}, {get: "a"}, {set: "b"}, {set: "c"}},
+			wantEvicted: []string{"b"},
+			wantKept:    []string{"a", "c"},
+		},
+		{
+			name:   "size evicts largest within byte budget",
+			config: &Config{MaxBytes: 30, EvictionPolicy: EvictionSize, Shards: 1},
+			ops: []op{
+				{set: "a", value: strings.Repeat("x", 10)},
+				{set: "b", value: "xxxxx"},
+				{set: "c", value: strings.Repeat("x", 10)},
+				{set: "d", value: "xxxxx"},
+			},
+			wantEvicted: []string{"a"},
+			wantKept:    []string{"b", "c", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mc := NewMemoryCache(tt.config)
+			defer mc.Close()
+
+			for _, o := range tt.ops {
+				if o.set != "" {
+					if err := mc.Set(ctx, o.set, []byte(o.value), 0); err != nil {
+						t.Fatalf("Set %s failed: %v", o.set, err)
+					}
+				} else {
+					mc.Get(ctx, o.get)
+				}
+			}
+
+			for _, key := range tt.wantEvicted {
+				if exists, _ := mc.Exists(ctx, key); exists {
+					t.Errorf("Expected %s to be evicted", key)
+				}
+			}
+			for _, key := range tt.wantKept {
+				if exists, _ := mc.Exists(ctx, key); !exists {
+					t.Errorf("Expected %s to be kept", key)
+				}
+			}
+
+			stats := mc.Stats()
+			if stats.Evictions != int64(len(tt.wantEvicted)) || stats.Items != int64(len(tt.wantKept)) {
+				t.Errorf("Expected %d evictions and %d items, got %+v", len(tt.wantEvicted), len(tt.wantKept), stats)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheRejectsValuesOverByteBudget(t *testing.T) {
+	mc := NewMemoryCache(&Config{MaxBytes: 10, Shards: 1})
+	defer mc.Close()
+
+	err := mc.Set(context.Background(), "key", []byte(strings.Repeat("x", 10)), 0)
+	if !errors.Is(err, ErrMaxSize) {
+		t.Errorf("Expected ErrMaxSize, got %v", err)
+	}
+}
+
+func TestMemoryCachesShareMetrics(t *testing.T) {
+	serviceMetrics := metrics.New("test-service")
+
+	// Registering the collectors once per registry keeps a second cache from panicking
+	posts := NewMemoryCache(&Config{Metrics: serviceMetrics, ServiceName: "test-service", Name: "posts"})
+	defer posts.Close()
+	users := NewMemoryCache(&Config{Metrics: serviceMetrics, ServiceName: "test-service", Name: "users"})
+	defer users.Close()
+	tiered := newTieredCache(users, nil, &Config{Metrics: serviceMetrics, ServiceName: "test-service", Name: "users"})
+	tiered.record(tierL1, true)
+
+	ctx := context.Background()
+	posts.Set(ctx, "a", []byte("1"), 0)
+	users.Set(ctx, "a", []byte("1"), 0)
+	users.Set(ctx, "b", []byte("2"), 0)
+
+	families, err := serviceMetrics.Registry().Gather()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	items := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "cache_items" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "cache" {
+					items[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+
+	if items["posts"] != 1 || items["users"] != 2 {
+		t.Errorf("Expected 1 posts and 2 users items, got %v", items)
+	}
+}
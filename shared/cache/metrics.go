@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+)
+
+// defaultCacheName labels the metrics of caches without a Name
+const defaultCacheName = "default"
+
+// collectors are the cache metrics of one registry
+// All caches reporting to the registry share them and are told apart by the cache label
+type collectors struct {
+	evicted *prometheus.CounterVec
+	items   *prometheus.GaugeVec
+	memory  *prometheus.GaugeVec
+	hits    *prometheus.CounterVec
+	misses  *prometheus.CounterVec
+}
+
+var (
+	collectorsMu sync.Mutex
+
+	// registered holds the collectors per registry, since registering twice panics
+	registered = make(map[*prometheus.Registry]*collectors)
+)
+
+// collectorsFor returns the cache metrics of m, registering them on first use
+func collectorsFor(m *metrics.Metrics) *collectors {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+
+	if c, ok := registered[m.Registry()]; ok {
+		return c
+	}
+
+	c := &collectors{
+		evicted: m.NewCounter("cache_evictions_total", "Total number of items evicted from the memory cache", []string{"service", "cache", "reason"}),
+		items:   m.NewGauge("cache_items", "Number of items in the memory cache", []string{"service", "cache"}),
+		memory:  m.NewGauge("cache_memory_bytes", "Approximate size of keys and values in the memory cache", []string{"service", "cache"}),
+		hits:    m.NewCounter("cache_hits_total", "Total number of cache hits", []string{"service", "cache", "tier"}),
+		misses:  m.NewCounter("cache_misses_total", "Total number of cache misses", []string{"service", "cache", "tier"}),
+	}
+	registered[m.Registry()] = c
+	return c
+}
+
+// cacheName returns the cache label of a config
+func (c *Config) cacheName() string {
+	if c.Name == "" {
+		return defaultCacheName
+	}
+	return c.Name
+}
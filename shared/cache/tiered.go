@@ -0,0 +1,307 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultInvalidationChannel = "cache:invalidate"
+	defaultL1TTL               = time.Minute
+
+	// tagKeyPrefix prefixes the Redis sets holding the keys of a tag
+	tagKeyPrefix = "cache:tag:"
+
+	tierL1 = "l1"
+	tierL2 = "l2"
+)
+
+// invalidation is broadcast to every replica when keys change
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys,omitempty"`
+	Clear  bool     `json:"clear,omitempty"`
+}
+
+// TieredCache uses a MemoryCache as L1 in front of a RedisCache as L2
+//
+// Writes go to both tiers; every write, delete and tag invalidation is broadcast
+// over Redis pub/sub, so the other replicas drop their L1 copy. L1 entries live at
+// most L1TTL, which bounds staleness when an invalidation gets lost
+type TieredCache struct {
+	l1     *MemoryCache
+	l2     *RedisCache
+	config *Config
+	origin string
+
+	pubsub *redis.PubSub
+	done   chan struct{}
+
+	metrics *collectors
+}
+
+// NewTieredCache creates a two-tier cache and subscribes to invalidations
+func NewTieredCache(config *Config) (*TieredCache, error) {
+	if config == nil {
+		config = RedisConfig("localhost:6379")
+	}
+	if config.InvalidationChannel == "" {
+		config.InvalidationChannel = defaultInvalidationChannel
+	}
+	if config.L1TTL <= 0 {
+		config.L1TTL = defaultL1TTL
+	}
+
+	l2, err := NewRedisCache(config)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := newTieredCache(NewMemoryCache(config), l2, config)
+
+	tc.pubsub = l2.client.Subscribe(context.Background(), config.InvalidationChannel)
+	if _, err := tc.pubsub.Receive(context.Background()); err != nil {
+		tc.pubsub.Close()
+		tc.l1.Close()
+		l2.Close()
+		return nil, fmt.Errorf("failed to subscribe to cache invalidations: %w", err)
+	}
+	go tc.listen()
+
+	return tc, nil
+}
+
+// newTieredCache wires the tiers without subscribing to invalidations
+func newTieredCache(l1 *MemoryCache, l2 *RedisCache, config *Config) *TieredCache {
+	tc := &TieredCache{
+		l1:     l1,
+		l2:     l2,
+		config: config,
+		origin: newOrigin(),
+		done:   make(chan struct{}),
+	}
+
+	if config.Metrics != nil {
+		tc.metrics = collectorsFor(config.Metrics)
+	}
+
+	return tc
+}
+
+// Get retrieves a value from L1, falling back to L2
+// L2 hits are copied into L1
+func (tc *TieredCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := tc.l1.Get(ctx, key); err == nil {
+		tc.record(tierL1, true)
+		return value, nil
+	}
+	tc.record(tierL1, false)
+
+	value, err := tc.l2.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			tc.record(tierL2, false)
+		}
+		return nil, err
+	}
+	tc.record(tierL2, true)
+
+	tc.l1.Set(ctx, key, value, tc.l1TTL(0))
+	return value, nil
+}
+
+// Set stores a value in both tiers and invalidates it on the other replicas
+func (tc *TieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := tc.l2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	tc.l1.Set(ctx, key, value, tc.l1TTL(ttl))
+
+	tc.broadcast(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// SetWithTags stores a value and adds its key to the tags, see InvalidateTag
+func (tc *TieredCache) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if err := tc.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	if ttl == 0 {
+		ttl = tc.config.DefaultTTL
+	}
+
+	_, err := tc.l2.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			tagKey := tagKeyPrefix + tag
+			pipe.SAdd(ctx, tagKey, key)
+			if ttl > 0 {
+				// A tag lives as long as its longest-living key
+				pipe.ExpireNX(ctx, tagKey, ttl)
+				pipe.ExpireGT(ctx, tagKey, ttl)
+			} else {
+				pipe.Persist(ctx, tagKey)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag cache key %s: %w", key, err)
+	}
+	return nil
+}
+
+// InvalidateTag removes every key stored with the tag from both tiers on all replicas
+func (tc *TieredCache) InvalidateTag(ctx context.Context, tag string) error {
+	tagKey := tagKeyPrefix + tag
+
+	keys, err := tc.l2.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read cache tag %s: %w", tag, err)
+	}
+
+	if err := tc.l2.client.Del(ctx, append(keys, tagKey)...).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate cache tag %s: %w", tag, err)
+	}
+	for _, key := range keys {
+		tc.l1.Delete(ctx, key)
+	}
+
+	if len(keys) > 0 {
+		tc.broadcast(ctx, invalidation{Keys: keys})
+	}
+	return nil
+}
+
+// Delete removes a value from both tiers on all replicas
+func (tc *TieredCache) Delete(ctx context.Context, key string) error {
+	if err := tc.l2.Delete(ctx, key); err != nil {
+		return err
+	}
+	tc.l1.Delete(ctx, key)
+
+	tc.broadcast(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// Exists checks if a key exists in either tier
+func (tc *TieredCache) Exists(ctx context.Context, key string) (bool, error) {
+	if exists, _ := tc.l1.Exists(ctx, key); exists {
+		return true, nil
+	}
+	return tc.l2.Exists(ctx, key)
+}
+
+// Clear removes all values from both tiers on all replicas
+func (tc *TieredCache) Clear(ctx context.Context) error {
+	if err := tc.l2.Clear(ctx); err != nil {
+		return err
+	}
+	tc.l1.Clear(ctx)
+
+	tc.broadcast(ctx, invalidation{Clear: true})
+	return nil
+}
+
+// Close stops listening for invalidations and closes both tiers
+func (tc *TieredCache) Close() error {
+	close(tc.done)
+
+	var errs []error
+	if tc.pubsub != nil {
+		errs = append(errs, tc.pubsub.Close())
+	}
+	errs = append(errs, tc.l1.Close(), tc.l2.Close())
+	return errors.Join(errs...)
+}
+
+// broadcast publishes an invalidation to the other replicas
+// A failed broadcast is only logged, L1TTL bounds how long replicas serve the stale value
+func (tc *TieredCache) broadcast(ctx context.Context, inv invalidation) {
+	inv.Origin = tc.origin
+
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		log.Printf("Warning: Failed to encode cache invalidation: %v", err)
+		return
+	}
+	if err := tc.l2.client.Publish(ctx, tc.config.InvalidationChannel, payload).Err(); err != nil {
+		log.Printf("Warning: Failed to broadcast cache invalidation: %v", err)
+	}
+}
+
+// listen applies invalidations from other replicas until Close
+func (tc *TieredCache) listen() {
+	messages := tc.pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			tc.handleInvalidation([]byte(msg.Payload))
+		case <-tc.done:
+			return
+		}
+	}
+}
+
+// handleInvalidation drops the invalidated L1 entries
+// Own invalidations are skipped, they were applied before broadcasting
+func (tc *TieredCache) handleInvalidation(payload []byte) {
+	var inv invalidation
+	if err := json.Unmarshal(payload, &inv); err != nil {
+		log.Printf("Warning: Failed to decode cache invalidation: %v", err)
+		return
+	}
+	if inv.Origin == tc.origin {
+		return
+	}
+
+	ctx := context.Background()
+	if inv.Clear {
+		tc.l1.Clear(ctx)
+		return
+	}
+	for _, key := range inv.Keys {
+		tc.l1.Delete(ctx, key)
+	}
+}
+
+// l1TTL caps a TTL at L1TTL
+func (tc *TieredCache) l1TTL(ttl time.Duration) time.Duration {
+	if ttl == 0 {
+		ttl = tc.config.DefaultTTL
+	}
+	if ttl <= 0 || ttl > tc.config.L1TTL {
+		return tc.config.L1TTL
+	}
+	return ttl
+}
+
+// record counts a hit or miss of a tier
+func (tc *TieredCache) record(tier string, hit bool) {
+	if tc.metrics == nil {
+		return
+	}
+	counter := tc.metrics.misses
+	if hit {
+		counter = tc.metrics.hits
+	}
+	counter.WithLabelValues(tc.config.ServiceName, tc.config.cacheName(), tier).Inc()
+}
+
+// newOrigin returns a random ID identifying this replica's invalidations
+func newOrigin() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTieredCacheHandleInvalidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		inv         invalidation
+		ownOrigin   bool
+		wantRemoved []string
+		wantKept    []string
+	}{
+		{name: "drops invalidated keys", inv: invalidation{Keys: []string{"a", "b"}}, wantRemoved: []string{"a", "b"}, wantKept: []string{"c"}},
+		{name: "clears everything", inv: invalidation{Clear: true}, wantRemoved: []string{"a", "b", "c"}},
+		{name: "skips own invalidations", inv: invalidation{Keys: []string{"a"}}, ownOrigin: true, wantKept: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			l1 := NewMemoryCache(nil)
+			defer l1.Close()
+			tc := newTieredCache(l1, nil, &Config{L1TTL: time.Minute})
+
+			for _, key := range []string{"a", "b", "c"} {
+				l1.Set(ctx, key, []byte(key), time.Minute)
+			}
+
+			tt.inv.Origin = "other-replica"
+			if tt.ownOrigin {
+				tt.inv.Origin = tc.origin
+			}
+			payload, _ := json.Marshal(tt.inv)
+			tc.handleInvalidation(payload)
+
+			for _, key := range tt.wantRemoved {
+				if exists, _ := l1.Exists(ctx, key); exists {
+					t.Errorf("Expected %s to be removed from L1", key)
+				}
+			}
+			for _, key := range tt.wantKept {
+				if exists, _ := l1.Exists(ctx, key); !exists {
+					t.Errorf("Expected %s to be kept in L1", key)
+				}
+			}
+		})
+	}
+}
+
+func TestTieredCacheL1TTL(t *testing.T) {
+	tc := newTieredCache(nil, nil, &Config{DefaultTTL: 5 * time.Minute, L1TTL: time.Minute})
+
+	tests := []struct {
+		ttl  time.Duration
+		want time.Duration
+	}{
+		{ttl: 0, want: time.Minute},
+		{ttl: 10 * time.Second, want: 10 * time.Second},
+		{ttl: time.Hour, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := tc.l1TTL(tt.ttl); got != tt.want {
+			t.Errorf("Expected L1 TTL %v for %v, got %v", tt.want, tt.ttl, got)
+		}
+	}
+}
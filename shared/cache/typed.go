@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultJitter         = 0.1
+	defaultRefreshTimeout = 10 * time.Second
+)
+
+// Loader loads a value on a cache miss
+// Returning ErrNotFound caches the miss when negative caching is enabled
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// TypedOptions configures a Typed cache
+type TypedOptions struct {
+	// Prefix is prepended to every key, e.g. "user:"
+	Prefix string
+
+	// NegativeTTL is how long a loader's ErrNotFound is cached (0 = disabled)
+	NegativeTTL time.Duration
+
+	// Jitter shortens every TTL by a random fraction up to this value, so entries
+	// written together don't expire together (default 0.1, negative = disabled)
+	Jitter float64
+
+	// StaleTTL is how long an expired value is still served while it is
+	// refreshed in the background (0 = disabled)
+	StaleTTL time.Duration
+
+	// RefreshTimeout limits background refreshes (default 10s)
+	RefreshTimeout time.Duration
+}
+
+// entry is the JSON envelope stored in the underlying cache
+type entry[T any] struct {
+	Value T `json:"v"`
+
+	// FreshUntil is the Unix time in nanoseconds after which the value is stale (0 = never)
+	FreshUntil int64 `json:"f,omitempty"`
+
+	// Missing marks a cached ErrNotFound
+	Missing bool `json:"m,omitempty"`
+}
+
+// Typed is a typed cache-aside wrapper around a Cache
+// Values are JSON encoded; concurrent misses of a key share a single load
+type Typed[T any] struct {
+	cache Cache
+	opts  TypedOptions
+	group singleflight.Group
+}
+
+// NewTyped creates a typed cache over a MemoryCache, RedisCache or any other Cache
+func NewTyped[T any](c Cache, opts TypedOptions) *Typed[T] {
+	if opts.Jitter == 0 {
+		opts.Jitter = defaultJitter
+	}
+	if opts.RefreshTimeout <= 0 {
+		opts.RefreshTimeout = defaultRefreshTimeout
+	}
+	return &Typed[T]{cache: c, opts: opts}
+}
+
+// Get returns a cached value, or ErrNotFound on a miss or a cached negative result
+// Stale values are returned as well
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+
+	e, err := t.get(ctx, key)
+	if err != nil {
+		return zero, err
+	}
+	if e.Missing {
+		return zero, ErrNotFound
+	}
+	return e.Value, nil
+}
+
+// Set stores a value for ttl (0 = the cache's default TTL)
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return t.set(ctx, key, &entry[T]{Value: value}, ttl)
+}
+
+// Delete removes a value
+func (t *Typed[T]) Delete(ctx context.Context, key string) error {
+	return t.cache.Delete(ctx, t.opts.Prefix+key)
+}
+
+// GetOrLoad returns the cached value or loads and caches it
+//
+// Concurrent misses of a key call loader once. Stale values are returned right away
+// while a single background load refreshes them. Cache errors fall back to the loader
+func (t *Typed[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) (T, error) {
+	var zero T
+
+	e, err := t.get(ctx, key)
+	switch {
+	case err == nil:
+		if e.FreshUntil != 0 && time.Now().UnixNano() > e.FreshUntil {
+			t.refresh(ctx, key, ttl, loader)
+		}
+		if e.Missing {
+			return zero, ErrNotFound
+		}
+		return e.Value, nil
+	case !errors.Is(err, ErrNotFound):
+		log.Printf("Warning: Failed to read cache key %s: %v", key, err)
+	}
+
+	// The shared load must outlive callers that give up waiting
+	result := t.group.DoChan(key, func() (interface{}, error) {
+		return t.load(context.WithoutCancel(ctx), key, ttl, loader)
+	})
+
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		value, _ := res.Val.(T)
+		return value, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// refresh reloads a stale value in the background unless a load is already running
+func (t *Typed[T]) refresh(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) {
+	t.group.DoChan(key, func() (interface{}, error) {
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), t.opts.RefreshTimeout)
+		defer cancel()
+
+		value, err := t.load(refreshCtx, key, ttl, loader)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			log.Printf("Warning: Failed to refresh cache key %s: %v", key, err)
+		}
+		return value, err
+	})
+}
+
+// load calls the loader and caches its result
+func (t *Typed[T]) load(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) (T, error) {
+	value, err := loader(ctx)
+	if errors.Is(err, ErrNotFound) && t.opts.NegativeTTL > 0 {
+		if err := t.set(ctx, key, &entry[T]{Missing: true}, t.opts.NegativeTTL); err != nil {
+			log.Printf("Warning: Failed to cache miss of key %s: %v", key, err)
+		}
+		return value, err
+	}
+	if err != nil {
+		return value, err
+	}
+
+	if err := t.set(ctx, key, &entry[T]{Value: value}, ttl); err != nil {
+		log.Printf("Warning: Failed to cache key %s: %v", key, err)
+	}
+	return value, nil
+}
+
+// get reads and decodes an entry
+func (t *Typed[T]) get(ctx context.Context, key string) (*entry[T], error) {
+	data, err := t.cache.Get(ctx, t.opts.Prefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	var e entry[T]
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("failed to decode cache entry: %w", err)
+	}
+	return &e, nil
+}
+
+// set encodes and stores an entry
+// The jittered ttl marks the entry stale, it is kept for StaleTTL longer
+func (t *Typed[T]) set(ctx context.Context, key string, e *entry[T], ttl time.Duration) error {
+	if ttl > 0 {
+		ttl = t.jitter(ttl)
+		e.FreshUntil = time.Now().Add(ttl).UnixNano()
+		if !e.Missing {
+			ttl += t.opts.StaleTTL
+		}
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	return t.cache.Set(ctx, t.opts.Prefix+key, data, ttl)
+}
+
+// jitter shortens a TTL by a random fraction up to Jitter
+func (t *Typed[T]) jitter(ttl time.Duration) time.Duration {
+	if t.opts.Jitter <= 0 {
+		return ttl
+	}
+	return ttl - time.Duration(rand.Float64()*t.opts.Jitter*float64(ttl))
+}
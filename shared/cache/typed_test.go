@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestTypedGetOrLoad(t *testing.T) {
+	loadErr := errors.New("database unavailable")
+
+	tests := []struct {
+		name      string
+		opts      TypedOptions
+		loadErr   error
+		calls     int
+		wantErr   error
+		wantLoads int32
+	}{
+		{name: "loads once and caches", calls: 3, wantLoads: 1},
+		{name: "caches misses", opts: TypedOptions{NegativeTTL: time.Minute}, loadErr: ErrNotFound, calls: 3, wantErr: ErrNotFound, wantLoads: 1},
+		{name: "retries misses without negative caching", loadErr: ErrNotFound, calls: 3, wantErr: ErrNotFound, wantLoads: 3},
+		{name: "doesn't cache errors", loadErr: loadErr, calls: 2, wantErr: loadErr, wantLoads: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := NewMemoryCache(nil)
+			defer mc.Close()
+			users := NewTyped[testUser](mc, tt.opts)
+
+			var loads atomic.Int32
+			loader := func(ctx context.Context) (testUser, error) {
+				loads.Add(1)
+				return testUser{ID: "1", Name: "Alice"}, tt.loadErr
+			}
+
+			for i := 0; i < tt.calls; i++ {
+				user, err := users.GetOrLoad(context.Background(), "1", time.Minute, loader)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+				if err == nil && user.Name != "Alice" {
+					t.Errorf("Expected Alice, got %s", user.Name)
+				}
+			}
+
+			if got := loads.Load(); got != tt.wantLoads {
+				t.Errorf("Expected %d loads, got %d", tt.wantLoads, got)
+			}
+		})
+	}
+}
+
+func TestTypedGetOrLoadDeduplicatesConcurrentMisses(t *testing.T) {
+	mc := NewMemoryCache(nil)
+	defer mc.Close()
+	users := NewTyped[testUser](mc, TypedOptions{Prefix: "user:"})
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (testUser, error) {
+		loads.Add(1)
+		<-release
+		return testUser{ID: "1", Name: "Alice"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := users.GetOrLoad(context.Background(), "1", time.Minute, loader); err != nil {
+				t.Errorf("GetOrLoad failed: %v", err)
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("Expected 1 load, got %d", got)
+	}
+	if exists, _ := mc.Exists(context.Background(), "user:1"); !exists {
+		t.Error("Expected the value to be stored under the prefixed key")
+	}
+}
+
+func TestTypedGetOrLoadServesStaleWhileRevalidating(t *testing.T) {
+	mc := NewMemoryCache(nil)
+	defer mc.Close()
+	users := NewTyped[testUser](mc, TypedOptions{Jitter: -1, StaleTTL: time.Minute})
+
+	ctx := context.Background()
+	if err := users.Set(ctx, "1", testUser{ID: "1", Name: "Alice"}, 10*time.Millisecond); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	refreshed := make(chan struct{})
+	user, err := users.GetOrLoad(ctx, "1", time.Minute, func(ctx context.Context) (testUser, error) {
+		defer close(refreshed)
+		return testUser{ID: "1", Name: "Bob"}, nil
+	})
+	if err != nil {
+		t.Fatalf("GetOrLoad failed: %v", err)
+	}
+	if user.Name != "Alice" {
+		t.Errorf("Expected the stale value Alice, got %s", user.Name)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a background refresh")
+	}
+
+	// The refresh stores its result after the loader returned
+	time.Sleep(10 * time.Millisecond)
+	user, err = users.Get(ctx, "1")
+	if err != nil || user.Name != "Bob" {
+		t.Errorf("Expected the refreshed value Bob, got %s (%v)", user.Name, err)
+	}
+}
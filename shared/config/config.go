@@ -16,6 +16,13 @@ type KeycloakConfig struct {
 	ClientID     string
 	ClientSecret string
 	PublicKey    string
+
+	// Issuer is the expected iss claim; defaults to URL/realms/Realm, override it when
+	// services reach Keycloak under a different URL than clients do
+	Issuer string
+
+	// Audience is the expected aud claim; not checked when empty
+	Audience string
 }
 
 // KafkaConfig holds Kafka/Redpanda configuration
@@ -24,6 +31,39 @@ type KafkaConfig struct {
 	GroupID string
 }
 
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	// ServiceName is reported as service.name of all spans
+	ServiceName string
+
+	// Endpoint is the OTLP gRPC URL of the collector (e.g. "http://jaeger:4317"); https
+	// uses TLS. Spans are not exported when empty, but trace context is still propagated
+	Endpoint string
+
+	// SampleRatio is the fraction of new traces recorded; calls continuing a trace
+	// follow the caller's sampling decision
+	SampleRatio float64
+}
+
+// LoggingConfig holds structured logging configuration
+type LoggingConfig struct {
+	// ServiceName is added as service to every log record
+	ServiceName string
+
+	// Level is the default minimum level: debug, info, warn or error
+	Level string
+
+	// Format is json or text
+	Format string
+
+	// ComponentLevels override the level per component, e.g. "kafka=debug,twitchbot.chat=warn"
+	ComponentLevels []string
+
+	// AdminToken is the bearer token required by the log level admin endpoint
+	// The endpoint is disabled without it
+	AdminToken string
+}
+
 // DatabaseConfig holds PostgreSQL configuration
 type DatabaseConfig struct {
 	Host         string
@@ -42,6 +82,10 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	// GRPCTimeout bounds gRPC calls without a client deadline; GRPCMaxTimeout caps client deadlines
+	GRPCTimeout    time.Duration
+	GRPCMaxTimeout time.Duration
 }
 
 // GetDatabaseURL returns PostgreSQL connection string
@@ -100,6 +144,16 @@ func GetEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func GetEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+		log.Printf("Warning: Invalid float value for %s: %s, using default: %g", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
 func GetEnvAsDuration(key string, defaultValue string) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -161,6 +215,8 @@ func LoadKeycloakConfig() KeycloakConfig {
 		ClientID:     GetEnv("KEYCLOAK_CLIENT_ID", ""),
 		ClientSecret: GetEnv("KEYCLOAK_CLIENT_SECRET", ""),
 		PublicKey:    GetEnv("KEYCLOAK_PUBLIC_KEY", ""),
+		Issuer:       GetEnv("KEYCLOAK_ISSUER", ""),
+		Audience:     GetEnv("KEYCLOAK_AUDIENCE", ""),
 	}
 }
 
@@ -193,5 +249,28 @@ func LoadServerConfig() ServerConfig {
 		ReadTimeout:  GetEnvAsDuration("SERVER_READ_TIMEOUT", "10s"),
 		WriteTimeout: GetEnvAsDuration("SERVER_WRITE_TIMEOUT", "10s"),
 		IdleTimeout:  GetEnvAsDuration("SERVER_IDLE_TIMEOUT", "60s"),
+
+		GRPCTimeout:    GetEnvAsDuration("GRPC_REQUEST_TIMEOUT", "30s"),
+		GRPCMaxTimeout: GetEnvAsDuration("GRPC_MAX_REQUEST_TIMEOUT", "5m"),
+	}
+}
+
+// LoadTracingConfig loads tracing configuration from the standard OpenTelemetry environment variables
+func LoadTracingConfig(serviceName string) TracingConfig {
+	return TracingConfig{
+		ServiceName: GetEnv("OTEL_SERVICE_NAME", serviceName),
+		Endpoint:    GetEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		SampleRatio: GetEnvAsFloat("OTEL_TRACES_SAMPLER_ARG", 1.0),
+	}
+}
+
+// LoadLoggingConfig loads logging configuration from environment
+func LoadLoggingConfig(serviceName string) LoggingConfig {
+	return LoggingConfig{
+		ServiceName:     serviceName,
+		Level:           GetEnv("LOG_LEVEL", "info"),
+		Format:          GetEnv("LOG_FORMAT", "json"),
+		ComponentLevels: GetEnvAsSlice("LOG_COMPONENT_LEVELS", ""),
+		AdminToken:      GetEnv("LOG_ADMIN_TOKEN", ""),
 	}
 }
@@ -31,14 +31,18 @@ type CommandHandler interface {
 
 // CommandBus dispatches commands to their handlers
 type CommandBus struct {
-	handlers map[string]CommandHandler
+	handlers    map[string]CommandHandler
+	middlewares []CommandMiddleware
+	pipeline    CommandHandler
 }
 
 // NewCommandBus creates a new command bus
 func NewCommandBus() *CommandBus {
-	return &CommandBus{
+	b := &CommandBus{
 		handlers: make(map[string]CommandHandler),
 	}
+	b.pipeline = b.buildPipeline()
+	return b
 }
 
 // RegisterHandler registers a command handler
@@ -46,20 +50,30 @@ func (b *CommandBus) RegisterHandler(commandName string, handler CommandHandler)
 	b.handlers[commandName] = handler
 }
 
-// Dispatch dispatches a command to its handler
+// Use appends middlewares to the dispatch pipeline
+// Middlewares run in registration order, the first one being the outermost
+func (b *CommandBus) Use(middlewares ...CommandMiddleware) {
+	b.middlewares = append(b.middlewares, middlewares...)
+	b.pipeline = b.buildPipeline()
+}
+
+// Dispatch dispatches a command through the middleware pipeline to its handler
 func (b *CommandBus) Dispatch(ctx context.Context, command Command) error {
-	// Validate command
-	if err := command.Validate(); err != nil {
-		return ErrCommandValidation
-	}
+	return b.pipeline.Handle(ctx, command)
+}
+
+// buildPipeline wraps handler lookup with validation and the registered middlewares
+func (b *CommandBus) buildPipeline() CommandHandler {
+	return chainCommand(validateCommand(CommandHandlerFunc(b.handle)), b.middlewares...)
+}
 
-	// Get handler
+// handle looks up the registered handler and executes it
+func (b *CommandBus) handle(ctx context.Context, command Command) error {
 	handler, ok := b.handlers[command.CommandName()]
 	if !ok {
 		return ErrCommandHandlerNotFound
 	}
 
-	// Execute handler
 	return handler.Handle(ctx, command)
 }
 
@@ -0,0 +1,114 @@
+package cqrs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemorySagaStore implements SagaStore in memory
+// It follows the same semantics as PostgresSagaStore and is intended for tests
+type MemorySagaStore struct {
+	mu    sync.Mutex
+	sagas map[string]*SagaState
+}
+
+// NewMemorySagaStore creates a new in-memory saga store
+func NewMemorySagaStore() *MemorySagaStore {
+	return &MemorySagaStore{
+		sagas: make(map[string]*SagaState),
+	}
+}
+
+// SaveSaga inserts or updates a saga with optimistic concurrency
+func (s *MemorySagaStore) SaveSaga(ctx context.Context, saga *SagaState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if saga.Version == 0 {
+		if _, ok := s.sagas[saga.ID]; ok {
+			return ErrSagaConflict
+		}
+		for _, existing := range s.sagas {
+			if existing.SagaType == saga.SagaType && existing.CorrelationID == saga.CorrelationID {
+				return ErrSagaConflict
+			}
+		}
+	} else {
+		existing, ok := s.sagas[saga.ID]
+		if !ok || existing.Version != saga.Version {
+			return ErrSagaConflict
+		}
+	}
+
+	saga.Version++
+	s.sagas[saga.ID] = copySaga(saga)
+	return nil
+}
+
+// GetSaga returns a saga by ID
+func (s *MemorySagaStore) GetSaga(ctx context.Context, id string) (*SagaState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saga, ok := s.sagas[id]
+	if !ok {
+		return nil, ErrSagaNotFound
+	}
+	return copySaga(saga), nil
+}
+
+// FindSaga returns the saga of a type for a correlation ID
+func (s *MemorySagaStore) FindSaga(ctx context.Context, sagaType, correlationID string) (*SagaState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, saga := range s.sagas {
+		if saga.SagaType == sagaType && saga.CorrelationID == correlationID {
+			return copySaga(saga), nil
+		}
+	}
+	return nil, nil
+}
+
+// FindDueSagas returns running or compensating sagas whose wake-up time has passed
+func (s *MemorySagaStore) FindDueSagas(ctx context.Context, now time.Time, limit int) ([]*SagaState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*SagaState
+	for _, saga := range s.sagas {
+		if saga.Status != SagaStatusRunning && saga.Status != SagaStatusCompensating {
+			continue
+		}
+		if saga.WakeAt != nil && !saga.WakeAt.After(now) {
+			due = append(due, copySaga(saga))
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].WakeAt.Before(*due[j].WakeAt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// copySaga returns a deep copy so stored sagas aren't modified through returned pointers
+func copySaga(saga *SagaState) *SagaState {
+	cp := *saga
+	if saga.WakeAt != nil {
+		wakeAt := *saga.WakeAt
+		cp.WakeAt = &wakeAt
+	}
+	if saga.Data != nil {
+		cp.Data = make(map[string]json.RawMessage, len(saga.Data))
+		for key, value := range saga.Data {
+			cp.Data[key] = append(json.RawMessage(nil), value...)
+		}
+	}
+	return &cp
+}
@@ -0,0 +1,458 @@
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	grpcmetadata "google.golang.org/grpc/metadata"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
+	sharedlogger "github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/middleware"
+)
+
+var (
+	// ErrPanicRecovered is returned when a handler panicked and the panic was recovered
+	ErrPanicRecovered = errors.New("handler panicked")
+
+	// ErrUnauthenticated is returned when a handler requires an authenticated caller but none is in the context
+	ErrUnauthenticated = errors.New("authentication required")
+
+	// ErrForbidden is returned when the caller lacks the roles or permissions required by a handler
+	ErrForbidden = errors.New("insufficient permissions")
+)
+
+// CommandHandlerFunc is an adapter to allow the use of ordinary functions as command handlers
+type CommandHandlerFunc func(ctx context.Context, command Command) error
+
+// Handle calls f(ctx, command)
+func (f CommandHandlerFunc) Handle(ctx context.Context, command Command) error {
+	return f(ctx, command)
+}
+
+// QueryHandlerFunc is an adapter to allow the use of ordinary functions as query handlers
+type QueryHandlerFunc func(ctx context.Context, query Query) (interface{}, error)
+
+// Handle calls f(ctx, query)
+func (f QueryHandlerFunc) Handle(ctx context.Context, query Query) (interface{}, error) {
+	return f(ctx, query)
+}
+
+// CommandMiddleware wraps a command handler with cross-cutting behaviour
+type CommandMiddleware func(next CommandHandler) CommandHandler
+
+// QueryMiddleware wraps a query handler with cross-cutting behaviour
+type QueryMiddleware func(next QueryHandler) QueryHandler
+
+// chainCommand wraps handler so that middlewares[0] is the outermost layer
+func chainCommand(handler CommandHandler, middlewares ...CommandMiddleware) CommandHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// chainQuery wraps handler so that middlewares[0] is the outermost layer
+func chainQuery(handler QueryHandler, middlewares ...QueryMiddleware) QueryHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// validateCommand validates the command before it reaches its handler
+// The returned error wraps both ErrCommandValidation and the original validation error
+func validateCommand(next CommandHandler) CommandHandler {
+	return CommandHandlerFunc(func(ctx context.Context, command Command) error {
+		if err := command.Validate(); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrCommandValidation, command.CommandName(), err)
+		}
+		return next.Handle(ctx, command)
+	})
+}
+
+// validateQuery validates the query before it reaches its handler
+// The returned error wraps both ErrQueryValidation and the original validation error
+func validateQuery(next QueryHandler) QueryHandler {
+	return QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+		if err := query.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrQueryValidation, query.QueryName(), err)
+		}
+		return next.Handle(ctx, query)
+	})
+}
+
+// ============================================
+// Logging
+// ============================================
+
+// LoggingCommandMiddleware logs every dispatched command with its duration and outcome
+// Records go to the "cqrs" component logger and carry the request and user of the context
+func LoggingCommandMiddleware() CommandMiddleware {
+	logger := sharedlogger.Component("cqrs")
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, command Command) error {
+			start := time.Now()
+			err := next.Handle(ctx, command)
+			if err != nil {
+				logger.ErrorContext(ctx, "command failed", "command", command.CommandName(), "duration", time.Since(start), "error", err)
+			} else {
+				logger.InfoContext(ctx, "command handled", "command", command.CommandName(), "duration", time.Since(start))
+			}
+			return err
+		})
+	}
+}
+
+// LoggingQueryMiddleware logs every dispatched query with its duration and outcome
+func LoggingQueryMiddleware() QueryMiddleware {
+	logger := sharedlogger.Component("cqrs")
+	return func(next QueryHandler) QueryHandler {
+		return QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+			start := time.Now()
+			result, err := next.Handle(ctx, query)
+			if err != nil {
+				logger.ErrorContext(ctx, "query failed", "query", query.QueryName(), "duration", time.Since(start), "error", err)
+			} else {
+				logger.InfoContext(ctx, "query handled", "query", query.QueryName(), "duration", time.Since(start))
+			}
+			return result, err
+		})
+	}
+}
+
+// ============================================
+// Metrics
+// ============================================
+
+// BusMetrics holds the Prometheus collectors used by the metrics middlewares
+// Create it once per service, since the collectors are registered on the service registry
+type BusMetrics struct {
+	serviceName string
+	total       *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+}
+
+// NewBusMetrics registers the command and query bus metrics on the given registry
+func NewBusMetrics(m *metrics.Metrics, serviceName string) *BusMetrics {
+	return &BusMetrics{
+		serviceName: serviceName,
+		total: m.NewCounter(
+			"cqrs_messages_total",
+			"Total number of dispatched commands and queries",
+			[]string{"service", "kind", "name", "status"},
+		),
+		duration: m.NewHistogram(
+			"cqrs_message_duration_seconds",
+			"Command and query handling duration in seconds",
+			[]string{"service", "kind", "name", "status"},
+			nil,
+		),
+	}
+}
+
+// observe records a single dispatch
+func (bm *BusMetrics) observe(kind, name string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	bm.total.WithLabelValues(bm.serviceName, kind, name, status).Inc()
+	bm.duration.WithLabelValues(bm.serviceName, kind, name, status).Observe(time.Since(start).Seconds())
+}
+
+// MetricsCommandMiddleware records count and duration of every dispatched command
+func MetricsCommandMiddleware(bm *BusMetrics) CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, command Command) error {
+			start := time.Now()
+			err := next.Handle(ctx, command)
+			bm.observe("command", command.CommandName(), start, err)
+			return err
+		})
+	}
+}
+
+// MetricsQueryMiddleware records count and duration of every dispatched query
+func MetricsQueryMiddleware(bm *BusMetrics) QueryMiddleware {
+	return func(next QueryHandler) QueryHandler {
+		return QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+			start := time.Now()
+			result, err := next.Handle(ctx, query)
+			bm.observe("query", query.QueryName(), start, err)
+			return result, err
+		})
+	}
+}
+
+// ============================================
+// Recovery
+// ============================================
+
+// RecoveryCommandMiddleware converts a panic in a command handler into an error wrapping ErrPanicRecovered
+// The panic and its stack trace are logged to the "cqrs" component logger
+func RecoveryCommandMiddleware() CommandMiddleware {
+	logger := sharedlogger.Component("cqrs")
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, command Command) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.ErrorContext(ctx, "recovered panic in command", "command", command.CommandName(), "panic", r, "stack", string(debug.Stack()))
+					err = fmt.Errorf("%w: %s: %v", ErrPanicRecovered, command.CommandName(), r)
+				}
+			}()
+			return next.Handle(ctx, command)
+		})
+	}
+}
+
+// RecoveryQueryMiddleware converts a panic in a query handler into an error wrapping ErrPanicRecovered
+func RecoveryQueryMiddleware() QueryMiddleware {
+	logger := sharedlogger.Component("cqrs")
+	return func(next QueryHandler) QueryHandler {
+		return QueryHandlerFunc(func(ctx context.Context, query Query) (result interface{}, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.ErrorContext(ctx, "recovered panic in query", "query", query.QueryName(), "panic", r, "stack", string(debug.Stack()))
+					result = nil
+					err = fmt.Errorf("%w: %s: %v", ErrPanicRecovered, query.QueryName(), r)
+				}
+			}()
+			return next.Handle(ctx, query)
+		})
+	}
+}
+
+// ============================================
+// Timeout
+// ============================================
+
+// TimeoutCommandMiddleware bounds command handling by the given timeout
+// Handlers must honour ctx for the timeout to take effect
+func TimeoutCommandMiddleware(timeout time.Duration) CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, command Command) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next.Handle(ctx, command)
+		})
+	}
+}
+
+// TimeoutQueryMiddleware bounds query handling by the given timeout
+// Handlers must honour ctx for the timeout to take effect
+func TimeoutQueryMiddleware(timeout time.Duration) QueryMiddleware {
+	return func(next QueryHandler) QueryHandler {
+		return QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next.Handle(ctx, query)
+		})
+	}
+}
+
+// ============================================
+// Authorization
+// ============================================
+
+// Principal is the caller identity found in the context
+type Principal struct {
+	UserID      string
+	Roles       []string
+	Permissions []string
+}
+
+// HasRole checks if the principal has a specific role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission checks if the principal has a specific permission
+func (p *Principal) HasPermission(permission string) bool {
+	for _, perm := range p.Permissions {
+		if perm == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// PrincipalFromContext extracts the verified caller identity from the context
+// It understands JWT claims validated by shared/middleware and the callers verified by
+// shared/grpc: Keycloak users, or x-user-* metadata of an mTLS-verified peer
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	if claims := middleware.GetClaims(ctx); claims != nil {
+		return &Principal{UserID: claims.UserID, Roles: claims.Roles, Permissions: claims.Permissions}, true
+	}
+	if user, ok := sharedgrpc.VerifiedUserFromContext(ctx); ok {
+		return &Principal{UserID: user.UserID, Roles: user.Roles, Permissions: user.Permissions}, true
+	}
+	return nil, false
+}
+
+// Requirement describes who may dispatch a command or query
+// A principal satisfies it with any one of the roles or any one of the permissions
+// An empty requirement only demands an authenticated caller
+type Requirement struct {
+	Roles       []string
+	Permissions []string
+}
+
+// AuthorizationPolicy maps command or query names to their requirements
+// Names without an entry are not checked
+type AuthorizationPolicy map[string]Requirement
+
+// authorize checks the principal in ctx against the requirement registered for name
+func (p AuthorizationPolicy) authorize(ctx context.Context, name string) error {
+	req, ok := p[name]
+	if !ok {
+		return nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnauthenticated, name)
+	}
+
+	if len(req.Roles) == 0 && len(req.Permissions) == 0 {
+		return nil
+	}
+	for _, role := range req.Roles {
+		if principal.HasRole(role) {
+			return nil
+		}
+	}
+	for _, permission := range req.Permissions {
+		if principal.HasPermission(permission) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrForbidden, name)
+}
+
+// AuthorizationCommandMiddleware rejects commands whose caller does not satisfy the policy
+func AuthorizationCommandMiddleware(policy AuthorizationPolicy) CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, command Command) error {
+			if err := policy.authorize(ctx, command.CommandName()); err != nil {
+				return err
+			}
+			return next.Handle(ctx, command)
+		})
+	}
+}
+
+// AuthorizationQueryMiddleware rejects queries whose caller does not satisfy the policy
+func AuthorizationQueryMiddleware(policy AuthorizationPolicy) QueryMiddleware {
+	return func(next QueryHandler) QueryHandler {
+		return QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+			if err := policy.authorize(ctx, query.QueryName()); err != nil {
+				return nil, err
+			}
+			return next.Handle(ctx, query)
+		})
+	}
+}
+
+// ============================================
+// Event metadata
+// ============================================
+
+// correlationHeaders are the incoming gRPC metadata keys checked for a correlation ID
+var correlationHeaders = []string{"x-correlation-id", "x-request-id"}
+
+// EventMetadataCommandMiddleware records the correlation ID, the acting user and the command name
+// in the context, so every event saved while handling the command carries them as metadata
+// The correlation ID is taken from the context, incoming gRPC metadata, or generated
+func EventMetadataCommandMiddleware() CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, command Command) error {
+			existing := eventstore.EventMetadataFromContext(ctx)
+
+			if _, ok := existing[eventstore.MetadataCorrelationID]; !ok {
+				ctx = eventstore.WithEventMetadata(ctx, eventstore.MetadataCorrelationID, correlationIDFromContext(ctx))
+			}
+			if principal, ok := PrincipalFromContext(ctx); ok && principal.UserID != "" {
+				ctx = eventstore.WithEventMetadata(ctx, eventstore.MetadataActor, principal.UserID)
+			}
+			ctx = eventstore.WithEventMetadata(ctx, eventstore.MetadataCommand, command.CommandName())
+
+			return next.Handle(ctx, command)
+		})
+	}
+}
+
+// correlationIDFromContext returns the caller's correlation ID or a new one
+func correlationIDFromContext(ctx context.Context) string {
+	if md, ok := grpcmetadata.FromIncomingContext(ctx); ok {
+		for _, header := range correlationHeaders {
+			if values := md.Get(header); len(values) > 0 && values[0] != "" {
+				return values[0]
+			}
+		}
+	}
+	return uuid.New().String()
+}
+
+// ============================================
+// Default pipeline
+// ============================================
+
+// PipelineConfig configures the standard middleware pipeline shared by all services
+type PipelineConfig struct {
+	// ServiceName is used as the service label on metrics
+	ServiceName string
+
+	// Metrics enables Prometheus timing when set
+	Metrics *metrics.Metrics
+
+	// Timeout bounds every dispatch when greater than zero
+	Timeout time.Duration
+
+	// Policy enables authorization when set
+	Policy AuthorizationPolicy
+}
+
+// UseDefaultPipeline installs recovery, logging, metrics, timeout and authorization
+// middlewares on both buses, in that order from outermost to innermost
+// Commands additionally record event metadata (correlation ID, actor) after logging
+func UseDefaultPipeline(commandBus *CommandBus, queryBus *QueryBus, cfg PipelineConfig) {
+	commandMiddlewares := []CommandMiddleware{RecoveryCommandMiddleware(), LoggingCommandMiddleware(), EventMetadataCommandMiddleware()}
+	queryMiddlewares := []QueryMiddleware{RecoveryQueryMiddleware(), LoggingQueryMiddleware()}
+
+	if cfg.Metrics != nil {
+		bm := NewBusMetrics(cfg.Metrics, cfg.ServiceName)
+		commandMiddlewares = append(commandMiddlewares, MetricsCommandMiddleware(bm))
+		queryMiddlewares = append(queryMiddlewares, MetricsQueryMiddleware(bm))
+	}
+
+	if cfg.Timeout > 0 {
+		commandMiddlewares = append(commandMiddlewares, TimeoutCommandMiddleware(cfg.Timeout))
+		queryMiddlewares = append(queryMiddlewares, TimeoutQueryMiddleware(cfg.Timeout))
+	}
+
+	if cfg.Policy != nil {
+		commandMiddlewares = append(commandMiddlewares, AuthorizationCommandMiddleware(cfg.Policy))
+		queryMiddlewares = append(queryMiddlewares, AuthorizationQueryMiddleware(cfg.Policy))
+	}
+
+	if commandBus != nil {
+		commandBus.Use(commandMiddlewares...)
+	}
+	if queryBus != nil {
+		queryBus.Use(queryMiddlewares...)
+	}
+}
@@ -0,0 +1,243 @@
+package cqrs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpcmetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/toxictoast/toxictoastgo/shared/auth"
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
+	"github.com/toxictoast/toxictoastgo/shared/jwt"
+	"github.com/toxictoast/toxictoastgo/shared/middleware"
+)
+
+type testCommand struct {
+	name string
+	err  error
+}
+
+func (c *testCommand) CommandName() string { return c.name }
+func (c *testCommand) Validate() error     { return c.err }
+
+type testQuery struct {
+	BaseQuery
+	name string
+}
+
+func (q *testQuery) QueryName() string { return q.name }
+
+func TestCommandBus_ValidationWrapsOriginalError(t *testing.T) {
+	bus := NewCommandBus()
+	bus.RegisterHandler("test", CommandHandlerFunc(func(ctx context.Context, command Command) error {
+		return nil
+	}))
+
+	fieldErr := errors.New("email is required")
+	err := bus.Dispatch(context.Background(), &testCommand{name: "test", err: fieldErr})
+
+	if !errors.Is(err, ErrCommandValidation) {
+		t.Errorf("Expected ErrCommandValidation, got %v", err)
+	}
+	if !errors.Is(err, fieldErr) {
+		t.Errorf("Expected original validation error to be wrapped, got %v", err)
+	}
+}
+
+func TestCommandBus_MiddlewareOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) CommandMiddleware {
+		return func(next CommandHandler) CommandHandler {
+			return CommandHandlerFunc(func(ctx context.Context, command Command) error {
+				calls = append(calls, name)
+				return next.Handle(ctx, command)
+			})
+		}
+	}
+
+	bus := NewCommandBus()
+	bus.RegisterHandler("test", CommandHandlerFunc(func(ctx context.Context, command Command) error {
+		calls = append(calls, "handler")
+		return nil
+	}))
+	bus.Use(record("first"), record("second"))
+
+	if err := bus.Dispatch(context.Background(), &testCommand{name: "test"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"first", "second", "handler"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected calls %v, got %v", expected, calls)
+			break
+		}
+	}
+}
+
+func TestCommandBus_HandlerNotFound(t *testing.T) {
+	bus := NewCommandBus()
+	bus.Use(LoggingCommandMiddleware())
+
+	err := bus.Dispatch(context.Background(), &testCommand{name: "missing"})
+	if !errors.Is(err, ErrCommandHandlerNotFound) {
+		t.Errorf("Expected ErrCommandHandlerNotFound, got %v", err)
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	// Panics are logged to the "cqrs" component of the default logger
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(previous)
+
+	bus := NewCommandBus()
+	bus.RegisterHandler("test", CommandHandlerFunc(func(ctx context.Context, command Command) error {
+		panic("boom")
+	}))
+	bus.Use(RecoveryCommandMiddleware())
+
+	err := bus.Dispatch(context.Background(), &testCommand{name: "test"})
+	if !errors.Is(err, ErrPanicRecovered) {
+		t.Errorf("Expected ErrPanicRecovered, got %v", err)
+	}
+
+	queryBus := NewQueryBus()
+	queryBus.RegisterHandler("test", QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+		panic("boom")
+	}))
+	queryBus.Use(RecoveryQueryMiddleware())
+
+	result, err := queryBus.Dispatch(context.Background(), &testQuery{name: "test"})
+	if !errors.Is(err, ErrPanicRecovered) {
+		t.Errorf("Expected ErrPanicRecovered, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result, got %v", result)
+	}
+
+	for _, msg := range []string{"recovered panic in command", "recovered panic in query"} {
+		if !strings.Contains(buf.String(), `"msg":"`+msg+`"`) {
+			t.Errorf("Expected %q to be logged, got %s", msg, buf.String())
+		}
+	}
+	if strings.Count(buf.String(), `"component":"cqrs"`) != 2 {
+		t.Errorf("Expected 2 records of the cqrs component, got %s", buf.String())
+	}
+}
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	queryBus := NewQueryBus()
+	queryBus.RegisterHandler("admin_only", QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+		return "ok", nil
+	}))
+	queryBus.RegisterHandler("public", QueryHandlerFunc(func(ctx context.Context, query Query) (interface{}, error) {
+		return "ok", nil
+	}))
+	queryBus.Use(AuthorizationQueryMiddleware(AuthorizationPolicy{
+		"admin_only": {Roles: []string{"admin"}},
+	}))
+
+	// The x-user-* metadata only identifies the caller over an mTLS-verified connection
+	forwardedAdmin := grpcmetadata.Pairs(sharedgrpc.MetadataKeyUserID, "user-1", sharedgrpc.MetadataKeyRoles, "admin")
+	verified := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}},
+	})
+
+	tests := []struct {
+		name      string
+		queryName string
+		ctx       context.Context
+		wantErr   error
+	}{
+		{name: "unlisted query passes", queryName: "public", ctx: context.Background()},
+		{name: "missing claims", queryName: "admin_only", ctx: context.Background(), wantErr: ErrUnauthenticated},
+		{name: "missing role", queryName: "admin_only", ctx: withClaims(&jwt.Claims{Roles: []string{"user"}}), wantErr: ErrForbidden},
+		{name: "has role", queryName: "admin_only", ctx: withClaims(&jwt.Claims{Roles: []string{"admin"}})},
+		{name: "plain metadata role", queryName: "admin_only", ctx: withGRPCUser(t, context.Background(), forwardedAdmin), wantErr: ErrUnauthenticated},
+		{name: "metadata role from verified peer", queryName: "admin_only", ctx: withGRPCUser(t, verified, forwardedAdmin)},
+		{name: "keycloak role", queryName: "admin_only", ctx: auth.WithUserContext(context.Background(), &auth.UserContext{UserID: "user-1", Roles: []string{"admin"}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := queryBus.Dispatch(tt.ctx, &testQuery{name: tt.queryName})
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// withClaims returns a context carrying JWT claims validated by the HTTP middleware
+func withClaims(claims *jwt.Claims) context.Context {
+	return context.WithValue(context.Background(), middleware.ClaimsContextKey, claims)
+}
+
+// withGRPCUser returns the handler context of a gRPC call carrying md, after the auth interceptor
+func withGRPCUser(t *testing.T, ctx context.Context, md grpcmetadata.MD) context.Context {
+	t.Helper()
+	var handlerCtx context.Context
+	_, err := sharedgrpc.AuthInterceptor(grpcmetadata.NewIncomingContext(ctx, md), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCtx = ctx
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("AuthInterceptor failed: %v", err)
+	}
+	return handlerCtx
+}
+
+func TestEventMetadataMiddleware(t *testing.T) {
+	var metadata map[string]interface{}
+
+	bus := NewCommandBus()
+	bus.RegisterHandler("rename_user", CommandHandlerFunc(func(ctx context.Context, command Command) error {
+		metadata = eventstore.EventMetadataFromContext(ctx)
+		return nil
+	}))
+	bus.Use(EventMetadataCommandMiddleware())
+
+	ctx := grpcmetadata.NewIncomingContext(context.Background(), grpcmetadata.Pairs("x-correlation-id", "corr-1"))
+	ctx = context.WithValue(ctx, middleware.ClaimsContextKey, &jwt.Claims{UserID: "admin-1"})
+
+	if err := bus.Dispatch(ctx, &testCommand{name: "rename_user"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		eventstore.MetadataCorrelationID: "corr-1",
+		eventstore.MetadataActor:         "admin-1",
+		eventstore.MetadataCommand:       "rename_user",
+	}
+	for key, value := range expected {
+		if metadata[key] != value {
+			t.Errorf("Expected %s=%s, got %v", key, value, metadata[key])
+		}
+	}
+	// A forged x-user-id doesn't become the actor of the events
+	forged := withGRPCUser(t, context.Background(), grpcmetadata.Pairs(sharedgrpc.MetadataKeyUserID, "victim-1"))
+	if err := bus.Dispatch(forged, &testCommand{name: "rename_user"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actor, ok := metadata[eventstore.MetadataActor]; ok {
+		t.Errorf("Expected no actor for unverified metadata, got %v", actor)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/toxictoast/toxictoastgo/shared/eventstore"
 )
@@ -20,18 +22,57 @@ type Projector interface {
 	GetProjectorName() string
 }
 
+// UnknownSchemaHandler is called during replays for events whose payload schema
+// could not be upcast; the event is skipped afterwards
+type UnknownSchemaHandler func(ctx context.Context, event *eventstore.EventEnvelope, err error)
+
 // ProjectorManager manages multiple projectors
 type ProjectorManager struct {
-	projectors map[string][]Projector // event type -> projectors
-	eventStore eventstore.EventStore
+	projectors    map[string][]Projector // event type -> projectors
+	eventStore    eventstore.EventStore
+	checkpoints   eventstore.CheckpointStore
+	unknownSchema UnknownSchemaHandler
+
+	mu            sync.Mutex
+	locks         map[string]*sync.Mutex   // projector name -> lock serializing its writes
+	subscriptions map[string]*Subscription // projector name -> running subscription
 }
 
 // NewProjectorManager creates a new projector manager
 func NewProjectorManager(eventStore eventstore.EventStore) *ProjectorManager {
 	return &ProjectorManager{
-		projectors: make(map[string][]Projector),
-		eventStore: eventStore,
+		projectors:    make(map[string][]Projector),
+		eventStore:    eventStore,
+		locks:         make(map[string]*sync.Mutex),
+		subscriptions: make(map[string]*Subscription),
+	}
+}
+
+// SetCheckpointStore sets the store used to persist subscription positions
+// Without one, subscriptions replay the whole event stream on every start
+func (m *ProjectorManager) SetCheckpointStore(checkpoints eventstore.CheckpointStore) {
+	m.checkpoints = checkpoints
+}
+
+// SetUnknownSchemaHandler sets the handler reporting events with an unknown schema
+// By default such events are logged and skipped
+func (m *ProjectorManager) SetUnknownSchemaHandler(handler UnknownSchemaHandler) {
+	m.unknownSchema = handler
+}
+
+// skipUnknownSchema reports and skips events whose schema could not be upcast
+func (m *ProjectorManager) skipUnknownSchema(ctx context.Context, event *eventstore.EventEnvelope) bool {
+	err := event.SchemaError()
+	if err == nil {
+		return false
 	}
+
+	if m.unknownSchema != nil {
+		m.unknownSchema(ctx, event, err)
+	} else {
+		log.Printf("Skipping event %s (%s, sequence %d): %v", event.EventID, event.EventType, event.Sequence, err)
+	}
+	return true
 }
 
 // RegisterProjector registers a projector for specific event types
@@ -50,6 +91,10 @@ func (m *ProjectorManager) ProjectEvent(ctx context.Context, event *eventstore.E
 		return nil
 	}
 
+	if m.skipUnknownSchema(ctx, event) {
+		return nil
+	}
+
 	for _, projector := range projectors {
 		if err := projector.ProjectEvent(ctx, event); err != nil {
 			log.Printf("Error projecting event %s with projector %s: %v",
@@ -61,16 +106,18 @@ func (m *ProjectorManager) ProjectEvent(ctx context.Context, event *eventstore.E
 	return nil
 }
 
-// RebuildProjections rebuilds all projections from event store
-// Useful for recovering read models or adding new projectors
+// RebuildProjections replays all events of an aggregate type into the live read models
+// Queries see partial data while it runs; use a ProjectionRebuilder for read models
+// that support shadow tables
 func (m *ProjectorManager) RebuildProjections(ctx context.Context, aggregateType string) error {
 	log.Printf("Rebuilding projections for aggregate type: %s", aggregateType)
 
-	offset := 0
+	var position int64
 	limit := 100
 
 	for {
-		events, err := m.eventStore.GetAllEvents(ctx, aggregateType, limit, offset)
+		// Keyset pagination over the global sequence stays fast however large the store grows
+		events, err := m.eventStore.GetEventsAfterSequence(ctx, position, limit)
 		if err != nil {
 			return fmt.Errorf("failed to get events: %w", err)
 		}
@@ -80,13 +127,15 @@ func (m *ProjectorManager) RebuildProjections(ctx context.Context, aggregateType
 		}
 
 		for _, event := range events {
+			position = event.Sequence
+			if event.AggregateType != aggregateType {
+				continue
+			}
 			if err := m.ProjectEvent(ctx, event); err != nil {
 				return fmt.Errorf("failed to project event %s: %w", event.EventID, err)
 			}
 		}
 
-		offset += len(events)
-
 		if len(events) < limit {
 			break
 		}
@@ -96,34 +145,56 @@ func (m *ProjectorManager) RebuildProjections(ctx context.Context, aggregateType
 	return nil
 }
 
-// StartEventStreamProjection continuously projects events from the event stream
-// This is useful for real-time read model updates
+// lockProjector serializes writes of a projector's subscription with a rebuild swapping its read model
+func (m *ProjectorManager) lockProjector(name string) func() {
+	m.mu.Lock()
+	lock, ok := m.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[name] = lock
+	}
+	m.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// movePosition sets a projector's subscription position and checkpoint
+// Must be called while holding the projector's lock
+func (m *ProjectorManager) movePosition(ctx context.Context, name string, position int64) error {
+	m.mu.Lock()
+	sub := m.subscriptions[name]
+	m.mu.Unlock()
+
+	if sub != nil {
+		sub.position.Store(position)
+	}
+	if m.checkpoints != nil {
+		if err := m.checkpoints.SaveCheckpoint(ctx, name, position); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartEventStreamProjection starts a checkpointed catch-up subscription for every registered projector
+// pollInterval is given in seconds; use Subscribe directly for finer control
 func (m *ProjectorManager) StartEventStreamProjection(ctx context.Context, pollInterval int64) {
-	lastTimestamp := int64(0)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("Stopping event stream projection")
-				return
-			default:
-				events, err := m.eventStore.GetEventStream(ctx, lastTimestamp, 100)
-				if err != nil {
-					log.Printf("Error fetching event stream: %v", err)
-					continue
-				}
-
-				for _, event := range events {
-					if err := m.ProjectEvent(ctx, event); err != nil {
-						log.Printf("Error projecting event from stream: %v", err)
-					}
-					lastTimestamp = event.Timestamp.Unix()
-				}
-
-				// Sleep before next poll
-				// time.Sleep(time.Duration(pollInterval) * time.Second)
+	seen := make(map[string]bool)
+
+	for _, projectors := range m.projectors {
+		for _, projector := range projectors {
+			if seen[projector.GetProjectorName()] {
+				continue
+			}
+			seen[projector.GetProjectorName()] = true
+
+			_, err := m.Subscribe(ctx, projector, SubscriptionOptions{
+				PollInterval: time.Duration(pollInterval) * time.Second,
+			})
+			if err != nil {
+				log.Printf("Error starting event stream projection for %s: %v", projector.GetProjectorName(), err)
 			}
 		}
-	}()
+	}
 }
@@ -31,14 +31,18 @@ type QueryHandler interface {
 
 // QueryBus dispatches queries to their handlers
 type QueryBus struct {
-	handlers map[string]QueryHandler
+	handlers    map[string]QueryHandler
+	middlewares []QueryMiddleware
+	pipeline    QueryHandler
 }
 
 // NewQueryBus creates a new query bus
 func NewQueryBus() *QueryBus {
-	return &QueryBus{
+	b := &QueryBus{
 		handlers: make(map[string]QueryHandler),
 	}
+	b.pipeline = b.buildPipeline()
+	return b
 }
 
 // RegisterHandler registers a query handler
@@ -46,20 +50,30 @@ func (b *QueryBus) RegisterHandler(queryName string, handler QueryHandler) {
 	b.handlers[queryName] = handler
 }
 
-// Dispatch dispatches a query to its handler
+// Use appends middlewares to the dispatch pipeline
+// Middlewares run in registration order, the first one being the outermost
+func (b *QueryBus) Use(middlewares ...QueryMiddleware) {
+	b.middlewares = append(b.middlewares, middlewares...)
+	b.pipeline = b.buildPipeline()
+}
+
+// Dispatch dispatches a query through the middleware pipeline to its handler
 func (b *QueryBus) Dispatch(ctx context.Context, query Query) (interface{}, error) {
-	// Validate query
-	if err := query.Validate(); err != nil {
-		return nil, ErrQueryValidation
-	}
+	return b.pipeline.Handle(ctx, query)
+}
+
+// buildPipeline wraps handler lookup with validation and the registered middlewares
+func (b *QueryBus) buildPipeline() QueryHandler {
+	return chainQuery(validateQuery(QueryHandlerFunc(b.handle)), b.middlewares...)
+}
 
-	// Get handler
+// handle looks up the registered handler and executes it
+func (b *QueryBus) handle(ctx context.Context, query Query) (interface{}, error) {
 	handler, ok := b.handlers[query.QueryName()]
 	if !ok {
 		return nil, ErrQueryHandlerNotFound
 	}
 
-	// Execute handler
 	return handler.Handle(ctx, query)
 }
 
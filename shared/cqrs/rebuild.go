@@ -0,0 +1,368 @@
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+)
+
+// defaultRebuildBatchSize is used when RebuildOptions.BatchSize is not set
+const defaultRebuildBatchSize = 500
+
+var (
+	// ErrUnknownProjector is returned when rebuilding a projector that wasn't registered
+	ErrUnknownProjector = errors.New("unknown projector")
+
+	// ErrRebuildRunning is returned when a rebuild of the projector is already in progress
+	ErrRebuildRunning = errors.New("rebuild already running")
+)
+
+// ShadowProjector is a projector whose read model can be rebuilt next to the live one
+// Each rebuild projects into a shadow read model identified by a version, which then
+// atomically replaces the live read model
+type ShadowProjector interface {
+	Projector
+
+	// PrepareShadow creates the shadow read model of a version if it doesn't exist
+	// Existing data is kept, so a resumed rebuild continues where it stopped
+	PrepareShadow(ctx context.Context, version int64) error
+
+	// Shadow returns a projector writing into the shadow read model of a version
+	Shadow(version int64) Projector
+
+	// SwapShadow atomically replaces the live read model with the shadow of a version
+	SwapShadow(ctx context.Context, version int64) error
+}
+
+// RebuildOptions configures a ProjectionRebuilder
+type RebuildOptions struct {
+	// BatchSize is the number of events read per round trip
+	BatchSize int
+
+	// Metrics enables rebuild progress metrics when set
+	Metrics *metrics.Metrics
+
+	// ServiceName is used as the service label on metrics
+	ServiceName string
+}
+
+// ProjectionRebuilder rebuilds read models without downtime
+//
+// Events are replayed into a shadow read model in global sequence order while the live
+// read model keeps serving queries and following the event stream. Once the shadow has
+// caught up, the projector's subscription is paused, the shadow swapped in and the
+// subscription moved to the shadow's position. Progress is persisted after every batch,
+// so an interrupted rebuild resumes where it stopped
+type ProjectionRebuilder struct {
+	manager *ProjectorManager
+	store   RebuildStore
+	opts    RebuildOptions
+
+	mu         sync.Mutex
+	runCtx     context.Context
+	projectors map[string]ShadowProjector
+	running    map[string]bool
+
+	position  *prometheus.GaugeVec
+	target    *prometheus.GaugeVec
+	projected *prometheus.CounterVec
+	active    *prometheus.GaugeVec
+}
+
+// NewProjectionRebuilder creates a new projection rebuilder
+func NewProjectionRebuilder(manager *ProjectorManager, store RebuildStore, opts RebuildOptions) *ProjectionRebuilder {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultRebuildBatchSize
+	}
+
+	r := &ProjectionRebuilder{
+		manager:    manager,
+		store:      store,
+		opts:       opts,
+		runCtx:     context.Background(),
+		projectors: make(map[string]ShadowProjector),
+		running:    make(map[string]bool),
+	}
+
+	if opts.Metrics != nil {
+		labels := []string{"service", "projector"}
+		r.position = opts.Metrics.NewGauge("projection_rebuild_position", "Last global sequence projected by the running rebuild", labels)
+		r.target = opts.Metrics.NewGauge("projection_rebuild_target_position", "Global sequence the running rebuild has to reach", labels)
+		r.projected = opts.Metrics.NewCounter("projection_rebuild_events_total", "Total number of events projected by rebuilds", labels)
+		r.active = opts.Metrics.NewGauge("projection_rebuild_running", "Whether a rebuild of the projector is running", labels)
+	}
+
+	return r
+}
+
+// Register registers a projector that can be rebuilt
+func (r *ProjectionRebuilder) Register(projector ShadowProjector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.projectors[projector.GetProjectorName()] = projector
+}
+
+// Start resumes rebuilds interrupted by a crash or restart in the background
+// Rebuilds started later with StartRebuild run until ctx is cancelled
+func (r *ProjectionRebuilder) Start(ctx context.Context) {
+	r.mu.Lock()
+	r.runCtx = ctx
+	names := make([]string, 0, len(r.projectors))
+	for name := range r.projectors {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+
+	for _, name := range names {
+		state, err := r.store.GetRebuild(ctx, name)
+		if err != nil {
+			log.Printf("Failed to load rebuild state for projector '%s': %v", name, err)
+			continue
+		}
+		if state == nil || state.Status != RebuildStatusRunning {
+			continue
+		}
+
+		log.Printf("Resuming rebuild of projector '%s' (version %d) at position %d", name, state.Version, state.Position)
+		if _, err := r.StartRebuild(name); err != nil {
+			log.Printf("Failed to resume rebuild of projector '%s': %v", name, err)
+		}
+	}
+}
+
+// StartRebuild starts rebuilding a projector in the background and returns its initial state
+func (r *ProjectionRebuilder) StartRebuild(name string) (*RebuildState, error) {
+	r.mu.Lock()
+	ctx := r.runCtx
+	r.mu.Unlock()
+
+	projector, state, err := r.begin(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	initial := *state
+	go func() {
+		if err := r.run(ctx, projector, state); err != nil {
+			log.Printf("Rebuild of projector '%s' failed: %v", name, err)
+		}
+	}()
+
+	return &initial, nil
+}
+
+// Rebuild rebuilds a projector and returns once the shadow read model was swapped in
+func (r *ProjectionRebuilder) Rebuild(ctx context.Context, name string) (*RebuildState, error) {
+	projector, state, err := r.begin(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.run(ctx, projector, state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// Status returns the state of a projector's latest rebuild, or nil if it was never rebuilt
+func (r *ProjectionRebuilder) Status(ctx context.Context, name string) (*RebuildState, error) {
+	r.mu.Lock()
+	_, ok := r.projectors[name]
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProjector, name)
+	}
+
+	return r.store.GetRebuild(ctx, name)
+}
+
+// begin marks a projector as rebuilding and loads or creates its rebuild state
+// An unfinished rebuild is resumed with its version and position, otherwise a new version is started
+func (r *ProjectionRebuilder) begin(ctx context.Context, name string) (ShadowProjector, *RebuildState, error) {
+	r.mu.Lock()
+	projector, ok := r.projectors[name]
+	if !ok {
+		r.mu.Unlock()
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownProjector, name)
+	}
+	if r.running[name] {
+		r.mu.Unlock()
+		return nil, nil, fmt.Errorf("%w: %s", ErrRebuildRunning, name)
+	}
+	r.running[name] = true
+	r.mu.Unlock()
+
+	state, err := r.store.GetRebuild(ctx, name)
+	if err != nil {
+		r.finish(name)
+		return nil, nil, fmt.Errorf("failed to load rebuild state: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if state == nil || state.Status == RebuildStatusCompleted {
+		var version int64 = 1
+		if state != nil {
+			version = state.Version + 1
+		}
+		state = &RebuildState{
+			ProjectorName: name,
+			Version:       version,
+			StartedAt:     now,
+		}
+	}
+
+	state.Status = RebuildStatusRunning
+	state.LastError = ""
+	state.UpdatedAt = now
+
+	if err := projector.PrepareShadow(ctx, state.Version); err != nil {
+		r.finish(name)
+		return nil, nil, fmt.Errorf("failed to prepare shadow read model: %w", err)
+	}
+
+	if state.TargetPosition, err = r.manager.eventStore.GetLastSequence(ctx); err != nil {
+		r.finish(name)
+		return nil, nil, fmt.Errorf("failed to get last sequence: %w", err)
+	}
+
+	if err := r.store.SaveRebuild(ctx, state); err != nil {
+		r.finish(name)
+		return nil, nil, fmt.Errorf("failed to save rebuild state: %w", err)
+	}
+
+	return projector, state, nil
+}
+
+// finish marks a projector as no longer rebuilding
+func (r *ProjectionRebuilder) finish(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.running, name)
+	if r.active != nil {
+		r.active.WithLabelValues(r.opts.ServiceName, name).Set(0)
+	}
+}
+
+// run replays events into the shadow read model and swaps it in
+func (r *ProjectionRebuilder) run(ctx context.Context, projector ShadowProjector, state *RebuildState) error {
+	name := projector.GetProjectorName()
+	defer r.finish(name)
+
+	if r.active != nil {
+		r.active.WithLabelValues(r.opts.ServiceName, name).Set(1)
+	}
+
+	log.Printf("Rebuilding projector '%s' into version %d from position %d", name, state.Version, state.Position)
+
+	shadow := projector.Shadow(state.Version)
+	handled := make(map[string]bool)
+	for _, eventType := range projector.GetEventTypes() {
+		handled[eventType] = true
+	}
+
+	// Catch up while the live read model keeps serving and following the stream
+	if err := r.catchUp(ctx, shadow, handled, state); err != nil {
+		return r.fail(state, err)
+	}
+
+	// Pause the live subscription, project what was appended meanwhile and swap
+	unlock := r.manager.lockProjector(name)
+	defer unlock()
+
+	if err := r.catchUp(ctx, shadow, handled, state); err != nil {
+		return r.fail(state, err)
+	}
+
+	if err := projector.SwapShadow(ctx, state.Version); err != nil {
+		return r.fail(state, fmt.Errorf("failed to swap shadow read model: %w", err))
+	}
+
+	// The shadow is at least as far as the live read model was, continue from its position
+	if err := r.manager.movePosition(ctx, name, state.Position); err != nil {
+		return r.fail(state, err)
+	}
+
+	now := time.Now().UTC()
+	state.Status = RebuildStatusCompleted
+	state.UpdatedAt = now
+	state.FinishedAt = &now
+	if err := r.store.SaveRebuild(ctx, state); err != nil {
+		return fmt.Errorf("failed to save rebuild state: %w", err)
+	}
+
+	log.Printf("Rebuilt projector '%s' (version %d, %d events)", name, state.Version, state.EventsProjected)
+	return nil
+}
+
+// catchUp projects batches into the shadow read model until the end of the stream
+func (r *ProjectionRebuilder) catchUp(ctx context.Context, shadow Projector, handled map[string]bool, state *RebuildState) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		events, err := r.manager.eventStore.GetEventsAfterSequence(ctx, state.Position, r.opts.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to read event stream: %w", err)
+		}
+
+		var projected int64
+		for _, event := range events {
+			if handled[event.EventType] && !r.manager.skipUnknownSchema(ctx, event) {
+				if err := shadow.ProjectEvent(ctx, event); err != nil {
+					return fmt.Errorf("failed to project event %s (sequence %d): %w", event.EventID, event.Sequence, err)
+				}
+				projected++
+			}
+			state.Position = event.Sequence
+		}
+
+		state.EventsProjected += projected
+		if state.Position > state.TargetPosition {
+			state.TargetPosition = state.Position
+		}
+		state.UpdatedAt = time.Now().UTC()
+		if err := r.store.SaveRebuild(ctx, state); err != nil {
+			return fmt.Errorf("failed to save rebuild state: %w", err)
+		}
+		r.recordProgress(state, projected)
+
+		if len(events) < r.opts.BatchSize {
+			return nil
+		}
+	}
+}
+
+// fail records a failed rebuild; it resumes from its position when started again
+func (r *ProjectionRebuilder) fail(state *RebuildState, cause error) error {
+	state.Status = RebuildStatusFailed
+	state.LastError = cause.Error()
+	state.UpdatedAt = time.Now().UTC()
+
+	// The rebuild's context may be cancelled already
+	if err := r.store.SaveRebuild(context.Background(), state); err != nil {
+		log.Printf("Failed to save rebuild state for projector '%s': %v", state.ProjectorName, err)
+	}
+	return cause
+}
+
+// recordProgress updates the rebuild metrics
+func (r *ProjectionRebuilder) recordProgress(state *RebuildState, projected int64) {
+	if r.position == nil {
+		return
+	}
+
+	r.position.WithLabelValues(r.opts.ServiceName, state.ProjectorName).Set(float64(state.Position))
+	r.target.WithLabelValues(r.opts.ServiceName, state.ProjectorName).Set(float64(state.TargetPosition))
+	r.projected.WithLabelValues(r.opts.ServiceName, state.ProjectorName).Add(float64(projected))
+}
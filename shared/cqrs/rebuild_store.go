@@ -0,0 +1,199 @@
+package cqrs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RebuildStatus is the state of a projection rebuild
+type RebuildStatus string
+
+const (
+	RebuildStatusRunning   RebuildStatus = "running"
+	RebuildStatusCompleted RebuildStatus = "completed"
+	RebuildStatusFailed    RebuildStatus = "failed"
+)
+
+// RebuildState is the persisted progress of a projector's latest rebuild
+type RebuildState struct {
+	ProjectorName string        `json:"projector_name"`
+	Version       int64         `json:"version"`
+	Status        RebuildStatus `json:"status"`
+
+	// Position is the last global sequence projected into the shadow read model
+	Position int64 `json:"position"`
+
+	// TargetPosition is the end of the event stream as last seen by the rebuild
+	TargetPosition int64 `json:"target_position"`
+
+	EventsProjected int64      `json:"events_projected"`
+	LastError       string     `json:"last_error,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// RebuildStore persists projection rebuild progress
+type RebuildStore interface {
+	// GetRebuild returns the projector's latest rebuild, or nil if it was never rebuilt
+	GetRebuild(ctx context.Context, projectorName string) (*RebuildState, error)
+
+	// SaveRebuild stores the projector's latest rebuild
+	SaveRebuild(ctx context.Context, state *RebuildState) error
+}
+
+// PostgresRebuildStore implements RebuildStore using PostgreSQL
+type PostgresRebuildStore struct {
+	db *sql.DB
+}
+
+// NewPostgresRebuildStore creates a new PostgreSQL rebuild store
+func NewPostgresRebuildStore(db *sql.DB) (*PostgresRebuildStore, error) {
+	store := &PostgresRebuildStore{db: db}
+
+	if err := store.createTables(); err != nil {
+		return nil, fmt.Errorf("failed to create rebuild tables: %w", err)
+	}
+
+	return store, nil
+}
+
+// createTables creates the rebuild table
+func (s *PostgresRebuildStore) createTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS projection_rebuilds (
+		projector_name VARCHAR(255) PRIMARY KEY,
+		version BIGINT NOT NULL,
+		status VARCHAR(50) NOT NULL,
+		position BIGINT NOT NULL,
+		target_position BIGINT NOT NULL,
+		events_projected BIGINT NOT NULL,
+		last_error TEXT,
+		started_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		finished_at TIMESTAMP
+	);
+	`
+
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// GetRebuild returns the projector's latest rebuild
+func (s *PostgresRebuildStore) GetRebuild(ctx context.Context, projectorName string) (*RebuildState, error) {
+	var state RebuildState
+	var lastError sql.NullString
+	var finishedAt sql.NullTime
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT projector_name, version, status, position, target_position, events_projected,
+			last_error, started_at, updated_at, finished_at
+		FROM projection_rebuilds
+		WHERE projector_name = $1
+	`, projectorName).Scan(
+		&state.ProjectorName,
+		&state.Version,
+		&state.Status,
+		&state.Position,
+		&state.TargetPosition,
+		&state.EventsProjected,
+		&lastError,
+		&state.StartedAt,
+		&state.UpdatedAt,
+		&finishedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rebuild: %w", err)
+	}
+
+	state.LastError = lastError.String
+	if finishedAt.Valid {
+		state.FinishedAt = &finishedAt.Time
+	}
+
+	return &state, nil
+}
+
+// SaveRebuild upserts the projector's latest rebuild
+func (s *PostgresRebuildStore) SaveRebuild(ctx context.Context, state *RebuildState) error {
+	var finishedAt sql.NullTime
+	if state.FinishedAt != nil {
+		finishedAt = sql.NullTime{Time: *state.FinishedAt, Valid: true}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO projection_rebuilds (
+			projector_name, version, status, position, target_position, events_projected,
+			last_error, started_at, updated_at, finished_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (projector_name) DO UPDATE SET
+			version = EXCLUDED.version,
+			status = EXCLUDED.status,
+			position = EXCLUDED.position,
+			target_position = EXCLUDED.target_position,
+			events_projected = EXCLUDED.events_projected,
+			last_error = EXCLUDED.last_error,
+			started_at = EXCLUDED.started_at,
+			updated_at = EXCLUDED.updated_at,
+			finished_at = EXCLUDED.finished_at
+	`,
+		state.ProjectorName,
+		state.Version,
+		state.Status,
+		state.Position,
+		state.TargetPosition,
+		state.EventsProjected,
+		state.LastError,
+		state.StartedAt,
+		state.UpdatedAt,
+		finishedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save rebuild: %w", err)
+	}
+
+	return nil
+}
+
+// MemoryRebuildStore implements RebuildStore in memory
+// It follows the same semantics as PostgresRebuildStore and is intended for tests
+type MemoryRebuildStore struct {
+	mu       sync.Mutex
+	rebuilds map[string]RebuildState
+}
+
+// NewMemoryRebuildStore creates a new in-memory rebuild store
+func NewMemoryRebuildStore() *MemoryRebuildStore {
+	return &MemoryRebuildStore{
+		rebuilds: make(map[string]RebuildState),
+	}
+}
+
+// GetRebuild returns the projector's latest rebuild
+func (s *MemoryRebuildStore) GetRebuild(ctx context.Context, projectorName string) (*RebuildState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.rebuilds[projectorName]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// SaveRebuild stores the projector's latest rebuild
+func (s *MemoryRebuildStore) SaveRebuild(ctx context.Context, state *RebuildState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rebuilds[state.ProjectorName] = *state
+	return nil
+}
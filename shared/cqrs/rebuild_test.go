@@ -0,0 +1,159 @@
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+// countingProjector counts projected events per read model version, version 0 being the live one
+type countingProjector struct {
+	mu      sync.Mutex
+	live    int64
+	counts  map[int64]int
+	failAt  int64 // sequence failing once in a shadow
+	swapped []int64
+}
+
+type versionProjector struct {
+	parent  *countingProjector
+	version int64
+}
+
+func (p *countingProjector) GetProjectorName() string { return "counting" }
+func (p *countingProjector) GetEventTypes() []string  { return []string{"counted"} }
+
+func (p *countingProjector) ProjectEvent(ctx context.Context, event *eventstore.EventEnvelope) error {
+	return (&versionProjector{parent: p, version: p.liveVersion()}).ProjectEvent(ctx, event)
+}
+
+func (p *countingProjector) PrepareShadow(ctx context.Context, version int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.counts[version]; !ok {
+		p.counts[version] = 0
+	}
+	return nil
+}
+
+func (p *countingProjector) Shadow(version int64) Projector {
+	return &versionProjector{parent: p, version: version}
+}
+
+func (p *countingProjector) SwapShadow(ctx context.Context, version int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.live = version
+	p.swapped = append(p.swapped, version)
+	return nil
+}
+
+func (p *countingProjector) liveVersion() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.live
+}
+
+func (p *versionProjector) GetProjectorName() string { return p.parent.GetProjectorName() }
+func (p *versionProjector) GetEventTypes() []string  { return p.parent.GetEventTypes() }
+
+func (p *versionProjector) ProjectEvent(ctx context.Context, event *eventstore.EventEnvelope) error {
+	p.parent.mu.Lock()
+	defer p.parent.mu.Unlock()
+
+	if p.version > 0 && event.Sequence == p.parent.failAt {
+		p.parent.failAt = 0
+		return errors.New("projection failed")
+	}
+	p.parent.counts[p.version]++
+	return nil
+}
+
+func TestProjectionRebuilder(t *testing.T) {
+	tests := []struct {
+		name              string
+		failAt            int64
+		expectedErr       bool
+		expectedVersion   int64
+		expectedProjected int64
+	}{
+		{
+			name:              "rebuilds and swaps",
+			expectedVersion:   1,
+			expectedProjected: 5,
+		},
+		{
+			name:              "resumes after a failure",
+			failAt:            5,
+			expectedErr:       true,
+			expectedVersion:   1,
+			expectedProjected: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := eventstore.NewMemoryEventStore()
+			for i := 0; i < 5; i++ {
+				events := make([]*eventstore.EventEnvelope, 0, 2)
+				for _, eventType := range []string{"counted", "ignored"} {
+					event, err := eventstore.NewEventEnvelope("counter", fmt.Sprintf("counter-%d", i), eventType, int64(len(events)), nil)
+					if err != nil {
+						t.Fatalf("Failed to create event: %v", err)
+					}
+					events = append(events, event)
+				}
+				if err := store.SaveEvents(ctx, events[0].AggregateID, -1, events); err != nil {
+					t.Fatalf("SaveEvents failed: %v", err)
+				}
+			}
+
+			checkpoints := eventstore.NewMemoryCheckpointStore()
+			manager := NewProjectorManager(store)
+			manager.SetCheckpointStore(checkpoints)
+
+			projector := &countingProjector{counts: map[int64]int{0: 0}, failAt: tt.failAt}
+			rebuilder := NewProjectionRebuilder(manager, NewMemoryRebuildStore(), RebuildOptions{BatchSize: 2})
+			rebuilder.Register(projector)
+
+			_, err := rebuilder.Rebuild(ctx, "counting")
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatal("Expected first rebuild to fail")
+				}
+				state, _ := rebuilder.Status(ctx, "counting")
+				if state.Status != RebuildStatusFailed || state.Position == 0 {
+					t.Fatalf("Expected failed rebuild with progress, got %+v", state)
+				}
+				_, err = rebuilder.Rebuild(ctx, "counting")
+			}
+			if err != nil {
+				t.Fatalf("Rebuild failed: %v", err)
+			}
+
+			state, err := rebuilder.Status(ctx, "counting")
+			if err != nil {
+				t.Fatalf("Status failed: %v", err)
+			}
+			if state.Status != RebuildStatusCompleted || state.Version != tt.expectedVersion {
+				t.Errorf("Expected completed rebuild of version %d, got %+v", tt.expectedVersion, state)
+			}
+			if state.EventsProjected != tt.expectedProjected || state.Position != 10 {
+				t.Errorf("Expected %d events projected up to position 10, got %+v", tt.expectedProjected, state)
+			}
+			if projector.live != tt.expectedVersion || projector.counts[tt.expectedVersion] != int(tt.expectedProjected) {
+				t.Errorf("Expected version %d to be live with %d events, got %d live with %v", tt.expectedVersion, tt.expectedProjected, projector.live, projector.counts)
+			}
+
+			position, _ := checkpoints.GetCheckpoint(ctx, "counting")
+			if position != 10 {
+				t.Errorf("Expected live checkpoint at 10, got %d", position)
+			}
+		})
+	}
+}
@@ -0,0 +1,517 @@
+package cqrs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+const (
+	// defaultSagaMaxAttempts is used when SagaOptions.MaxAttempts is not set
+	defaultSagaMaxAttempts = 3
+
+	// defaultSagaRetryDelay is used when SagaOptions.RetryDelay is not set
+	defaultSagaRetryDelay = 10 * time.Second
+
+	// defaultSagaPollInterval is used when SagaOptions.PollInterval is not set
+	defaultSagaPollInterval = 5 * time.Second
+
+	// sagaBatchSize is the number of due sagas processed per poll
+	sagaBatchSize = 100
+)
+
+var (
+	// ErrSagaNotFound is returned when a saga instance doesn't exist
+	ErrSagaNotFound = errors.New("saga not found")
+
+	// ErrSagaConflict is returned when a saga was modified since it was loaded
+	ErrSagaConflict = errors.New("saga was modified concurrently")
+
+	// ErrInvalidSaga is returned when registering an incomplete saga definition
+	ErrInvalidSaga = errors.New("invalid saga definition")
+
+	// ErrSagaStepTimeout is recorded when a step's completion event didn't arrive in time
+	ErrSagaStepTimeout = errors.New("saga step timed out")
+)
+
+// SagaStatus is the lifecycle state of a saga instance
+type SagaStatus string
+
+const (
+	SagaStatusRunning      SagaStatus = "running"
+	SagaStatusCompleted    SagaStatus = "completed"
+	SagaStatusCompensating SagaStatus = "compensating"
+	SagaStatusCompensated  SagaStatus = "compensated"
+	SagaStatusFailed       SagaStatus = "failed" // a compensation gave up, needs manual attention
+)
+
+// SagaState is the persisted state of one saga instance
+type SagaState struct {
+	ID            string     `json:"id"`
+	SagaType      string     `json:"saga_type"`
+	CorrelationID string     `json:"correlation_id"`
+	Status        SagaStatus `json:"status"`
+
+	// Step is the index of the running step, or of the step being compensated
+	Step int `json:"step"`
+
+	// Dispatched is set while a step's command succeeded and its completion event is awaited
+	Dispatched bool `json:"dispatched"`
+
+	// Attempts counts the failed attempts of the current step or compensation
+	Attempts int `json:"attempts"`
+
+	// WakeAt is when the saga is retried, or when the awaited step times out
+	WakeAt *time.Time `json:"wake_at,omitempty"`
+
+	LastError string                     `json:"last_error,omitempty"`
+	Data      map[string]json.RawMessage `json:"data,omitempty"`
+	Version   int64                      `json:"version"`
+	CreatedAt time.Time                  `json:"created_at"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// SetData stores a JSON encoded value in the saga, e.g. what a compensation needs to undo a step
+func (s *SagaState) SetData(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saga data %s: %w", key, err)
+	}
+	if s.Data == nil {
+		s.Data = make(map[string]json.RawMessage)
+	}
+	s.Data[key] = data
+	return nil
+}
+
+// GetData decodes a value stored with SetData, reporting whether it was present
+func (s *SagaState) GetData(key string, value interface{}) (bool, error) {
+	data, ok := s.Data[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return true, fmt.Errorf("failed to unmarshal saga data %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// SagaCommandBuilder builds the command of a step or compensation
+// It may record data on the saga, which is persisted before the command is dispatched
+type SagaCommandBuilder func(ctx context.Context, saga *SagaState) (Command, error)
+
+// SagaStep is one step of a saga
+type SagaStep struct {
+	Name string
+
+	// Command builds the command dispatched to run the step
+	Command SagaCommandBuilder
+
+	// CompletedBy is the event type confirming the step
+	// When empty the step completes as soon as its command was handled
+	CompletedBy string
+
+	// Timeout bounds how long the step waits for CompletedBy; zero waits forever
+	// A step that times out is compensated together with the steps before it
+	Timeout time.Duration
+
+	// Compensation builds the command undoing the step; nil if there's nothing to undo
+	// Compensations may run more than once and must be idempotent
+	Compensation SagaCommandBuilder
+}
+
+// SagaDefinition describes a saga type
+// At most one saga of a type runs per correlation ID
+type SagaDefinition struct {
+	Type string
+
+	// StartedBy is the event type starting a new saga
+	StartedBy string
+
+	// CorrelationID extracts the correlation ID from the start and completion events
+	// Defaults to the event's aggregate ID
+	CorrelationID func(event *eventstore.EventEnvelope) string
+
+	Steps []SagaStep
+}
+
+// correlationID returns the correlation ID of an event
+func (d *SagaDefinition) correlationID(event *eventstore.EventEnvelope) string {
+	if d.CorrelationID != nil {
+		return d.CorrelationID(event)
+	}
+	return event.AggregateID
+}
+
+// SagaOptions configures a SagaManager
+type SagaOptions struct {
+	// MaxAttempts is the number of attempts of a step or compensation before giving up
+	MaxAttempts int
+
+	// RetryDelay is the wait between two attempts
+	RetryDelay time.Duration
+
+	// PollInterval is how often retries and timeouts are checked by Start
+	PollInterval time.Duration
+}
+
+// SagaManager runs sagas (process managers): it starts them from events, dispatches
+// their steps' commands, waits for completion events and compensates failed sagas
+//
+// The manager implements Projector, so it is fed with a catch-up subscription:
+//
+//	projectorManager.Subscribe(ctx, sagaManager, cqrs.SubscriptionOptions{StartAtEnd: true})
+//	sagaManager.Start(ctx)
+type SagaManager struct {
+	mu          sync.Mutex
+	commandBus  *CommandBus
+	store       SagaStore
+	opts        SagaOptions
+	definitions map[string]*SagaDefinition
+	startedBy   map[string][]*SagaDefinition
+	completedBy map[string][]*SagaDefinition
+	now         func() time.Time
+}
+
+// NewSagaManager creates a new saga manager
+func NewSagaManager(commandBus *CommandBus, store SagaStore, opts SagaOptions) *SagaManager {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultSagaMaxAttempts
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultSagaRetryDelay
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultSagaPollInterval
+	}
+
+	return &SagaManager{
+		commandBus:  commandBus,
+		store:       store,
+		opts:        opts,
+		definitions: make(map[string]*SagaDefinition),
+		startedBy:   make(map[string][]*SagaDefinition),
+		completedBy: make(map[string][]*SagaDefinition),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Register registers a saga definition
+func (m *SagaManager) Register(definition *SagaDefinition) error {
+	if definition.Type == "" || definition.StartedBy == "" || len(definition.Steps) == 0 {
+		return fmt.Errorf("%w: type, start event and steps are required", ErrInvalidSaga)
+	}
+	for _, step := range definition.Steps {
+		if step.Command == nil {
+			return fmt.Errorf("%w: step %s of %s has no command", ErrInvalidSaga, step.Name, definition.Type)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.definitions[definition.Type]; ok {
+		return fmt.Errorf("%w: %s is already registered", ErrInvalidSaga, definition.Type)
+	}
+
+	m.definitions[definition.Type] = definition
+	m.startedBy[definition.StartedBy] = append(m.startedBy[definition.StartedBy], definition)
+
+	seen := make(map[string]bool)
+	for _, step := range definition.Steps {
+		if step.CompletedBy != "" && !seen[step.CompletedBy] {
+			seen[step.CompletedBy] = true
+			m.completedBy[step.CompletedBy] = append(m.completedBy[step.CompletedBy], definition)
+		}
+	}
+
+	log.Printf("Registered saga '%s' started by '%s'", definition.Type, definition.StartedBy)
+	return nil
+}
+
+// GetProjectorName returns the name the manager's subscription checkpoints under
+func (m *SagaManager) GetProjectorName() string {
+	return "saga_manager"
+}
+
+// GetEventTypes returns the event types starting or advancing a registered saga
+func (m *SagaManager) GetEventTypes() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var eventTypes []string
+	for eventType := range m.startedBy {
+		eventTypes = append(eventTypes, eventType)
+	}
+	for eventType := range m.completedBy {
+		if _, ok := m.startedBy[eventType]; !ok {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes
+}
+
+// ProjectEvent starts or advances sagas for an event
+// Events may be delivered more than once; duplicates are ignored
+func (m *SagaManager) ProjectEvent(ctx context.Context, event *eventstore.EventEnvelope) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, definition := range m.startedBy[event.EventType] {
+		if err := m.start(ctx, definition, event); err != nil {
+			return err
+		}
+	}
+
+	for _, definition := range m.completedBy[event.EventType] {
+		saga, err := m.store.FindSaga(ctx, definition.Type, definition.correlationID(event))
+		if err != nil {
+			return fmt.Errorf("failed to find saga: %w", err)
+		}
+		if saga == nil || saga.Status != SagaStatusRunning || !saga.Dispatched {
+			continue
+		}
+		if definition.Steps[saga.Step].CompletedBy != event.EventType {
+			continue
+		}
+
+		saga.Dispatched = false
+		saga.Step++
+		if err := m.run(ctx, definition, saga); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ProcessDue retries failed steps and compensations and times out steps whose completion event is overdue
+func (m *SagaManager) ProcessDue(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sagas, err := m.store.FindDueSagas(ctx, m.now(), sagaBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find due sagas: %w", err)
+	}
+
+	for _, saga := range sagas {
+		definition, ok := m.definitions[saga.SagaType]
+		if !ok {
+			continue
+		}
+
+		if saga.Status == SagaStatusRunning && saga.Dispatched {
+			step := definition.Steps[saga.Step]
+			err = m.compensate(ctx, definition, saga, fmt.Errorf("%w: %s", ErrSagaStepTimeout, step.Name))
+		} else {
+			err = m.run(ctx, definition, saga)
+		}
+
+		if err != nil {
+			log.Printf("Error processing saga %s (%s): %v", saga.ID, saga.SagaType, err)
+		}
+	}
+
+	return nil
+}
+
+// Start processes due sagas every PollInterval until ctx is cancelled
+func (m *SagaManager) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.ProcessDue(ctx); err != nil {
+					log.Printf("Error processing due sagas: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// start creates a saga for a start event unless one already exists
+func (m *SagaManager) start(ctx context.Context, definition *SagaDefinition, event *eventstore.EventEnvelope) error {
+	correlationID := definition.correlationID(event)
+
+	existing, err := m.store.FindSaga(ctx, definition.Type, correlationID)
+	if err != nil {
+		return fmt.Errorf("failed to find saga: %w", err)
+	}
+	if existing != nil {
+		return nil
+	}
+
+	now := m.now()
+	saga := &SagaState{
+		ID:            uuid.New().String(),
+		SagaType:      definition.Type,
+		CorrelationID: correlationID,
+		Status:        SagaStatusRunning,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	log.Printf("Starting saga %s (%s) for %s", saga.ID, saga.SagaType, correlationID)
+	return m.run(ctx, definition, saga)
+}
+
+// run executes a saga until it waits for an event, a retry, or is finished
+func (m *SagaManager) run(ctx context.Context, definition *SagaDefinition, saga *SagaState) error {
+	if saga.Status == SagaStatusCompensating {
+		return m.runCompensation(ctx, definition, saga)
+	}
+
+	for saga.Status == SagaStatusRunning {
+		if saga.Step >= len(definition.Steps) {
+			saga.Status = SagaStatusCompleted
+			saga.WakeAt = nil
+			log.Printf("Saga %s (%s) completed", saga.ID, saga.SagaType)
+			return m.save(ctx, saga)
+		}
+
+		step := definition.Steps[saga.Step]
+		if saga.Dispatched {
+			return nil
+		}
+
+		if err := m.dispatch(ctx, saga, step.Command); err != nil {
+			if errors.Is(err, ErrSagaConflict) {
+				return err
+			}
+
+			saga.Attempts++
+			saga.LastError = fmt.Sprintf("%s: %v", step.Name, err)
+			if saga.Attempts < m.opts.MaxAttempts {
+				m.scheduleRetry(saga)
+				return m.save(ctx, saga)
+			}
+			return m.compensate(ctx, definition, saga, err)
+		}
+
+		saga.Attempts = 0
+		if step.CompletedBy != "" {
+			saga.Dispatched = true
+			saga.WakeAt = nil
+			if step.Timeout > 0 {
+				deadline := m.now().Add(step.Timeout)
+				saga.WakeAt = &deadline
+			}
+			return m.save(ctx, saga)
+		}
+
+		saga.Step++
+		saga.WakeAt = nil
+		if err := m.save(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compensate starts compensating a saga whose step failed
+func (m *SagaManager) compensate(ctx context.Context, definition *SagaDefinition, saga *SagaState, cause error) error {
+	log.Printf("Saga %s (%s) failed at step %s, compensating: %v",
+		saga.ID, saga.SagaType, definition.Steps[saga.Step].Name, cause)
+
+	// The failing step is undone too: it may have partly taken effect (e.g. revoked some of
+	// the roles) or, when dispatched but never confirmed, completed after all
+	// Compensations are idempotent, so undoing a step that had no effect is harmless
+
+	saga.Status = SagaStatusCompensating
+	saga.Dispatched = false
+	saga.Attempts = 0
+	saga.LastError = cause.Error()
+
+	return m.runCompensation(ctx, definition, saga)
+}
+
+// runCompensation undoes completed steps in reverse order
+func (m *SagaManager) runCompensation(ctx context.Context, definition *SagaDefinition, saga *SagaState) error {
+	for saga.Status == SagaStatusCompensating {
+		if saga.Step < 0 {
+			saga.Status = SagaStatusCompensated
+			saga.WakeAt = nil
+			log.Printf("Saga %s (%s) compensated", saga.ID, saga.SagaType)
+			return m.save(ctx, saga)
+		}
+
+		step := definition.Steps[saga.Step]
+		if step.Compensation != nil {
+			if err := m.dispatch(ctx, saga, step.Compensation); err != nil {
+				if errors.Is(err, ErrSagaConflict) {
+					return err
+				}
+
+				saga.Attempts++
+				if saga.Attempts < m.opts.MaxAttempts {
+					m.scheduleRetry(saga)
+					return m.save(ctx, saga)
+				}
+
+				saga.Status = SagaStatusFailed
+				saga.WakeAt = nil
+				saga.LastError = fmt.Sprintf("compensating %s: %v", step.Name, err)
+				log.Printf("Saga %s (%s) failed to compensate step %s: %v", saga.ID, saga.SagaType, step.Name, err)
+				return m.save(ctx, saga)
+			}
+		}
+
+		saga.Step--
+		saga.Attempts = 0
+		saga.WakeAt = nil
+		if err := m.save(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatch builds a command, persists the saga and dispatches the command
+// The saga is saved with a retry time first, so a crash mid-dispatch is picked up by ProcessDue
+func (m *SagaManager) dispatch(ctx context.Context, saga *SagaState, build SagaCommandBuilder) error {
+	command, err := build(ctx, saga)
+	if err != nil {
+		return fmt.Errorf("failed to build command: %w", err)
+	}
+
+	m.scheduleRetry(saga)
+	if err := m.save(ctx, saga); err != nil {
+		return err
+	}
+
+	ctx = eventstore.WithEventMetadata(ctx, eventstore.MetadataCorrelationID, saga.ID)
+	ctx = eventstore.WithEventMetadata(ctx, eventstore.MetadataActor, "saga:"+saga.SagaType)
+
+	return m.commandBus.Dispatch(ctx, command)
+}
+
+// scheduleRetry sets the saga to wake up after the retry delay
+func (m *SagaManager) scheduleRetry(saga *SagaState) {
+	wakeAt := m.now().Add(m.opts.RetryDelay)
+	saga.WakeAt = &wakeAt
+}
+
+// save persists the saga state
+func (m *SagaManager) save(ctx context.Context, saga *SagaState) error {
+	saga.UpdatedAt = m.now()
+	if err := m.store.SaveSaga(ctx, saga); err != nil {
+		return fmt.Errorf("failed to save saga %s: %w", saga.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package cqrs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SagaStore persists saga instances
+type SagaStore interface {
+	// SaveSaga inserts a saga with version 0 and updates it otherwise, incrementing its version
+	// Returns ErrSagaConflict if the saga was modified since it was loaded, or if a saga
+	// of the same type already exists for the correlation ID
+	SaveSaga(ctx context.Context, saga *SagaState) error
+
+	// GetSaga returns a saga by ID, or ErrSagaNotFound
+	GetSaga(ctx context.Context, id string) (*SagaState, error)
+
+	// FindSaga returns the saga of a type for a correlation ID, or nil if there is none
+	FindSaga(ctx context.Context, sagaType, correlationID string) (*SagaState, error)
+
+	// FindDueSagas returns running or compensating sagas whose wake-up time has passed
+	FindDueSagas(ctx context.Context, now time.Time, limit int) ([]*SagaState, error)
+}
+
+// PostgresSagaStore implements SagaStore using PostgreSQL
+type PostgresSagaStore struct {
+	db *sql.DB
+}
+
+// NewPostgresSagaStore creates a new PostgreSQL saga store
+func NewPostgresSagaStore(db *sql.DB) (*PostgresSagaStore, error) {
+	store := &PostgresSagaStore{db: db}
+
+	if err := store.createTables(); err != nil {
+		return nil, fmt.Errorf("failed to create saga tables: %w", err)
+	}
+
+	return store, nil
+}
+
+// createTables creates the saga table
+func (s *PostgresSagaStore) createTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS saga_instances (
+		id VARCHAR(36) PRIMARY KEY,
+		saga_type VARCHAR(255) NOT NULL,
+		correlation_id VARCHAR(255) NOT NULL,
+		status VARCHAR(50) NOT NULL,
+		step INTEGER NOT NULL,
+		dispatched BOOLEAN NOT NULL DEFAULT FALSE,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		wake_at TIMESTAMP,
+		last_error TEXT,
+		data JSONB,
+		version BIGINT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		UNIQUE(saga_type, correlation_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_saga_instances_wake_at ON saga_instances(wake_at) WHERE wake_at IS NOT NULL;
+	`
+
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// SaveSaga inserts or updates a saga with optimistic concurrency
+func (s *PostgresSagaStore) SaveSaga(ctx context.Context, saga *SagaState) error {
+	data, err := json.Marshal(saga.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saga data: %w", err)
+	}
+
+	if saga.Version == 0 {
+		result, err := s.db.ExecContext(ctx, `
+			INSERT INTO saga_instances (id, saga_type, correlation_id, status, step, dispatched, attempts,
+				wake_at, last_error, data, version, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 1, $11, $12)
+			ON CONFLICT DO NOTHING
+		`, saga.ID, saga.SagaType, saga.CorrelationID, saga.Status, saga.Step, saga.Dispatched, saga.Attempts,
+			sql.NullTime{Time: derefTime(saga.WakeAt), Valid: saga.WakeAt != nil}, saga.LastError, data,
+			saga.CreatedAt, saga.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert saga: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return ErrSagaConflict
+		}
+		saga.Version = 1
+		return nil
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE saga_instances SET
+			status = $1, step = $2, dispatched = $3, attempts = $4, wake_at = $5,
+			last_error = $6, data = $7, version = version + 1, updated_at = $8
+		WHERE id = $9 AND version = $10
+	`, saga.Status, saga.Step, saga.Dispatched, saga.Attempts,
+		sql.NullTime{Time: derefTime(saga.WakeAt), Valid: saga.WakeAt != nil},
+		saga.LastError, data, saga.UpdatedAt, saga.ID, saga.Version)
+	if err != nil {
+		return fmt.Errorf("failed to update saga: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrSagaConflict
+	}
+
+	saga.Version++
+	return nil
+}
+
+// GetSaga returns a saga by ID
+func (s *PostgresSagaStore) GetSaga(ctx context.Context, id string) (*SagaState, error) {
+	saga, err := s.scanSaga(s.db.QueryRowContext(ctx, sagaSelect+` WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrSagaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+	return saga, nil
+}
+
+// FindSaga returns the saga of a type for a correlation ID
+func (s *PostgresSagaStore) FindSaga(ctx context.Context, sagaType, correlationID string) (*SagaState, error) {
+	saga, err := s.scanSaga(s.db.QueryRowContext(ctx,
+		sagaSelect+` WHERE saga_type = $1 AND correlation_id = $2`, sagaType, correlationID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find saga: %w", err)
+	}
+	return saga, nil
+}
+
+// FindDueSagas returns running or compensating sagas whose wake-up time has passed
+func (s *PostgresSagaStore) FindDueSagas(ctx context.Context, now time.Time, limit int) ([]*SagaState, error) {
+	rows, err := s.db.QueryContext(ctx, sagaSelect+`
+		WHERE status IN ($1, $2) AND wake_at IS NOT NULL AND wake_at <= $3
+		ORDER BY wake_at ASC
+		LIMIT $4
+	`, SagaStatusRunning, SagaStatusCompensating, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due sagas: %w", err)
+	}
+	defer rows.Close()
+
+	var sagas []*SagaState
+	for rows.Next() {
+		saga, err := s.scanSaga(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saga: %w", err)
+		}
+		sagas = append(sagas, saga)
+	}
+
+	return sagas, rows.Err()
+}
+
+// sagaSelect selects the columns read by scanSaga
+const sagaSelect = `
+	SELECT id, saga_type, correlation_id, status, step, dispatched, attempts,
+		wake_at, last_error, data, version, created_at, updated_at
+	FROM saga_instances`
+
+// scanSaga scans a row selected with sagaSelect
+func (s *PostgresSagaStore) scanSaga(row interface{ Scan(...interface{}) error }) (*SagaState, error) {
+	var saga SagaState
+	var wakeAt sql.NullTime
+	var lastError sql.NullString
+	var data []byte
+
+	err := row.Scan(&saga.ID, &saga.SagaType, &saga.CorrelationID, &saga.Status, &saga.Step,
+		&saga.Dispatched, &saga.Attempts, &wakeAt, &lastError, &data, &saga.Version,
+		&saga.CreatedAt, &saga.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if wakeAt.Valid {
+		saga.WakeAt = &wakeAt.Time
+	}
+	saga.LastError = lastError.String
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &saga.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal saga data: %w", err)
+		}
+	}
+
+	return &saga, nil
+}
+
+// derefTime returns the time a pointer points to, or the zero time
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,151 @@
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+func newTestSaga(t *testing.T, failing map[string]bool) (*SagaManager, *[]string, *time.Time) {
+	t.Helper()
+
+	var dispatched []string
+	bus := NewCommandBus()
+	for _, name := range []string{"reserve", "release", "charge", "refund", "notify"} {
+		name := name
+		bus.RegisterHandler(name, CommandHandlerFunc(func(ctx context.Context, command Command) error {
+			dispatched = append(dispatched, name)
+			if failing[name] {
+				return errors.New(name + " failed")
+			}
+			return nil
+		}))
+	}
+
+	command := func(name string) SagaCommandBuilder {
+		return func(ctx context.Context, saga *SagaState) (Command, error) {
+			return &testCommand{name: name}, nil
+		}
+	}
+
+	manager := NewSagaManager(bus, NewMemorySagaStore(), SagaOptions{MaxAttempts: 2, RetryDelay: time.Second})
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return clock }
+
+	err := manager.Register(&SagaDefinition{
+		Type:      "order",
+		StartedBy: "order.placed",
+		Steps: []SagaStep{
+			{Name: "reserve", Command: command("reserve"), Compensation: command("release")},
+			{Name: "charge", Command: command("charge"), Compensation: command("refund"), CompletedBy: "order.charged", Timeout: time.Minute},
+			{Name: "notify", Command: command("notify")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	return manager, &dispatched, &clock
+}
+
+func projectSagaEvent(t *testing.T, manager *SagaManager, eventType string) {
+	t.Helper()
+
+	event, err := eventstore.NewEventEnvelope("order", "order-1", eventType, 0, map[string]string{})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	if err := manager.ProjectEvent(context.Background(), event); err != nil {
+		t.Fatalf("ProjectEvent failed: %v", err)
+	}
+}
+
+func TestSagaManager(t *testing.T) {
+	tests := []struct {
+		name               string
+		failing            map[string]bool
+		run                func(t *testing.T, manager *SagaManager, clock *time.Time)
+		expectedDispatched []string
+		expectedStatus     SagaStatus
+	}{
+		{
+			name: "completes all steps",
+			run: func(t *testing.T, manager *SagaManager, clock *time.Time) {
+				projectSagaEvent(t, manager, "order.placed")
+				projectSagaEvent(t, manager, "order.placed")
+				projectSagaEvent(t, manager, "order.charged")
+			},
+			expectedDispatched: []string{"reserve", "charge", "notify"},
+			expectedStatus:     SagaStatusCompleted,
+		},
+		{
+			name:    "compensates after retries are exhausted",
+			failing: map[string]bool{"charge": true},
+			run: func(t *testing.T, manager *SagaManager, clock *time.Time) {
+				projectSagaEvent(t, manager, "order.placed")
+				*clock = clock.Add(2 * time.Second)
+				if err := manager.ProcessDue(context.Background()); err != nil {
+					t.Fatalf("ProcessDue failed: %v", err)
+				}
+			},
+			expectedDispatched: []string{"reserve", "charge", "charge", "refund", "release"},
+			expectedStatus:     SagaStatusCompensated,
+		},
+		{
+			name: "compensates a step that timed out",
+			run: func(t *testing.T, manager *SagaManager, clock *time.Time) {
+				projectSagaEvent(t, manager, "order.placed")
+				*clock = clock.Add(2 * time.Minute)
+				if err := manager.ProcessDue(context.Background()); err != nil {
+					t.Fatalf("ProcessDue failed: %v", err)
+				}
+				projectSagaEvent(t, manager, "order.charged")
+			},
+			expectedDispatched: []string{"reserve", "charge", "refund", "release"},
+			expectedStatus:     SagaStatusCompensated,
+		},
+		{
+			name:    "fails when a compensation keeps failing",
+			failing: map[string]bool{"charge": true, "release": true},
+			run: func(t *testing.T, manager *SagaManager, clock *time.Time) {
+				projectSagaEvent(t, manager, "order.placed")
+				for i := 0; i < 3; i++ {
+					*clock = clock.Add(2 * time.Second)
+					if err := manager.ProcessDue(context.Background()); err != nil {
+						t.Fatalf("ProcessDue failed: %v", err)
+					}
+				}
+			},
+			expectedDispatched: []string{"reserve", "charge", "charge", "refund", "release", "release"},
+			expectedStatus:     SagaStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, dispatched, clock := newTestSaga(t, tt.failing)
+			tt.run(t, manager, clock)
+
+			if len(*dispatched) != len(tt.expectedDispatched) {
+				t.Fatalf("Expected commands %v, got %v", tt.expectedDispatched, *dispatched)
+			}
+			for i := range tt.expectedDispatched {
+				if (*dispatched)[i] != tt.expectedDispatched[i] {
+					t.Errorf("Expected commands %v, got %v", tt.expectedDispatched, *dispatched)
+					break
+				}
+			}
+
+			saga, err := manager.store.FindSaga(context.Background(), "order", "order-1")
+			if err != nil || saga == nil {
+				t.Fatalf("Expected saga to be stored, got %v", err)
+			}
+			if saga.Status != tt.expectedStatus {
+				t.Errorf("Expected status %s, got %s", tt.expectedStatus, saga.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package cqrs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+const (
+	// defaultSubscriptionPollInterval is used when SubscriptionOptions.PollInterval is not set
+	defaultSubscriptionPollInterval = time.Second
+
+	// defaultSubscriptionBatchSize is used when SubscriptionOptions.BatchSize is not set
+	defaultSubscriptionBatchSize = 100
+)
+
+// SubscriptionOptions configures a catch-up subscription
+type SubscriptionOptions struct {
+	// PollInterval is how long to wait for new events once caught up
+	PollInterval time.Duration
+
+	// BatchSize is the number of events read per round trip
+	BatchSize int
+
+	// Notifier wakes the subscription as soon as events are appended (optional)
+	// Polling still happens as a fallback
+	Notifier eventstore.EventNotifier
+
+	// StartAtEnd makes a subscriber without a checkpoint skip the existing history
+	// and only receive events appended from now on
+	StartAtEnd bool
+}
+
+// Subscription is a running catch-up subscription for a single projector
+// Events are delivered in global sequence order, at least once
+type Subscription struct {
+	name     string
+	position atomic.Int64
+	done     chan struct{}
+}
+
+// Name returns the projector name the subscription checkpoints under
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Position returns the last global sequence processed by the subscription
+func (s *Subscription) Position() int64 {
+	return s.position.Load()
+}
+
+// Done is closed when the subscription stops
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Subscribe starts a catch-up subscription that feeds the projector every event
+// after its last checkpoint, then keeps following the event stream until ctx is cancelled
+func (m *ProjectorManager) Subscribe(ctx context.Context, projector Projector, opts SubscriptionOptions) (*Subscription, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultSubscriptionPollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultSubscriptionBatchSize
+	}
+
+	sub := &Subscription{
+		name: projector.GetProjectorName(),
+		done: make(chan struct{}),
+	}
+
+	if m.checkpoints != nil {
+		position, err := m.checkpoints.GetCheckpoint(ctx, sub.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load checkpoint for %s: %w", sub.name, err)
+		}
+		sub.position.Store(position)
+	} else if !opts.StartAtEnd {
+		log.Printf("No checkpoint store configured, projector '%s' starts from the beginning", sub.name)
+	}
+
+	if sub.Position() == 0 && opts.StartAtEnd {
+		last, err := m.eventStore.GetLastSequence(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last sequence for %s: %w", sub.name, err)
+		}
+		sub.position.Store(last)
+
+		// Persist the starting point, so a restart doesn't skip events appended meanwhile
+		if m.checkpoints != nil {
+			if err := m.checkpoints.SaveCheckpoint(ctx, sub.name, last); err != nil {
+				return nil, fmt.Errorf("failed to save checkpoint for %s: %w", sub.name, err)
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.subscriptions[sub.name] = sub
+	m.mu.Unlock()
+
+	handled := make(map[string]bool)
+	for _, eventType := range projector.GetEventTypes() {
+		handled[eventType] = true
+	}
+
+	var wake <-chan struct{}
+	release := func() {}
+	if opts.Notifier != nil {
+		wake, release = opts.Notifier.Subscribe()
+	}
+
+	go func() {
+		defer close(sub.done)
+		defer release()
+		defer func() {
+			m.mu.Lock()
+			if m.subscriptions[sub.name] == sub {
+				delete(m.subscriptions, sub.name)
+			}
+			m.mu.Unlock()
+		}()
+
+		log.Printf("Projector '%s' subscribed at position %d", sub.name, sub.Position())
+
+		for {
+			caughtUp, err := m.catchUp(ctx, sub, projector, handled, opts.BatchSize)
+			if err != nil {
+				log.Printf("Projector '%s' stalled at position %d: %v", sub.name, sub.Position(), err)
+			} else if !caughtUp {
+				// More events are waiting, keep reading without sleeping
+				if ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				log.Printf("Stopping subscription for projector '%s' at position %d", sub.name, sub.Position())
+				return
+			case <-wake:
+			case <-time.After(opts.PollInterval):
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// catchUp projects one batch of events and advances the checkpoint
+// It reports whether the subscription has reached the end of the stream
+func (m *ProjectorManager) catchUp(ctx context.Context, sub *Subscription, projector Projector, handled map[string]bool, batchSize int) (bool, error) {
+	unlock := m.lockProjector(sub.name)
+	defer unlock()
+
+	events, err := m.eventStore.GetEventsAfterSequence(ctx, sub.Position(), batchSize)
+	if err != nil {
+		return false, fmt.Errorf("failed to read event stream: %w", err)
+	}
+
+	if len(events) == 0 {
+		return true, nil
+	}
+
+	start := sub.Position()
+	for _, event := range events {
+		if handled[event.EventType] && !m.skipUnknownSchema(ctx, event) {
+			if err := projector.ProjectEvent(ctx, event); err != nil {
+				// Keep what has been projected so far and retry the failing event later
+				if saveErr := m.saveCheckpoint(ctx, sub, start); saveErr != nil {
+					log.Printf("Failed to save checkpoint for projector '%s': %v", sub.name, saveErr)
+				}
+				return false, fmt.Errorf("failed to project event %s (sequence %d): %w", event.EventID, event.Sequence, err)
+			}
+		}
+		sub.position.Store(event.Sequence)
+	}
+
+	if err := m.saveCheckpoint(ctx, sub, start); err != nil {
+		return false, err
+	}
+
+	return len(events) < batchSize, nil
+}
+
+// saveCheckpoint persists the subscription position if it moved since previous
+func (m *ProjectorManager) saveCheckpoint(ctx context.Context, sub *Subscription, previous int64) error {
+	if m.checkpoints == nil || sub.Position() == previous {
+		return nil
+	}
+	if err := m.checkpoints.SaveCheckpoint(ctx, sub.name, sub.Position()); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
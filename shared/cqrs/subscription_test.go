@@ -0,0 +1,169 @@
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+// recordingProjector records the sequences of projected events
+type recordingProjector struct {
+	name string
+
+	mu        sync.Mutex
+	sequences []int64
+	failAt    int64 // sequence failing once
+}
+
+func (p *recordingProjector) GetProjectorName() string { return p.name }
+func (p *recordingProjector) GetEventTypes() []string  { return []string{"counted"} }
+
+func (p *recordingProjector) ProjectEvent(ctx context.Context, event *eventstore.EventEnvelope) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if event.Sequence == p.failAt {
+		p.failAt = 0
+		return errors.New("projection failed")
+	}
+	p.sequences = append(p.sequences, event.Sequence)
+	return nil
+}
+
+func (p *recordingProjector) projected() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int64(nil), p.sequences...)
+}
+
+// appendCounters saves a "counted" and an "ignored" event for each counter
+func appendCounters(t *testing.T, store eventstore.EventStore, from, to int) {
+	t.Helper()
+	for i := from; i < to; i++ {
+		events := make([]*eventstore.EventEnvelope, 0, 2)
+		for _, eventType := range []string{"counted", "ignored"} {
+			event, err := eventstore.NewEventEnvelope("counter", fmt.Sprintf("counter-%d", i), eventType, int64(len(events)), nil)
+			if err != nil {
+				t.Fatalf("Failed to create event: %v", err)
+			}
+			events = append(events, event)
+		}
+		if err := store.SaveEvents(context.Background(), events[0].AggregateID, -1, events); err != nil {
+			t.Fatalf("SaveEvents failed: %v", err)
+		}
+	}
+}
+
+// waitForPosition waits until the subscription processed the stream up to position
+func waitForPosition(t *testing.T, sub *Subscription, position int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for sub.Position() < position {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected subscription to reach position %d, got %d", position, sub.Position())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func assertSequences(t *testing.T, got, expected []int64) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected sequences %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Expected sequences %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestSubscription_CatchUpCheckpointAndResume(t *testing.T) {
+	store := eventstore.NewMemoryEventStore()
+	checkpoints := eventstore.NewMemoryCheckpointStore()
+	opts := SubscriptionOptions{PollInterval: 10 * time.Millisecond, BatchSize: 3}
+
+	// Catch up on the existing history in batches, skipping unhandled event types
+	appendCounters(t, store, 0, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager := NewProjectorManager(store)
+	manager.SetCheckpointStore(checkpoints)
+	first := &recordingProjector{name: "counting"}
+	sub, err := manager.Subscribe(ctx, first, opts)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	waitForPosition(t, sub, 10)
+	assertSequences(t, first.projected(), []int64{1, 3, 5, 7, 9})
+
+	// Events appended while subscribed are picked up by polling
+	appendCounters(t, store, 5, 6)
+	waitForPosition(t, sub, 12)
+	assertSequences(t, first.projected(), []int64{1, 3, 5, 7, 9, 11})
+
+	cancel()
+	<-sub.Done()
+
+	position, err := checkpoints.GetCheckpoint(context.Background(), "counting")
+	if err != nil || position != 12 {
+		t.Fatalf("Expected checkpoint at 12, got %d (%v)", position, err)
+	}
+
+	// A restarted subscription resumes after its checkpoint instead of replaying
+	appendCounters(t, store, 6, 8)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	manager = NewProjectorManager(store)
+	manager.SetCheckpointStore(checkpoints)
+	resumed := &recordingProjector{name: "counting"}
+	sub, err = manager.Subscribe(ctx, resumed, opts)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	waitForPosition(t, sub, 16)
+	assertSequences(t, resumed.projected(), []int64{13, 15})
+
+	// StartAtEnd only applies to subscribers without a checkpoint
+	late := &recordingProjector{name: "late"}
+	lateSub, err := manager.Subscribe(ctx, late, SubscriptionOptions{PollInterval: 10 * time.Millisecond, StartAtEnd: true})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	appendCounters(t, store, 8, 9)
+	waitForPosition(t, lateSub, 18)
+	waitForPosition(t, sub, 18)
+	assertSequences(t, late.projected(), []int64{17})
+	assertSequences(t, resumed.projected(), []int64{13, 15, 17})
+}
+
+func TestSubscription_RetriesFailedEvent(t *testing.T) {
+	store := eventstore.NewMemoryEventStore()
+	checkpoints := eventstore.NewMemoryCheckpointStore()
+	appendCounters(t, store, 0, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager := NewProjectorManager(store)
+	manager.SetCheckpointStore(checkpoints)
+
+	// The failing event is retried from the checkpoint saved before it; earlier events aren't replayed
+	projector := &recordingProjector{name: "counting", failAt: 5}
+	sub, err := manager.Subscribe(ctx, projector, SubscriptionOptions{PollInterval: 10 * time.Millisecond, BatchSize: 10})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	waitForPosition(t, sub, 10)
+	assertSequences(t, projector.projected(), []int64{1, 3, 5, 7, 9})
+
+	position, _ := checkpoints.GetCheckpoint(context.Background(), "counting")
+	if position != 10 {
+		t.Errorf("Expected checkpoint at 10, got %d", position)
+	}
+}
@@ -27,6 +27,11 @@ func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
 			continue
 		}
 
+		// Trace queries run with db.WithContext(ctx)
+		if err := db.Use(NewTracingPlugin()); err != nil {
+			return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+		}
+
 		// Get underlying sql.DB
 		sqlDB, err := db.DB()
 		if err != nil {
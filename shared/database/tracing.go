@@ -0,0 +1,108 @@
+package database
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey stores the query span in the GORM instance
+const spanKey = "tracing:span"
+
+var tracer = otel.Tracer("github.com/toxictoast/toxictoastgo/shared/database")
+
+// TracingPlugin traces GORM queries as client spans
+// Queries join the caller's trace when run with db.WithContext(ctx)
+type TracingPlugin struct{}
+
+// NewTracingPlugin creates a GORM plugin tracing all queries
+func NewTracingPlugin() *TracingPlugin {
+	return &TracingPlugin{}
+}
+
+// Name returns the plugin name
+func (p *TracingPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers span callbacks around every GORM operation
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	register := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"select", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range register {
+		if err := r.before("tracing:before_"+r.operation, startQuerySpan(r.operation)); err != nil {
+			return err
+		}
+		if err := r.after("tracing:after_"+r.operation, endQuerySpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startQuerySpan returns a callback starting the span of an operation
+func startQuerySpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+
+		name := "gorm." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+
+		ctx, span := tracer.Start(db.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNamePostgreSQL,
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// endQuerySpan records the statement, affected rows and error and ends the span
+func endQuerySpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	// The SQL keeps placeholders, bound values are not recorded
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		semconv.DBResponseReturnedRows(int(db.Statement.RowsAffected)),
+	)
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
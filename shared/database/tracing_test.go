@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeConnPool answers statements without a database: writes affect one row, queries fail
+type fakeConnPool struct{}
+
+type fakeResult struct{}
+
+func (fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+func (fakeConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (fakeConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return fakeResult{}, nil
+}
+
+func (fakeConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (fakeConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return &sql.Row{}
+}
+
+func TestTracingPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	otel.SetTracerProvider(provider)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: fakeConnPool{}, WithoutReturning: true}), &gorm.Config{SkipDefaultTransaction: true, Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.Use(NewTracingPlugin()); err != nil {
+		t.Fatalf("Failed to register tracing plugin: %v", err)
+	}
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "request")
+	defer request.End()
+
+	tests := []struct {
+		name       string
+		run        func(db *gorm.DB) error
+		wantSpan   string
+		wantSQL    string
+		wantRows   int
+		wantStatus codes.Code
+	}{
+		{
+			name:     "update",
+			run:      func(db *gorm.DB) error { return db.Table("links").Where("id = ?", 1).Update("title", "Go").Error },
+			wantSpan: "gorm.update links",
+			wantSQL:  `UPDATE "links" SET "title"=$1 WHERE id = $2`,
+			wantRows: 1,
+		},
+		{
+			name:       "failed query",
+			run:        func(db *gorm.DB) error { var rows []map[string]interface{}; return db.Table("links").Find(&rows).Error },
+			wantSpan:   "gorm.select links",
+			wantSQL:    `SELECT * FROM "links"`,
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.Reset()
+			tt.run(db.WithContext(ctx))
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("Expected 1 span, got %d", len(spans))
+			}
+			span := spans[0]
+
+			if span.Name() != tt.wantSpan || span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("Expected client span %q, got %s span %q", tt.wantSpan, span.SpanKind(), span.Name())
+			}
+			if span.Parent().SpanID() != request.SpanContext().SpanID() {
+				t.Errorf("Expected parent span %s, got %s", request.SpanContext().SpanID(), span.Parent().SpanID())
+			}
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("Expected status %v, got %v", tt.wantStatus, span.Status().Code)
+			}
+
+			attributes := map[string]string{}
+			for _, attr := range span.Attributes() {
+				attributes[string(attr.Key)] = attr.Value.Emit()
+			}
+			if !strings.HasPrefix(attributes[string(semconv.DBQueryTextKey)], tt.wantSQL) {
+				t.Errorf("Expected query %q, got %q", tt.wantSQL, attributes[string(semconv.DBQueryTextKey)])
+			}
+			if attributes[string(semconv.DBCollectionNameKey)] != "links" {
+				t.Errorf("Expected collection links, got %q", attributes[string(semconv.DBCollectionNameKey)])
+			}
+			if rows := attributes[string(semconv.DBResponseReturnedRowsKey)]; rows != strconv.Itoa(tt.wantRows) {
+				t.Errorf("Expected %d rows, got %s", tt.wantRows, rows)
+			}
+		})
+	}
+}
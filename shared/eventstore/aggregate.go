@@ -3,6 +3,8 @@ package eventstore
 import (
 	"context"
 	"fmt"
+	"log"
+	"time"
 )
 
 // Aggregate is the base interface for all event-sourced aggregates
@@ -102,7 +104,9 @@ func (a *BaseAggregate) LoadFromHistory(events []*EventEnvelope, applyFunc func(
 
 // AggregateRepository provides methods to load and save aggregates
 type AggregateRepository struct {
-	eventStore EventStore
+	eventStore     EventStore
+	snapshotStore  SnapshotStore
+	snapshotPolicy SnapshotPolicy
 }
 
 // NewAggregateRepository creates a new aggregate repository
@@ -112,13 +116,78 @@ func NewAggregateRepository(eventStore EventStore) *AggregateRepository {
 	}
 }
 
+// NewSnapshotAggregateRepository creates an aggregate repository that loads from snapshots
+// and takes new ones according to defaultPolicy, unless an aggregate provides its own policy
+func NewSnapshotAggregateRepository(eventStore EventStore, snapshotStore SnapshotStore, defaultPolicy SnapshotPolicy) *AggregateRepository {
+	if defaultPolicy == nil {
+		defaultPolicy = NeverSnapshot()
+	}
+
+	return &AggregateRepository{
+		eventStore:     eventStore,
+		snapshotStore:  snapshotStore,
+		snapshotPolicy: defaultPolicy,
+	}
+}
+
 // Load loads an aggregate from the event store
+// Snapshot-capable aggregates are restored from their latest snapshot first,
+// and only the events recorded after it are replayed
 func (r *AggregateRepository) Load(ctx context.Context, aggregate Aggregate) error {
+	if snapshotAggregate, ok := r.snapshotCapable(aggregate); ok {
+		snapshot, err := r.snapshotStore.GetSnapshot(ctx, aggregate.GetType(), aggregate.GetID())
+		if err != nil {
+			log.Printf("Failed to load snapshot for %s %s, replaying full history: %v", aggregate.GetType(), aggregate.GetID(), err)
+		} else if snapshot != nil {
+			return r.loadFromSnapshot(ctx, snapshotAggregate, snapshot)
+		}
+	}
+
 	events, err := r.eventStore.GetEvents(ctx, aggregate.GetType(), aggregate.GetID())
 	if err != nil {
 		return fmt.Errorf("failed to get events: %w", err)
 	}
 
+	return r.loadHistory(aggregate, events)
+}
+
+// LoadAtVersion loads an aggregate as it was right after the given version
+// Snapshots are not used, the history is replayed up to the version
+func (r *AggregateRepository) LoadAtVersion(ctx context.Context, aggregate Aggregate, version int64) error {
+	if version < 0 {
+		return ErrInvalidVersion
+	}
+
+	events, err := r.eventStore.GetEventsUntilVersion(ctx, aggregate.GetType(), aggregate.GetID(), version)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	return r.loadHistory(aggregate, events)
+}
+
+// LoadAsOf loads an aggregate as it was at the given point in time
+// Returns ErrAggregateNotFound if the aggregate did not exist yet
+func (r *AggregateRepository) LoadAsOf(ctx context.Context, aggregate Aggregate, at time.Time) error {
+	events, err := r.eventStore.GetEventsUntilTime(ctx, aggregate.GetType(), aggregate.GetID(), at)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	return r.loadHistory(aggregate, events)
+}
+
+// GetHistory pages through the events of an aggregate in version order
+func (r *AggregateRepository) GetHistory(ctx context.Context, aggregateType, aggregateID, cursor string, limit int) (*EventPage, error) {
+	page, err := r.eventStore.GetEventHistory(ctx, aggregateType, aggregateID, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	return page, nil
+}
+
+// loadHistory replays events into an aggregate
+func (r *AggregateRepository) loadHistory(aggregate Aggregate, events []*EventEnvelope) error {
 	if len(events) == 0 {
 		return ErrAggregateNotFound
 	}
@@ -130,6 +199,24 @@ func (r *AggregateRepository) Load(ctx context.Context, aggregate Aggregate) err
 	return nil
 }
 
+// loadFromSnapshot restores the aggregate from a snapshot and applies newer events
+func (r *AggregateRepository) loadFromSnapshot(ctx context.Context, aggregate SnapshotAggregate, snapshot *Snapshot) error {
+	if err := aggregate.RestoreSnapshot(snapshot); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	events, err := r.eventStore.GetEventsSince(ctx, aggregate.GetType(), aggregate.GetID(), snapshot.Version)
+	if err != nil {
+		return fmt.Errorf("failed to get events since snapshot: %w", err)
+	}
+
+	if err := aggregate.LoadFromHistory(events); err != nil {
+		return fmt.Errorf("failed to load from history: %w", err)
+	}
+
+	return nil
+}
+
 // Save saves an aggregate to the event store
 func (r *AggregateRepository) Save(ctx context.Context, aggregate Aggregate) error {
 	uncommittedEvents := aggregate.GetUncommittedEvents()
@@ -139,14 +226,71 @@ func (r *AggregateRepository) Save(ctx context.Context, aggregate Aggregate) err
 
 	expectedVersion := aggregate.GetVersion() - int64(len(uncommittedEvents))
 
+	// Record correlation ID, actor, ... carried by the context
+	stampMetadata(ctx, uncommittedEvents)
+
 	if err := r.eventStore.SaveEvents(ctx, aggregate.GetID(), expectedVersion, uncommittedEvents); err != nil {
 		return fmt.Errorf("failed to save events: %w", err)
 	}
 
 	aggregate.MarkEventsAsCommitted()
+
+	// Events are committed at this point, so a failed snapshot only costs a longer replay
+	if err := r.maybeSnapshot(ctx, aggregate, expectedVersion); err != nil {
+		log.Printf("Failed to snapshot %s %s: %v", aggregate.GetType(), aggregate.GetID(), err)
+	}
+
 	return nil
 }
 
+// maybeSnapshot takes a snapshot if the applicable policy asks for one
+func (r *AggregateRepository) maybeSnapshot(ctx context.Context, aggregate Aggregate, previousVersion int64) error {
+	snapshotAggregate, ok := r.snapshotCapable(aggregate)
+	if !ok {
+		return nil
+	}
+
+	policy := r.snapshotPolicy
+	if provider, ok := aggregate.(SnapshotPolicyProvider); ok {
+		policy = provider.SnapshotPolicy()
+	}
+
+	if policy == nil || !policy.ShouldSnapshot(aggregate, previousVersion, aggregate.GetVersion()) {
+		return nil
+	}
+
+	return r.SaveSnapshot(ctx, snapshotAggregate)
+}
+
+// SaveSnapshot captures the current state of an aggregate in the snapshot store
+func (r *AggregateRepository) SaveSnapshot(ctx context.Context, aggregate SnapshotAggregate) error {
+	if r.snapshotStore == nil {
+		return fmt.Errorf("no snapshot store configured")
+	}
+
+	state, err := aggregate.SnapshotState()
+	if err != nil {
+		return fmt.Errorf("failed to serialize snapshot state: %w", err)
+	}
+
+	return r.snapshotStore.SaveSnapshot(ctx, &Snapshot{
+		AggregateID:   aggregate.GetID(),
+		AggregateType: aggregate.GetType(),
+		Version:       aggregate.GetVersion(),
+		State:         state,
+		CreatedAt:     time.Now().UTC().Unix(),
+	})
+}
+
+// snapshotCapable reports whether snapshots are enabled for the aggregate
+func (r *AggregateRepository) snapshotCapable(aggregate Aggregate) (SnapshotAggregate, bool) {
+	if r.snapshotStore == nil {
+		return nil, false
+	}
+	snapshotAggregate, ok := aggregate.(SnapshotAggregate)
+	return snapshotAggregate, ok
+}
+
 // Exists checks if an aggregate exists in the event store
 func (r *AggregateRepository) Exists(ctx context.Context, aggregateType, aggregateID string) (bool, error) {
 	version, err := r.eventStore.GetAggregateVersion(ctx, aggregateType, aggregateID)
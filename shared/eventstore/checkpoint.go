@@ -0,0 +1,135 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// CheckpointStore persists the last processed global sequence per subscriber
+// Subscribers resume from their checkpoint after a restart
+type CheckpointStore interface {
+	// GetCheckpoint returns the last processed sequence, or 0 if the subscriber has none yet
+	GetCheckpoint(ctx context.Context, name string) (int64, error)
+
+	// SaveCheckpoint stores the last processed sequence for a subscriber
+	SaveCheckpoint(ctx context.Context, name string, sequence int64) error
+
+	// DeleteCheckpoint removes a subscriber checkpoint so it replays from the beginning
+	DeleteCheckpoint(ctx context.Context, name string) error
+}
+
+// PostgresCheckpointStore implements CheckpointStore using PostgreSQL
+type PostgresCheckpointStore struct {
+	db *sql.DB
+}
+
+// NewPostgresCheckpointStore creates a new PostgreSQL checkpoint store
+func NewPostgresCheckpointStore(db *sql.DB) (*PostgresCheckpointStore, error) {
+	store := &PostgresCheckpointStore{db: db}
+
+	if err := store.createTables(); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint tables: %w", err)
+	}
+
+	return store, nil
+}
+
+// createTables creates the checkpoint table
+func (s *PostgresCheckpointStore) createTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS projector_checkpoints (
+		projector_name VARCHAR(255) PRIMARY KEY,
+		position BIGINT NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+	`
+
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// GetCheckpoint returns the last processed sequence for a subscriber
+func (s *PostgresCheckpointStore) GetCheckpoint(ctx context.Context, name string) (int64, error) {
+	var position int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT position FROM projector_checkpoints WHERE projector_name = $1`,
+		name,
+	).Scan(&position)
+
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+
+	return position, nil
+}
+
+// SaveCheckpoint upserts the last processed sequence for a subscriber
+func (s *PostgresCheckpointStore) SaveCheckpoint(ctx context.Context, name string, sequence int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO projector_checkpoints (projector_name, position, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (projector_name) DO UPDATE SET
+			position = EXCLUDED.position,
+			updated_at = EXCLUDED.updated_at
+	`, name, sequence)
+
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCheckpoint removes a subscriber checkpoint
+func (s *PostgresCheckpointStore) DeleteCheckpoint(ctx context.Context, name string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM projector_checkpoints WHERE projector_name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	return nil
+}
+
+// MemoryCheckpointStore implements CheckpointStore in memory
+// It follows the same semantics as PostgresCheckpointStore and is intended for tests
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]int64
+}
+
+// NewMemoryCheckpointStore creates a new in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]int64),
+	}
+}
+
+// GetCheckpoint returns the last processed sequence for a subscriber
+func (s *MemoryCheckpointStore) GetCheckpoint(ctx context.Context, name string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.checkpoints[name], nil
+}
+
+// SaveCheckpoint stores the last processed sequence for a subscriber
+func (s *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, name string, sequence int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[name] = sequence
+	return nil
+}
+
+// DeleteCheckpoint removes a subscriber checkpoint
+func (s *MemoryCheckpointStore) DeleteCheckpoint(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.checkpoints, name)
+	return nil
+}
@@ -0,0 +1,287 @@
+package eventstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EncryptingEventStore is an EventStore decorator encrypting personal data inside event payloads
+//
+// Fields registered in the PIIRegistry are encrypted with a per-aggregate data key before
+// they reach the wrapped store, and decrypted again on every read. Once an aggregate's key
+// is shredded its fields read as anonymized values, so aggregates can still be replayed and
+// projections rebuilt without the personal data
+//
+// Upcasters have to be set on the decorator instead of the wrapped store: ciphertexts are
+// bound to the field name they were stored under, so payloads are decrypted before their
+// fields are renamed
+type EncryptingEventStore struct {
+	inner     EventStore
+	keys      KeyStore
+	registry  *PIIRegistry
+	upcasters *UpcasterRegistry
+}
+
+// NewEncryptingEventStore wraps an event store with field-level encryption
+func NewEncryptingEventStore(inner EventStore, keys KeyStore, registry *PIIRegistry) *EncryptingEventStore {
+	return &EncryptingEventStore{
+		inner:    inner,
+		keys:     keys,
+		registry: registry,
+	}
+}
+
+// SetUpcasterRegistry sets the registry used to stamp schema versions on append and to
+// upcast payloads after decryption on read
+// The wrapped store must not have an upcaster registry of its own
+func (s *EncryptingEventStore) SetUpcasterRegistry(registry *UpcasterRegistry) {
+	s.upcasters = registry
+}
+
+// ShredAggregate deletes the aggregate's data key, making its personal data permanently unreadable
+// Snapshots hold decrypted state and have to be deleted separately
+func (s *EncryptingEventStore) ShredAggregate(ctx context.Context, aggregateID string) error {
+	return s.keys.ShredKey(ctx, aggregateID)
+}
+
+// SaveEvents encrypts personal data fields and saves the events to the wrapped store
+// Returns ErrKeyShredded when saving personal data for an aggregate whose key was shredded
+func (s *EncryptingEventStore) SaveEvents(ctx context.Context, aggregateID string, expectedVersion int64, events []*EventEnvelope) error {
+	var key []byte
+
+	encrypted := make([]*EventEnvelope, len(events))
+	for i, event := range events {
+		if s.upcasters != nil && event.SchemaVersion == 0 {
+			event.SchemaVersion = s.upcasters.CurrentVersion(event.EventType)
+		}
+
+		fields := s.registry.Fields(event.EventType)
+		if len(fields) == 0 {
+			encrypted[i] = event
+			continue
+		}
+
+		if key == nil {
+			var err error
+			if key, err = s.keys.GetOrCreateKey(ctx, aggregateID); err != nil {
+				return fmt.Errorf("failed to get data key: %w", err)
+			}
+		}
+
+		data, err := encryptFields(key, aggregateID, event.Data, fields)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt event %s: %w", event.EventID, err)
+		}
+
+		// Encrypt a copy so the caller's events keep their plaintext payload
+		encrypted[i] = copyEvent(event)
+		encrypted[i].Data = data
+	}
+
+	if err := s.inner.SaveEvents(ctx, aggregateID, expectedVersion, encrypted); err != nil {
+		return err
+	}
+
+	// Propagate what the wrapped store stamped on the copies
+	for i, event := range events {
+		event.SchemaVersion = encrypted[i].SchemaVersion
+	}
+
+	return nil
+}
+
+// GetEvents retrieves all events for an aggregate
+func (s *EncryptingEventStore) GetEvents(ctx context.Context, aggregateType, aggregateID string) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetEvents(ctx, aggregateType, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetEventsSince retrieves events since a specific version
+func (s *EncryptingEventStore) GetEventsSince(ctx context.Context, aggregateType, aggregateID string, sinceVersion int64) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetEventsSince(ctx, aggregateType, aggregateID, sinceVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetEventsByType retrieves events of a specific type
+func (s *EncryptingEventStore) GetEventsByType(ctx context.Context, aggregateType, aggregateID, eventType string) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetEventsByType(ctx, aggregateType, aggregateID, eventType)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetAllEvents retrieves all events of a specific aggregate type
+func (s *EncryptingEventStore) GetAllEvents(ctx context.Context, aggregateType string, limit, offset int) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetAllEvents(ctx, aggregateType, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetEventStream retrieves events across all aggregates
+func (s *EncryptingEventStore) GetEventStream(ctx context.Context, sinceTimestamp int64, limit int) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetEventStream(ctx, sinceTimestamp, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetEventsAfterSequence retrieves events across all aggregates with a sequence greater than afterSequence
+func (s *EncryptingEventStore) GetEventsAfterSequence(ctx context.Context, afterSequence int64, limit int) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetEventsAfterSequence(ctx, afterSequence, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetLastSequence returns the global sequence of the most recent event
+func (s *EncryptingEventStore) GetLastSequence(ctx context.Context) (int64, error) {
+	return s.inner.GetLastSequence(ctx)
+}
+
+// GetEventsUntilVersion retrieves events for an aggregate up to and including a version
+func (s *EncryptingEventStore) GetEventsUntilVersion(ctx context.Context, aggregateType, aggregateID string, untilVersion int64) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetEventsUntilVersion(ctx, aggregateType, aggregateID, untilVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetEventsUntilTime retrieves events for an aggregate recorded at or before a point in time
+func (s *EncryptingEventStore) GetEventsUntilTime(ctx context.Context, aggregateType, aggregateID string, until time.Time) ([]*EventEnvelope, error) {
+	events, err := s.inner.GetEventsUntilTime(ctx, aggregateType, aggregateID, until)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+// GetEventHistory pages through an aggregate's events in version order
+func (s *EncryptingEventStore) GetEventHistory(ctx context.Context, aggregateType, aggregateID, cursor string, limit int) (*EventPage, error) {
+	page, err := s.inner.GetEventHistory(ctx, aggregateType, aggregateID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.decrypt(ctx, page.Events)
+	if err != nil {
+		return nil, err
+	}
+	page.Events = events
+	return page, nil
+}
+
+// FindEventsByMetadata pages through events whose metadata key equals value
+func (s *EncryptingEventStore) FindEventsByMetadata(ctx context.Context, key, value, cursor string, limit int) (*EventPage, error) {
+	page, err := s.inner.FindEventsByMetadata(ctx, key, value, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.decrypt(ctx, page.Events)
+	if err != nil {
+		return nil, err
+	}
+	page.Events = events
+	return page, nil
+}
+
+// GetAggregateVersion gets the current version of an aggregate
+func (s *EncryptingEventStore) GetAggregateVersion(ctx context.Context, aggregateType, aggregateID string) (int64, error) {
+	return s.inner.GetAggregateVersion(ctx, aggregateType, aggregateID)
+}
+
+// Close closes the wrapped store
+func (s *EncryptingEventStore) Close() error {
+	return s.inner.Close()
+}
+
+// aggregateKey is the data key of an aggregate as seen by a read
+type aggregateKey struct {
+	key      []byte
+	shredded bool
+}
+
+// decrypt decrypts personal data fields in place, anonymizing those whose key was shredded,
+// and upcasts the decrypted payloads
+func (s *EncryptingEventStore) decrypt(ctx context.Context, events []*EventEnvelope) ([]*EventEnvelope, error) {
+	// Look up each aggregate's key once per read; nil for aggregates without key
+	keys := make(map[string]*aggregateKey)
+
+	for _, event := range events {
+		fields := s.registry.Fields(event.EventType)
+		encrypted := bytes.Contains(event.Data, []byte(encryptedPrefix))
+
+		var key *aggregateKey
+		if len(fields) > 0 || encrypted {
+			var err error
+			if key, err = s.aggregateKey(ctx, keys, event.AggregateID); err != nil {
+				return nil, err
+			}
+		}
+
+		if key != nil && encrypted {
+			data, err := decryptFields(key.key, event.AggregateID, event.Data, fields)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt event %s: %w", event.EventID, err)
+			}
+			event.Data = data
+		}
+
+		if s.upcasters != nil {
+			// The wrapped store has no upcasters and flags every newer schema as unknown
+			event.schemaErr = nil
+			if err := upcastOnRead(s.upcasters, event); err != nil {
+				return nil, err
+			}
+		}
+
+		// Fields written before encryption was enabled are anonymized as well
+		if key != nil && key.shredded && len(fields) > 0 {
+			data, err := anonymizeFields(event.AggregateID, event.Data, fields)
+			if err != nil {
+				return nil, fmt.Errorf("failed to anonymize event %s: %w", event.EventID, err)
+			}
+			event.Data = data
+		}
+	}
+
+	return events, nil
+}
+
+// aggregateKey returns the cached data key of an aggregate, or nil if it never had one
+// because its events were written before encryption was enabled
+func (s *EncryptingEventStore) aggregateKey(ctx context.Context, keys map[string]*aggregateKey, aggregateID string) (*aggregateKey, error) {
+	if key, ok := keys[aggregateID]; ok {
+		return key, nil
+	}
+
+	var key *aggregateKey
+	data, err := s.keys.GetKey(ctx, aggregateID)
+	switch {
+	case errors.Is(err, ErrKeyShredded):
+		key = &aggregateKey{shredded: true}
+	case errors.Is(err, ErrKeyNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get data key: %w", err)
+	default:
+		key = &aggregateKey{key: data}
+	}
+
+	keys[aggregateID] = key
+	return key, nil
+}
@@ -0,0 +1,317 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	"github.com/toxictoast/toxictoastgo/shared/eventstore/eventstoretest"
+)
+
+type personRegistered struct {
+	Email    string `json:"email" pii:"email"`
+	Username string `json:"username" pii:""`
+	Bio      string `json:"bio,omitempty" pii:"empty"`
+	Plan     string `json:"plan"`
+}
+
+type personRenamed struct {
+	Username string `json:"new_username" pii:"token"`
+}
+
+const (
+	eventTypePersonRegistered = "person.registered"
+	eventTypePersonRenamed    = "person.renamed"
+	eventTypePersonVerified   = "person.verified"
+)
+
+func newEncryptingStore(t *testing.T) (*eventstore.EncryptingEventStore, *eventstore.MemoryEventStore) {
+	t.Helper()
+
+	registry := eventstore.NewPIIRegistry()
+	if err := registry.Register(eventTypePersonRegistered, personRegistered{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register(eventTypePersonRenamed, &personRenamed{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	inner := eventstore.NewMemoryEventStore()
+	return eventstore.NewEncryptingEventStore(inner, eventstore.NewMemoryKeyStore(), registry), inner
+}
+
+func savePerson(t *testing.T, store eventstore.EventStore, id string) {
+	t.Helper()
+
+	registered, err := eventstore.NewEventEnvelope("person", id, eventTypePersonRegistered, 0, personRegistered{
+		Email:    "john@example.com",
+		Username: "johndoe",
+		Bio:      "Likes toast",
+		Plan:     "pro",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	renamed, err := eventstore.NewEventEnvelope("person", id, eventTypePersonRenamed, 1, personRenamed{Username: "jdoe"})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+
+	if err := store.SaveEvents(context.Background(), id, -1, []*eventstore.EventEnvelope{registered, renamed}); err != nil {
+		t.Fatalf("SaveEvents failed: %v", err)
+	}
+}
+
+func TestEncryptingEventStore_Conformance(t *testing.T) {
+	eventstoretest.RunEventStoreSuite(t, func(t *testing.T) eventstore.EventStore {
+		store, _ := newEncryptingStore(t)
+		return store
+	})
+}
+
+func TestEncryptingEventStore_EncryptsAtRest(t *testing.T) {
+	ctx := context.Background()
+	store, inner := newEncryptingStore(t)
+	savePerson(t, store, "person-1")
+
+	raw, err := inner.GetEvents(ctx, "person", "person-1")
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	for _, event := range raw {
+		for _, secret := range []string{"john@example.com", "johndoe", "Likes toast", "jdoe"} {
+			if strings.Contains(string(event.Data), secret) {
+				t.Errorf("Expected %q to be encrypted at rest, got %s", secret, event.Data)
+			}
+		}
+	}
+	if !strings.Contains(string(raw[0].Data), `"plan":"pro"`) {
+		t.Errorf("Expected untagged fields to stay readable, got %s", raw[0].Data)
+	}
+
+	events, err := store.GetEvents(ctx, "person", "person-1")
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+
+	var person personRegistered
+	if err := events[0].UnmarshalData(&person); err != nil {
+		t.Fatalf("UnmarshalData failed: %v", err)
+	}
+	if person.Email != "john@example.com" || person.Username != "johndoe" || person.Bio != "Likes toast" {
+		t.Errorf("Expected decrypted payload, got %+v", person)
+	}
+}
+
+func TestEncryptingEventStore_ShredAggregate(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newEncryptingStore(t)
+	savePerson(t, store, "person-1")
+	savePerson(t, store, "person-2")
+
+	if err := store.ShredAggregate(ctx, "person-1"); err != nil {
+		t.Fatalf("ShredAggregate failed: %v", err)
+	}
+
+	events, err := store.GetAllEvents(ctx, "person", 100, 0)
+	if err != nil {
+		t.Fatalf("GetAllEvents failed: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events, got %d", len(events))
+	}
+
+	for _, event := range events {
+		if event.EventType != eventTypePersonRegistered {
+			continue
+		}
+
+		var person personRegistered
+		if err := event.UnmarshalData(&person); err != nil {
+			t.Fatalf("UnmarshalData failed: %v", err)
+		}
+
+		expected := personRegistered{Email: "john@example.com", Username: "johndoe", Bio: "Likes toast", Plan: "pro"}
+		if event.AggregateID == "person-1" {
+			expected = personRegistered{
+				Email:    "erased-person-1@erased.invalid",
+				Username: "erased-person-1",
+				Plan:     "pro",
+			}
+		}
+		if person != expected {
+			t.Errorf("Expected %+v for %s, got %+v", expected, event.AggregateID, person)
+		}
+	}
+
+	renamed, err := eventstore.NewEventEnvelope("person", "person-1", eventTypePersonRenamed, 2, personRenamed{Username: "again"})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	err = store.SaveEvents(ctx, "person-1", 1, []*eventstore.EventEnvelope{renamed})
+	if !errors.Is(err, eventstore.ErrKeyShredded) {
+		t.Errorf("Expected ErrKeyShredded when saving personal data, got %v", err)
+	}
+
+	verified, err := eventstore.NewEventEnvelope("person", "person-1", eventTypePersonVerified, 2, map[string]bool{"verified": true})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	if err := store.SaveEvents(ctx, "person-1", 1, []*eventstore.EventEnvelope{verified}); err != nil {
+		t.Errorf("Expected events without personal data to be saved, got %v", err)
+	}
+}
+
+func TestEncryptingEventStore_PlaintextEvents(t *testing.T) {
+	ctx := context.Background()
+	store, inner := newEncryptingStore(t)
+
+	// Events written before encryption was enabled
+	savePerson(t, inner, "legacy")
+
+	events, err := store.GetEvents(ctx, "person", "legacy")
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+
+	var person personRegistered
+	if err := events[0].UnmarshalData(&person); err != nil {
+		t.Fatalf("UnmarshalData failed: %v", err)
+	}
+	if person.Email != "john@example.com" {
+		t.Errorf("Expected plaintext email to be read as is, got %s", person.Email)
+	}
+
+	if err := store.ShredAggregate(ctx, "legacy"); err != nil {
+		t.Fatalf("ShredAggregate failed: %v", err)
+	}
+	events, err = store.GetEvents(ctx, "person", "legacy")
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if err := events[0].UnmarshalData(&person); err != nil {
+		t.Fatalf("UnmarshalData failed: %v", err)
+	}
+	if person.Email != "erased-legacy@erased.invalid" {
+		t.Errorf("Expected plaintext email to be anonymized after shredding, got %s", person.Email)
+	}
+}
+
+func TestEncryptingEventStore_RenamingUpcaster(t *testing.T) {
+	ctx := context.Background()
+	inner := eventstore.NewMemoryEventStore()
+	keys := eventstore.NewMemoryKeyStore()
+
+	// Version 1 stored the new username as "username"
+	v1Registry := eventstore.NewPIIRegistry()
+	if err := v1Registry.Register(eventTypePersonRenamed, struct {
+		Username string `json:"username" pii:"token"`
+	}{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	v1 := eventstore.NewEncryptingEventStore(inner, keys, v1Registry)
+
+	old, err := eventstore.NewEventEnvelope("person", "person-1", eventTypePersonRenamed, 0, map[string]string{"username": "jdoe"})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	if err := v1.SaveEvents(ctx, "person-1", -1, []*eventstore.EventEnvelope{old}); err != nil {
+		t.Fatalf("SaveEvents failed: %v", err)
+	}
+
+	// Version 2 renamed it to "new_username"
+	registry := eventstore.NewPIIRegistry()
+	if err := registry.Register(eventTypePersonRenamed, personRenamed{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	upcasters := eventstore.NewUpcasterRegistry()
+	upcasters.Register(eventTypePersonRenamed, 1, eventstore.RenameField("username", "new_username"))
+
+	store := eventstore.NewEncryptingEventStore(inner, keys, registry)
+	store.SetUpcasterRegistry(upcasters)
+
+	current, err := eventstore.NewEventEnvelope("person", "person-1", eventTypePersonRenamed, 1, personRenamed{Username: "johnd"})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	if err := store.SaveEvents(ctx, "person-1", 0, []*eventstore.EventEnvelope{current}); err != nil {
+		t.Fatalf("SaveEvents failed: %v", err)
+	}
+	if current.SchemaVersion != 2 {
+		t.Errorf("Expected new events to be stamped with schema version 2, got %d", current.SchemaVersion)
+	}
+
+	read := func() []personRenamed {
+		events, err := store.GetEvents(ctx, "person", "person-1")
+		if err != nil {
+			t.Fatalf("GetEvents failed: %v", err)
+		}
+		payloads := make([]personRenamed, len(events))
+		for i, event := range events {
+			if event.SchemaVersion != 2 {
+				t.Errorf("Expected event to be upcast to version 2, got %d", event.SchemaVersion)
+			}
+			if err := event.UnmarshalData(&payloads[i]); err != nil {
+				t.Fatalf("UnmarshalData failed: %v", err)
+			}
+		}
+		return payloads
+	}
+
+	payloads := read()
+	if len(payloads) != 2 || payloads[0].Username != "jdoe" || payloads[1].Username != "johnd" {
+		t.Errorf("Expected decrypted usernames under the renamed field, got %+v", payloads)
+	}
+
+	if err := store.ShredAggregate(ctx, "person-1"); err != nil {
+		t.Fatalf("ShredAggregate failed: %v", err)
+	}
+	for _, payload := range read() {
+		if payload.Username != "erased-person-1" {
+			t.Errorf("Expected renamed field to be anonymized after shredding, got %q", payload.Username)
+		}
+	}
+}
+
+func TestPIIRegistry_Register(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     interface{}
+		expectedErr error
+	}{
+		{
+			name:    "tagged struct",
+			payload: personRegistered{},
+		},
+		{
+			name:        "not a struct",
+			payload:     map[string]string{},
+			expectedErr: eventstore.ErrInvalidPIIField,
+		},
+		{
+			name: "non-string field",
+			payload: struct {
+				Age int `json:"age" pii:"true"`
+			}{},
+			expectedErr: eventstore.ErrInvalidPIIField,
+		},
+		{
+			name: "unknown mode",
+			payload: struct {
+				Name string `json:"name" pii:"hash"`
+			}{},
+			expectedErr: eventstore.ErrInvalidPIIField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := eventstore.NewPIIRegistry().Register("person.registered", tt.payload)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -17,11 +17,22 @@ type EventEnvelope struct {
 	Version       int64     `json:"version" db:"version"`
 	Timestamp     time.Time `json:"timestamp" db:"timestamp"`
 
+	// Sequence is the global position assigned by the event store on append
+	// It is zero for events that have not been persisted yet
+	Sequence int64 `json:"sequence,omitempty" db:"sequence"`
+
+	// SchemaVersion is the version of the payload schema
+	// Zero means the current version, which the event store fills in on append
+	SchemaVersion int `json:"schema_version,omitempty" db:"schema_version"`
+
 	// Event payload
 	Data json.RawMessage `json:"data" db:"data"`
 
 	// Metadata
 	Metadata map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
+
+	// schemaErr is set when the payload could not be upcast to the current schema
+	schemaErr error
 }
 
 // NewEventEnvelope creates a new event envelope
@@ -44,10 +55,19 @@ func NewEventEnvelope(aggregateType, aggregateID, eventType string, version int6
 }
 
 // UnmarshalData unmarshals the event data into the provided struct
+// Fails with ErrUnknownSchema if the payload schema is unknown to this service
 func (e *EventEnvelope) UnmarshalData(v interface{}) error {
+	if e.schemaErr != nil {
+		return e.schemaErr
+	}
 	return json.Unmarshal(e.Data, v)
 }
 
+// SchemaError returns the reason the payload could not be upcast, or nil
+func (e *EventEnvelope) SchemaError() error {
+	return e.schemaErr
+}
+
 // WithMetadata adds metadata to the event
 func (e *EventEnvelope) WithMetadata(key string, value interface{}) *EventEnvelope {
 	if e.Metadata == nil {
@@ -74,6 +94,7 @@ const (
 	EventTypeUserDeleted  = "user.deleted"
 	EventTypeUserActivated = "user.activated"
 	EventTypeUserDeactivated = "user.deactivated"
+	EventTypeUserErased      = "user.erased"
 
 	// Auth aggregate events
 	EventTypeAuthRegistered    = "auth.registered"
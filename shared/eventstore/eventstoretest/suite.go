@@ -0,0 +1,698 @@
+// Package eventstoretest provides conformance tests that every EventStore and
+// SnapshotStore implementation must pass
+package eventstoretest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+const testAggregateType = "conformance"
+
+// EventStoreFactory returns an empty event store for a single test
+type EventStoreFactory func(t *testing.T) eventstore.EventStore
+
+// SnapshotStoreFactory returns an empty snapshot store for a single test
+type SnapshotStoreFactory func(t *testing.T) eventstore.SnapshotStore
+
+// RunEventStoreSuite runs the event store conformance tests against the given factory
+func RunEventStoreSuite(t *testing.T, newStore EventStoreFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store eventstore.EventStore)
+	}{
+		{"SaveAndLoadInVersionOrder", testSaveAndLoad},
+		{"SaveNoEventsIsNoop", testSaveNoEvents},
+		{"ConcurrencyConflictOnWrongVersion", testConcurrencyConflict},
+		{"ConcurrencyConflictOnDuplicateEventID", testDuplicateEventID},
+		{"FailedAppendStoresNothing", testAtomicAppend},
+		{"ConcurrentWritersOnlyOneWins", testConcurrentWriters},
+		{"GetEventsSince", testGetEventsSince},
+		{"GetEventsByType", testGetEventsByType},
+		{"GetAggregateVersion", testGetAggregateVersion},
+		{"GetAllEventsPaging", testGetAllEvents},
+		{"GetEventStream", testGetEventStream},
+		{"GetEventsAfterSequence", testGetEventsAfterSequence},
+		{"SchemaVersionStampedOnAppend", testSchemaVersion},
+		{"GetEventsUntilVersion", testGetEventsUntilVersion},
+		{"GetEventsUntilTime", testGetEventsUntilTime},
+		{"GetEventHistoryPaging", testGetEventHistory},
+		{"FindEventsByMetadata", testFindEventsByMetadata},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t))
+		})
+	}
+}
+
+// RunSnapshotStoreSuite runs the snapshot store conformance tests against the given factory
+func RunSnapshotStoreSuite(t *testing.T, newStore SnapshotStoreFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store eventstore.SnapshotStore)
+	}{
+		{"MissingSnapshotIsNil", testMissingSnapshot},
+		{"LatestSnapshotWins", testLatestSnapshot},
+		{"SaveSameVersionReplaces", testReplaceSnapshot},
+		{"DeleteSnapshot", testDeleteSnapshot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t))
+		})
+	}
+}
+
+// newEvents builds count events for an aggregate starting at fromVersion
+func newEvents(t *testing.T, aggregateID string, fromVersion int64, count int, timestamp time.Time) []*eventstore.EventEnvelope {
+	t.Helper()
+
+	events := make([]*eventstore.EventEnvelope, count)
+	for i := range events {
+		version := fromVersion + int64(i)
+		event, err := eventstore.NewEventEnvelope(testAggregateType, aggregateID, "conformance.happened", version, map[string]int64{"n": version})
+		if err != nil {
+			t.Fatalf("Failed to create event: %v", err)
+		}
+		event.Timestamp = timestamp.Add(time.Duration(i) * time.Millisecond)
+		events[i] = event
+	}
+	return events
+}
+
+// save appends events and fails the test on error
+func save(t *testing.T, store eventstore.EventStore, aggregateID string, expectedVersion int64, events []*eventstore.EventEnvelope) {
+	t.Helper()
+
+	if err := store.SaveEvents(context.Background(), aggregateID, expectedVersion, events); err != nil {
+		t.Fatalf("SaveEvents failed: %v", err)
+	}
+}
+
+// baseTime is a fixed, second aligned timestamp shared by the tests
+func baseTime() time.Time {
+	return time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+}
+
+func testSaveAndLoad(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	events := newEvents(t, id, 0, 3, baseTime())
+	save(t, store, id, -1, events)
+
+	loaded, err := store.GetEvents(ctx, testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(loaded))
+	}
+
+	for i, event := range loaded {
+		if event.Version != int64(i) {
+			t.Errorf("Expected version %d, got %d", i, event.Version)
+		}
+		if event.EventID != events[i].EventID {
+			t.Errorf("Expected event ID %s, got %s", events[i].EventID, event.EventID)
+		}
+		if event.Sequence <= 0 {
+			t.Errorf("Expected a positive sequence, got %d", event.Sequence)
+		}
+
+		var data map[string]int64
+		if err := event.UnmarshalData(&data); err != nil {
+			t.Fatalf("Failed to unmarshal data: %v", err)
+		}
+		if data["n"] != int64(i) {
+			t.Errorf("Expected data n=%d, got %d", i, data["n"])
+		}
+	}
+
+	other, err := store.GetEvents(ctx, "other", id)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("Expected no events for another aggregate type, got %d", len(other))
+	}
+}
+
+func testSaveNoEvents(t *testing.T, store eventstore.EventStore) {
+	id := uuid.New().String()
+
+	// The expected version is not checked when there is nothing to append
+	if err := store.SaveEvents(context.Background(), id, 42, nil); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func testConcurrencyConflict(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	save(t, store, id, -1, newEvents(t, id, 0, 2, baseTime()))
+
+	tests := []struct {
+		name            string
+		expectedVersion int64
+	}{
+		{"new aggregate expected", -1},
+		{"stale version", 0},
+		{"future version", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.SaveEvents(ctx, id, tt.expectedVersion, newEvents(t, id, tt.expectedVersion+1, 1, baseTime()))
+			if !errors.Is(err, eventstore.ErrConcurrencyConflict) {
+				t.Errorf("Expected ErrConcurrencyConflict, got %v", err)
+			}
+		})
+	}
+
+	save(t, store, id, 1, newEvents(t, id, 2, 1, baseTime()))
+}
+
+func testDuplicateEventID(t *testing.T, store eventstore.EventStore) {
+	id := uuid.New().String()
+
+	events := newEvents(t, id, 0, 1, baseTime())
+	save(t, store, id, -1, events)
+
+	duplicate := newEvents(t, id, 1, 1, baseTime())
+	duplicate[0].EventID = events[0].EventID
+
+	err := store.SaveEvents(context.Background(), id, 0, duplicate)
+	if !errors.Is(err, eventstore.ErrConcurrencyConflict) {
+		t.Errorf("Expected ErrConcurrencyConflict, got %v", err)
+	}
+}
+
+func testAtomicAppend(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	// The second event repeats version 0, so the whole batch must be rejected
+	events := newEvents(t, id, 0, 2, baseTime())
+	events[1].Version = 0
+
+	if err := store.SaveEvents(ctx, id, -1, events); err == nil {
+		t.Fatal("Expected an error for duplicate versions, got nil")
+	}
+
+	version, err := store.GetAggregateVersion(ctx, testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetAggregateVersion failed: %v", err)
+	}
+	if version != -1 {
+		t.Errorf("Expected version -1 after failed append, got %d", version)
+	}
+}
+
+func testConcurrentWriters(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	save(t, store, id, -1, newEvents(t, id, 0, 1, baseTime()))
+
+	const writers = 8
+	var wg sync.WaitGroup
+	results := make(chan error, writers)
+
+	for i := 0; i < writers; i++ {
+		events := newEvents(t, id, 1, 1, baseTime())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- store.SaveEvents(ctx, id, 0, events)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, eventstore.ErrConcurrencyConflict):
+			t.Errorf("Expected ErrConcurrencyConflict, got %v", err)
+		}
+	}
+
+	if succeeded != 1 {
+		t.Errorf("Expected exactly 1 successful writer, got %d", succeeded)
+	}
+}
+
+func testGetEventsSince(t *testing.T, store eventstore.EventStore) {
+	id := uuid.New().String()
+	save(t, store, id, -1, newEvents(t, id, 0, 5, baseTime()))
+
+	events, err := store.GetEventsSince(context.Background(), testAggregateType, id, 2)
+	if err != nil {
+		t.Fatalf("GetEventsSince failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Version != 3 || events[1].Version != 4 {
+		t.Errorf("Expected versions 3 and 4, got %d and %d", events[0].Version, events[1].Version)
+	}
+}
+
+func testGetEventsByType(t *testing.T, store eventstore.EventStore) {
+	id := uuid.New().String()
+
+	events := newEvents(t, id, 0, 4, baseTime())
+	events[1].EventType = "conformance.other"
+	events[3].EventType = "conformance.other"
+	save(t, store, id, -1, events)
+
+	matched, err := store.GetEventsByType(context.Background(), testAggregateType, id, "conformance.other")
+	if err != nil {
+		t.Fatalf("GetEventsByType failed: %v", err)
+	}
+	if len(matched) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(matched))
+	}
+	if matched[0].Version != 1 || matched[1].Version != 3 {
+		t.Errorf("Expected versions 1 and 3, got %d and %d", matched[0].Version, matched[1].Version)
+	}
+}
+
+func testGetAggregateVersion(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	version, err := store.GetAggregateVersion(ctx, testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetAggregateVersion failed: %v", err)
+	}
+	if version != -1 {
+		t.Errorf("Expected version -1 for a new aggregate, got %d", version)
+	}
+
+	save(t, store, id, -1, newEvents(t, id, 0, 3, baseTime()))
+
+	version, err = store.GetAggregateVersion(ctx, testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetAggregateVersion failed: %v", err)
+	}
+	if version != 2 {
+		t.Errorf("Expected version 2, got %d", version)
+	}
+}
+
+func testGetAllEvents(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	start := baseTime()
+
+	first := uuid.New().String()
+	second := uuid.New().String()
+	save(t, store, first, -1, newEvents(t, first, 0, 2, start))
+	save(t, store, second, -1, newEvents(t, second, 0, 2, start.Add(time.Second)))
+
+	all, err := store.GetAllEvents(ctx, testAggregateType, 10, 0)
+	if err != nil {
+		t.Fatalf("GetAllEvents failed: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Expected 4 events, got %d", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Timestamp.Before(all[i-1].Timestamp) {
+			t.Errorf("Expected events ordered by timestamp, event %d is older than event %d", i, i-1)
+		}
+	}
+
+	paged, err := store.GetAllEvents(ctx, testAggregateType, 2, 1)
+	if err != nil {
+		t.Fatalf("GetAllEvents failed: %v", err)
+	}
+	if len(paged) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(paged))
+	}
+	if paged[0].EventID != all[1].EventID || paged[1].EventID != all[2].EventID {
+		t.Errorf("Expected page to contain events 1 and 2")
+	}
+}
+
+func testGetEventStream(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	start := baseTime()
+
+	early := uuid.New().String()
+	late := uuid.New().String()
+	save(t, store, late, -1, newEvents(t, late, 0, 2, start.Add(2*time.Second)))
+	save(t, store, early, -1, newEvents(t, early, 0, 2, start))
+
+	stream, err := store.GetEventStream(ctx, start.Add(-time.Second).Unix(), 10)
+	if err != nil {
+		t.Fatalf("GetEventStream failed: %v", err)
+	}
+	if len(stream) != 4 {
+		t.Fatalf("Expected 4 events, got %d", len(stream))
+	}
+	if stream[0].AggregateID != early || stream[3].AggregateID != late {
+		t.Errorf("Expected events ordered by timestamp rather than insert order")
+	}
+
+	// Only events strictly after the given second are returned
+	stream, err = store.GetEventStream(ctx, start.Add(time.Second).Unix(), 10)
+	if err != nil {
+		t.Fatalf("GetEventStream failed: %v", err)
+	}
+	if len(stream) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(stream))
+	}
+	for _, event := range stream {
+		if event.AggregateID != late {
+			t.Errorf("Expected only events of %s, got %s", late, event.AggregateID)
+		}
+	}
+
+	limited, err := store.GetEventStream(ctx, start.Add(-time.Second).Unix(), 1)
+	if err != nil {
+		t.Fatalf("GetEventStream failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("Expected 1 event, got %d", len(limited))
+	}
+}
+
+func testGetEventsAfterSequence(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+
+	first := uuid.New().String()
+	second := uuid.New().String()
+	save(t, store, first, -1, newEvents(t, first, 0, 2, baseTime()))
+	save(t, store, second, -1, newEvents(t, second, 0, 2, baseTime()))
+
+	all, err := store.GetEventsAfterSequence(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("GetEventsAfterSequence failed: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Expected 4 events, got %d", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Sequence <= all[i-1].Sequence {
+			t.Errorf("Expected strictly increasing sequences, got %d after %d", all[i].Sequence, all[i-1].Sequence)
+		}
+	}
+	if all[0].AggregateID != first || all[2].AggregateID != second {
+		t.Errorf("Expected events in append order")
+	}
+
+	rest, err := store.GetEventsAfterSequence(ctx, all[1].Sequence, 1)
+	if err != nil {
+		t.Fatalf("GetEventsAfterSequence failed: %v", err)
+	}
+	if len(rest) != 1 || rest[0].EventID != all[2].EventID {
+		t.Errorf("Expected the third event after sequence %d", all[1].Sequence)
+	}
+
+	none, err := store.GetEventsAfterSequence(ctx, all[3].Sequence, 10)
+	if err != nil {
+		t.Fatalf("GetEventsAfterSequence failed: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("Expected no events after the last sequence, got %d", len(none))
+	}
+
+	last, err := store.GetLastSequence(ctx)
+	if err != nil {
+		t.Fatalf("GetLastSequence failed: %v", err)
+	}
+	if last != all[3].Sequence {
+		t.Errorf("Expected last sequence %d, got %d", all[3].Sequence, last)
+	}
+}
+
+func testSchemaVersion(t *testing.T, store eventstore.EventStore) {
+	id := uuid.New().String()
+
+	events := newEvents(t, id, 0, 2, baseTime())
+	events[1].SchemaVersion = eventstore.DefaultSchemaVersion
+	save(t, store, id, -1, events)
+
+	loaded, err := store.GetEvents(context.Background(), testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	for _, event := range loaded {
+		if event.SchemaVersion != eventstore.DefaultSchemaVersion {
+			t.Errorf("Expected schema version %d, got %d", eventstore.DefaultSchemaVersion, event.SchemaVersion)
+		}
+		if event.SchemaError() != nil {
+			t.Errorf("Expected no schema error, got %v", event.SchemaError())
+		}
+	}
+}
+
+func testGetEventsUntilVersion(t *testing.T, store eventstore.EventStore) {
+	id := uuid.New().String()
+	save(t, store, id, -1, newEvents(t, id, 0, 5, baseTime()))
+
+	events, err := store.GetEventsUntilVersion(context.Background(), testAggregateType, id, 2)
+	if err != nil {
+		t.Fatalf("GetEventsUntilVersion failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
+	}
+	if events[2].Version != 2 {
+		t.Errorf("Expected last version 2, got %d", events[2].Version)
+	}
+}
+
+func testGetEventsUntilTime(t *testing.T, store eventstore.EventStore) {
+	id := uuid.New().String()
+	start := baseTime()
+
+	events := newEvents(t, id, 0, 3, start)
+	events[1].Timestamp = start.Add(time.Minute)
+	events[2].Timestamp = start.Add(2 * time.Minute)
+	save(t, store, id, -1, events)
+
+	tests := []struct {
+		name     string
+		until    time.Time
+		expected int
+	}{
+		{"before first event", start.Add(-time.Second), 0},
+		{"exactly at an event", start.Add(time.Minute), 2},
+		{"between events", start.Add(90 * time.Second), 2},
+		{"after last event", start.Add(time.Hour), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := store.GetEventsUntilTime(context.Background(), testAggregateType, id, tt.until)
+			if err != nil {
+				t.Fatalf("GetEventsUntilTime failed: %v", err)
+			}
+			if len(loaded) != tt.expected {
+				t.Errorf("Expected %d events, got %d", tt.expected, len(loaded))
+			}
+		})
+	}
+}
+
+func testGetEventHistory(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+	save(t, store, id, -1, newEvents(t, id, 0, 5, baseTime()))
+
+	var versions []int64
+	cursor := ""
+	pages := 0
+	for {
+		page, err := store.GetEventHistory(ctx, testAggregateType, id, cursor, 2)
+		if err != nil {
+			t.Fatalf("GetEventHistory failed: %v", err)
+		}
+		pages++
+		for _, event := range page.Events {
+			versions = append(versions, event.Version)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+	if len(versions) != 5 {
+		t.Fatalf("Expected 5 events, got %d", len(versions))
+	}
+	for i, version := range versions {
+		if version != int64(i) {
+			t.Errorf("Expected version %d at position %d, got %d", i, i, version)
+		}
+	}
+
+	if _, err := store.GetEventHistory(ctx, testAggregateType, id, "not-a-cursor", 2); !errors.Is(err, eventstore.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func testFindEventsByMetadata(t *testing.T, store eventstore.EventStore) {
+	ctx := context.Background()
+	correlationID := uuid.New().String()
+
+	first := uuid.New().String()
+	second := uuid.New().String()
+
+	firstEvents := newEvents(t, first, 0, 3, baseTime())
+	firstEvents[0].WithMetadata(eventstore.MetadataCorrelationID, correlationID)
+	firstEvents[2].WithMetadata(eventstore.MetadataCorrelationID, correlationID)
+	firstEvents[1].WithMetadata(eventstore.MetadataCorrelationID, uuid.New().String())
+	save(t, store, first, -1, firstEvents)
+
+	secondEvents := newEvents(t, second, 0, 1, baseTime())
+	secondEvents[0].WithMetadata(eventstore.MetadataCorrelationID, correlationID)
+	secondEvents[0].WithMetadata(eventstore.MetadataActor, "admin")
+	save(t, store, second, -1, secondEvents)
+
+	page, err := store.FindEventsByMetadata(ctx, eventstore.MetadataCorrelationID, correlationID, "", 2)
+	if err != nil {
+		t.Fatalf("FindEventsByMetadata failed: %v", err)
+	}
+	if len(page.Events) != 2 || page.NextCursor == "" {
+		t.Fatalf("Expected a full first page with a cursor, got %d events", len(page.Events))
+	}
+	if page.Events[0].EventID != firstEvents[0].EventID || page.Events[1].EventID != firstEvents[2].EventID {
+		t.Errorf("Expected matching events in sequence order")
+	}
+
+	page, err = store.FindEventsByMetadata(ctx, eventstore.MetadataCorrelationID, correlationID, page.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("FindEventsByMetadata failed: %v", err)
+	}
+	if len(page.Events) != 1 || page.Events[0].EventID != secondEvents[0].EventID {
+		t.Errorf("Expected the event of the second aggregate on the last page")
+	}
+	if page.NextCursor != "" {
+		t.Errorf("Expected no cursor on the last page, got %s", page.NextCursor)
+	}
+
+	page, err = store.FindEventsByMetadata(ctx, eventstore.MetadataActor, "admin", "", 10)
+	if err != nil {
+		t.Fatalf("FindEventsByMetadata failed: %v", err)
+	}
+	if len(page.Events) != 1 {
+		t.Errorf("Expected 1 event by actor, got %d", len(page.Events))
+	}
+}
+
+func testMissingSnapshot(t *testing.T, store eventstore.SnapshotStore) {
+	snapshot, err := store.GetSnapshot(context.Background(), testAggregateType, uuid.New().String())
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if snapshot != nil {
+		t.Errorf("Expected nil snapshot, got version %d", snapshot.Version)
+	}
+}
+
+func testLatestSnapshot(t *testing.T, store eventstore.SnapshotStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	for _, version := range []int64{10, 30, 20} {
+		err := store.SaveSnapshot(ctx, &eventstore.Snapshot{
+			AggregateType: testAggregateType,
+			AggregateID:   id,
+			Version:       version,
+			State:         []byte(fmt.Sprintf(`{"version":%d}`, version)),
+		})
+		if err != nil {
+			t.Fatalf("SaveSnapshot failed: %v", err)
+		}
+	}
+
+	snapshot, err := store.GetSnapshot(ctx, testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if snapshot == nil {
+		t.Fatal("Expected a snapshot, got nil")
+	}
+	if snapshot.Version != 30 {
+		t.Errorf("Expected version 30, got %d", snapshot.Version)
+	}
+	if string(snapshot.State) != `{"version":30}` {
+		t.Errorf("Expected state of version 30, got %s", snapshot.State)
+	}
+	if snapshot.CreatedAt == 0 {
+		t.Errorf("Expected CreatedAt to be set")
+	}
+}
+
+func testReplaceSnapshot(t *testing.T, store eventstore.SnapshotStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	for _, state := range []string{`{"a":1}`, `{"a":2}`} {
+		err := store.SaveSnapshot(ctx, &eventstore.Snapshot{
+			AggregateType: testAggregateType,
+			AggregateID:   id,
+			Version:       5,
+			State:         []byte(state),
+		})
+		if err != nil {
+			t.Fatalf("SaveSnapshot failed: %v", err)
+		}
+	}
+
+	snapshot, err := store.GetSnapshot(ctx, testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if snapshot == nil || string(snapshot.State) != `{"a":2}` {
+		t.Errorf("Expected the replaced state")
+	}
+}
+
+func testDeleteSnapshot(t *testing.T, store eventstore.SnapshotStore) {
+	ctx := context.Background()
+	id := uuid.New().String()
+
+	err := store.SaveSnapshot(ctx, &eventstore.Snapshot{
+		AggregateType: testAggregateType,
+		AggregateID:   id,
+		Version:       1,
+		State:         []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+
+	if err := store.DeleteSnapshot(ctx, testAggregateType, id); err != nil {
+		t.Fatalf("DeleteSnapshot failed: %v", err)
+	}
+
+	snapshot, err := store.GetSnapshot(ctx, testAggregateType, id)
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if snapshot != nil {
+		t.Errorf("Expected nil snapshot after delete, got version %d", snapshot.Version)
+	}
+}
@@ -0,0 +1,134 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultHistoryPageSize is used when a history or search query has no limit
+	DefaultHistoryPageSize = 50
+
+	// MaxHistoryPageSize caps the page size of history and search queries
+	MaxHistoryPageSize = 500
+
+	// cursorPrefix versions the cursor format
+	cursorPrefix = "v1:"
+)
+
+// Well-known metadata keys recorded on events
+const (
+	MetadataCorrelationID = "correlation_id"
+	MetadataCausationID   = "causation_id"
+	MetadataActor         = "actor"
+	MetadataCommand       = "command"
+)
+
+var (
+	// ErrInvalidCursor is returned when a history cursor can't be decoded
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// EventPage is one page of a history or search query
+type EventPage struct {
+	Events []*EventEnvelope `json:"events"`
+
+	// NextCursor fetches the following page; empty when there are no more events
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// encodeCursor encodes a position (a version or a sequence) as an opaque cursor
+func encodeCursor(position int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(position, 10)))
+}
+
+// decodeCursor decodes a cursor, returning defaultPosition for an empty cursor
+func decodeCursor(cursor string, defaultPosition int64) (int64, error) {
+	if cursor == "" {
+		return defaultPosition, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+
+	position, err := strconv.ParseInt(strings.TrimPrefix(string(raw), cursorPrefix), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return position, nil
+}
+
+// normalizePageSize applies the default and maximum page size
+func normalizePageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultHistoryPageSize
+	}
+	if limit > MaxHistoryPageSize {
+		return MaxHistoryPageSize
+	}
+	return limit
+}
+
+// newEventPage trims events fetched with limit+1 rows and sets the next cursor
+func newEventPage(events []*EventEnvelope, limit int, position func(*EventEnvelope) int64) *EventPage {
+	page := &EventPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.NextCursor = encodeCursor(position(page.Events[limit-1]))
+	}
+	return page
+}
+
+// eventMetadataKey is the context key for metadata stamped on saved events
+type eventMetadataKey struct{}
+
+// WithEventMetadata returns a context that stamps key=value on every event saved through an AggregateRepository
+// Metadata set explicitly on an event takes precedence
+func WithEventMetadata(ctx context.Context, key string, value interface{}) context.Context {
+	existing := EventMetadataFromContext(ctx)
+
+	metadata := make(map[string]interface{}, len(existing)+1)
+	for k, v := range existing {
+		metadata[k] = v
+	}
+	metadata[key] = value
+
+	return context.WithValue(ctx, eventMetadataKey{}, metadata)
+}
+
+// EventMetadataFromContext returns the event metadata carried by the context
+func EventMetadataFromContext(ctx context.Context) map[string]interface{} {
+	metadata, _ := ctx.Value(eventMetadataKey{}).(map[string]interface{})
+	return metadata
+}
+
+// stampMetadata copies context metadata onto events without overwriting existing keys
+func stampMetadata(ctx context.Context, events []*EventEnvelope) {
+	metadata := EventMetadataFromContext(ctx)
+	if len(metadata) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		for key, value := range metadata {
+			if _, ok := event.GetMetadata(key); !ok {
+				event.WithMetadata(key, value)
+			}
+		}
+	}
+}
+
+// metadataFilter builds the JSON containment filter for a metadata search
+func metadataFilter(key, value string) ([]byte, error) {
+	if key == "" {
+		return nil, fmt.Errorf("metadata key is required")
+	}
+	return json.Marshal(map[string]string{key: value})
+}
@@ -0,0 +1,154 @@
+package eventstore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DataKeySize is the size of per-aggregate data encryption keys and of the master key (AES-256)
+const DataKeySize = 32
+
+var (
+	// ErrKeyNotFound is returned when no data key was created for an aggregate
+	ErrKeyNotFound = errors.New("data key not found")
+
+	// ErrKeyShredded is returned once an aggregate's data key has been shredded
+	ErrKeyShredded = errors.New("data key has been shredded")
+
+	// ErrInvalidKeySize is returned for master or data keys that aren't DataKeySize bytes
+	ErrInvalidKeySize = errors.New("invalid key size")
+)
+
+// KeyStore manages the per-aggregate data encryption keys used for personal data
+type KeyStore interface {
+	// GetOrCreateKey returns the aggregate's data key, creating one on first use
+	// Returns ErrKeyShredded once the key has been shredded
+	GetOrCreateKey(ctx context.Context, aggregateID string) ([]byte, error)
+
+	// GetKey returns the aggregate's data key
+	// Returns ErrKeyNotFound if none was created and ErrKeyShredded once it was shredded
+	GetKey(ctx context.Context, aggregateID string) ([]byte, error)
+
+	// ShredKey permanently deletes the aggregate's data key
+	// The aggregate's personal data can't be decrypted afterwards and no new key is created for it
+	ShredKey(ctx context.Context, aggregateID string) error
+}
+
+// MemoryKeyStore implements KeyStore in memory
+// It follows the same semantics as PostgresKeyStore and is intended for tests
+type MemoryKeyStore struct {
+	mu       sync.Mutex
+	keys     map[string][]byte
+	shredded map[string]struct{}
+}
+
+// NewMemoryKeyStore creates a new in-memory key store
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{
+		keys:     make(map[string][]byte),
+		shredded: make(map[string]struct{}),
+	}
+}
+
+// GetOrCreateKey returns the aggregate's data key, creating one on first use
+func (s *MemoryKeyStore) GetOrCreateKey(ctx context.Context, aggregateID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.shredded[aggregateID]; ok {
+		return nil, ErrKeyShredded
+	}
+	if key, ok := s.keys[aggregateID]; ok {
+		return key, nil
+	}
+
+	key, err := newDataKey()
+	if err != nil {
+		return nil, err
+	}
+	s.keys[aggregateID] = key
+	return key, nil
+}
+
+// GetKey returns the aggregate's data key
+func (s *MemoryKeyStore) GetKey(ctx context.Context, aggregateID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.shredded[aggregateID]; ok {
+		return nil, ErrKeyShredded
+	}
+	key, ok := s.keys[aggregateID]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// ShredKey permanently deletes the aggregate's data key
+func (s *MemoryKeyStore) ShredKey(ctx context.Context, aggregateID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, aggregateID)
+	s.shredded[aggregateID] = struct{}{}
+	return nil
+}
+
+// newDataKey generates a random data encryption key
+func newDataKey() ([]byte, error) {
+	key := make([]byte, DataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return key, nil
+}
+
+// seal encrypts plaintext with AES-GCM, returning nonce || ciphertext
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open decrypts a value produced by seal
+func open(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+// newGCM creates an AES-GCM cipher for a DataKeySize key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != DataKeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
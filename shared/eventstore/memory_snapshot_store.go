@@ -0,0 +1,80 @@
+package eventstore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemorySnapshotStore implements SnapshotStore in memory
+// It follows the same semantics as PostgresSnapshotStore and is intended for tests
+type MemorySnapshotStore struct {
+	mu        sync.RWMutex
+	snapshots map[string]map[int64]*Snapshot
+}
+
+// NewMemorySnapshotStore creates a new in-memory snapshot store
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{
+		snapshots: make(map[string]map[int64]*Snapshot),
+	}
+}
+
+// SaveSnapshot saves a snapshot of an aggregate
+// A snapshot for an existing version replaces the stored one
+func (s *MemorySnapshotStore) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	if snapshot.CreatedAt == 0 {
+		snapshot.CreatedAt = time.Now().UTC().Unix()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := snapshotKey(snapshot.AggregateType, snapshot.AggregateID)
+	if s.snapshots[key] == nil {
+		s.snapshots[key] = make(map[int64]*Snapshot)
+	}
+	s.snapshots[key][snapshot.Version] = copySnapshot(snapshot)
+
+	return nil
+}
+
+// GetSnapshot retrieves the latest snapshot for an aggregate
+// Returns nil without error if the aggregate has no snapshot
+func (s *MemorySnapshotStore) GetSnapshot(ctx context.Context, aggregateType, aggregateID string) (*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *Snapshot
+	for _, snapshot := range s.snapshots[snapshotKey(aggregateType, aggregateID)] {
+		if latest == nil || snapshot.Version > latest.Version {
+			latest = snapshot
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+	return copySnapshot(latest), nil
+}
+
+// DeleteSnapshot deletes all snapshots for an aggregate
+func (s *MemorySnapshotStore) DeleteSnapshot(ctx context.Context, aggregateType, aggregateID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.snapshots, snapshotKey(aggregateType, aggregateID))
+	return nil
+}
+
+// snapshotKey builds the map key for an aggregate
+func snapshotKey(aggregateType, aggregateID string) string {
+	return aggregateType + "/" + aggregateID
+}
+
+// copySnapshot copies a snapshot including its state
+func copySnapshot(snapshot *Snapshot) *Snapshot {
+	c := *snapshot
+	c.State = append([]byte(nil), snapshot.State...)
+	return &c
+}
@@ -0,0 +1,348 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryEventStore implements EventStore in memory
+// It follows the same semantics as PostgresEventStore and is intended for tests
+type MemoryEventStore struct {
+	mu           sync.RWMutex
+	events       []*EventEnvelope // all events in sequence order
+	byAggregate  map[string][]*EventEnvelope
+	eventIDs     map[string]struct{}
+	lastSequence int64
+	upcasters    *UpcasterRegistry
+}
+
+// NewMemoryEventStore creates a new in-memory event store
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{
+		byAggregate: make(map[string][]*EventEnvelope),
+		eventIDs:    make(map[string]struct{}),
+	}
+}
+
+// SetUpcasterRegistry sets the registry used to stamp schema versions on append
+// and to upcast old payloads on read
+func (s *MemoryEventStore) SetUpcasterRegistry(registry *UpcasterRegistry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upcasters = registry
+}
+
+// SaveEvents saves events with optimistic locking
+// Either all events are stored or none are
+func (s *MemoryEventStore) SaveEvents(ctx context.Context, aggregateID string, expectedVersion int64, events []*EventEnvelope) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing := s.byAggregate[aggregateID]
+	actualVersion := int64(-1)
+	for _, event := range existing {
+		if event.Version > actualVersion {
+			actualVersion = event.Version
+		}
+	}
+
+	if actualVersion != expectedVersion {
+		return ErrConcurrencyConflict
+	}
+
+	// Reject duplicate event IDs and versions, as the unique constraints do in Postgres
+	batchIDs := make(map[string]struct{}, len(events))
+	batchVersions := make(map[int64]struct{}, len(events))
+	for _, event := range events {
+		if _, ok := s.eventIDs[event.EventID]; ok {
+			return ErrConcurrencyConflict
+		}
+		if _, ok := batchIDs[event.EventID]; ok {
+			return ErrConcurrencyConflict
+		}
+		if _, ok := batchVersions[event.Version]; ok {
+			return ErrConcurrencyConflict
+		}
+		for _, stored := range existing {
+			if stored.Version == event.Version {
+				return ErrConcurrencyConflict
+			}
+		}
+		batchIDs[event.EventID] = struct{}{}
+		batchVersions[event.Version] = struct{}{}
+	}
+
+	for _, event := range events {
+		if event.SchemaVersion == 0 {
+			event.SchemaVersion = s.upcasters.CurrentVersion(event.EventType)
+		}
+
+		stored := copyEvent(event)
+		s.lastSequence++
+		stored.Sequence = s.lastSequence
+
+		s.events = append(s.events, stored)
+		s.byAggregate[aggregateID] = append(s.byAggregate[aggregateID], stored)
+		s.eventIDs[stored.EventID] = struct{}{}
+	}
+
+	return nil
+}
+
+// GetEvents retrieves all events for an aggregate
+func (s *MemoryEventStore) GetEvents(ctx context.Context, aggregateType, aggregateID string) ([]*EventEnvelope, error) {
+	return s.aggregateEvents(aggregateType, aggregateID, func(e *EventEnvelope) bool {
+		return true
+	})
+}
+
+// GetEventsSince retrieves events since a specific version
+func (s *MemoryEventStore) GetEventsSince(ctx context.Context, aggregateType, aggregateID string, sinceVersion int64) ([]*EventEnvelope, error) {
+	return s.aggregateEvents(aggregateType, aggregateID, func(e *EventEnvelope) bool {
+		return e.Version > sinceVersion
+	})
+}
+
+// GetEventsByType retrieves events of a specific type
+func (s *MemoryEventStore) GetEventsByType(ctx context.Context, aggregateType, aggregateID, eventType string) ([]*EventEnvelope, error) {
+	return s.aggregateEvents(aggregateType, aggregateID, func(e *EventEnvelope) bool {
+		return e.EventType == eventType
+	})
+}
+
+// GetAllEvents retrieves all events of a specific aggregate type
+func (s *MemoryEventStore) GetAllEvents(ctx context.Context, aggregateType string, limit, offset int) ([]*EventEnvelope, error) {
+	s.mu.RLock()
+	var matched []*EventEnvelope
+	for _, event := range s.events {
+		if event.AggregateType == aggregateType {
+			matched = append(matched, event)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].Timestamp.Equal(matched[j].Timestamp) {
+			return matched[i].Timestamp.Before(matched[j].Timestamp)
+		}
+		return matched[i].Version < matched[j].Version
+	})
+
+	return s.read(page(matched, limit, offset))
+}
+
+// GetEventStream retrieves events across all aggregates
+func (s *MemoryEventStore) GetEventStream(ctx context.Context, sinceTimestamp int64, limit int) ([]*EventEnvelope, error) {
+	since := time.Unix(sinceTimestamp, 0).UTC()
+
+	s.mu.RLock()
+	var matched []*EventEnvelope
+	for _, event := range s.events {
+		if event.Timestamp.After(since) {
+			matched = append(matched, event)
+		}
+	}
+	s.mu.RUnlock()
+
+	// s.events is in sequence order, so a stable sort keeps sequence as the tie breaker
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Timestamp.Before(matched[j].Timestamp)
+	})
+
+	return s.read(page(matched, limit, 0))
+}
+
+// GetEventsAfterSequence retrieves events across all aggregates with a sequence greater than afterSequence
+func (s *MemoryEventStore) GetEventsAfterSequence(ctx context.Context, afterSequence int64, limit int) ([]*EventEnvelope, error) {
+	s.mu.RLock()
+	events := s.events
+	s.mu.RUnlock()
+
+	// Sequences start at 1 and have no gaps, so the position maps directly to an index
+	start := int(afterSequence)
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(events) {
+		return nil, nil
+	}
+
+	return s.read(page(events[start:], limit, 0))
+}
+
+// GetLastSequence returns the global sequence of the most recent event
+func (s *MemoryEventStore) GetLastSequence(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastSequence, nil
+}
+
+// GetEventsUntilVersion retrieves events for an aggregate up to and including a version
+func (s *MemoryEventStore) GetEventsUntilVersion(ctx context.Context, aggregateType, aggregateID string, untilVersion int64) ([]*EventEnvelope, error) {
+	return s.aggregateEvents(aggregateType, aggregateID, func(e *EventEnvelope) bool {
+		return e.Version <= untilVersion
+	})
+}
+
+// GetEventsUntilTime retrieves events for an aggregate recorded at or before a point in time
+func (s *MemoryEventStore) GetEventsUntilTime(ctx context.Context, aggregateType, aggregateID string, until time.Time) ([]*EventEnvelope, error) {
+	return s.aggregateEvents(aggregateType, aggregateID, func(e *EventEnvelope) bool {
+		return !e.Timestamp.After(until)
+	})
+}
+
+// GetEventHistory pages through an aggregate's events in version order
+func (s *MemoryEventStore) GetEventHistory(ctx context.Context, aggregateType, aggregateID, cursor string, limit int) (*EventPage, error) {
+	afterVersion, err := decodeCursor(cursor, -1)
+	if err != nil {
+		return nil, err
+	}
+	limit = normalizePageSize(limit)
+
+	events, err := s.aggregateEvents(aggregateType, aggregateID, func(e *EventEnvelope) bool {
+		return e.Version > afterVersion
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newEventPage(page(events, limit+1, 0), limit, func(e *EventEnvelope) int64 { return e.Version }), nil
+}
+
+// FindEventsByMetadata pages through events whose metadata key equals value
+func (s *MemoryEventStore) FindEventsByMetadata(ctx context.Context, key, value, cursor string, limit int) (*EventPage, error) {
+	afterSequence, err := decodeCursor(cursor, 0)
+	if err != nil {
+		return nil, err
+	}
+	limit = normalizePageSize(limit)
+
+	if _, err := metadataFilter(key, value); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var matched []*EventEnvelope
+	for _, event := range s.events {
+		if event.Sequence <= afterSequence {
+			continue
+		}
+		// Compare like a JSON containment check on a string value
+		if v, ok := event.Metadata[key].(string); ok && v == value {
+			matched = append(matched, event)
+			if len(matched) > limit {
+				break
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	events, err := s.read(matched)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEventPage(events, limit, func(e *EventEnvelope) int64 { return e.Sequence }), nil
+}
+
+// GetAggregateVersion gets the current version of an aggregate
+func (s *MemoryEventStore) GetAggregateVersion(ctx context.Context, aggregateType, aggregateID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	version := int64(-1)
+	for _, event := range s.byAggregate[aggregateID] {
+		if event.AggregateType == aggregateType && event.Version > version {
+			version = event.Version
+		}
+	}
+
+	return version, nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryEventStore) Close() error {
+	return nil
+}
+
+// aggregateEvents returns copies of an aggregate's events matching filter, ordered by version
+func (s *MemoryEventStore) aggregateEvents(aggregateType, aggregateID string, filter func(*EventEnvelope) bool) ([]*EventEnvelope, error) {
+	s.mu.RLock()
+	var matched []*EventEnvelope
+	for _, event := range s.byAggregate[aggregateID] {
+		if event.AggregateType == aggregateType && filter(event) {
+			matched = append(matched, event)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Version < matched[j].Version
+	})
+
+	return s.read(matched)
+}
+
+// read copies stored events and upcasts them, like scanEvents does for Postgres
+func (s *MemoryEventStore) read(events []*EventEnvelope) ([]*EventEnvelope, error) {
+	s.mu.RLock()
+	registry := s.upcasters
+	s.mu.RUnlock()
+
+	copies := copyEvents(events)
+	for _, event := range copies {
+		if err := upcastOnRead(registry, event); err != nil {
+			return nil, err
+		}
+	}
+	return copies, nil
+}
+
+// page applies LIMIT/OFFSET semantics to events
+func page(events []*EventEnvelope, limit, offset int) []*EventEnvelope {
+	if offset >= len(events) {
+		return nil
+	}
+	if offset > 0 {
+		events = events[offset:]
+	}
+	if limit >= 0 && limit < len(events) {
+		events = events[:limit]
+	}
+	return events
+}
+
+// copyEvents copies events so callers can't modify stored state
+func copyEvents(events []*EventEnvelope) []*EventEnvelope {
+	if len(events) == 0 {
+		return nil
+	}
+
+	copies := make([]*EventEnvelope, len(events))
+	for i, event := range events {
+		copies[i] = copyEvent(event)
+	}
+	return copies
+}
+
+// copyEvent copies an event including its payload and metadata
+func copyEvent(event *EventEnvelope) *EventEnvelope {
+	c := *event
+	if event.Data != nil {
+		c.Data = append(json.RawMessage(nil), event.Data...)
+	}
+	if event.Metadata != nil {
+		c.Metadata = make(map[string]interface{}, len(event.Metadata))
+		for k, v := range event.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return &c
+}
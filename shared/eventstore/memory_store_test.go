@@ -0,0 +1,20 @@
+package eventstore_test
+
+import (
+	"testing"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	"github.com/toxictoast/toxictoastgo/shared/eventstore/eventstoretest"
+)
+
+func TestMemoryEventStore(t *testing.T) {
+	eventstoretest.RunEventStoreSuite(t, func(t *testing.T) eventstore.EventStore {
+		return eventstore.NewMemoryEventStore()
+	})
+}
+
+func TestMemorySnapshotStore(t *testing.T) {
+	eventstoretest.RunSnapshotStoreSuite(t, func(t *testing.T) eventstore.SnapshotStore {
+		return eventstore.NewMemorySnapshotStore()
+	})
+}
@@ -0,0 +1,111 @@
+package eventstore
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EventNotifier signals subscribers that new events may be available
+// Notifications are hints only: subscribers must still read from their checkpoint
+type EventNotifier interface {
+	// Subscribe returns a channel receiving a value whenever events were appended,
+	// and a function that releases the subscription
+	Subscribe() (<-chan struct{}, func())
+
+	// Close stops delivering notifications
+	Close() error
+}
+
+// PostgresEventNotifier implements EventNotifier using PostgreSQL LISTEN/NOTIFY
+// PostgresEventStore.SaveEvents notifies EventStoreNotifyChannel on every commit
+type PostgresEventNotifier struct {
+	listener    *pq.Listener
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+	done        chan struct{}
+}
+
+// NewPostgresEventNotifier listens on EventStoreNotifyChannel using a dedicated connection
+func NewPostgresEventNotifier(connStr string) (*PostgresEventNotifier, error) {
+	listener := pq.NewListener(connStr, 1*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Event notifier connection event %d: %v", ev, err)
+		}
+	})
+
+	if err := listener.Listen(EventStoreNotifyChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", EventStoreNotifyChannel, err)
+	}
+
+	n := &PostgresEventNotifier{
+		listener:    listener,
+		subscribers: make(map[chan struct{}]struct{}),
+		done:        make(chan struct{}),
+	}
+
+	go n.run()
+
+	return n, nil
+}
+
+// run fans out every notification to all subscribers
+func (n *PostgresEventNotifier) run() {
+	// Ping periodically so a silently dropped connection is detected and re-established
+	ticker := time.NewTicker(90 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-n.listener.Notify:
+			// A nil notification means the connection was re-established and
+			// notifications may have been lost, so wake everybody up either way
+			n.broadcast()
+		case <-ticker.C:
+			if err := n.listener.Ping(); err != nil {
+				log.Printf("Event notifier ping failed: %v", err)
+			}
+		}
+	}
+}
+
+// broadcast wakes every subscriber without blocking
+func (n *PostgresEventNotifier) broadcast() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A wakeup is already pending
+		}
+	}
+}
+
+// Subscribe registers a new subscriber
+func (n *PostgresEventNotifier) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	n.subscribers[ch] = struct{}{}
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		delete(n.subscribers, ch)
+		n.mu.Unlock()
+	}
+}
+
+// Close stops the listener
+func (n *PostgresEventNotifier) Close() error {
+	close(n.done)
+	return n.listener.Close()
+}
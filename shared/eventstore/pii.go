@@ -0,0 +1,255 @@
+package eventstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	// piiTag is the struct tag marking payload fields that hold personal data
+	piiTag = "pii"
+
+	// encryptedPrefix marks an encrypted field value inside an event payload
+	encryptedPrefix = "pii:v1:"
+)
+
+// Anonymization modes, given as the value of the pii struct tag
+// They decide what a field reads as once the aggregate's key was shredded
+const (
+	// PIIModeToken replaces the value with "erased-<aggregate id>", which stays unique per aggregate
+	// It is the default for `pii:""` and `pii:"true"`
+	PIIModeToken = "token"
+
+	// PIIModeEmail replaces the value with an address in the reserved .invalid domain
+	PIIModeEmail = "email"
+
+	// PIIModeEmpty replaces the value with an empty string
+	PIIModeEmpty = "empty"
+)
+
+var (
+	// ErrInvalidPIIField is returned when a pii tag is used on a field that can't be encrypted
+	ErrInvalidPIIField = errors.New("invalid pii field")
+)
+
+// PIIRegistry records which payload fields of each event type hold personal data
+//
+// Fields are marked with a struct tag on the event payload:
+//
+//	type UserCreatedEvent struct {
+//		Email    string `json:"email" pii:"email"`
+//		Username string `json:"username" pii:"token"`
+//	}
+type PIIRegistry struct {
+	mu     sync.RWMutex
+	fields map[string]map[string]string // event type -> JSON field -> anonymization mode
+}
+
+// NewPIIRegistry creates a new PII registry
+func NewPIIRegistry() *PIIRegistry {
+	return &PIIRegistry{
+		fields: make(map[string]map[string]string),
+	}
+}
+
+// Register records the pii-tagged fields of an event payload struct
+// Several payload structs may be registered for the same event type; their fields are merged
+func (r *PIIRegistry) Register(eventType string, payload interface{}) error {
+	t := reflect.TypeOf(payload)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: payload for %s must be a struct", ErrInvalidPIIField, eventType)
+	}
+
+	fields := make(map[string]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		mode, ok := field.Tag.Lookup(piiTag)
+		if !ok {
+			continue
+		}
+		if field.Type.Kind() != reflect.String {
+			return fmt.Errorf("%w: %s.%s must be a string", ErrInvalidPIIField, t.Name(), field.Name)
+		}
+
+		switch mode {
+		case "", "true":
+			mode = PIIModeToken
+		case PIIModeToken, PIIModeEmail, PIIModeEmpty:
+		default:
+			return fmt.Errorf("%w: unknown mode %q on %s.%s", ErrInvalidPIIField, mode, t.Name(), field.Name)
+		}
+
+		name := jsonFieldName(field)
+		if name == "" {
+			return fmt.Errorf("%w: %s.%s is not serialized", ErrInvalidPIIField, t.Name(), field.Name)
+		}
+		fields[name] = mode
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fields[eventType] == nil {
+		r.fields[eventType] = make(map[string]string)
+	}
+	for name, mode := range fields {
+		r.fields[eventType][name] = mode
+	}
+
+	return nil
+}
+
+// Fields returns the personal data fields of an event type and their anonymization modes
+// A nil registry has no fields
+func (r *PIIRegistry) Fields(eventType string) map[string]string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fields[eventType]
+}
+
+// AnonymizedValue returns the value a personal data field reads as after its key was shredded
+func AnonymizedValue(mode, aggregateID string) string {
+	switch mode {
+	case PIIModeEmpty:
+		return ""
+	case PIIModeEmail:
+		return "erased-" + aggregateID + "@erased.invalid"
+	default:
+		return "erased-" + aggregateID
+	}
+}
+
+// jsonFieldName returns the JSON key of a struct field, or "" if it isn't serialized
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// encryptFields encrypts the personal data fields of a payload with the aggregate's data key
+// Empty values and values that are already encrypted are left as they are
+func encryptFields(key []byte, aggregateID string, data json.RawMessage, fields map[string]string) (json.RawMessage, error) {
+	return transformFields(data, registered(fields), func(name, value string) (string, error) {
+		if value == "" || strings.HasPrefix(value, encryptedPrefix) {
+			return value, nil
+		}
+
+		ciphertext, err := seal(key, []byte(value), fieldAAD(aggregateID, name))
+		if err != nil {
+			return "", err
+		}
+		return encryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+	})
+}
+
+// decryptFields decrypts every encrypted field of a payload under the name it was stored with,
+// so payloads can be decrypted before upcasters rename their fields
+// A nil key means the key was shredded, and encrypted fields are anonymized
+func decryptFields(key []byte, aggregateID string, data json.RawMessage, fields map[string]string) (json.RawMessage, error) {
+	encrypted := func(name, value string) bool {
+		return strings.HasPrefix(value, encryptedPrefix)
+	}
+
+	return transformFields(data, encrypted, func(name, value string) (string, error) {
+		if key == nil {
+			return AnonymizedValue(fields[name], aggregateID), nil
+		}
+
+		ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+		if err != nil {
+			return "", fmt.Errorf("failed to decode field %s: %w", name, err)
+		}
+		plaintext, err := open(key, ciphertext, fieldAAD(aggregateID, name))
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt field %s: %w", name, err)
+		}
+		return string(plaintext), nil
+	})
+}
+
+// anonymizeFields anonymizes the non-empty personal data fields of a payload whose key was shredded
+func anonymizeFields(aggregateID string, data json.RawMessage, fields map[string]string) (json.RawMessage, error) {
+	return transformFields(data, registered(fields), func(name, value string) (string, error) {
+		if value == "" {
+			return value, nil
+		}
+		return AnonymizedValue(fields[name], aggregateID), nil
+	})
+}
+
+// registered selects the payload fields of a registry entry
+func registered(fields map[string]string) func(name, value string) bool {
+	return func(name, value string) bool {
+		_, ok := fields[name]
+		return ok
+	}
+}
+
+// transformFields rewrites the selected top-level string values of a payload
+func transformFields(data json.RawMessage, selected func(name, value string) bool, transform func(name, value string) (string, error)) (json.RawMessage, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+	}
+
+	changed := false
+	for name, raw := range payload {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			// null or a non-string value: nothing to protect
+			continue
+		}
+		if !selected(name, value) {
+			continue
+		}
+
+		transformed, err := transform(name, value)
+		if err != nil {
+			return nil, err
+		}
+		if transformed == value {
+			continue
+		}
+
+		encoded, err := json.Marshal(transformed)
+		if err != nil {
+			return nil, err
+		}
+		payload[name] = encoded
+		changed = true
+	}
+
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(payload)
+}
+
+// fieldAAD binds a ciphertext to its aggregate and field, so values can't be swapped between them
+func fieldAAD(aggregateID, field string) []byte {
+	return []byte(aggregateID + "/" + field)
+}
@@ -0,0 +1,119 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PostgresKeyStore implements KeyStore using PostgreSQL
+// Data keys are stored wrapped (encrypted) with a master key that never reaches the database
+type PostgresKeyStore struct {
+	db        *sql.DB
+	masterKey []byte
+}
+
+// NewPostgresKeyStore creates a new PostgreSQL key store
+// masterKey must be DataKeySize bytes
+func NewPostgresKeyStore(db *sql.DB, masterKey []byte) (*PostgresKeyStore, error) {
+	if len(masterKey) != DataKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes: %w", DataKeySize, ErrInvalidKeySize)
+	}
+
+	store := &PostgresKeyStore{
+		db:        db,
+		masterKey: append([]byte(nil), masterKey...),
+	}
+
+	if err := store.createTables(); err != nil {
+		return nil, fmt.Errorf("failed to create key store tables: %w", err)
+	}
+
+	return store, nil
+}
+
+// createTables creates the data key table
+// A shredded key keeps its row, with wrapped_key cleared, so it is never recreated
+func (s *PostgresKeyStore) createTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS aggregate_data_keys (
+		aggregate_id VARCHAR(36) PRIMARY KEY,
+		wrapped_key BYTEA,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		shredded_at TIMESTAMP
+	);
+	`
+
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// GetOrCreateKey returns the aggregate's data key, creating one on first use
+func (s *PostgresKeyStore) GetOrCreateKey(ctx context.Context, aggregateID string) ([]byte, error) {
+	key, err := newDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := seal(s.masterKey, key, []byte(aggregateID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	// A concurrent writer may have created the key first; whichever row won is read back below
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO aggregate_data_keys (aggregate_id, wrapped_key, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (aggregate_id) DO NOTHING
+	`, aggregateID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	return s.GetKey(ctx, aggregateID)
+}
+
+// GetKey returns the aggregate's data key
+func (s *PostgresKeyStore) GetKey(ctx context.Context, aggregateID string) ([]byte, error) {
+	var wrapped []byte
+	var shreddedAt sql.NullTime
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT wrapped_key, shredded_at FROM aggregate_data_keys WHERE aggregate_id = $1`,
+		aggregateID,
+	).Scan(&wrapped, &shreddedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data key: %w", err)
+	}
+	if shreddedAt.Valid || wrapped == nil {
+		return nil, ErrKeyShredded
+	}
+
+	key, err := open(s.masterKey, wrapped, []byte(aggregateID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	return key, nil
+}
+
+// ShredKey permanently deletes the aggregate's data key
+func (s *PostgresKeyStore) ShredKey(ctx context.Context, aggregateID string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO aggregate_data_keys (aggregate_id, wrapped_key, created_at, shredded_at)
+		VALUES ($1, NULL, NOW(), NOW())
+		ON CONFLICT (aggregate_id) DO UPDATE SET
+			wrapped_key = NULL,
+			shredded_at = COALESCE(aggregate_data_keys.shredded_at, EXCLUDED.shredded_at)
+	`, aggregateID)
+
+	if err != nil {
+		return fmt.Errorf("failed to shred data key: %w", err)
+	}
+
+	return nil
+}
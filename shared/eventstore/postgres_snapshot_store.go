@@ -0,0 +1,114 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PostgresSnapshotStore implements SnapshotStore using PostgreSQL
+type PostgresSnapshotStore struct {
+	db *sql.DB
+}
+
+// NewPostgresSnapshotStore creates a new PostgreSQL snapshot store
+func NewPostgresSnapshotStore(db *sql.DB) (*PostgresSnapshotStore, error) {
+	store := &PostgresSnapshotStore{db: db}
+
+	if err := store.createTables(); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot store tables: %w", err)
+	}
+
+	return store, nil
+}
+
+// createTables creates the snapshot table
+func (s *PostgresSnapshotStore) createTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS aggregate_snapshots (
+		aggregate_type VARCHAR(255) NOT NULL,
+		aggregate_id VARCHAR(36) NOT NULL,
+		version BIGINT NOT NULL,
+		state BYTEA NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (aggregate_type, aggregate_id, version)
+	);
+	`
+
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// SaveSnapshot saves a snapshot of an aggregate
+// Older snapshots of the same aggregate are kept until DeleteSnapshot is called
+func (s *PostgresSnapshotStore) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	if snapshot.CreatedAt == 0 {
+		snapshot.CreatedAt = time.Now().UTC().Unix()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO aggregate_snapshots (aggregate_type, aggregate_id, version, state, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (aggregate_type, aggregate_id, version) DO UPDATE SET
+			state = EXCLUDED.state,
+			created_at = EXCLUDED.created_at
+	`,
+		snapshot.AggregateType,
+		snapshot.AggregateID,
+		snapshot.Version,
+		snapshot.State,
+		time.Unix(snapshot.CreatedAt, 0).UTC(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetSnapshot retrieves the latest snapshot for an aggregate
+// Returns nil without error if the aggregate has no snapshot
+func (s *PostgresSnapshotStore) GetSnapshot(ctx context.Context, aggregateType, aggregateID string) (*Snapshot, error) {
+	var snapshot Snapshot
+	var createdAt time.Time
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT aggregate_type, aggregate_id, version, state, created_at
+		FROM aggregate_snapshots
+		WHERE aggregate_type = $1 AND aggregate_id = $2
+		ORDER BY version DESC
+		LIMIT 1
+	`, aggregateType, aggregateID).Scan(
+		&snapshot.AggregateType,
+		&snapshot.AggregateID,
+		&snapshot.Version,
+		&snapshot.State,
+		&createdAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	snapshot.CreatedAt = createdAt.Unix()
+	return &snapshot, nil
+}
+
+// DeleteSnapshot deletes all snapshots for an aggregate
+func (s *PostgresSnapshotStore) DeleteSnapshot(ctx context.Context, aggregateType, aggregateID string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM aggregate_snapshots
+		WHERE aggregate_type = $1 AND aggregate_id = $2
+	`, aggregateType, aggregateID)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -10,9 +10,27 @@ import (
 	"github.com/lib/pq"
 )
 
+const (
+	// EventStoreNotifyChannel is the LISTEN/NOTIFY channel signalled whenever events are appended
+	EventStoreNotifyChannel = "event_store_appended"
+
+	// appendLockID is the advisory lock key serializing appends, so that sequence
+	// numbers become visible to readers in strictly increasing order
+	appendLockID = 727_100_001
+
+	// eventColumns lists the columns read by scanEvents, in scan order
+	eventColumns = "event_id, event_type, aggregate_id, aggregate_type, version, timestamp, data, metadata, sequence, schema_version"
+)
+
+// AppendHook runs inside the SaveEvents transaction, after the events were inserted
+// Returning an error rolls the whole append back
+type AppendHook func(ctx context.Context, tx *sql.Tx, events []*EventEnvelope) error
+
 // PostgresEventStore implements EventStore using PostgreSQL
 type PostgresEventStore struct {
-	db *sql.DB
+	db        *sql.DB
+	hooks     []AppendHook
+	upcasters *UpcasterRegistry
 }
 
 // NewPostgresEventStore creates a new PostgreSQL event store
@@ -31,6 +49,7 @@ func NewPostgresEventStore(db *sql.DB) (*PostgresEventStore, error) {
 func (s *PostgresEventStore) createTables() error {
 	query := `
 	CREATE TABLE IF NOT EXISTS event_store (
+		sequence BIGSERIAL NOT NULL,
 		event_id VARCHAR(36) PRIMARY KEY,
 		event_type VARCHAR(255) NOT NULL,
 		aggregate_id VARCHAR(36) NOT NULL,
@@ -47,12 +66,34 @@ func (s *PostgresEventStore) createTables() error {
 	CREATE INDEX IF NOT EXISTS idx_event_store_type ON event_store(event_type);
 	CREATE INDEX IF NOT EXISTS idx_event_store_timestamp ON event_store(timestamp);
 	CREATE INDEX IF NOT EXISTS idx_event_store_aggregate_version ON event_store(aggregate_id, version);
+
+	-- Global position for ordered catch-up subscriptions (backfilled for existing tables)
+	ALTER TABLE event_store ADD COLUMN IF NOT EXISTS sequence BIGSERIAL;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_event_store_sequence ON event_store(sequence);
+
+	-- Payload schema version for upcasting (existing events are version 1)
+	ALTER TABLE event_store ADD COLUMN IF NOT EXISTS schema_version INT NOT NULL DEFAULT 1;
+
+	-- Metadata search (correlation ID, actor, ...)
+	CREATE INDEX IF NOT EXISTS idx_event_store_metadata ON event_store USING GIN (metadata jsonb_path_ops);
 	`
 
 	_, err := s.db.Exec(query)
 	return err
 }
 
+// AddAppendHook registers a hook that runs in the same transaction as every append
+// Used e.g. by the transactional outbox to record events for publishing
+func (s *PostgresEventStore) AddAppendHook(hook AppendHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// SetUpcasterRegistry sets the registry used to stamp schema versions on append
+// and to upcast old payloads on read
+func (s *PostgresEventStore) SetUpcasterRegistry(registry *UpcasterRegistry) {
+	s.upcasters = registry
+}
+
 // SaveEvents saves events with optimistic locking
 func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string, expectedVersion int64, events []*EventEnvelope) error {
 	if len(events) == 0 {
@@ -65,6 +106,11 @@ func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string,
 	}
 	defer tx.Rollback()
 
+	// Serialize appends so sequence numbers commit in order and subscribers never skip a gap
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, appendLockID); err != nil {
+		return fmt.Errorf("failed to acquire append lock: %w", err)
+	}
+
 	// Check current version (optimistic locking)
 	var currentVersion sql.NullInt64
 	err = tx.QueryRowContext(ctx,
@@ -89,8 +135,8 @@ func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string,
 	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO event_store (
 			event_id, event_type, aggregate_id, aggregate_type,
-			version, timestamp, data, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			version, timestamp, data, metadata, schema_version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -98,6 +144,10 @@ func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string,
 	defer stmt.Close()
 
 	for _, event := range events {
+		if event.SchemaVersion == 0 {
+			event.SchemaVersion = s.upcasters.CurrentVersion(event.EventType)
+		}
+
 		metadataJSON, err := json.Marshal(event.Metadata)
 		if err != nil {
 			return fmt.Errorf("failed to marshal metadata: %w", err)
@@ -112,6 +162,7 @@ func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string,
 			event.Timestamp,
 			event.Data,
 			metadataJSON,
+			event.SchemaVersion,
 		)
 
 		if err != nil {
@@ -124,6 +175,17 @@ func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string,
 		}
 	}
 
+	for _, hook := range s.hooks {
+		if err := hook(ctx, tx, events); err != nil {
+			return fmt.Errorf("append hook failed: %w", err)
+		}
+	}
+
+	// Wake up subscribers once the transaction commits
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, '')`, EventStoreNotifyChannel); err != nil {
+		return fmt.Errorf("failed to notify subscribers: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -134,7 +196,7 @@ func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string,
 // GetEvents retrieves all events for an aggregate
 func (s *PostgresEventStore) GetEvents(ctx context.Context, aggregateType, aggregateID string) ([]*EventEnvelope, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT event_id, event_type, aggregate_id, aggregate_type, version, timestamp, data, metadata
+		SELECT `+eventColumns+`
 		FROM event_store
 		WHERE aggregate_type = $1 AND aggregate_id = $2
 		ORDER BY version ASC
@@ -151,7 +213,7 @@ func (s *PostgresEventStore) GetEvents(ctx context.Context, aggregateType, aggre
 // GetEventsSince retrieves events since a specific version
 func (s *PostgresEventStore) GetEventsSince(ctx context.Context, aggregateType, aggregateID string, sinceVersion int64) ([]*EventEnvelope, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT event_id, event_type, aggregate_id, aggregate_type, version, timestamp, data, metadata
+		SELECT `+eventColumns+`
 		FROM event_store
 		WHERE aggregate_type = $1 AND aggregate_id = $2 AND version > $3
 		ORDER BY version ASC
@@ -168,7 +230,7 @@ func (s *PostgresEventStore) GetEventsSince(ctx context.Context, aggregateType,
 // GetEventsByType retrieves events of a specific type
 func (s *PostgresEventStore) GetEventsByType(ctx context.Context, aggregateType, aggregateID, eventType string) ([]*EventEnvelope, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT event_id, event_type, aggregate_id, aggregate_type, version, timestamp, data, metadata
+		SELECT `+eventColumns+`
 		FROM event_store
 		WHERE aggregate_type = $1 AND aggregate_id = $2 AND event_type = $3
 		ORDER BY version ASC
@@ -185,7 +247,7 @@ func (s *PostgresEventStore) GetEventsByType(ctx context.Context, aggregateType,
 // GetAllEvents retrieves all events of a specific aggregate type
 func (s *PostgresEventStore) GetAllEvents(ctx context.Context, aggregateType string, limit, offset int) ([]*EventEnvelope, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT event_id, event_type, aggregate_id, aggregate_type, version, timestamp, data, metadata
+		SELECT `+eventColumns+`
 		FROM event_store
 		WHERE aggregate_type = $1
 		ORDER BY timestamp ASC, version ASC
@@ -205,10 +267,10 @@ func (s *PostgresEventStore) GetEventStream(ctx context.Context, sinceTimestamp
 	timestamp := time.Unix(sinceTimestamp, 0).UTC()
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT event_id, event_type, aggregate_id, aggregate_type, version, timestamp, data, metadata
+		SELECT `+eventColumns+`
 		FROM event_store
 		WHERE timestamp > $1
-		ORDER BY timestamp ASC
+		ORDER BY timestamp ASC, sequence ASC
 		LIMIT $2
 	`, timestamp, limit)
 
@@ -220,6 +282,131 @@ func (s *PostgresEventStore) GetEventStream(ctx context.Context, sinceTimestamp
 	return s.scanEvents(rows)
 }
 
+// GetEventsAfterSequence retrieves events across all aggregates with a sequence greater than afterSequence
+func (s *PostgresEventStore) GetEventsAfterSequence(ctx context.Context, afterSequence int64, limit int) ([]*EventEnvelope, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM event_store
+		WHERE sequence > $1
+		ORDER BY sequence ASC
+		LIMIT $2
+	`, afterSequence, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events after sequence: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanEvents(rows)
+}
+
+// GetLastSequence returns the global sequence of the most recent event
+func (s *PostgresEventStore) GetLastSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(sequence), 0) FROM event_store`).Scan(&sequence)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last sequence: %w", err)
+	}
+	return sequence, nil
+}
+
+// GetEventsUntilVersion retrieves events for an aggregate up to and including a version
+func (s *PostgresEventStore) GetEventsUntilVersion(ctx context.Context, aggregateType, aggregateID string, untilVersion int64) ([]*EventEnvelope, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM event_store
+		WHERE aggregate_type = $1 AND aggregate_id = $2 AND version <= $3
+		ORDER BY version ASC
+	`, aggregateType, aggregateID, untilVersion)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanEvents(rows)
+}
+
+// GetEventsUntilTime retrieves events for an aggregate recorded at or before a point in time
+func (s *PostgresEventStore) GetEventsUntilTime(ctx context.Context, aggregateType, aggregateID string, until time.Time) ([]*EventEnvelope, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM event_store
+		WHERE aggregate_type = $1 AND aggregate_id = $2 AND timestamp <= $3
+		ORDER BY version ASC
+	`, aggregateType, aggregateID, until.UTC())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanEvents(rows)
+}
+
+// GetEventHistory pages through an aggregate's events in version order
+func (s *PostgresEventStore) GetEventHistory(ctx context.Context, aggregateType, aggregateID, cursor string, limit int) (*EventPage, error) {
+	afterVersion, err := decodeCursor(cursor, -1)
+	if err != nil {
+		return nil, err
+	}
+	limit = normalizePageSize(limit)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM event_store
+		WHERE aggregate_type = $1 AND aggregate_id = $2 AND version > $3
+		ORDER BY version ASC
+		LIMIT $4
+	`, aggregateType, aggregateID, afterVersion, limit+1)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event history: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := s.scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEventPage(events, limit, func(e *EventEnvelope) int64 { return e.Version }), nil
+}
+
+// FindEventsByMetadata pages through events whose metadata key equals value
+func (s *PostgresEventStore) FindEventsByMetadata(ctx context.Context, key, value, cursor string, limit int) (*EventPage, error) {
+	afterSequence, err := decodeCursor(cursor, 0)
+	if err != nil {
+		return nil, err
+	}
+	limit = normalizePageSize(limit)
+
+	filter, err := metadataFilter(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM event_store
+		WHERE metadata @> $1::jsonb AND sequence > $2
+		ORDER BY sequence ASC
+		LIMIT $3
+	`, filter, afterSequence, limit+1)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events by metadata: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := s.scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEventPage(events, limit, func(e *EventEnvelope) int64 { return e.Sequence }), nil
+}
+
 // GetAggregateVersion gets the current version of an aggregate
 func (s *PostgresEventStore) GetAggregateVersion(ctx context.Context, aggregateType, aggregateID string) (int64, error) {
 	var version sql.NullInt64
@@ -257,6 +444,8 @@ func (s *PostgresEventStore) scanEvents(rows *sql.Rows) ([]*EventEnvelope, error
 			&event.Timestamp,
 			&event.Data,
 			&metadataJSON,
+			&event.Sequence,
+			&event.SchemaVersion,
 		)
 
 		if err != nil {
@@ -269,6 +458,10 @@ func (s *PostgresEventStore) scanEvents(rows *sql.Rows) ([]*EventEnvelope, error
 			}
 		}
 
+		if err := upcastOnRead(s.upcasters, &event); err != nil {
+			return nil, err
+		}
+
 		events = append(events, &event)
 	}
 
@@ -0,0 +1,62 @@
+package eventstore_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	"github.com/toxictoast/toxictoastgo/shared/eventstore/eventstoretest"
+)
+
+// openTestDB connects to the database in EVENTSTORE_TEST_DATABASE_URL
+// The tests truncate the event store tables, so never point this at real data
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("EVENTSTORE_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("EVENTSTORE_TEST_DATABASE_URL not set, skipping Postgres conformance tests")
+	}
+	if testing.Short() {
+		t.Skip("Skipping Postgres conformance tests in short mode")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestPostgresEventStore(t *testing.T) {
+	db := openTestDB(t)
+
+	eventstoretest.RunEventStoreSuite(t, func(t *testing.T) eventstore.EventStore {
+		store, err := eventstore.NewPostgresEventStore(db)
+		if err != nil {
+			t.Fatalf("Failed to create event store: %v", err)
+		}
+		if _, err := db.Exec(`TRUNCATE event_store`); err != nil {
+			t.Fatalf("Failed to truncate event store: %v", err)
+		}
+		return store
+	})
+}
+
+func TestPostgresSnapshotStore(t *testing.T) {
+	db := openTestDB(t)
+
+	eventstoretest.RunSnapshotStoreSuite(t, func(t *testing.T) eventstore.SnapshotStore {
+		store, err := eventstore.NewPostgresSnapshotStore(db)
+		if err != nil {
+			t.Fatalf("Failed to create snapshot store: %v", err)
+		}
+		if _, err := db.Exec(`TRUNCATE aggregate_snapshots`); err != nil {
+			t.Fatalf("Failed to truncate snapshots: %v", err)
+		}
+		return store
+	})
+}
@@ -0,0 +1,51 @@
+package eventstore
+
+// SnapshotAggregate is implemented by aggregates that can be captured in a snapshot
+// AggregateRepository only uses snapshots for aggregates implementing this interface
+type SnapshotAggregate interface {
+	Aggregate
+
+	// SnapshotState serializes the current aggregate state
+	SnapshotState() ([]byte, error)
+
+	// RestoreSnapshot restores aggregate state and version from a snapshot
+	RestoreSnapshot(snapshot *Snapshot) error
+}
+
+// SnapshotPolicy decides when AggregateRepository takes a snapshot after saving
+type SnapshotPolicy interface {
+	// ShouldSnapshot is called after events were saved, moving the aggregate
+	// from previousVersion to currentVersion
+	ShouldSnapshot(aggregate Aggregate, previousVersion, currentVersion int64) bool
+}
+
+// SnapshotPolicyProvider lets an aggregate override the repository's default snapshot policy
+type SnapshotPolicyProvider interface {
+	SnapshotPolicy() SnapshotPolicy
+}
+
+// SnapshotPolicyFunc is an adapter to allow the use of ordinary functions as snapshot policies
+type SnapshotPolicyFunc func(aggregate Aggregate, previousVersion, currentVersion int64) bool
+
+// ShouldSnapshot calls f(aggregate, previousVersion, currentVersion)
+func (f SnapshotPolicyFunc) ShouldSnapshot(aggregate Aggregate, previousVersion, currentVersion int64) bool {
+	return f(aggregate, previousVersion, currentVersion)
+}
+
+// EveryNEvents takes a snapshot whenever a save crosses a multiple of n events
+// Versions start at 0, so the aggregate holds version+1 events
+func EveryNEvents(n int64) SnapshotPolicy {
+	return SnapshotPolicyFunc(func(aggregate Aggregate, previousVersion, currentVersion int64) bool {
+		if n <= 0 {
+			return false
+		}
+		return (currentVersion+1)/n > (previousVersion+1)/n
+	})
+}
+
+// NeverSnapshot disables snapshots
+func NeverSnapshot() SnapshotPolicy {
+	return SnapshotPolicyFunc(func(aggregate Aggregate, previousVersion, currentVersion int64) bool {
+		return false
+	})
+}
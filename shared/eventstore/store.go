@@ -3,6 +3,7 @@ package eventstore
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
@@ -40,6 +41,27 @@ type EventStore interface {
 	// Useful for projections and event replay
 	GetEventStream(ctx context.Context, sinceTimestamp int64, limit int) ([]*EventEnvelope, error)
 
+	// GetEventsAfterSequence retrieves events across all aggregates ordered by their global sequence
+	// Useful for checkpointed catch-up subscriptions
+	GetEventsAfterSequence(ctx context.Context, afterSequence int64, limit int) ([]*EventEnvelope, error)
+
+	// GetLastSequence returns the global sequence of the most recent event, or 0 for an empty store
+	GetLastSequence(ctx context.Context) (int64, error)
+
+	// GetEventsUntilVersion retrieves events for an aggregate up to and including a version
+	GetEventsUntilVersion(ctx context.Context, aggregateType, aggregateID string, untilVersion int64) ([]*EventEnvelope, error)
+
+	// GetEventsUntilTime retrieves events for an aggregate recorded at or before a point in time
+	GetEventsUntilTime(ctx context.Context, aggregateType, aggregateID string, until time.Time) ([]*EventEnvelope, error)
+
+	// GetEventHistory pages through an aggregate's events in version order
+	// Pass the NextCursor of the previous page to continue, or an empty cursor to start
+	GetEventHistory(ctx context.Context, aggregateType, aggregateID, cursor string, limit int) (*EventPage, error)
+
+	// FindEventsByMetadata pages through events of all aggregates whose metadata key equals value,
+	// in global sequence order (e.g. all events of one correlation ID or actor)
+	FindEventsByMetadata(ctx context.Context, key, value, cursor string, limit int) (*EventPage, error)
+
 	// GetAggregateVersion gets the current version of an aggregate
 	GetAggregateVersion(ctx context.Context, aggregateType, aggregateID string) (int64, error)
 
@@ -0,0 +1,132 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultSchemaVersion is the schema version of event types without upcasters
+const DefaultSchemaVersion = 1
+
+var (
+	// ErrUnknownSchema is returned when an event's schema version can't be upcast to the current version
+	ErrUnknownSchema = errors.New("unknown event schema version")
+)
+
+// Upcaster transforms an event payload from one schema version to the next
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+// UpcasterRegistry holds upcasters keyed by event type and schema version
+// Registering an upcaster from version N makes N+1 the current version of that event type
+type UpcasterRegistry struct {
+	mu        sync.RWMutex
+	upcasters map[string]map[int]Upcaster
+	current   map[string]int
+}
+
+// NewUpcasterRegistry creates a new upcaster registry
+func NewUpcasterRegistry() *UpcasterRegistry {
+	return &UpcasterRegistry{
+		upcasters: make(map[string]map[int]Upcaster),
+		current:   make(map[string]int),
+	}
+}
+
+// Register adds an upcaster converting payloads of eventType from fromVersion to fromVersion+1
+func (r *UpcasterRegistry) Register(eventType string, fromVersion int, upcaster Upcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.upcasters[eventType] == nil {
+		r.upcasters[eventType] = make(map[int]Upcaster)
+	}
+	r.upcasters[eventType][fromVersion] = upcaster
+
+	if fromVersion+1 > r.current[eventType] {
+		r.current[eventType] = fromVersion + 1
+	}
+}
+
+// CurrentVersion returns the schema version new events of eventType are written with
+func (r *UpcasterRegistry) CurrentVersion(eventType string) int {
+	if r == nil {
+		return DefaultSchemaVersion
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if version, ok := r.current[eventType]; ok {
+		return version
+	}
+	return DefaultSchemaVersion
+}
+
+// Upcast transforms the event payload step by step up to the current schema version
+// Returns ErrUnknownSchema if the event is newer than the current version or a step is missing
+func (r *UpcasterRegistry) Upcast(event *EventEnvelope) error {
+	if event.SchemaVersion == 0 {
+		event.SchemaVersion = DefaultSchemaVersion
+	}
+
+	current := r.CurrentVersion(event.EventType)
+	if event.SchemaVersion > current {
+		return fmt.Errorf("%w: %s v%d is newer than v%d", ErrUnknownSchema, event.EventType, event.SchemaVersion, current)
+	}
+	if r == nil || event.SchemaVersion == current {
+		return nil
+	}
+
+	r.mu.RLock()
+	steps := r.upcasters[event.EventType]
+	r.mu.RUnlock()
+
+	data := event.Data
+	for version := event.SchemaVersion; version < current; version++ {
+		upcaster, ok := steps[version]
+		if !ok {
+			return fmt.Errorf("%w: no upcaster for %s v%d", ErrUnknownSchema, event.EventType, version)
+		}
+
+		upcasted, err := upcaster(data)
+		if err != nil {
+			return fmt.Errorf("failed to upcast %s from v%d: %w", event.EventType, version, err)
+		}
+		data = upcasted
+	}
+
+	event.Data = data
+	event.SchemaVersion = current
+	return nil
+}
+
+// RenameField returns an upcaster that renames a top-level JSON field
+func RenameField(from, to string) Upcaster {
+	return func(data json.RawMessage) (json.RawMessage, error) {
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+
+		if value, ok := payload[from]; ok {
+			payload[to] = value
+			delete(payload, from)
+		}
+
+		return json.Marshal(payload)
+	}
+}
+
+// upcastOnRead upcasts an event loaded from a store
+// Unknown schemas are recorded on the event instead of failing the whole read,
+// so replays can report and skip them; failing upcasters are returned as errors
+func upcastOnRead(registry *UpcasterRegistry, event *EventEnvelope) error {
+	err := registry.Upcast(event)
+	if errors.Is(err, ErrUnknownSchema) {
+		event.schemaErr = fmt.Errorf("event %s: %w", event.EventID, err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestUpcasterRegistry_Upcast(t *testing.T) {
+	registry := NewUpcasterRegistry()
+	registry.Register("user.created", 1, RenameField("name", "username"))
+	registry.Register("user.created", 2, func(data json.RawMessage) (json.RawMessage, error) {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		payload["status"] = "active"
+		return json.Marshal(payload)
+	})
+
+	tests := []struct {
+		name          string
+		schemaVersion int
+		data          string
+		expected      string
+		expectedErr   error
+	}{
+		{
+			name:          "from version 1",
+			schemaVersion: 1,
+			data:          `{"name":"john"}`,
+			expected:      `{"status":"active","username":"john"}`,
+		},
+		{
+			name:          "missing version defaults to 1",
+			schemaVersion: 0,
+			data:          `{"name":"john"}`,
+			expected:      `{"status":"active","username":"john"}`,
+		},
+		{
+			name:          "from version 2",
+			schemaVersion: 2,
+			data:          `{"username":"john"}`,
+			expected:      `{"status":"active","username":"john"}`,
+		},
+		{
+			name:          "already current",
+			schemaVersion: 3,
+			data:          `{"username":"john","status":"inactive"}`,
+			expected:      `{"username":"john","status":"inactive"}`,
+		},
+		{
+			name:          "newer than current",
+			schemaVersion: 4,
+			data:          `{}`,
+			expectedErr:   ErrUnknownSchema,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &EventEnvelope{EventType: "user.created", SchemaVersion: tt.schemaVersion, Data: json.RawMessage(tt.data)}
+
+			err := registry.Upcast(event)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if string(event.Data) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, event.Data)
+			}
+			if event.SchemaVersion != 3 {
+				t.Errorf("Expected schema version 3, got %d", event.SchemaVersion)
+			}
+		})
+	}
+}
+
+func TestUpcasterRegistry_MissingStep(t *testing.T) {
+	registry := NewUpcasterRegistry()
+	registry.Register("user.updated", 2, RenameField("a", "b"))
+
+	event := &EventEnvelope{EventType: "user.updated", SchemaVersion: 1, Data: json.RawMessage(`{}`)}
+	if err := registry.Upcast(event); !errors.Is(err, ErrUnknownSchema) {
+		t.Errorf("Expected ErrUnknownSchema, got %v", err)
+	}
+}
+
+func TestMemoryEventStore_UpcastsOnRead(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryEventStore()
+
+	old, _ := NewEventEnvelope(AggregateTypeUser, "user-1", "user.created", 0, map[string]string{"name": "john"})
+	newer, _ := NewEventEnvelope(AggregateTypeUser, "user-1", "user.updated", 1, map[string]string{})
+	newer.SchemaVersion = 5
+	if err := store.SaveEvents(ctx, "user-1", -1, []*EventEnvelope{old, newer}); err != nil {
+		t.Fatalf("SaveEvents failed: %v", err)
+	}
+	if old.SchemaVersion != DefaultSchemaVersion {
+		t.Errorf("Expected schema version %d to be stamped, got %d", DefaultSchemaVersion, old.SchemaVersion)
+	}
+
+	// Deploy a new schema for user.created
+	registry := NewUpcasterRegistry()
+	registry.Register("user.created", 1, RenameField("name", "username"))
+	store.SetUpcasterRegistry(registry)
+
+	events, err := store.GetEvents(ctx, AggregateTypeUser, "user-1")
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+
+	var created struct {
+		Username string `json:"username"`
+	}
+	if err := events[0].UnmarshalData(&created); err != nil {
+		t.Fatalf("UnmarshalData failed: %v", err)
+	}
+	if created.Username != "john" {
+		t.Errorf("Expected username john, got %q", created.Username)
+	}
+
+	if !errors.Is(events[1].SchemaError(), ErrUnknownSchema) {
+		t.Errorf("Expected ErrUnknownSchema on the newer event, got %v", events[1].SchemaError())
+	}
+	if err := events[1].UnmarshalData(&struct{}{}); !errors.Is(err, ErrUnknownSchema) {
+		t.Errorf("Expected UnmarshalData to fail with ErrUnknownSchema, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/toxictoast/toxictoastgo/shared/auth"
+)
+
+// Requirement describes who may call a method
+// A caller satisfies it with any one of the roles or any one of the permissions
+// An empty requirement only demands an authenticated caller
+type Requirement struct {
+	Roles       []string
+	Permissions []string
+}
+
+// PermissionRegistry maps full method names to their requirements
+// A trailing "*" matches a prefix, e.g. "/blog.BlogService/*"; the longest match wins
+// Methods without an entry are not checked
+type PermissionRegistry map[string]Requirement
+
+// lookup returns the requirement registered for a method
+func (r PermissionRegistry) lookup(method string) (Requirement, bool) {
+	if req, ok := r[method]; ok {
+		return req, true
+	}
+
+	var match Requirement
+	longest := -1
+	for pattern, req := range r {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if ok && strings.HasPrefix(method, prefix) && len(prefix) > longest {
+			match, longest = req, len(prefix)
+		}
+	}
+	return match, longest >= 0
+}
+
+// authorize checks the caller in ctx against the requirement registered for method
+func (r PermissionRegistry) authorize(ctx context.Context, method string) error {
+	req, ok := r.lookup(method)
+	if !ok {
+		return nil
+	}
+
+	caller, ok := VerifiedUserFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if len(req.Roles) == 0 && len(req.Permissions) == 0 {
+		return nil
+	}
+	for _, role := range req.Roles {
+		if contains(caller.Roles, role) {
+			return nil
+		}
+	}
+	for _, permission := range req.Permissions {
+		if contains(caller.Permissions, permission) {
+			return nil
+		}
+	}
+	return status.Error(codes.PermissionDenied, "insufficient permissions")
+}
+
+// VerifiedUserFromContext returns the caller verified by the service itself: a user authenticated
+// by Keycloak, or the user forwarded by the gateway over a connection with a verified client
+// certificate (mTLS). Any client can set the x-user-* metadata, so it isn't trusted otherwise
+// Use it instead of GetUserFromContext wherever the caller grants access or is recorded
+func VerifiedUserFromContext(ctx context.Context) (*UserInfo, bool) {
+	if user, err := auth.GetUserContext(ctx); err == nil && user != nil {
+		return &UserInfo{UserID: user.UserID, Email: user.Email, Username: user.Username, Roles: user.Roles}, true
+	}
+	if !verifiedPeer(ctx) {
+		return nil, false
+	}
+	if user, ok := GetUserFromContext(ctx); ok && user != nil {
+		return user, true
+	}
+	return nil, false
+}
+
+// verifiedPeer reports whether the client presented a certificate verified by the server
+func verifiedPeer(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	return ok && len(info.State.VerifiedChains) > 0
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionUnaryInterceptor rejects unary calls whose caller doesn't satisfy the registry
+func PermissionUnaryInterceptor(registry PermissionRegistry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := registry.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// PermissionStreamInterceptor rejects streams whose caller doesn't satisfy the registry
+func PermissionStreamInterceptor(registry PermissionRegistry) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := registry.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
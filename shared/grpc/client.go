@@ -0,0 +1,15 @@
+package grpc
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// ClientOptions returns the dial options every service-to-service client should use:
+// client spans continuing the caller's trace and request ID propagation
+func ClientOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(RequestIDClientInterceptor),
+	}
+}
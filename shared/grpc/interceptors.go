@@ -0,0 +1,230 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
+)
+
+// ============================================
+// Logging
+// ============================================
+
+// LoggingUnaryInterceptor writes one structured access log line per unary call
+func LoggingUnaryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logAccess(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor writes one structured access log line per stream
+func LoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logAccess(ss.Context(), logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// logAccess logs a finished call; server errors are logged at error level
+func logAccess(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("request_id", RequestIDFromContext(ctx)),
+	}
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		attrs = append(attrs, slog.String("trace_id", traceID))
+	}
+	if user, ok := VerifiedUserFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("user_id", user.UserID))
+	}
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded:
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", err.Error()))
+	default:
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	logger.LogAttrs(ctx, level, "grpc request", attrs...)
+}
+
+// ============================================
+// Metrics
+// ============================================
+
+// MetricsUnaryInterceptor records rate, errors and duration of unary calls per method
+func MetricsUnaryInterceptor(m *metrics.Metrics, serviceName string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(m, serviceName, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor records rate, errors and duration of streams per method
+func MetricsStreamInterceptor(m *metrics.Metrics, serviceName string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(m, serviceName, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// observe records a finished call with its status code
+func observe(m *metrics.Metrics, serviceName, method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	m.GRPCRequestsTotal.WithLabelValues(serviceName, method, code).Inc()
+	m.GRPCRequestDuration.WithLabelValues(serviceName, method, code).Observe(time.Since(start).Seconds())
+}
+
+// ============================================
+// Recovery
+// ============================================
+
+// RecoveryUnaryInterceptor converts a panic in a unary handler into codes.Internal
+func RecoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Recovered panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			resp = nil
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// RecoveryStreamInterceptor converts a panic in a stream handler into codes.Internal
+func RecoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Recovered panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// ============================================
+// Deadlines
+// ============================================
+
+// withDeadline applies defaultTimeout to calls without a deadline and caps client
+// deadlines at maxTimeout; zero disables either
+func withDeadline(ctx context.Context, defaultTimeout, maxTimeout time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	switch {
+	case !ok && defaultTimeout > 0:
+		return context.WithTimeout(ctx, defaultTimeout)
+	case maxTimeout > 0 && (!ok || time.Until(deadline) > maxTimeout):
+		return context.WithTimeout(ctx, maxTimeout)
+	default:
+		return ctx, func() {}
+	}
+}
+
+// deadlineError reports handler errors caused by the deadline as codes.DeadlineExceeded
+func deadlineError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok && status.Code(err) != codes.Unknown {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return err
+}
+
+// DeadlineUnaryInterceptor enforces default and maximum deadlines on unary calls
+func DeadlineUnaryInterceptor(defaultTimeout, maxTimeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := withDeadline(ctx, defaultTimeout, maxTimeout)
+		defer cancel()
+
+		resp, err := handler(ctx, req)
+		return resp, deadlineError(ctx, err)
+	}
+}
+
+// DeadlineStreamInterceptor enforces default and maximum deadlines on streams
+// Long-lived streams (e.g. subscriptions) should be served without a default timeout
+func DeadlineStreamInterceptor(defaultTimeout, maxTimeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := withDeadline(ss.Context(), defaultTimeout, maxTimeout)
+		defer cancel()
+
+		err := handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+		return deadlineError(ctx, err)
+	}
+}
+
+// ============================================
+// Validation
+// ============================================
+
+// validator is implemented by messages that can validate themselves,
+// e.g. by protoc-gen-validate generated code
+type validator interface {
+	Validate() error
+}
+
+// validate rejects invalid messages with codes.InvalidArgument
+func validate(msg interface{}) error {
+	if v, ok := msg.(validator); ok {
+		if err := v.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return nil
+}
+
+// ValidationUnaryInterceptor validates requests implementing Validate() error
+func ValidationUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// ValidationStreamInterceptor validates every received message implementing Validate() error
+func ValidationStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatingServerStream{ServerStream: ss})
+}
+
+// validatingServerStream validates messages as they are received
+type validatingServerStream struct {
+	grpc.ServerStream
+}
+
+// RecvMsg receives and validates a message
+func (s *validatingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validate(m)
+}
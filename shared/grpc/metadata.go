@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/toxictoast/toxictoastgo/shared/jwt"
+	sharedlogger "github.com/toxictoast/toxictoastgo/shared/logger"
 )
 
 const (
@@ -122,8 +123,9 @@ func GetUserFromContext(ctx context.Context) (*UserInfo, bool) {
 	return user, ok
 }
 
-// InjectUserIntoContext stores user info in context
+// InjectUserIntoContext stores user info in context and adds the user ID to its log records
 func InjectUserIntoContext(ctx context.Context, user *UserInfo) context.Context {
+	ctx = sharedlogger.WithUserID(ctx, user.UserID)
 	return context.WithValue(ctx, UserContextKey, user)
 }
 
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	sharedlogger "github.com/toxictoast/toxictoastgo/shared/logger"
+)
+
+// MetadataKeyRequestID is the key for the request ID in gRPC metadata
+const MetadataKeyRequestID = "x-request-id"
+
+// RequestIDFromContext returns the request ID set by the request ID interceptors
+func RequestIDFromContext(ctx context.Context) string {
+	return sharedlogger.RequestIDFromContext(ctx)
+}
+
+// withRequestID takes the caller's request ID or generates one
+// The ID is stored in the context (where log records pick it up), added to the incoming metadata (where the CQRS
+// event metadata middleware picks it up as correlation ID) and returned as header
+func withRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+
+	id := getMetadataValue(md, MetadataKeyRequestID)
+	if id == "" {
+		id = getMetadataValue(md, "x-correlation-id")
+	}
+	if id == "" {
+		id = uuid.New().String()
+		md.Set(MetadataKeyRequestID, id)
+	}
+
+	ctx = metadata.NewIncomingContext(ctx, md)
+	return sharedlogger.WithRequestID(ctx, id)
+}
+
+// RequestIDUnaryInterceptor propagates or generates the request ID of unary calls
+func RequestIDUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = withRequestID(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(MetadataKeyRequestID, RequestIDFromContext(ctx)))
+	return handler(ctx, req)
+}
+
+// RequestIDStreamInterceptor propagates or generates the request ID of streams
+func RequestIDStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withRequestID(ss.Context())
+	ss.SetHeader(metadata.Pairs(MetadataKeyRequestID, RequestIDFromContext(ctx)))
+	return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// RequestIDClientInterceptor forwards the request ID of the current call to downstream services
+func RequestIDClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, MetadataKeyRequestID, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
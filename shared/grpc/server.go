@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+
+	sharedlogger "github.com/toxictoast/toxictoastgo/shared/logger"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+)
+
+// ServerConfig configures the standard interceptor chain shared by all services
+type ServerConfig struct {
+	// ServiceName is used as the service label on metrics
+	ServiceName string
+
+	// Logger receives the access log; defaults to the "grpc" component logger
+	Logger *slog.Logger
+
+	// Metrics enables the grpc_requests_total and grpc_request_duration_seconds metrics when set
+	Metrics *metrics.Metrics
+
+	// DefaultTimeout bounds calls without a client deadline; MaxTimeout caps client deadlines
+	// Zero disables either; streams only get MaxTimeout, so subscriptions aren't cut off
+	DefaultTimeout time.Duration
+	MaxTimeout     time.Duration
+
+	// Permissions declares the roles or permissions required per method
+	// They are checked against the Keycloak user, or the gateway's user over mTLS only
+	Permissions PermissionRegistry
+
+	// UnaryInterceptors and StreamInterceptors run after the user was extracted from the
+	// metadata and before the permission check, e.g. Keycloak authentication or rate limiting
+	UnaryInterceptors  []grpc.UnaryServerInterceptor
+	StreamInterceptors []grpc.StreamServerInterceptor
+}
+
+// ServerOptions returns the tracing stats handler and the interceptor chain as server options
+// Interceptors run from outermost to innermost: request ID, access log, metrics, panic
+// recovery, deadlines, user extraction, the configured interceptors, permissions and
+// payload validation
+func ServerOptions(cfg ServerConfig) []grpc.ServerOption {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = sharedlogger.Component("grpc")
+	}
+
+	unary := []grpc.UnaryServerInterceptor{RequestIDUnaryInterceptor, LoggingUnaryInterceptor(logger)}
+	stream := []grpc.StreamServerInterceptor{RequestIDStreamInterceptor, LoggingStreamInterceptor(logger)}
+
+	if cfg.Metrics != nil {
+		unary = append(unary, MetricsUnaryInterceptor(cfg.Metrics, cfg.ServiceName))
+		stream = append(stream, MetricsStreamInterceptor(cfg.Metrics, cfg.ServiceName))
+	}
+
+	unary = append(unary,
+		RecoveryUnaryInterceptor,
+		DeadlineUnaryInterceptor(cfg.DefaultTimeout, cfg.MaxTimeout),
+		AuthInterceptor,
+	)
+	stream = append(stream,
+		RecoveryStreamInterceptor,
+		DeadlineStreamInterceptor(0, cfg.MaxTimeout),
+		StreamAuthInterceptor,
+	)
+
+	unary = append(unary, cfg.UnaryInterceptors...)
+	stream = append(stream, cfg.StreamInterceptors...)
+
+	if cfg.Permissions != nil {
+		unary = append(unary, PermissionUnaryInterceptor(cfg.Permissions))
+		stream = append(stream, PermissionStreamInterceptor(cfg.Permissions))
+	}
+
+	unary = append(unary, ValidationUnaryInterceptor)
+	stream = append(stream, ValidationStreamInterceptor)
+
+	return []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
+
+// NewServer creates a gRPC server with the standard interceptor chain
+func NewServer(cfg ServerConfig, opts ...grpc.ServerOption) *grpc.Server {
+	return grpc.NewServer(append(ServerOptions(cfg), opts...)...)
+}
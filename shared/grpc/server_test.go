@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/toxictoast/toxictoastgo/shared/auth"
+)
+
+type testRequest struct {
+	err error
+}
+
+func (r testRequest) Validate() error {
+	return r.err
+}
+
+func TestPermissionRegistry(t *testing.T) {
+	registry := PermissionRegistry{
+		"/blog.BlogService/*":          {},
+		"/blog.BlogService/DeletePost": {Roles: []string{"admin"}},
+		"/blog.BlogService/Admin*":     {Permissions: []string{"blog:admin"}},
+	}
+
+	// Users forwarded by the gateway only count over a connection with a verified client certificate
+	verified := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}},
+	})
+	user := func(roles, permissions []string) context.Context {
+		return InjectUserIntoContext(verified, &UserInfo{UserID: "user-1", Roles: roles, Permissions: permissions})
+	}
+	unverifiedAdmin := InjectUserIntoContext(context.Background(), &UserInfo{UserID: "user-1", Roles: []string{"admin"}})
+	keycloakAdmin := auth.WithUserContext(context.Background(), &auth.UserContext{UserID: "user-1", Roles: []string{"admin"}})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "unregistered method", ctx: context.Background(), method: "/link.LinkService/GetLink", wantCode: codes.OK},
+		{name: "anonymous caller", ctx: context.Background(), method: "/blog.BlogService/CreatePost", wantCode: codes.Unauthenticated},
+		{name: "authenticated caller", ctx: user(nil, nil), method: "/blog.BlogService/CreatePost", wantCode: codes.OK},
+		{name: "missing role", ctx: user([]string{"user"}, nil), method: "/blog.BlogService/DeletePost", wantCode: codes.PermissionDenied},
+		{name: "matching role", ctx: user([]string{"user", "admin"}, nil), method: "/blog.BlogService/DeletePost", wantCode: codes.OK},
+		{name: "longest prefix wins", ctx: user(nil, nil), method: "/blog.BlogService/AdminStats", wantCode: codes.PermissionDenied},
+		{name: "matching permission", ctx: user(nil, []string{"blog:admin"}), method: "/blog.BlogService/AdminStats", wantCode: codes.OK},
+		{name: "metadata without mTLS", ctx: unverifiedAdmin, method: "/blog.BlogService/DeletePost", wantCode: codes.Unauthenticated},
+		{name: "keycloak user", ctx: keycloakAdmin, method: "/blog.BlogService/DeletePost", wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		if code := status.Code(registry.authorize(tt.ctx, tt.method)); code != tt.wantCode {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.wantCode, code)
+		}
+	}
+}
+
+func TestServerInterceptors(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.TestService/Call"}
+
+	tests := []struct {
+		name        string
+		interceptor grpc.UnaryServerInterceptor
+		req         interface{}
+		handler     grpc.UnaryHandler
+		wantCode    codes.Code
+	}{
+		{
+			name:        "recovery",
+			interceptor: RecoveryUnaryInterceptor,
+			handler:     func(ctx context.Context, req interface{}) (interface{}, error) { panic("boom") },
+			wantCode:    codes.Internal,
+		},
+		{
+			name:        "valid request",
+			interceptor: ValidationUnaryInterceptor,
+			req:         testRequest{},
+			handler:     func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil },
+			wantCode:    codes.OK,
+		},
+		{
+			name:        "invalid request",
+			interceptor: ValidationUnaryInterceptor,
+			req:         testRequest{err: errors.New("name is required")},
+			handler:     func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil },
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "default deadline",
+			interceptor: DeadlineUnaryInterceptor(10*time.Millisecond, 0),
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name:        "handler status kept",
+			interceptor: DeadlineUnaryInterceptor(time.Second, 0),
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.interceptor(context.Background(), tt.req, info, tt.handler)
+		if code := status.Code(err); code != tt.wantCode {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.wantCode, code)
+		}
+	}
+}
+
+func TestDeadlineCap(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	ctx, cancel := withDeadline(parent, time.Second, time.Minute)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("Expected deadline capped at 1m, got %v", time.Until(deadline))
+	}
+}
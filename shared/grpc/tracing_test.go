@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestTracePropagation(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	// The stats handlers pick up the global provider when the server and client are created
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	otel.SetTracerProvider(provider)
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := NewServer(ServerConfig{ServiceName: "test-service"})
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(listener)
+	defer srv.Stop()
+
+	opts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+	}, ClientOptions()...)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "request")
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	request.End()
+
+	// The server span ends after the response is sent, possibly after the client returned
+	deadline := time.Now().Add(time.Second)
+	for len(recorder.Ended()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	spans := map[trace.SpanKind]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.SpanKind()] = span
+	}
+	client, server := spans[trace.SpanKindClient], spans[trace.SpanKindServer]
+	if client == nil || server == nil {
+		t.Fatalf("Expected a client and a server span, got %v", spans)
+	}
+
+	tests := []struct {
+		name   string
+		span   sdktrace.ReadOnlySpan
+		parent trace.SpanID
+	}{
+		{name: "client", span: client, parent: request.SpanContext().SpanID()},
+		{name: "server", span: server, parent: client.SpanContext().SpanID()},
+	}
+	for _, tt := range tests {
+		if tt.span.Name() != "grpc.health.v1.Health/Check" {
+			t.Errorf("%s: Expected span grpc.health.v1.Health/Check, got %s", tt.name, tt.span.Name())
+		}
+		if tt.span.SpanContext().TraceID() != request.SpanContext().TraceID() {
+			t.Errorf("%s: Expected trace ID %s, got %s", tt.name, request.SpanContext().TraceID(), tt.span.SpanContext().TraceID())
+		}
+		if tt.span.Parent().SpanID() != tt.parent {
+			t.Errorf("%s: Expected parent span %s, got %s", tt.name, tt.parent, tt.span.Parent().SpanID())
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // Client is an HTTP client with retry capabilities
@@ -26,6 +28,8 @@ func New(config *Config) *Client {
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: config.Timeout,
+			// Client spans continue the trace in ctx and send traceparent downstream
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 		config:          config,
 		backoffStrategy: ExponentialBackoff,
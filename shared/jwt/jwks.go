@@ -0,0 +1,299 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWK is a JSON Web Key (RFC 7517) holding an RSA or Ed25519 public key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set as served by /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK encodes a public key as a signature JWK
+func NewJWK(kid, algorithm string, public crypto.PublicKey) (*JWK, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: algorithm,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return &JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: algorithm,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, public)
+	}
+}
+
+// PublicKey decodes the JWK's public key
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode N: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode E: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKeyType, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode X: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid Ed25519 key size", ErrUnsupportedKeyType)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, k.Kty)
+	}
+}
+
+// Thumbprint returns the key's RFC 7638 thumbprint
+func (k *JWK) Thumbprint() string {
+	// Required members in lexicographic order, without whitespace
+	var canonical string
+	if k.Kty == "OKP" {
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q}`, k.Crv, k.Kty, k.X)
+	} else {
+		canonical = fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, k.E, k.Kty, k.N)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWKSHandler serves the public keys of a key ring, e.g. at /.well-known/jwks.json
+func JWKSHandler(ring *KeyRing) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Validators refresh on unknown kids, so a short cache is enough
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		json.NewEncoder(w).Encode(ring.JWKS())
+	})
+}
+
+// JWKSOptions configures a JWKSClient
+type JWKSOptions struct {
+	// RefreshInterval is how long fetched keys are used before refetching (default 5m)
+	RefreshInterval time.Duration
+
+	// MinRefreshInterval limits refetches triggered by unknown kids (default 30s)
+	MinRefreshInterval time.Duration
+
+	// HTTPClient fetches the key set (default: 10s timeout)
+	HTTPClient *http.Client
+}
+
+// JWKSClient is a KeySource fetching public keys from a JWKS endpoint
+// Keys are cached and refetched after RefreshInterval, or earlier when a token
+// carries an unknown kid (a key was rotated). Fetch errors keep the cached keys
+type JWKSClient struct {
+	url  string
+	opts JWKSOptions
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+
+	fetchMu     sync.Mutex
+	lastAttempt time.Time
+}
+
+// NewJWKSClient creates a JWKS client with default options
+func NewJWKSClient(url string) *JWKSClient {
+	return NewJWKSClientWithOptions(url, JWKSOptions{})
+}
+
+// NewJWKSClientWithOptions creates a JWKS client with custom refresh intervals
+func NewJWKSClientWithOptions(url string, opts JWKSOptions) *JWKSClient {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 5 * time.Minute
+	}
+	if opts.MinRefreshInterval <= 0 {
+		opts.MinRefreshInterval = 30 * time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &JWKSClient{
+		url:  url,
+		opts: opts,
+		keys: make(map[string]crypto.PublicKey),
+	}
+}
+
+// PublicKey returns the key with the given kid, fetching the key set when needed
+func (c *JWKSClient) PublicKey(kid string) (crypto.PublicKey, error) {
+	key, ok, fresh := c.cached(kid)
+	if ok && fresh {
+		return key, nil
+	}
+
+	if err := c.refresh(!ok); err != nil {
+		log.Printf("Warning: Failed to refresh JWKS from %s: %v", c.url, err)
+	}
+
+	if key, ok, _ := c.cached(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
+}
+
+// cached returns a cached key and whether the cache is within RefreshInterval
+func (c *JWKSClient) cached(kid string) (crypto.PublicKey, bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	key, ok := c.keys[kid]
+	return key, ok, time.Since(c.fetchedAt) < c.opts.RefreshInterval
+}
+
+// Refresh fetches the key set now, e.g. to warm the cache at startup
+func (c *JWKSClient) Refresh() error {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	return c.fetchLocked()
+}
+
+// StartRefresh refetches the key set every RefreshInterval until ctx is done, so
+// validations don't wait for a fetch once the cache got stale
+func (c *JWKSClient) StartRefresh(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.opts.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.Refresh(); err != nil {
+					log.Printf("Warning: Failed to refresh JWKS from %s: %v", c.url, err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// refresh refetches the key set when the cache is stale or a kid is unknown
+// Fetches are at most every MinRefreshInterval, so garbage tokens or an unreachable
+// endpoint don't turn every validation into a request; concurrent callers share one fetch
+func (c *JWKSClient) refresh(unknownKey bool) error {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	c.mu.RLock()
+	fetchedAt := c.fetchedAt
+	c.mu.RUnlock()
+
+	if time.Since(c.lastAttempt) < c.opts.MinRefreshInterval {
+		return nil
+	}
+	if !unknownKey && time.Since(fetchedAt) < c.opts.RefreshInterval {
+		// Another caller refreshed while we waited for the lock
+		return nil
+	}
+	return c.fetchLocked()
+}
+
+// fetchLocked fetches the key set and replaces the cache; fetchMu must be held
+func (c *JWKSClient) fetchLocked() error {
+	c.lastAttempt = time.Now()
+
+	keys, err := c.fetch()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// fetch downloads and decodes the key set
+func (c *JWKSClient) fetch() (map[string]crypto.PublicKey, error) {
+	resp, err := c.opts.HTTPClient.Get(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			// Skip key types we can't use (e.g. EC keys) instead of rejecting the whole set
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no usable keys found in JWKS")
+	}
+
+	return keys, nil
+}
@@ -1,10 +1,15 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // Claims represents the JWT claims structure
@@ -14,27 +19,76 @@ type Claims struct {
 	Username    string   `json:"username"`
 	Roles       []string `json:"roles"`
 	Permissions []string `json:"permissions"`
+	// SessionID is shared by the access and refresh tokens of one login
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// KeySource resolves the public key a token was signed with by its kid
+type KeySource interface {
+	PublicKey(kid string) (crypto.PublicKey, error)
+}
+
+// Options configures how a JWTHelper signs and validates tokens
+type Options struct {
+	// SecretKey signs and validates HS256 tokens when no KeyRing is set
+	// With a KeyRing or KeySource it only keeps validating existing HS256 tokens; leave
+	// it empty once they have expired, so validators no longer hold a minting secret
+	SecretKey string
+
+	// KeyRing signs tokens with its signing key and validates them with all its keys
+	KeyRing *KeyRing
+
+	// KeySource validates asymmetric tokens when there is no KeyRing, e.g. a JWKSClient
+	KeySource KeySource
+
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration
+}
+
 // JWTHelper provides JWT token generation and validation
 type JWTHelper struct {
 	secretKey            []byte
+	keyRing              *KeyRing
+	keySource            KeySource
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
 }
 
-// NewJWTHelper creates a new JWT helper instance
+// NewJWTHelper creates a new JWT helper instance signing with a shared HS256 secret
 func NewJWTHelper(secretKey string, accessTokenDuration, refreshTokenDuration time.Duration) *JWTHelper {
-	return &JWTHelper{
-		secretKey:            []byte(secretKey),
-		accessTokenDuration:  accessTokenDuration,
-		refreshTokenDuration: refreshTokenDuration,
+	return NewJWTHelperWithOptions(Options{
+		SecretKey:            secretKey,
+		AccessTokenDuration:  accessTokenDuration,
+		RefreshTokenDuration: refreshTokenDuration,
+	})
+}
+
+// NewJWTHelperWithOptions creates a JWT helper signing with a key ring or validating
+// with public keys only
+func NewJWTHelperWithOptions(opts Options) *JWTHelper {
+	h := &JWTHelper{
+		keyRing:              opts.KeyRing,
+		keySource:            opts.KeySource,
+		accessTokenDuration:  opts.AccessTokenDuration,
+		refreshTokenDuration: opts.RefreshTokenDuration,
 	}
+	if opts.SecretKey != "" {
+		h.secretKey = []byte(opts.SecretKey)
+	}
+	if h.keySource == nil && h.keyRing != nil {
+		h.keySource = h.keyRing
+	}
+	return h
 }
 
 // GenerateAccessToken generates a new access token
 func (h *JWTHelper) GenerateAccessToken(userID, email, username string, roles, permissions []string) (string, error) {
+	return h.GenerateSessionAccessToken("", userID, email, username, roles, permissions)
+}
+
+// GenerateSessionAccessToken generates a new access token belonging to a login session
+func (h *JWTHelper) GenerateSessionAccessToken(sessionID, userID, email, username string, roles, permissions []string) (string, error) {
 	now := time.Now()
 	claims := &Claims{
 		UserID:      userID,
@@ -42,41 +96,62 @@ func (h *JWTHelper) GenerateAccessToken(userID, email, username string, roles, p
 		Username:    username,
 		Roles:       roles,
 		Permissions: permissions,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(h.accessTokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(h.secretKey)
+	return h.sign(claims)
 }
 
 // GenerateRefreshToken generates a new refresh token
 func (h *JWTHelper) GenerateRefreshToken(userID string) (string, error) {
+	return h.GenerateSessionRefreshToken("", userID)
+}
+
+// GenerateSessionRefreshToken generates a new refresh token belonging to a login session
+func (h *JWTHelper) GenerateSessionRefreshToken(sessionID, userID string) (string, error) {
 	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(h.refreshTokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(h.secretKey)
+	return h.sign(claims)
+}
+
+// sign signs claims with the key ring's signing key, or the secret key without a key ring
+func (h *JWTHelper) sign(claims *Claims) (string, error) {
+	if h.keyRing == nil {
+		if h.secretKey == nil {
+			return "", ErrNoSigningKey
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(h.secretKey)
+	}
+
+	key, err := h.keyRing.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
 }
 
 // ValidateToken validates and parses a JWT token
 func (h *JWTHelper) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return h.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, h.keyFunc, jwt.WithValidMethods(h.validMethods()))
 
 	if err != nil {
 		return nil, err
@@ -89,6 +164,54 @@ func (h *JWTHelper) ValidateToken(tokenString string) (*Claims, error) {
 	return nil, fmt.Errorf("invalid token")
 }
 
+// validMethods returns the algorithms the helper has keys for
+func (h *JWTHelper) validMethods() []string {
+	var methods []string
+	if h.secretKey != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if h.keySource != nil {
+		methods = append(methods, AlgorithmRS256, AlgorithmEdDSA)
+	}
+	return methods
+}
+
+// keyFunc returns the key a token must have been signed with
+// The key type has to match the algorithm, so a public key can't be used as HMAC secret
+func (h *JWTHelper) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if h.secretKey == nil {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return h.secretKey, nil
+	}
+	if h.keySource == nil {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("missing kid header")
+	}
+
+	key, err := h.keySource.PublicKey(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+	case *jwt.SigningMethodEd25519:
+		if edKey, ok := key.(ed25519.PublicKey); ok {
+			return edKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+}
+
 // GetAccessTokenDuration returns the access token duration
 func (h *JWTHelper) GetAccessTokenDuration() time.Duration {
 	return h.accessTokenDuration
@@ -0,0 +1,243 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported asymmetric signing algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrKeyNotFound        = errors.New("signing key not found")
+	ErrNoSigningKey       = errors.New("no active signing key")
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+)
+
+// KeyStatus is the lifecycle state of a key in a KeyRing
+type KeyStatus string
+
+const (
+	// KeyActive keys validate tokens; the newest active key signs them
+	KeyActive KeyStatus = "active"
+
+	// KeyRetiring keys only validate tokens issued before a rotation
+	KeyRetiring KeyStatus = "retiring"
+)
+
+// SigningKey is an asymmetric key identified by its kid
+// PrivateKey is nil for keys that can only validate tokens
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// method returns the JWT signing method of the key
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// GenerateSigningKey generates a new RS256 (2048 bit) or EdDSA (Ed25519) key
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	var private crypto.Signer
+	var err error
+
+	switch algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	return newSigningKey(private, private.Public())
+}
+
+// LoadSigningKeyFile loads a PEM encoded RSA or Ed25519 key
+// Private keys (PKCS#1 or PKCS#8) can sign, public keys (PKIX) only validate
+func LoadSigningKeyFile(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	return ParseSigningKeyPEM(data)
+}
+
+// ParseSigningKeyPEM parses a PEM encoded RSA or Ed25519 key
+// The kid is the key's RFC 7638 thumbprint, so every service derives the same ID
+func ParseSigningKeyPEM(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse signing key: no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM block %s", ErrUnsupportedKeyType, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	if private, ok := parsed.(crypto.Signer); ok {
+		return newSigningKey(private, private.Public())
+	}
+	return newSigningKey(nil, parsed)
+}
+
+// newSigningKey derives algorithm and kid from a key pair
+func newSigningKey(private crypto.Signer, public crypto.PublicKey) (*SigningKey, error) {
+	key := &SigningKey{PrivateKey: private, PublicKey: public}
+
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.Algorithm = AlgorithmRS256
+	case ed25519.PublicKey:
+		key.Algorithm = AlgorithmEdDSA
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, public)
+	}
+
+	jwk, err := NewJWK("", key.Algorithm, public)
+	if err != nil {
+		return nil, err
+	}
+	key.ID = jwk.Thumbprint()
+
+	return key, nil
+}
+
+// keyEntry is a key with its status
+type keyEntry struct {
+	key    *SigningKey
+	status KeyStatus
+}
+
+// KeyRing holds the keys tokens are signed and validated with
+// Rotating adds a new signing key and keeps the previous one around as retiring,
+// so tokens it signed stay valid until they expire
+type KeyRing struct {
+	mu   sync.RWMutex
+	keys []keyEntry
+}
+
+// NewKeyRing creates a key ring; the last key with a private key becomes the signing key
+func NewKeyRing(keys ...*SigningKey) *KeyRing {
+	ring := &KeyRing{}
+	for _, key := range keys {
+		ring.Add(key, KeyActive)
+	}
+	return ring
+}
+
+// Add adds a key or changes the status of a key with the same kid
+// The newest active key with a private key signs new tokens
+func (r *KeyRing) Add(key *SigningKey, status KeyStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(key.ID)
+	r.keys = append(r.keys, keyEntry{key: key, status: status})
+}
+
+// Rotate makes key the signing key and retires all other active keys
+func (r *KeyRing) Rotate(key *SigningKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.keys {
+		r.keys[i].status = KeyRetiring
+	}
+	r.remove(key.ID)
+	r.keys = append(r.keys, keyEntry{key: key, status: KeyActive})
+}
+
+// Remove drops a key; tokens signed with it no longer validate
+func (r *KeyRing) Remove(kid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(kid)
+}
+
+// remove drops a key from the locked ring
+func (r *KeyRing) remove(kid string) {
+	for i, entry := range r.keys {
+		if entry.key.ID == kid {
+			r.keys = append(r.keys[:i], r.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// SigningKey returns the key new tokens are signed with
+func (r *KeyRing) SigningKey() (*SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		entry := r.keys[i]
+		if entry.status == KeyActive && entry.key.PrivateKey != nil {
+			return entry.key, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// PublicKey returns the public key of an active or retiring key
+func (r *KeyRing) PublicKey(kid string) (crypto.PublicKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entry := range r.keys {
+		if entry.key.ID == kid {
+			return entry.key.PublicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
+}
+
+// JWKS returns the public keys of the ring
+func (r *KeyRing) JWKS() *JWKS {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := &JWKS{Keys: make([]JWK, 0, len(r.keys))}
+	for _, entry := range r.keys {
+		jwk, err := NewJWK(entry.key.ID, entry.key.Algorithm, entry.key.PublicKey)
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, *jwk)
+	}
+	return set
+}
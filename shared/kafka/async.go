@@ -0,0 +1,411 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultAsyncBatchSize    = 100
+	defaultAsyncBatchBytes   = 1024 * 1024
+	defaultAsyncLinger       = 10 * time.Millisecond
+	defaultAsyncBufferSize   = 10000
+	defaultAsyncBlockTimeout = 5 * time.Second
+	defaultAsyncFlushTimeout = 30 * time.Second
+
+	// flushPollInterval is how often Flush checks for undelivered messages
+	flushPollInterval = 10 * time.Millisecond
+)
+
+var (
+	// ErrBufferFull is returned when an async publish finds the buffer full
+	ErrBufferFull = errors.New("kafka async buffer full")
+
+	// ErrProducerClosed is returned when publishing after Close
+	ErrProducerClosed = errors.New("kafka producer closed")
+)
+
+// BackpressurePolicy decides what an async publish does when the buffer is full
+type BackpressurePolicy string
+
+const (
+	// BackpressureBlock waits up to BlockTimeout for a free slot, then fails with ErrBufferFull
+	BackpressureBlock BackpressurePolicy = "block"
+
+	// BackpressureError fails with ErrBufferFull right away
+	BackpressureError BackpressurePolicy = "error"
+
+	// BackpressureDrop drops the message without failing the publish
+	// Delivery callbacks still receive ErrBufferFull
+	BackpressureDrop BackpressurePolicy = "drop"
+)
+
+// Delivery reports the outcome of an async publish
+type Delivery struct {
+	Topic     string
+	Key       string
+	Partition int32
+	Offset    int64
+	Err       error
+}
+
+// DeliveryCallback is called once per async message after it was delivered or failed
+// It runs on the producer's delivery goroutine and must not block
+type DeliveryCallback func(delivery Delivery)
+
+// AsyncOptions configures the async mode of a Producer
+type AsyncOptions struct {
+	// Enabled creates the async pipeline next to the sync producer
+	Enabled bool
+
+	// BatchSize is the number of messages that triggers sending a batch
+	BatchSize int
+
+	// BatchBytes is the batch size in bytes that triggers sending a batch
+	BatchBytes int
+
+	// Linger is how long a message may wait for its batch to fill up
+	Linger time.Duration
+
+	// MaxMessageBytes limits the size of a single message; defaults to sarama's limit
+	MaxMessageBytes int
+
+	// BufferSize is the maximum number of undelivered messages
+	BufferSize int
+
+	// Backpressure decides what happens when BufferSize is reached; defaults to BackpressureBlock
+	Backpressure BackpressurePolicy
+
+	// BlockTimeout is how long BackpressureBlock waits for a free slot
+	BlockTimeout time.Duration
+
+	// FlushTimeout is how long Close waits for buffered messages to be delivered
+	FlushTimeout time.Duration
+
+	// OnDelivery is called for every async message in addition to per-message callbacks
+	OnDelivery DeliveryCallback
+}
+
+// withDefaults fills in unset options
+func (o AsyncOptions) withDefaults() AsyncOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultAsyncBatchSize
+	}
+	if o.BatchBytes <= 0 {
+		o.BatchBytes = defaultAsyncBatchBytes
+	}
+	if o.Linger <= 0 {
+		o.Linger = defaultAsyncLinger
+	}
+	if o.BufferSize <= 0 {
+		o.BufferSize = defaultAsyncBufferSize
+	}
+	if o.Backpressure == "" {
+		o.Backpressure = BackpressureBlock
+	}
+	if o.BlockTimeout <= 0 {
+		o.BlockTimeout = defaultAsyncBlockTimeout
+	}
+	if o.FlushTimeout <= 0 {
+		o.FlushTimeout = defaultAsyncFlushTimeout
+	}
+	return o
+}
+
+// saramaConfig returns the producer config batching messages as configured
+func (o AsyncOptions) saramaConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 5
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	config.Producer.Compression = sarama.CompressionSnappy
+	config.Producer.Timeout = 10 * time.Second
+	config.Producer.Flush.Messages = o.BatchSize
+	config.Producer.Flush.Bytes = o.BatchBytes
+	config.Producer.Flush.Frequency = o.Linger
+	if o.MaxMessageBytes > 0 {
+		config.Producer.MaxMessageBytes = o.MaxMessageBytes
+	}
+	// Our own slots bound the buffer, so sends to the input channel never wait for long
+	config.ChannelBufferSize = o.BufferSize
+	return config
+}
+
+// Async returns the async variant of a publisher, or the publisher itself when it
+// has none, e.g. a Producer without async mode or a MemoryBroker
+// Call sites on the hot path use it to publish without waiting for the broker
+func Async(p Publisher) Publisher {
+	if a, ok := p.(interface{ Async() Publisher }); ok {
+		return a.Async()
+	}
+	return p
+}
+
+// AsyncPublisher publishes events in batches without waiting for the brokers
+// Publish methods only fail when the message can't be buffered; delivery errors
+// are reported to the delivery callbacks, metrics and log
+type AsyncPublisher struct {
+	eventHelpers
+
+	producer sarama.AsyncProducer
+	opts     ProducerOptions
+	async    AsyncOptions
+
+	// slots holds one token per undelivered message and bounds the buffer
+	slots chan struct{}
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	closeErr  error
+	done      chan struct{}
+
+	queueDepth *prometheus.GaugeVec
+	delivered  *prometheus.CounterVec
+	failures   *prometheus.CounterVec
+}
+
+// newAsyncPublisher starts delivering the results of an async producer
+func newAsyncPublisher(producer sarama.AsyncProducer, opts ProducerOptions) *AsyncPublisher {
+	a := &AsyncPublisher{
+		producer: producer,
+		opts:     opts,
+		async:    opts.Async,
+		slots:    make(chan struct{}, opts.Async.BufferSize),
+		done:     make(chan struct{}),
+	}
+	a.eventHelpers = eventHelpers{publisher: a}
+
+	if opts.Metrics != nil {
+		a.queueDepth = opts.Metrics.NewGauge("kafka_producer_queue_depth", "Number of async Kafka messages waiting for delivery", []string{"service"})
+		a.delivered = opts.Metrics.NewCounter("kafka_producer_delivered_total", "Total number of async Kafka messages delivered", []string{"service", "topic"})
+		a.failures = opts.Metrics.NewCounter("kafka_producer_errors_total", "Total number of async Kafka messages that failed or were dropped", []string{"service", "topic", "reason"})
+	}
+
+	go a.deliver()
+	return a
+}
+
+// pendingMessage is attached to every sarama message as metadata
+type pendingMessage struct {
+	key      string
+	callback DeliveryCallback
+}
+
+// PublishEvent buffers an event for publishing as a CloudEvent
+func (a *AsyncPublisher) PublishEvent(topic string, key string, event interface{}) error {
+	return a.PublishEventContext(context.Background(), topic, key, event)
+}
+
+// PublishEventContext buffers an event carrying the context's correlation and causation IDs
+// A cancelled context stops waiting for a free slot
+func (a *AsyncPublisher) PublishEventContext(ctx context.Context, topic string, key string, event interface{}) error {
+	return a.PublishEventCallback(ctx, topic, key, event, nil)
+}
+
+// PublishEventCallback buffers an event and reports its delivery to callback
+// The producer span covers buffering the message, not its delivery
+func (a *AsyncPublisher) PublishEventCallback(ctx context.Context, topic string, key string, event interface{}, callback DeliveryCallback) (err error) {
+	ctx, span := startPublishSpan(ctx, topic, key)
+	defer func() { endSpan(span, err) }()
+
+	cloudEvent, err := a.opts.newCloudEvent(ctx, topic, key, event)
+	if err != nil {
+		return err
+	}
+
+	value, headers, err := a.opts.encode(cloudEvent)
+	if err != nil {
+		return err
+	}
+	return a.enqueue(ctx, topic, key, value, headers, callback)
+}
+
+// PublishCloudEvent buffers a CloudEvent
+func (a *AsyncPublisher) PublishCloudEvent(topic string, key string, event *CloudEvent) error {
+	value, headers, err := a.opts.encode(event)
+	if err != nil {
+		return err
+	}
+	return a.enqueue(context.Background(), topic, key, value, headers, nil)
+}
+
+// PublishMessage buffers an already encoded payload with custom headers
+func (a *AsyncPublisher) PublishMessage(topic string, key string, payload []byte, headers map[string]string) error {
+	return a.enqueue(context.Background(), topic, key, payload, a.opts.withSourceHeader(headers), nil)
+}
+
+// enqueue takes a buffer slot according to the backpressure policy and hands the message to sarama
+func (a *AsyncPublisher) enqueue(ctx context.Context, topic string, key string, payload []byte, headers map[string]string, callback DeliveryCallback) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrProducerClosed
+	}
+
+	if err := a.acquire(ctx); err != nil {
+		a.recordFailure(topic, "buffer_full")
+		if a.async.Backpressure == BackpressureDrop && errors.Is(err, ErrBufferFull) {
+			a.report(Delivery{Topic: topic, Key: key, Partition: -1, Offset: -1, Err: err}, callback)
+			return nil
+		}
+		return err
+	}
+	a.recordQueueDepth()
+
+	a.producer.Input() <- &sarama.ProducerMessage{
+		Topic:    topic,
+		Key:      sarama.StringEncoder(key),
+		Value:    sarama.ByteEncoder(payload),
+		Headers:  recordHeaders(topic, headers),
+		Metadata: &pendingMessage{key: key, callback: callback},
+	}
+	return nil
+}
+
+// acquire takes a buffer slot
+func (a *AsyncPublisher) acquire(ctx context.Context) error {
+	select {
+	case a.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if a.async.Backpressure != BackpressureBlock {
+		return ErrBufferFull
+	}
+
+	timer := time.NewTimer(a.async.BlockTimeout)
+	defer timer.Stop()
+
+	select {
+	case a.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrBufferFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver reports delivery results until the producer shut down
+func (a *AsyncPublisher) deliver() {
+	defer close(a.done)
+
+	successes := a.producer.Successes()
+	errs := a.producer.Errors()
+
+	for successes != nil || errs != nil {
+		select {
+		case msg, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+			a.complete(msg, nil)
+		case perr, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			a.complete(perr.Msg, perr.Err)
+		}
+	}
+}
+
+// complete reports the outcome of a message and releases its slot
+// The slot is released last, so Flush returns only after the callbacks ran
+func (a *AsyncPublisher) complete(msg *sarama.ProducerMessage, err error) {
+	var callback DeliveryCallback
+	delivery := Delivery{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset, Err: err}
+	if pending, ok := msg.Metadata.(*pendingMessage); ok {
+		delivery.Key = pending.key
+		callback = pending.callback
+	}
+
+	if err != nil {
+		a.recordFailure(msg.Topic, "delivery")
+		if callback == nil && a.async.OnDelivery == nil {
+			log.Printf("Failed to deliver message to topic %s: %v", msg.Topic, err)
+		}
+	} else if a.delivered != nil {
+		a.delivered.WithLabelValues(a.opts.Source, msg.Topic).Inc()
+	}
+
+	a.report(delivery, callback)
+
+	<-a.slots
+	a.recordQueueDepth()
+}
+
+// report calls the global and the per-message delivery callback
+func (a *AsyncPublisher) report(delivery Delivery, callback DeliveryCallback) {
+	if a.async.OnDelivery != nil {
+		a.async.OnDelivery(delivery)
+	}
+	if callback != nil {
+		callback(delivery)
+	}
+}
+
+// Pending returns the number of buffered messages not delivered yet
+func (a *AsyncPublisher) Pending() int {
+	return len(a.slots)
+}
+
+// Flush waits until every buffered message was delivered or failed
+func (a *AsyncPublisher) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(flushPollInterval)
+	defer ticker.Stop()
+
+	for a.Pending() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to flush %d pending messages: %w", a.Pending(), ctx.Err())
+		}
+	}
+	return nil
+}
+
+// Close stops accepting messages and waits up to FlushTimeout for buffered messages to be delivered
+func (a *AsyncPublisher) Close() error {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		a.closed = true
+		a.mu.Unlock()
+
+		// AsyncClose flushes buffered batches before closing the result channels
+		a.producer.AsyncClose()
+
+		select {
+		case <-a.done:
+		case <-time.After(a.async.FlushTimeout):
+			a.closeErr = fmt.Errorf("failed to flush %d pending messages within %v", a.Pending(), a.async.FlushTimeout)
+		}
+	})
+	return a.closeErr
+}
+
+// recordQueueDepth updates the queue depth gauge
+func (a *AsyncPublisher) recordQueueDepth() {
+	if a.queueDepth != nil {
+		a.queueDepth.WithLabelValues(a.opts.Source).Set(float64(a.Pending()))
+	}
+}
+
+// recordFailure counts a failed or dropped message
+func (a *AsyncPublisher) recordFailure(topic string, reason string) {
+	if a.failures != nil {
+		a.failures.WithLabelValues(a.opts.Source, topic, reason).Inc()
+	}
+}
@@ -0,0 +1,162 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// fakeAsyncProducer holds messages until the test delivers them
+type fakeAsyncProducer struct {
+	sarama.AsyncProducer
+
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errs      chan *sarama.ProducerError
+}
+
+func newFakeAsyncProducer(size int) *fakeAsyncProducer {
+	return &fakeAsyncProducer{
+		input:     make(chan *sarama.ProducerMessage, size),
+		successes: make(chan *sarama.ProducerMessage, size),
+		errs:      make(chan *sarama.ProducerError, size),
+	}
+}
+
+func (f *fakeAsyncProducer) Input() chan<- *sarama.ProducerMessage     { return f.input }
+func (f *fakeAsyncProducer) Successes() <-chan *sarama.ProducerMessage { return f.successes }
+func (f *fakeAsyncProducer) Errors() <-chan *sarama.ProducerError      { return f.errs }
+
+// deliverNext acknowledges the next buffered message, or fails it with err
+func (f *fakeAsyncProducer) deliverNext(err error) {
+	msg := <-f.input
+	if err != nil {
+		f.errs <- &sarama.ProducerError{Msg: msg, Err: err}
+		return
+	}
+	f.successes <- msg
+}
+
+// AsyncClose delivers the remaining messages like sarama flushes its batches
+func (f *fakeAsyncProducer) AsyncClose() {
+	close(f.input)
+	for msg := range f.input {
+		f.successes <- msg
+	}
+	close(f.successes)
+	close(f.errs)
+}
+
+func TestAsyncPublisher(t *testing.T) {
+	deliveryErr := errors.New("broker unavailable")
+
+	tests := []struct {
+		name          string
+		policy        BackpressurePolicy
+		deliveryErr   error
+		overflow      bool
+		wantErr       error
+		wantDelivered []error
+	}{
+		{name: "delivered", policy: BackpressureError, wantDelivered: []error{nil}},
+		{name: "delivery failed", policy: BackpressureError, deliveryErr: deliveryErr, wantDelivered: []error{deliveryErr}},
+		{name: "buffer full fails", policy: BackpressureError, overflow: true, wantErr: ErrBufferFull, wantDelivered: []error{nil}},
+		{name: "buffer full drops", policy: BackpressureDrop, overflow: true, wantDelivered: []error{ErrBufferFull, nil}},
+		{name: "buffer full blocks until timeout", policy: BackpressureBlock, overflow: true, wantErr: ErrBufferFull, wantDelivered: []error{nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := newFakeAsyncProducer(1)
+			opts, err := ProducerOptions{
+				Source: "test-service",
+				Async:  AsyncOptions{Enabled: true, BufferSize: 1, Backpressure: tt.policy, BlockTimeout: 10 * time.Millisecond},
+			}.withDefaults()
+			if err != nil {
+				t.Fatalf("withDefaults failed: %v", err)
+			}
+
+			var mu sync.Mutex
+			var delivered []error
+			opts.Async.OnDelivery = func(delivery Delivery) {
+				mu.Lock()
+				defer mu.Unlock()
+				delivered = append(delivered, delivery.Err)
+			}
+			publisher := newAsyncPublisher(producer, opts)
+
+			if err := publisher.PublishEvent("orders", "1", testPayload{ID: "1"}); err != nil {
+				t.Fatalf("PublishEvent failed: %v", err)
+			}
+
+			if tt.overflow {
+				err := publisher.PublishEvent("orders", "2", testPayload{ID: "2"})
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+				}
+			}
+
+			producer.deliverNext(tt.deliveryErr)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if err := publisher.Flush(ctx); err != nil {
+				t.Fatalf("Flush failed: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(delivered) != len(tt.wantDelivered) {
+				t.Fatalf("Expected %d deliveries, got %d", len(tt.wantDelivered), len(delivered))
+			}
+			for i, want := range tt.wantDelivered {
+				if !errors.Is(delivered[i], want) {
+					t.Errorf("Expected delivery %d to report %v, got %v", i, want, delivered[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAsyncPublisherCloseFlushes(t *testing.T) {
+	producer := newFakeAsyncProducer(10)
+	opts, err := ProducerOptions{Async: AsyncOptions{Enabled: true, BufferSize: 10}}.withDefaults()
+	if err != nil {
+		t.Fatalf("withDefaults failed: %v", err)
+	}
+	publisher := newAsyncPublisher(producer, opts)
+
+	var delivered []string
+	for _, key := range []string{"1", "2", "3"} {
+		err := publisher.PublishEventCallback(context.Background(), "orders", key, testPayload{ID: key}, func(delivery Delivery) {
+			delivered = append(delivered, delivery.Key)
+		})
+		if err != nil {
+			t.Fatalf("PublishEventCallback failed: %v", err)
+		}
+	}
+
+	if err := publisher.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(delivered) != 3 || publisher.Pending() != 0 {
+		t.Errorf("Expected 3 delivered and 0 pending messages, got %v and %d", delivered, publisher.Pending())
+	}
+
+	if err := publisher.PublishEvent("orders", "4", testPayload{ID: "4"}); !errors.Is(err, ErrProducerClosed) {
+		t.Errorf("Expected ErrProducerClosed, got %v", err)
+	}
+}
+
+func TestAsyncFallsBackToPublisher(t *testing.T) {
+	broker := NewMemoryBroker()
+	if Async(broker) != Publisher(broker) {
+		t.Error("Expected Async to return a publisher without async mode unchanged")
+	}
+	if Async(nil) != nil {
+		t.Error("Expected Async to return nil for a nil publisher")
+	}
+}
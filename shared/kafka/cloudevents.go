@@ -0,0 +1,255 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+// CloudEventsSpecVersion is the CloudEvents version produced and accepted
+const CloudEventsSpecVersion = "1.0"
+
+const (
+	// ContentTypeJSON is the content type of JSON event data
+	ContentTypeJSON = "application/json"
+
+	// ContentTypeCloudEventsJSON is the content type of structured mode messages
+	ContentTypeCloudEventsJSON = "application/cloudevents+json"
+
+	// HeaderContentType is the Kafka header holding the message content type
+	HeaderContentType = "content-type"
+
+	// cloudEventsHeaderPrefix prefixes CloudEvents attributes in binary mode headers
+	cloudEventsHeaderPrefix = "ce_"
+)
+
+// CloudEvents attribute headers in binary mode
+const (
+	HeaderCloudEventSpecVersion   = cloudEventsHeaderPrefix + "specversion"
+	HeaderCloudEventID            = cloudEventsHeaderPrefix + "id"
+	HeaderCloudEventSource        = cloudEventsHeaderPrefix + "source"
+	HeaderCloudEventType          = cloudEventsHeaderPrefix + "type"
+	HeaderCloudEventSubject       = cloudEventsHeaderPrefix + "subject"
+	HeaderCloudEventTime          = cloudEventsHeaderPrefix + "time"
+	HeaderCloudEventDataSchema    = cloudEventsHeaderPrefix + "dataschema"
+	HeaderCloudEventCorrelationID = cloudEventsHeaderPrefix + "correlationid"
+	HeaderCloudEventCausationID   = cloudEventsHeaderPrefix + "causationid"
+)
+
+var (
+	// ErrNotCloudEvent is returned when decoding a message that isn't a CloudEvent
+	ErrNotCloudEvent = errors.New("message is not a CloudEvent")
+
+	// ErrInvalidCloudEvent is returned when a CloudEvent misses required attributes
+	ErrInvalidCloudEvent = errors.New("invalid CloudEvent")
+)
+
+// ContentMode selects how CloudEvents are mapped onto Kafka messages
+type ContentMode string
+
+const (
+	// ContentModeBinary keeps the event data as message value and the attributes in ce_ headers
+	ContentModeBinary ContentMode = "binary"
+
+	// ContentModeStructured encodes attributes and data together as a JSON envelope
+	ContentModeStructured ContentMode = "structured"
+)
+
+// CloudEvent is a CloudEvents 1.0 event with JSON data
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype,omitempty"`
+	DataSchema      string    `json:"dataschema,omitempty"`
+
+	// CorrelationID groups all events caused by one request (extension attribute)
+	CorrelationID string `json:"correlationid,omitempty"`
+
+	// CausationID is the ID of the message or command that caused the event (extension attribute)
+	CausationID string `json:"causationid,omitempty"`
+
+	// TraceParent and TraceState hold the W3C trace context of the publisher (distributed tracing extension)
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// NewCloudEvent creates a CloudEvent with a new ID and JSON encoded data
+func NewCloudEvent(source, eventType, subject string, data interface{}) (*CloudEvent, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              uuid.New().String(),
+		Source:          source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: ContentTypeJSON,
+		Data:            encoded,
+	}, nil
+}
+
+// WithContext sets the correlation and causation IDs and the trace context carried by the context, if not set yet
+func (e *CloudEvent) WithContext(ctx context.Context) *CloudEvent {
+	metadata := eventstore.EventMetadataFromContext(ctx)
+
+	if id, ok := metadata[eventstore.MetadataCorrelationID].(string); ok && e.CorrelationID == "" {
+		e.CorrelationID = id
+	}
+	if id, ok := metadata[eventstore.MetadataCausationID].(string); ok && e.CausationID == "" {
+		e.CausationID = id
+	}
+
+	if e.TraceParent == "" {
+		carrier := make(map[string]string, 2)
+		InjectTraceContext(ctx, carrier)
+		e.TraceParent = carrier[HeaderTraceParent]
+		e.TraceState = carrier[HeaderTraceState]
+	}
+	return e
+}
+
+// DecodeData decodes the event data into v
+func (e *CloudEvent) DecodeData(v interface{}) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("failed to decode event data: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the required CloudEvents attributes
+func (e *CloudEvent) Validate() error {
+	switch {
+	case e.SpecVersion != CloudEventsSpecVersion:
+		return fmt.Errorf("%w: unsupported specversion %q", ErrInvalidCloudEvent, e.SpecVersion)
+	case e.ID == "":
+		return fmt.Errorf("%w: missing id", ErrInvalidCloudEvent)
+	case e.Source == "":
+		return fmt.Errorf("%w: missing source", ErrInvalidCloudEvent)
+	case e.Type == "":
+		return fmt.Errorf("%w: missing type", ErrInvalidCloudEvent)
+	}
+	return nil
+}
+
+// Encode maps the event onto a Kafka message value and headers
+func (e *CloudEvent) Encode(mode ContentMode) ([]byte, map[string]string, error) {
+	if err := e.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if mode == ContentModeStructured {
+		value, err := json.Marshal(e)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal CloudEvent: %w", err)
+		}
+		headers := map[string]string{HeaderContentType: ContentTypeCloudEventsJSON}
+		if e.TraceParent != "" {
+			// Also in the headers, so consumers continue the trace without decoding the envelope
+			headers[HeaderTraceParent] = e.TraceParent
+		}
+		if e.TraceState != "" {
+			headers[HeaderTraceState] = e.TraceState
+		}
+		return value, headers, nil
+	}
+
+	return e.Data, e.Headers(), nil
+}
+
+// Headers returns the binary mode headers of the event
+func (e *CloudEvent) Headers() map[string]string {
+	headers := map[string]string{
+		HeaderCloudEventSpecVersion: e.SpecVersion,
+		HeaderCloudEventID:          e.ID,
+		HeaderCloudEventSource:      e.Source,
+		HeaderCloudEventType:        e.Type,
+		HeaderCloudEventTime:        e.Time.UTC().Format(time.RFC3339Nano),
+	}
+
+	optional := map[string]string{
+		HeaderContentType:             e.DataContentType,
+		HeaderCloudEventSubject:       e.Subject,
+		HeaderCloudEventDataSchema:    e.DataSchema,
+		HeaderCloudEventCorrelationID: e.CorrelationID,
+		HeaderCloudEventCausationID:   e.CausationID,
+		HeaderTraceParent:             e.TraceParent,
+		HeaderTraceState:              e.TraceState,
+	}
+	for key, value := range optional {
+		if value != "" {
+			headers[key] = value
+		}
+	}
+
+	return headers
+}
+
+// DecodeCloudEvent decodes a binary or structured mode CloudEvent from a Kafka message
+// Messages without CloudEvents attributes return ErrNotCloudEvent, so consumers can
+// fall back to their legacy payload handling
+func DecodeCloudEvent(value []byte, headers map[string]string) (*CloudEvent, error) {
+	if strings.HasPrefix(headers[HeaderContentType], ContentTypeCloudEventsJSON) {
+		var event CloudEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCloudEvent, err)
+		}
+		if err := event.Validate(); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+
+	if _, ok := headers[HeaderCloudEventSpecVersion]; !ok {
+		return nil, ErrNotCloudEvent
+	}
+
+	event := &CloudEvent{
+		SpecVersion:     headers[HeaderCloudEventSpecVersion],
+		ID:              headers[HeaderCloudEventID],
+		Source:          headers[HeaderCloudEventSource],
+		Type:            headers[HeaderCloudEventType],
+		Subject:         headers[HeaderCloudEventSubject],
+		DataContentType: headers[HeaderContentType],
+		DataSchema:      headers[HeaderCloudEventDataSchema],
+		CorrelationID:   headers[HeaderCloudEventCorrelationID],
+		CausationID:     headers[HeaderCloudEventCausationID],
+		TraceParent:     headers[HeaderTraceParent],
+		TraceState:      headers[HeaderTraceState],
+		Data:            value,
+	}
+
+	if raw := headers[HeaderCloudEventTime]; raw != "" {
+		eventTime, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid time %q", ErrInvalidCloudEvent, raw)
+		}
+		event.Time = eventTime
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CloudEvent decodes the message as a CloudEvent
+func (m *Message) CloudEvent() (*CloudEvent, error) {
+	return DecodeCloudEvent(m.Value, m.Headers)
+}
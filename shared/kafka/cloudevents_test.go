@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+func TestCloudEventRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		mode ContentMode
+	}{
+		{name: "binary mode", mode: ContentModeBinary},
+		{name: "structured mode", mode: ContentModeStructured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := eventstore.WithEventMetadata(context.Background(), eventstore.MetadataCorrelationID, "corr-1")
+			ctx = eventstore.WithEventMetadata(ctx, eventstore.MetadataCausationID, "cause-1")
+
+			event, err := NewCloudEvent("user-service", "user.created", "user-1", testPayload{ID: "1"})
+			if err != nil {
+				t.Fatalf("NewCloudEvent failed: %v", err)
+			}
+			event.DataSchema = "https://schemas.example.com/user.created/1.json"
+			event.WithContext(ctx)
+
+			value, headers, err := event.Encode(tt.mode)
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+
+			decoded, err := DecodeCloudEvent(value, headers)
+			if err != nil {
+				t.Fatalf("DecodeCloudEvent failed: %v", err)
+			}
+
+			if decoded.ID != event.ID || decoded.Source != "user-service" || decoded.Type != "user.created" || decoded.Subject != "user-1" {
+				t.Errorf("Expected attributes of %+v, got %+v", event, decoded)
+			}
+			if !decoded.Time.Equal(event.Time) {
+				t.Errorf("Expected time %v, got %v", event.Time, decoded.Time)
+			}
+			if decoded.DataSchema != event.DataSchema {
+				t.Errorf("Expected dataschema %s, got %s", event.DataSchema, decoded.DataSchema)
+			}
+			if decoded.CorrelationID != "corr-1" || decoded.CausationID != "cause-1" {
+				t.Errorf("Expected correlation corr-1 and causation cause-1, got %s and %s", decoded.CorrelationID, decoded.CausationID)
+			}
+
+			var payload testPayload
+			if err := decoded.DecodeData(&payload); err != nil {
+				t.Fatalf("DecodeData failed: %v", err)
+			}
+			if payload.ID != "1" {
+				t.Errorf("Expected payload id 1, got %s", payload.ID)
+			}
+		})
+	}
+}
+
+func TestDecodeCloudEventErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		expect  error
+	}{
+		{name: "legacy message", headers: map[string]string{"event_type": "user.created"}, expect: ErrNotCloudEvent},
+		{name: "missing id", headers: map[string]string{HeaderCloudEventSpecVersion: "1.0", HeaderCloudEventSource: "s", HeaderCloudEventType: "t"}, expect: ErrInvalidCloudEvent},
+		{name: "unsupported version", headers: map[string]string{HeaderCloudEventSpecVersion: "0.3", HeaderCloudEventID: "1", HeaderCloudEventSource: "s", HeaderCloudEventType: "t"}, expect: ErrInvalidCloudEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCloudEvent([]byte(`{}`), tt.headers)
+			if !errors.Is(err, tt.expect) {
+				t.Errorf("Expected %v, got %v", tt.expect, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,631 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka/schema"
+)
+
+const (
+	// HeaderRetryAttempt holds the number of failed attempts of a retried message
+	HeaderRetryAttempt = "x-retry-attempt"
+
+	// HeaderOriginalTopic holds the topic a retried or dead-lettered message was consumed from
+	HeaderOriginalTopic = "x-original-topic"
+
+	// HeaderRetryNotBefore holds the time (RFC 3339) a retried message is due
+	HeaderRetryNotBefore = "x-retry-not-before"
+
+	// HeaderError holds the error of the last failed attempt
+	HeaderError = "x-error"
+)
+
+const (
+	defaultConsumerWorkers   = 5
+	defaultWorkerQueueSize   = 16
+	defaultMaxRetries        = 3
+	defaultRetryBackoff      = 5 * time.Second
+	defaultMaxRetryBackoff   = 5 * time.Minute
+	defaultDrainTimeout      = 30 * time.Second
+	publishFailureRetryDelay = time.Second
+)
+
+// ErrPermanent marks handler errors that retrying can't fix, e.g. malformed payloads
+// Such messages go to the dead-letter topic immediately
+var ErrPermanent = errors.New("permanent failure")
+
+// Permanent marks an error as permanent
+func Permanent(err error) error {
+	return fmt.Errorf("%w: %w", ErrPermanent, err)
+}
+
+// Message is a consumed Kafka message
+type Message struct {
+	// Topic is the topic the message was originally published to, also for retries
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       string
+	Value     []byte
+	Headers   map[string]string
+	Timestamp time.Time
+
+	// Attempt is the number of failed attempts before this delivery
+	Attempt int
+}
+
+// Handler processes consumed messages
+// A returned error retries the message through the retry topics, ErrPermanent skips the retries
+type Handler interface {
+	Handle(ctx context.Context, msg *Message) error
+}
+
+// HandlerFunc is a function adapter for Handler
+type HandlerFunc func(ctx context.Context, msg *Message) error
+
+// Handle calls the function
+func (f HandlerFunc) Handle(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// TypedHandler decodes JSON payloads into T before calling fn
+// Payloads that can't be decoded are dead-lettered without retries
+func TypedHandler[T any](fn func(ctx context.Context, msg *Message, payload *T) error) Handler {
+	return HandlerFunc(func(ctx context.Context, msg *Message) error {
+		var payload T
+		if err := json.Unmarshal(msg.Value, &payload); err != nil {
+			return Permanent(fmt.Errorf("failed to decode message: %w", err))
+		}
+		return fn(ctx, msg, &payload)
+	})
+}
+
+// MessagePublisher publishes encoded messages; implemented by Producer
+type MessagePublisher interface {
+	PublishMessage(topic string, key string, payload []byte, headers map[string]string) error
+}
+
+// ConsumerConfig configures a Consumer
+type ConsumerConfig struct {
+	Brokers []string
+	GroupID string
+	Topics  []string
+
+	// InitialOffset is where a new consumer group starts: "earliest" or "latest" (default)
+	InitialOffset string
+
+	// Workers is the number of concurrent handlers
+	// Messages with the same key are always handled by the same worker, in order
+	Workers int
+
+	// MaxRetries is the number of retries before a message is dead-lettered
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled for every further retry
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the retry delay
+	MaxRetryBackoff time.Duration
+
+	// DrainTimeout bounds how long Stop waits for in-flight messages
+	DrainTimeout time.Duration
+
+	// Publisher publishes retried and dead-lettered messages
+	// Defaults to a Producer connected to Brokers
+	Publisher MessagePublisher
+
+	// Schemas validates CloudEvents referencing a registered dataschema before they are
+	// handled; invalid events are dead-lettered. Defaults to the embedded registry of EventTypes
+	Schemas *schema.Registry
+}
+
+// RetryTopic returns the retry topic of a consumer group for an attempt
+func RetryTopic(groupID string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", groupID, attempt)
+}
+
+// DeadLetterTopic returns the dead-letter topic of a consumer group
+func DeadLetterTopic(groupID string) string {
+	return groupID + ".dlq"
+}
+
+// Consumer consumes topics as a consumer group with a bounded worker pool
+//
+// Messages are handled concurrently across keys and in order per key. Offsets are
+// committed once every earlier message of the partition was handled, so delivery is
+// at-least-once. A failed message is moved to the group's retry topic of its attempt,
+// consumed again once its backoff passed, and moved to the dead-letter topic after
+// MaxRetries. Retried messages are no longer ordered with later messages of their key
+type Consumer struct {
+	cfg       ConsumerConfig
+	handler   Handler
+	group     sarama.ConsumerGroup
+	publisher MessagePublisher
+	producer  *Producer // owned producer, closed on Stop
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers []chan *consumerJob
+	wg      sync.WaitGroup
+	done    chan struct{}
+}
+
+// consumerJob is a message queued for a worker
+type consumerJob struct {
+	msg   *Message
+	claim *claimState
+}
+
+// claimState tracks the messages of a partition claim dispatched to the workers
+type claimState struct {
+	session   sarama.ConsumerGroupSession
+	claim     sarama.ConsumerGroupClaim
+	tracker   *offsetTracker
+	completed chan int64
+
+	// released is closed once the claim stops waiting for completions
+	released chan struct{}
+}
+
+// mark records a handled offset and marks the partition's committable offset
+func (s *claimState) mark(offset int64) {
+	if next, ok := s.tracker.complete(offset); ok {
+		s.session.MarkOffset(s.claim.Topic(), s.claim.Partition(), next, "")
+	}
+}
+
+// NewConsumer creates a new consumer group consumer
+func NewConsumer(cfg ConsumerConfig, handler Handler) (*Consumer, error) {
+	if cfg.Schemas == nil {
+		registry, err := EventSchemas()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load event schemas: %w", err)
+		}
+		cfg.Schemas = registry
+	}
+
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V2_6_0_0
+	saramaConfig.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
+	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+	if cfg.InitialOffset == "earliest" {
+		saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+
+	group, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
+	}
+
+	c := newConsumer(cfg, handler)
+	c.group = group
+
+	if c.publisher == nil {
+		producer, err := NewProducer(cfg.Brokers)
+		if err != nil {
+			group.Close()
+			return nil, err
+		}
+		c.producer = producer
+		c.publisher = producer
+	}
+
+	return c, nil
+}
+
+// newConsumer applies defaults and sets up a consumer without connecting to Kafka
+func newConsumer(cfg ConsumerConfig, handler Handler) *Consumer {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultConsumerWorkers
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.MaxRetryBackoff <= 0 {
+		cfg.MaxRetryBackoff = defaultMaxRetryBackoff
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = defaultDrainTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Consumer{
+		cfg:       cfg,
+		handler:   handler,
+		publisher: cfg.Publisher,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+}
+
+// Start starts the workers and joins the consumer group
+func (c *Consumer) Start() {
+	c.startWorkers()
+
+	topics := append([]string(nil), c.cfg.Topics...)
+	for attempt := 1; attempt <= c.cfg.MaxRetries; attempt++ {
+		topics = append(topics, RetryTopic(c.cfg.GroupID, attempt))
+	}
+
+	log.Printf("Kafka consumer group '%s' starting with %d workers on %d topics", c.cfg.GroupID, c.cfg.Workers, len(topics))
+
+	go func() {
+		defer close(c.done)
+
+		for {
+			// Consume returns on every rebalance and has to be called again
+			if err := c.group.Consume(c.ctx, topics, c); err != nil {
+				if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+					return
+				}
+				log.Printf("Kafka consumer group '%s' error: %v", c.cfg.GroupID, err)
+
+				select {
+				case <-c.ctx.Done():
+					return
+				case <-time.After(5 * time.Second):
+				}
+			}
+			if c.ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops fetching, waits up to DrainTimeout for in-flight messages and leaves the group
+// Offsets of handled messages are committed; unhandled messages are redelivered later
+func (c *Consumer) Stop() error {
+	log.Printf("Stopping Kafka consumer group '%s'...", c.cfg.GroupID)
+
+	// Closing the group ends the sessions; claims drain before their offsets are committed
+	err := c.group.Close()
+	c.cancel()
+	<-c.done
+
+	c.stopWorkers()
+
+	if c.producer != nil {
+		if closeErr := c.producer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	log.Printf("Kafka consumer group '%s' stopped", c.cfg.GroupID)
+	return err
+}
+
+// Setup is called at the beginning of a consumer group session
+func (c *Consumer) Setup(session sarama.ConsumerGroupSession) error {
+	log.Printf("Kafka consumer group '%s' assigned %v", c.cfg.GroupID, session.Claims())
+	return nil
+}
+
+// Cleanup is called at the end of a consumer group session
+func (c *Consumer) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim dispatches a partition's messages to the workers and marks handled offsets
+func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	state := &claimState{
+		session:   session,
+		claim:     claim,
+		tracker:   newOffsetTracker(),
+		completed: make(chan int64, defaultWorkerQueueSize),
+		released:  make(chan struct{}),
+	}
+	defer close(state.released)
+	defer c.drain(state)
+
+	ctx := session.Context()
+	for {
+		select {
+		case raw, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			msg := newMessage(raw)
+			if !c.waitUntilDue(ctx, state, msg) {
+				return nil
+			}
+
+			state.tracker.add(raw.Offset)
+			if !c.dispatch(ctx, state, &consumerJob{msg: msg, claim: state}) {
+				return nil
+			}
+
+		case offset := <-state.completed:
+			state.mark(offset)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// drain waits up to DrainTimeout for the claim's dispatched messages and marks their offsets
+func (c *Consumer) drain(state *claimState) {
+	timeout := time.NewTimer(c.cfg.DrainTimeout)
+	defer timeout.Stop()
+
+	for state.tracker.pending() > 0 {
+		select {
+		case offset := <-state.completed:
+			state.mark(offset)
+		case <-timeout.C:
+			log.Printf("Kafka consumer group '%s' released %s/%d with %d messages in flight",
+				c.cfg.GroupID, state.claim.Topic(), state.claim.Partition(), state.tracker.pending())
+			return
+		}
+	}
+}
+
+// waitUntilDue delays a retried message until its backoff passed
+// Retry topics hold a single attempt, so their messages become due in order
+func (c *Consumer) waitUntilDue(ctx context.Context, state *claimState, msg *Message) bool {
+	notBefore, err := time.Parse(time.RFC3339Nano, msg.Headers[HeaderRetryNotBefore])
+	if err != nil {
+		return true
+	}
+
+	wait := time.Until(notBefore)
+	if wait <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case offset := <-state.completed:
+			state.mark(offset)
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// dispatch queues a message on the worker owning its key
+func (c *Consumer) dispatch(ctx context.Context, state *claimState, job *consumerJob) bool {
+	key := job.msg.Key
+	if key == "" {
+		// Keep the partition's order for messages without a key
+		key = fmt.Sprintf("%s/%d", job.msg.Topic, job.msg.Partition)
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	worker := c.workers[hash.Sum32()%uint32(len(c.workers))]
+
+	// Keep marking completions while the worker's queue is full, its worker may wait on them
+	for {
+		select {
+		case worker <- job:
+			return true
+		case offset := <-state.completed:
+			state.mark(offset)
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// startWorkers starts the worker pool
+func (c *Consumer) startWorkers() {
+	c.workers = make([]chan *consumerJob, c.cfg.Workers)
+	for i := range c.workers {
+		jobs := make(chan *consumerJob, defaultWorkerQueueSize)
+		c.workers[i] = jobs
+
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			for job := range jobs {
+				if !c.process(c.ctx, job.msg) {
+					continue
+				}
+				select {
+				case job.claim.completed <- job.msg.Offset:
+				case <-job.claim.released:
+				}
+			}
+		}()
+	}
+}
+
+// stopWorkers stops the worker pool once its queues are empty
+func (c *Consumer) stopWorkers() {
+	for _, jobs := range c.workers {
+		close(jobs)
+	}
+	c.wg.Wait()
+}
+
+// process handles a message, moving it to a retry or dead-letter topic on failure
+// It reports whether the message is done and its offset may be committed
+func (c *Consumer) process(ctx context.Context, msg *Message) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	err := c.validate(msg)
+	if err == nil {
+		err = c.handle(ctx, msg)
+	}
+	if err == nil {
+		return true
+	}
+	if ctx.Err() != nil {
+		// Interrupted by shutdown, the message is redelivered
+		return false
+	}
+
+	attempt := msg.Attempt + 1
+	topic := RetryTopic(c.cfg.GroupID, attempt)
+	if errors.Is(err, ErrPermanent) || attempt > c.cfg.MaxRetries {
+		topic = DeadLetterTopic(c.cfg.GroupID)
+	}
+
+	log.Printf("Failed to handle message from %s (partition %d, offset %d, attempt %d), moving it to %s: %v",
+		msg.Topic, msg.Partition, msg.Offset, attempt, topic, err)
+
+	headers := make(map[string]string, len(msg.Headers)+4)
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[HeaderOriginalTopic] = msg.Topic
+	headers[HeaderRetryAttempt] = strconv.Itoa(attempt)
+	headers[HeaderError] = err.Error()
+	headers[HeaderRetryNotBefore] = time.Now().Add(c.retryDelay(attempt)).UTC().Format(time.RFC3339Nano)
+
+	// The offset is only committed once the message is safe in the retry or dead-letter topic
+	for {
+		publishErr := c.publisher.PublishMessage(topic, msg.Key, msg.Value, headers)
+		if publishErr == nil {
+			return true
+		}
+		log.Printf("Failed to publish message to %s: %v", topic, publishErr)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(publishFailureRetryDelay):
+		}
+	}
+}
+
+// handle runs the handler in a consumer span continuing the producer's trace
+func (c *Consumer) handle(ctx context.Context, msg *Message) error {
+	ctx, span := startProcessSpan(ctx, msg, c.cfg.GroupID)
+	err := c.handler.Handle(ctx, msg)
+	endSpan(span, err)
+	return err
+}
+
+// validate checks a CloudEvent's data against the schema its dataschema references
+// Messages that aren't CloudEvents or reference unknown schemas are passed on unchecked
+func (c *Consumer) validate(msg *Message) error {
+	if c.cfg.Schemas == nil {
+		return nil
+	}
+
+	cloudEvent, err := msg.CloudEvent()
+	if errors.Is(err, ErrNotCloudEvent) {
+		return nil
+	}
+	if err != nil {
+		return Permanent(err)
+	}
+	if cloudEvent.DataSchema == "" {
+		return nil
+	}
+
+	s, err := c.cfg.Schemas.Resolve(cloudEvent.DataSchema)
+	if err != nil {
+		return nil
+	}
+	if err := s.Validate(cloudEvent.Data); err != nil {
+		return Permanent(fmt.Errorf("event %s: %w", cloudEvent.ID, err))
+	}
+	return nil
+}
+
+// retryDelay returns the backoff before an attempt
+func (c *Consumer) retryDelay(attempt int) time.Duration {
+	delay := c.cfg.RetryBackoff
+	for i := 1; i < attempt && delay < c.cfg.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.cfg.MaxRetryBackoff {
+		delay = c.cfg.MaxRetryBackoff
+	}
+	return delay
+}
+
+// newMessage converts a sarama message, restoring the original topic and attempt of retries
+func newMessage(raw *sarama.ConsumerMessage) *Message {
+	headers := make(map[string]string, len(raw.Headers))
+	for _, header := range raw.Headers {
+		if header != nil {
+			headers[string(header.Key)] = string(header.Value)
+		}
+	}
+
+	msg := &Message{
+		Topic:     raw.Topic,
+		Partition: raw.Partition,
+		Offset:    raw.Offset,
+		Key:       string(raw.Key),
+		Value:     raw.Value,
+		Headers:   headers,
+		Timestamp: raw.Timestamp,
+	}
+
+	if topic, ok := headers[HeaderOriginalTopic]; ok && topic != "" {
+		msg.Topic = topic
+	}
+	if attempt, err := strconv.Atoi(headers[HeaderRetryAttempt]); err == nil {
+		msg.Attempt = attempt
+	}
+
+	return msg
+}
+
+// offsetTracker tracks the in-flight offsets of a partition
+// Messages complete out of order, but only the offset after the oldest unfinished
+// message may be committed
+type offsetTracker struct {
+	inFlight []int64
+	done     map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{done: make(map[int64]bool)}
+}
+
+// add records a dispatched offset; offsets are added in increasing order
+func (t *offsetTracker) add(offset int64) {
+	t.inFlight = append(t.inFlight, offset)
+}
+
+// complete records a handled offset and returns the next offset to commit, if it moved
+func (t *offsetTracker) complete(offset int64) (int64, bool) {
+	t.done[offset] = true
+
+	moved := false
+	var next int64
+	for len(t.inFlight) > 0 && t.done[t.inFlight[0]] {
+		delete(t.done, t.inFlight[0])
+		next = t.inFlight[0] + 1
+		t.inFlight = t.inFlight[1:]
+		moved = true
+	}
+	return next, moved
+}
+
+// pending returns the number of dispatched offsets not handled yet
+func (t *offsetTracker) pending() int {
+	return len(t.inFlight)
+}
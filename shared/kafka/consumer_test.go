@@ -0,0 +1,113 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type publishedMessage struct {
+	topic   string
+	headers map[string]string
+}
+
+type fakePublisher struct {
+	published []publishedMessage
+}
+
+func (p *fakePublisher) PublishMessage(topic string, key string, payload []byte, headers map[string]string) error {
+	p.published = append(p.published, publishedMessage{topic: topic, headers: headers})
+	return nil
+}
+
+type testPayload struct {
+	ID string `json:"id"`
+}
+
+func TestConsumerProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		attempt     int
+		handlerErr  error
+		expectTopic string
+		expectRetry string
+	}{
+		{name: "handled", value: `{"id":"1"}`},
+		{name: "first failure is retried", value: `{"id":"1"}`, handlerErr: errors.New("boom"), expectTopic: "group.retry.1", expectRetry: "1"},
+		{name: "later failure moves to next retry topic", value: `{"id":"1"}`, attempt: 2, handlerErr: errors.New("boom"), expectTopic: "group.retry.3", expectRetry: "3"},
+		{name: "exhausted retries are dead-lettered", value: `{"id":"1"}`, attempt: 3, handlerErr: errors.New("boom"), expectTopic: "group.dlq", expectRetry: "4"},
+		{name: "permanent failure is dead-lettered", value: `{"id":"1"}`, handlerErr: Permanent(errors.New("invalid")), expectTopic: "group.dlq", expectRetry: "1"},
+		{name: "undecodable payload is dead-lettered", value: `not json`, expectTopic: "group.dlq", expectRetry: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &fakePublisher{}
+			handler := TypedHandler(func(ctx context.Context, msg *Message, payload *testPayload) error {
+				if payload.ID != "1" {
+					t.Errorf("Expected payload id 1, got %s", payload.ID)
+				}
+				return tt.handlerErr
+			})
+
+			c := newConsumer(ConsumerConfig{GroupID: "group", RetryBackoff: time.Second, Publisher: publisher}, handler)
+			msg := &Message{Topic: "orders", Value: []byte(tt.value), Headers: map[string]string{}, Attempt: tt.attempt}
+
+			if !c.process(context.Background(), msg) {
+				t.Fatalf("Expected message to be done")
+			}
+
+			if tt.expectTopic == "" {
+				if len(publisher.published) != 0 {
+					t.Errorf("Expected nothing published, got %d messages", len(publisher.published))
+				}
+				return
+			}
+
+			if len(publisher.published) != 1 {
+				t.Fatalf("Expected 1 published message, got %d", len(publisher.published))
+			}
+			published := publisher.published[0]
+			if published.topic != tt.expectTopic {
+				t.Errorf("Expected topic %s, got %s", tt.expectTopic, published.topic)
+			}
+			if published.headers[HeaderRetryAttempt] != tt.expectRetry {
+				t.Errorf("Expected attempt %s, got %s", tt.expectRetry, published.headers[HeaderRetryAttempt])
+			}
+			if published.headers[HeaderOriginalTopic] != "orders" {
+				t.Errorf("Expected original topic orders, got %s", published.headers[HeaderOriginalTopic])
+			}
+		})
+	}
+}
+
+func TestOffsetTracker(t *testing.T) {
+	tracker := newOffsetTracker()
+	for offset := int64(10); offset < 14; offset++ {
+		tracker.add(offset)
+	}
+
+	steps := []struct {
+		complete   int64
+		expectNext int64
+		expectMove bool
+	}{
+		{complete: 11},
+		{complete: 13},
+		{complete: 10, expectNext: 12, expectMove: true},
+		{complete: 12, expectNext: 14, expectMove: true},
+	}
+
+	for _, step := range steps {
+		next, moved := tracker.complete(step.complete)
+		if moved != step.expectMove || next != step.expectNext {
+			t.Errorf("Completing %d: expected (%d, %v), got (%d, %v)", step.complete, step.expectNext, step.expectMove, next, moved)
+		}
+	}
+
+	if tracker.pending() != 0 {
+		t.Errorf("Expected no pending offsets, got %d", tracker.pending())
+	}
+}
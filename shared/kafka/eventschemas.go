@@ -0,0 +1,212 @@
+package kafka
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sync"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka/schema"
+)
+
+//go:generate go run ./internal/schemagen -dir schemas
+
+// schemaFiles is the versioned JSON Schema registry of the event types
+//
+//go:embed schemas
+var schemaFiles embed.FS
+
+var (
+	eventSchemasOnce sync.Once
+	eventSchemas     *schema.Registry
+	eventSchemasErr  error
+)
+
+// EventTypes returns a value of every event type published through this package
+// Their JSON Schemas are registered under the type name in the schemas directory
+func EventTypes() []interface{} {
+	return []interface{}{
+		// Category Events
+		CategoryCreatedEvent{},
+		CategoryUpdatedEvent{},
+		CategoryDeletedEvent{},
+
+		// Post Events
+		PostCreatedEvent{},
+		PostUpdatedEvent{},
+		PostPublishedEvent{},
+		PostDeletedEvent{},
+
+		// Tag Events
+		TagCreatedEvent{},
+		TagUpdatedEvent{},
+		TagDeletedEvent{},
+
+		// Comment Events
+		CommentCreatedEvent{},
+		CommentModeratedEvent{},
+		CommentDeletedEvent{},
+		CommentApprovedEvent{},
+		CommentRejectedEvent{},
+
+		// Media Events
+		MediaUploadedEvent{},
+		MediaDeletedEvent{},
+		MediaThumbnailGeneratedEvent{},
+
+		// Link Events
+		LinkCreatedEvent{},
+		LinkUpdatedEvent{},
+		LinkDeletedEvent{},
+		LinkActivatedEvent{},
+		LinkDeactivatedEvent{},
+		LinkExpiredEvent{},
+		LinkClickedEvent{},
+		LinkClickFraudDetectedEvent{},
+
+		// Foodfolio Category Events
+		FoodfolioCategoryCreatedEvent{},
+		FoodfolioCategoryUpdatedEvent{},
+		FoodfolioCategoryDeletedEvent{},
+
+		// Foodfolio Company Events
+		FoodfolioCompanyCreatedEvent{},
+		FoodfolioCompanyUpdatedEvent{},
+		FoodfolioCompanyDeletedEvent{},
+
+		// Foodfolio Item Events
+		FoodfolioItemCreatedEvent{},
+		FoodfolioItemUpdatedEvent{},
+		FoodfolioItemDeletedEvent{},
+
+		// Foodfolio Item Variant Events
+		FoodfolioVariantCreatedEvent{},
+		FoodfolioVariantUpdatedEvent{},
+		FoodfolioVariantDeletedEvent{},
+		FoodfolioVariantStockLowEvent{},
+		FoodfolioVariantStockEmptyEvent{},
+
+		// Foodfolio Item Detail Events
+		FoodfolioDetailCreatedEvent{},
+		FoodfolioDetailOpenedEvent{},
+		FoodfolioDetailExpiredEvent{},
+		FoodfolioDetailExpiringSoonEvent{},
+		FoodfolioDetailConsumedEvent{},
+		FoodfolioDetailMovedEvent{},
+		FoodfolioDetailFrozenEvent{},
+		FoodfolioDetailThawedEvent{},
+
+		// Foodfolio Location Events
+		FoodfolioLocationCreatedEvent{},
+		FoodfolioLocationUpdatedEvent{},
+		FoodfolioLocationDeletedEvent{},
+
+		// Foodfolio Warehouse Events
+		FoodfolioWarehouseCreatedEvent{},
+		FoodfolioWarehouseUpdatedEvent{},
+		FoodfolioWarehouseDeletedEvent{},
+
+		// Foodfolio Receipt Events
+		FoodfolioReceiptCreatedEvent{},
+		FoodfolioReceiptScannedEvent{},
+		FoodfolioReceiptDeletedEvent{},
+
+		// Foodfolio Shopping List Events
+		FoodfolioShoppinglistCreatedEvent{},
+		FoodfolioShoppinglistUpdatedEvent{},
+		FoodfolioShoppinglistDeletedEvent{},
+		FoodfolioShoppinglistItemAddedEvent{},
+		FoodfolioShoppinglistItemRemovedEvent{},
+		FoodfolioShoppinglistItemPurchasedEvent{},
+
+		// Character Events
+		WarcraftCharacterCreatedEvent{},
+		WarcraftCharacterSyncedEvent{},
+		WarcraftCharacterDeletedEvent{},
+		WarcraftCharacterEquipmentUpdatedEvent{},
+		WarcraftCharacterStatsUpdatedEvent{},
+
+		// Guild Events
+		WarcraftGuildCreatedEvent{},
+		WarcraftGuildSyncedEvent{},
+		WarcraftGuildDeletedEvent{},
+
+		// Stream Events
+		TwitchbotStreamStartedEvent{},
+		TwitchbotStreamEndedEvent{},
+		TwitchbotStreamUpdatedEvent{},
+
+		// Message Events
+		TwitchbotMessageReceivedEvent{},
+		TwitchbotMessageDeletedEvent{},
+		TwitchbotMessageTimeoutEvent{},
+
+		// Viewer Events
+		TwitchbotViewerJoinedEvent{},
+		TwitchbotViewerLeftEvent{},
+		TwitchbotViewerBannedEvent{},
+		TwitchbotViewerUnbannedEvent{},
+		TwitchbotViewerModAddedEvent{},
+		TwitchbotViewerModRemovedEvent{},
+		TwitchbotViewerVipAddedEvent{},
+		TwitchbotViewerVipRemovedEvent{},
+
+		// Clip Events
+		TwitchbotClipCreatedEvent{},
+		TwitchbotClipUpdatedEvent{},
+		TwitchbotClipDeletedEvent{},
+
+		// Command Events
+		TwitchbotCommandCreatedEvent{},
+		TwitchbotCommandUpdatedEvent{},
+		TwitchbotCommandDeletedEvent{},
+		TwitchbotCommandExecutedEvent{},
+
+		// Blog Service Events
+		BlogPostScheduledPublishedEvent{},
+	}
+}
+
+// EventSchemas returns the embedded schema registry of the event types
+func EventSchemas() (*schema.Registry, error) {
+	eventSchemasOnce.Do(func() {
+		fsys, err := fs.Sub(schemaFiles, "schemas")
+		if err != nil {
+			eventSchemasErr = err
+			return
+		}
+		eventSchemas, eventSchemasErr = schema.LoadRegistry(fsys)
+	})
+	return eventSchemas, eventSchemasErr
+}
+
+// SchemaSubject returns the schema subject of an event, which is its type name
+func SchemaSubject(event interface{}) string {
+	t := reflect.TypeOf(event)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
+
+// validateEvent validates an encoded event against the latest schema of its subject
+// It returns the dataschema URI, or an empty URI for events without a registered schema
+func validateEvent(registry *schema.Registry, subject string, data json.RawMessage) (string, error) {
+	s, version, err := registry.Latest(subject)
+	if errors.Is(err, schema.ErrUnknownSubject) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := s.Validate(data); err != nil {
+		return "", fmt.Errorf("event %s: %w", subject, err)
+	}
+	return schema.URI(subject, version), nil
+}
@@ -0,0 +1,72 @@
+package kafka
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka/schema"
+)
+
+// TestEventSchemasUpToDate fails the build when an event struct changed without
+// registering its schema, or changed in a way that breaks consumers
+func TestEventSchemasUpToDate(t *testing.T) {
+	registry, err := EventSchemas()
+	if err != nil {
+		t.Fatalf("Failed to load event schemas: %v", err)
+	}
+
+	for _, event := range EventTypes() {
+		subject := SchemaSubject(event)
+		generated := schema.Generate(event)
+
+		latest, version, err := registry.Latest(subject)
+		if err != nil {
+			t.Errorf("%s has no registered schema, run go generate ./kafka", subject)
+			continue
+		}
+		if schema.Equal(latest, generated) {
+			continue
+		}
+
+		if changes := schema.BreakingChanges(latest, generated); len(changes) > 0 {
+			t.Errorf("%s breaks consumers of version %d: %s", subject, version, strings.Join(changes, "; "))
+		} else {
+			t.Errorf("%s changed since version %d, run go generate ./kafka", subject, version)
+		}
+	}
+}
+
+func TestValidateEvent(t *testing.T) {
+	registry, err := EventSchemas()
+	if err != nil {
+		t.Fatalf("Failed to load event schemas: %v", err)
+	}
+
+	valid, _ := json.Marshal(TagDeletedEvent{TagID: "tag-1"})
+
+	tests := []struct {
+		name      string
+		subject   string
+		data      string
+		expectURI string
+		expectErr bool
+	}{
+		{name: "valid payload", subject: "TagDeletedEvent", data: string(valid), expectURI: "urn:toxictoast:schema:TagDeletedEvent:1"},
+		{name: "missing required field", subject: "TagDeletedEvent", data: `{"tag_id":"tag-1"}`, expectErr: true},
+		{name: "wrong type", subject: "TagDeletedEvent", data: `{"tag_id":1,"deleted_at":"2024-01-01T00:00:00Z"}`, expectErr: true},
+		{name: "unregistered type", subject: "LocalEvent", data: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := validateEvent(registry, tt.subject, []byte(tt.data))
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+			if uri != tt.expectURI {
+				t.Errorf("Expected dataschema %q, got %q", tt.expectURI, uri)
+			}
+		})
+	}
+}
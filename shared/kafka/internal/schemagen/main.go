@@ -0,0 +1,70 @@
+// Command schemagen generates the JSON Schemas of the Kafka event types into the
+// versioned schema registry directory. A changed event type is registered as a new
+// version if it is compatible with the previous one; breaking changes fail
+//
+//	go generate ./kafka
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
+	"github.com/toxictoast/toxictoastgo/shared/kafka/schema"
+)
+
+func main() {
+	dir := flag.String("dir", "schemas", "schema registry directory")
+	flag.Parse()
+
+	registry, err := schema.LoadFileRegistry(*dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		registry = schema.NewRegistry()
+	} else if err != nil {
+		log.Fatalf("Failed to load schema registry: %v", err)
+	}
+
+	failed := false
+	for _, event := range kafka.EventTypes() {
+		subject := kafka.SchemaSubject(event)
+		_, previous, _ := registry.Latest(subject)
+
+		version, err := registry.Register(subject, schema.Generate(event))
+		if err != nil {
+			log.Printf("%v", err)
+			failed = true
+			continue
+		}
+		if version == previous {
+			continue
+		}
+
+		s, _ := registry.Get(subject, version)
+		if err := writeSchema(*dir, subject, version, s); err != nil {
+			log.Fatalf("Failed to write schema %s version %d: %v", subject, version, err)
+		}
+		log.Printf("Registered %s version %d", subject, version)
+	}
+
+	if failed {
+		log.Fatalf("Breaking schema changes found; publish breaking changes as a new event type instead")
+	}
+}
+
+// writeSchema writes a schema version into the registry directory
+func writeSchema(dir, subject string, version int, s *schema.Schema) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, subject), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, subject, schema.FileName(version)), append(data, '\n'), 0o644)
+}
@@ -0,0 +1,122 @@
+// Package kafkatest provides assertions on messages recorded by a kafka.MemoryBroker
+package kafkatest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
+)
+
+// pollInterval is how often WaitForMessage checks the broker
+const pollInterval = 10 * time.Millisecond
+
+// MessageMatcher selects messages; a nil matcher matches every message
+type MessageMatcher func(msg *kafka.Message) bool
+
+// AssertPublished fails the test unless a matching message was published to the topic
+// It returns the first matching message
+func AssertPublished(t testing.TB, broker *kafka.MemoryBroker, topic string, match MessageMatcher) *kafka.Message {
+	t.Helper()
+
+	if msg := findMessage(broker, topic, match); msg != nil {
+		return msg
+	}
+	t.Fatalf("Expected a matching message on topic %s, got %d messages", topic, len(broker.Messages(topic)))
+	return nil
+}
+
+// AssertNotPublished fails the test if a matching message was published to the topic
+func AssertNotPublished(t testing.TB, broker *kafka.MemoryBroker, topic string, match MessageMatcher) {
+	t.Helper()
+
+	if msg := findMessage(broker, topic, match); msg != nil {
+		t.Fatalf("Expected no matching message on topic %s, got one with key %s", topic, msg.Key)
+	}
+}
+
+// AssertPublishedCount fails the test unless exactly count messages were published to the topic
+func AssertPublishedCount(t testing.TB, broker *kafka.MemoryBroker, topic string, count int) {
+	t.Helper()
+
+	if got := len(broker.Messages(topic)); got != count {
+		t.Fatalf("Expected %d messages on topic %s, got %d", count, topic, got)
+	}
+}
+
+// WaitForMessage waits until a matching message was published to the topic
+// Use it for publishers running in the background, e.g. an outbox relay
+func WaitForMessage(t testing.TB, broker *kafka.MemoryBroker, topic string, timeout time.Duration, match MessageMatcher) *kafka.Message {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if msg := findMessage(broker, topic, match); msg != nil {
+			return msg
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a matching message on topic %s within %v", topic, timeout)
+			return nil
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// AssertCloudEvent fails the test unless the message is a valid CloudEvent
+func AssertCloudEvent(t testing.TB, msg *kafka.Message) *kafka.CloudEvent {
+	t.Helper()
+
+	event, err := msg.CloudEvent()
+	if err != nil {
+		t.Fatalf("Expected a CloudEvent on topic %s, got %v", msg.Topic, err)
+	}
+	return event
+}
+
+// DecodeEvent decodes the event data of a message into T
+// CloudEvents are unwrapped, other messages are decoded as they are
+func DecodeEvent[T any](t testing.TB, msg *kafka.Message) *T {
+	t.Helper()
+
+	data := msg.Value
+	event, err := msg.CloudEvent()
+	switch {
+	case err == nil:
+		data = event.Data
+	case !errors.Is(err, kafka.ErrNotCloudEvent):
+		t.Fatalf("Failed to decode CloudEvent on topic %s: %v", msg.Topic, err)
+	}
+
+	var payload T
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Failed to decode event on topic %s: %v", msg.Topic, err)
+	}
+	return &payload
+}
+
+// AssertEvent fails the test unless an event matching the predicate was published to the topic
+// It returns the first matching event decoded into T
+func AssertEvent[T any](t testing.TB, broker *kafka.MemoryBroker, topic string, match func(event *T) bool) *T {
+	t.Helper()
+
+	for _, msg := range broker.Messages(topic) {
+		payload := DecodeEvent[T](t, msg)
+		if match == nil || match(payload) {
+			return payload
+		}
+	}
+	t.Fatalf("Expected a matching event on topic %s, got %d messages", topic, len(broker.Messages(topic)))
+	return nil
+}
+
+// findMessage returns the first matching message of a topic, or nil
+func findMessage(broker *kafka.MemoryBroker, topic string, match MessageMatcher) *kafka.Message {
+	for _, msg := range broker.Messages(topic) {
+		if match == nil || match(msg) {
+			return msg
+		}
+	}
+	return nil
+}
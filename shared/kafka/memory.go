@@ -0,0 +1,157 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBroker is an in-memory Publisher intended for tests
+// Published messages are encoded like the Producer encodes them, recorded per topic
+// and delivered synchronously to the handlers subscribed to their topic
+type MemoryBroker struct {
+	eventHelpers
+
+	opts ProducerOptions
+
+	mu          sync.Mutex
+	messages    []*Message
+	offsets     map[string]int64
+	subscribers map[string][]Handler
+	errs        []error
+}
+
+// NewMemoryBroker creates an in-memory broker with the default producer options
+func NewMemoryBroker() *MemoryBroker {
+	return NewMemoryBrokerWithOptions(ProducerOptions{})
+}
+
+// NewMemoryBrokerWithOptions creates an in-memory broker publishing with the given options
+func NewMemoryBrokerWithOptions(opts ProducerOptions) *MemoryBroker {
+	// The embedded schema registry is part of the binary, loading it can't fail at runtime
+	opts, _ = opts.withDefaults()
+
+	b := &MemoryBroker{
+		opts:        opts,
+		offsets:     make(map[string]int64),
+		subscribers: make(map[string][]Handler),
+	}
+	b.eventHelpers = eventHelpers{publisher: b}
+
+	return b
+}
+
+// PublishEvent publishes an event as a CloudEvent
+func (b *MemoryBroker) PublishEvent(topic string, key string, event interface{}) error {
+	return b.PublishEventContext(context.Background(), topic, key, event)
+}
+
+// PublishEventContext publishes an event as a CloudEvent carrying the context's correlation and causation IDs
+func (b *MemoryBroker) PublishEventContext(ctx context.Context, topic string, key string, event interface{}) error {
+	cloudEvent, err := b.opts.newCloudEvent(ctx, topic, key, event)
+	if err != nil {
+		return err
+	}
+	return b.PublishCloudEvent(topic, key, cloudEvent)
+}
+
+// PublishCloudEvent publishes a CloudEvent
+func (b *MemoryBroker) PublishCloudEvent(topic string, key string, event *CloudEvent) error {
+	value, headers, err := b.opts.encode(event)
+	if err != nil {
+		return err
+	}
+	return b.PublishMessage(topic, key, value, headers)
+}
+
+// PublishMessage records a message and delivers it to the topic's subscribers
+// Handler errors don't fail the publish, they are collected for HandlerErrors
+func (b *MemoryBroker) PublishMessage(topic string, key string, payload []byte, headers map[string]string) error {
+	headers = b.opts.withSourceHeader(headers)
+
+	b.mu.Lock()
+	msg := &Message{
+		Topic:     topic,
+		Offset:    b.offsets[topic],
+		Key:       key,
+		Value:     append([]byte(nil), payload...),
+		Headers:   make(map[string]string, len(headers)),
+		Timestamp: time.Now().UTC(),
+	}
+	for k, v := range headers {
+		msg.Headers[k] = v
+	}
+	b.offsets[topic]++
+	b.messages = append(b.messages, msg)
+	handlers := append([]Handler(nil), b.subscribers[topic]...)
+	b.mu.Unlock()
+
+	// Deliver outside the lock, handlers may publish follow-up messages
+	for _, handler := range handlers {
+		if err := handler.Handle(ExtractTraceContext(context.Background(), msg.Headers), copyMessage(msg)); err != nil {
+			b.mu.Lock()
+			b.errs = append(b.errs, err)
+			b.mu.Unlock()
+		}
+	}
+
+	return nil
+}
+
+// Close is a no-op; recorded messages stay available
+func (b *MemoryBroker) Close() error {
+	return nil
+}
+
+// Subscribe delivers every message published to the topics from now on to the handler
+func (b *MemoryBroker) Subscribe(handler Handler, topics ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		b.subscribers[topic] = append(b.subscribers[topic], handler)
+	}
+}
+
+// Messages returns the messages published to a topic in publish order, or all messages for an empty topic
+func (b *MemoryBroker) Messages(topic string) []*Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var messages []*Message
+	for _, msg := range b.messages {
+		if topic == "" || msg.Topic == topic {
+			messages = append(messages, copyMessage(msg))
+		}
+	}
+	return messages
+}
+
+// HandlerErrors returns the errors subscribers returned so far
+func (b *MemoryBroker) HandlerErrors() []error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]error(nil), b.errs...)
+}
+
+// Reset forgets all recorded messages and handler errors; subscriptions are kept
+func (b *MemoryBroker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.messages = nil
+	b.offsets = make(map[string]int64)
+	b.errs = nil
+}
+
+// copyMessage copies a message, so handlers and tests can't modify the recorded one
+func copyMessage(msg *Message) *Message {
+	c := *msg
+	c.Value = append([]byte(nil), msg.Value...)
+	c.Headers = make(map[string]string, len(msg.Headers))
+	for k, v := range msg.Headers {
+		c.Headers[k] = v
+	}
+	return &c
+}
@@ -0,0 +1,79 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
+	"github.com/toxictoast/toxictoastgo/shared/kafka/kafkatest"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       kafka.ContentMode
+		handlerErr error
+	}{
+		{name: "binary mode", mode: kafka.ContentModeBinary},
+		{name: "structured mode", mode: kafka.ContentModeStructured},
+		{name: "handler error", mode: kafka.ContentModeBinary, handlerErr: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := kafka.NewMemoryBrokerWithOptions(kafka.ProducerOptions{Source: "link-service", Mode: tt.mode})
+
+			var received []string
+			broker.Subscribe(kafka.HandlerFunc(func(ctx context.Context, msg *kafka.Message) error {
+				event := kafkatest.DecodeEvent[kafka.LinkCreatedEvent](t, msg)
+				received = append(received, event.LinkID)
+				return tt.handlerErr
+			}), "link.created")
+
+			var publisher kafka.Publisher = broker
+			err := publisher.PublishLinkCreated("link.created", kafka.LinkCreatedEvent{
+				LinkID:      "link-1",
+				OriginalURL: "https://example.com",
+				ShortCode:   "abc",
+				IsActive:    true,
+				CreatedAt:   time.Now(),
+			})
+			if err != nil {
+				t.Fatalf("PublishLinkCreated failed: %v", err)
+			}
+
+			kafkatest.AssertPublishedCount(t, broker, "link.created", 1)
+			kafkatest.AssertNotPublished(t, broker, "link.deleted", nil)
+
+			msg := kafkatest.AssertPublished(t, broker, "link.created", func(msg *kafka.Message) bool {
+				return msg.Key == "link-1"
+			})
+			cloudEvent := kafkatest.AssertCloudEvent(t, msg)
+			if cloudEvent.Source != "link-service" {
+				t.Errorf("Expected source link-service, got %s", cloudEvent.Source)
+			}
+			if cloudEvent.DataSchema == "" {
+				t.Error("Expected dataschema to be set")
+			}
+
+			event := kafkatest.AssertEvent(t, broker, "link.created", func(event *kafka.LinkCreatedEvent) bool {
+				return event.ShortCode == "abc"
+			})
+			if event.OriginalURL != "https://example.com" {
+				t.Errorf("Expected original URL https://example.com, got %s", event.OriginalURL)
+			}
+
+			if len(received) != 1 || received[0] != "link-1" {
+				t.Errorf("Expected subscriber to receive link-1, got %v", received)
+			}
+			if got := len(broker.HandlerErrors()); (tt.handlerErr != nil) != (got == 1) {
+				t.Errorf("Expected handler error %v, got %d errors", tt.handlerErr, got)
+			}
+
+			broker.Reset()
+			kafkatest.AssertPublishedCount(t, broker, "link.created", 0)
+		})
+	}
+}
@@ -1,20 +1,63 @@
 package kafka
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/IBM/sarama"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka/schema"
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
 )
 
+// defaultEventSource is the CloudEvents source used when ProducerOptions.Source is not set
+const defaultEventSource = "toxictoast"
+
+// ProducerOptions configures a Producer
+type ProducerOptions struct {
+	// Source is the CloudEvents source of published events, usually the service name
+	Source string
+
+	// Mode selects binary (default) or structured CloudEvents messages
+	Mode ContentMode
+
+	// Schemas validates payloads of registered event types before sending and sets their
+	// dataschema; defaults to the embedded registry of EventTypes
+	Schemas *schema.Registry
+
+	// Async enables the batching async mode next to the sync producer, see Producer.Async
+	Async AsyncOptions
+
+	// Metrics enables queue depth and delivery error metrics of the async mode when set
+	Metrics *metrics.Metrics
+}
+
+// Producer publishes events to Kafka brokers
+// Publish methods wait for the brokers to acknowledge the message; call sites on the
+// hot path can publish through Async instead when the async mode is enabled
 type Producer struct {
+	eventHelpers
+
 	producer sarama.SyncProducer
+	async    *AsyncPublisher
 	brokers  []string
+	opts     ProducerOptions
 }
 
 func NewProducer(brokers []string) (*Producer, error) {
+	return NewProducerWithOptions(brokers, ProducerOptions{})
+}
+
+// NewProducerWithOptions creates a producer publishing CloudEvents with the given options
+func NewProducerWithOptions(brokers []string, opts ProducerOptions) (*Producer, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
 	saramaConfig.Producer.Retry.Max = 5
@@ -27,42 +70,79 @@ func NewProducer(brokers []string) (*Producer, error) {
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
-	log.Printf("Kafka producer connected to brokers: %v", brokers)
-
-	return &Producer{
+	p := &Producer{
 		producer: producer,
 		brokers:  brokers,
-	}, nil
+		opts:     opts,
+	}
+	p.eventHelpers = eventHelpers{publisher: p}
+
+	if opts.Async.Enabled {
+		asyncProducer, err := sarama.NewAsyncProducer(brokers, opts.Async.saramaConfig())
+		if err != nil {
+			producer.Close()
+			return nil, fmt.Errorf("failed to create async Kafka producer: %w", err)
+		}
+		p.async = newAsyncPublisher(asyncProducer, opts)
+	}
+
+	log.Printf("Kafka producer connected to brokers: %v", brokers)
+
+	return p, nil
+}
+
+// Async returns the async publisher, or the producer itself when the async mode is disabled
+func (p *Producer) Async() Publisher {
+	if p.async != nil {
+		return p.async
+	}
+	return p
 }
 
+// Close flushes the async publisher and closes the producer
 func (p *Producer) Close() error {
+	var errs []error
+	if p.async != nil {
+		errs = append(errs, p.async.Close())
+	}
 	if p.producer != nil {
-		return p.producer.Close()
+		errs = append(errs, p.producer.Close())
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
-// PublishEvent publishes an event to a Kafka topic
+// PublishEvent publishes an event to a Kafka topic as a CloudEvent
+// The topic is used as event type and the key as subject
 func (p *Producer) PublishEvent(topic string, key string, event interface{}) error {
-	eventData, err := json.Marshal(event)
+	return p.PublishEventContext(context.Background(), topic, key, event)
+}
+
+// PublishEventContext publishes an event as a CloudEvent carrying the context's correlation and causation IDs
+// The send is traced as a producer span, which consumers continue
+func (p *Producer) PublishEventContext(ctx context.Context, topic string, key string, event interface{}) (err error) {
+	ctx, span := startPublishSpan(ctx, topic, key)
+	defer func() { endSpan(span, err) }()
+
+	cloudEvent, err := p.opts.newCloudEvent(ctx, topic, key, event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return err
+	}
+	return p.PublishCloudEvent(topic, key, cloudEvent)
+}
+
+// PublishCloudEvent publishes a CloudEvent to a Kafka topic
+// The source is filled from the producer options when not set
+func (p *Producer) PublishCloudEvent(topic string, key string, event *CloudEvent) error {
+	value, headers, err := p.opts.encode(event)
+	if err != nil {
+		return err
 	}
 
 	msg := &sarama.ProducerMessage{
-		Topic: topic,
-		Key:   sarama.StringEncoder(key),
-		Value: sarama.ByteEncoder(eventData),
-		Headers: []sarama.RecordHeader{
-			{
-				Key:   []byte("event_type"),
-				Value: []byte(topic),
-			},
-			{
-				Key:   []byte("timestamp"),
-				Value: []byte(time.Now().Format(time.RFC3339)),
-			},
-		},
+		Topic:   topic,
+		Key:     sarama.StringEncoder(key),
+		Value:   sarama.ByteEncoder(value),
+		Headers: recordHeaders(topic, headers),
 	}
 
 	partition, offset, err := p.producer.SendMessage(msg)
@@ -70,424 +150,110 @@ func (p *Producer) PublishEvent(topic string, key string, event interface{}) err
 		return fmt.Errorf("failed to send message to Kafka: %w", err)
 	}
 
-	log.Printf("Event published to topic %s (partition: %d, offset: %d)", topic, partition, offset)
+	log.Printf("Event %s published to topic %s (partition: %d, offset: %d)", event.ID, topic, partition, offset)
 	return nil
 }
 
-// Helper methods for common event publishing patterns
-// Services should pass their configured topic names
-
-// PublishCategoryCreated publishes a category created event
-func (p *Producer) PublishCategoryCreated(topic string, event CategoryCreatedEvent) error {
-	return p.PublishEvent(topic, event.CategoryID, event)
-}
-
-// PublishCategoryUpdated publishes a category updated event
-func (p *Producer) PublishCategoryUpdated(topic string, event CategoryUpdatedEvent) error {
-	return p.PublishEvent(topic, event.CategoryID, event)
-}
-
-// PublishCategoryDeleted publishes a category deleted event
-func (p *Producer) PublishCategoryDeleted(topic string, event CategoryDeletedEvent) error {
-	return p.PublishEvent(topic, event.CategoryID, event)
-}
-
-// PublishPostCreated publishes a post created event
-func (p *Producer) PublishPostCreated(topic string, event PostCreatedEvent) error {
-	return p.PublishEvent(topic, event.PostID, event)
-}
-
-// PublishPostUpdated publishes a post updated event
-func (p *Producer) PublishPostUpdated(topic string, event PostUpdatedEvent) error {
-	return p.PublishEvent(topic, event.PostID, event)
-}
-
-// PublishPostPublished publishes a post published event
-func (p *Producer) PublishPostPublished(topic string, event PostPublishedEvent) error {
-	return p.PublishEvent(topic, event.PostID, event)
-}
-
-// PublishPostDeleted publishes a post deleted event
-func (p *Producer) PublishPostDeleted(topic string, event PostDeletedEvent) error {
-	return p.PublishEvent(topic, event.PostID, event)
-}
-
-// PublishTagCreated publishes a tag created event
-func (p *Producer) PublishTagCreated(topic string, event TagCreatedEvent) error {
-	return p.PublishEvent(topic, event.TagID, event)
-}
+// PublishMessage publishes an already encoded payload with custom headers
+// Used by relays that forward stored messages verbatim, e.g. the transactional outbox
+// Binary mode CloudEvents headers without a source get the producer's source
+func (p *Producer) PublishMessage(topic string, key string, payload []byte, headers map[string]string) error {
+	headers = p.opts.withSourceHeader(headers)
 
-// PublishTagUpdated publishes a tag updated event
-func (p *Producer) PublishTagUpdated(topic string, event TagUpdatedEvent) error {
-	return p.PublishEvent(topic, event.TagID, event)
-}
-
-// PublishTagDeleted publishes a tag deleted event
-func (p *Producer) PublishTagDeleted(topic string, event TagDeletedEvent) error {
-	return p.PublishEvent(topic, event.TagID, event)
-}
-
-// PublishCommentCreated publishes a comment created event
-func (p *Producer) PublishCommentCreated(topic string, event CommentCreatedEvent) error {
-	return p.PublishEvent(topic, event.CommentID, event)
-}
-
-// PublishCommentModerated publishes a comment moderated event
-func (p *Producer) PublishCommentModerated(topic string, event CommentModeratedEvent) error {
-	return p.PublishEvent(topic, event.CommentID, event)
-}
-
-// PublishCommentDeleted publishes a comment deleted event
-func (p *Producer) PublishCommentDeleted(topic string, event CommentDeletedEvent) error {
-	return p.PublishEvent(topic, event.CommentID, event)
-}
-
-// PublishCommentApproved publishes a comment approved event
-func (p *Producer) PublishCommentApproved(topic string, event CommentApprovedEvent) error {
-	return p.PublishEvent(topic, event.CommentID, event)
-}
-
-// PublishCommentRejected publishes a comment rejected event
-func (p *Producer) PublishCommentRejected(topic string, event CommentRejectedEvent) error {
-	return p.PublishEvent(topic, event.CommentID, event)
-}
-
-// PublishMediaUploaded publishes a media uploaded event
-func (p *Producer) PublishMediaUploaded(topic string, event MediaUploadedEvent) error {
-	return p.PublishEvent(topic, event.MediaID, event)
-}
-
-// PublishMediaDeleted publishes a media deleted event
-func (p *Producer) PublishMediaDeleted(topic string, event MediaDeletedEvent) error {
-	return p.PublishEvent(topic, event.MediaID, event)
-}
-
-// PublishMediaThumbnailGenerated publishes a media thumbnail generated event
-func (p *Producer) PublishMediaThumbnailGenerated(topic string, event MediaThumbnailGeneratedEvent) error {
-	return p.PublishEvent(topic, event.MediaID, event)
-}
-
-// PublishLinkCreated publishes a link created event
-func (p *Producer) PublishLinkCreated(topic string, event LinkCreatedEvent) error {
-	return p.PublishEvent(topic, event.LinkID, event)
-}
-
-// PublishLinkUpdated publishes a link updated event
-func (p *Producer) PublishLinkUpdated(topic string, event LinkUpdatedEvent) error {
-	return p.PublishEvent(topic, event.LinkID, event)
-}
-
-// PublishLinkDeleted publishes a link deleted event
-func (p *Producer) PublishLinkDeleted(topic string, event LinkDeletedEvent) error {
-	return p.PublishEvent(topic, event.LinkID, event)
-}
-
-// PublishLinkActivated publishes a link activated event
-func (p *Producer) PublishLinkActivated(topic string, event LinkActivatedEvent) error {
-	return p.PublishEvent(topic, event.LinkID, event)
-}
-
-// PublishLinkDeactivated publishes a link deactivated event
-func (p *Producer) PublishLinkDeactivated(topic string, event LinkDeactivatedEvent) error {
-	return p.PublishEvent(topic, event.LinkID, event)
-}
-
-// PublishLinkExpired publishes a link expired event
-func (p *Producer) PublishLinkExpired(topic string, event LinkExpiredEvent) error {
-	return p.PublishEvent(topic, event.LinkID, event)
-}
-
-// PublishLinkClicked publishes a link clicked event
-func (p *Producer) PublishLinkClicked(topic string, event LinkClickedEvent) error {
-	return p.PublishEvent(topic, event.ClickID, event)
-}
-
-// PublishLinkClickFraudDetected publishes a link click fraud detected event
-func (p *Producer) PublishLinkClickFraudDetected(topic string, event LinkClickFraudDetectedEvent) error {
-	return p.PublishEvent(topic, event.ClickID, event)
-}
-
-// Foodfolio Category Event Publishers
-func (p *Producer) PublishFoodfolioCategoryCreated(topic string, event FoodfolioCategoryCreatedEvent) error {
-	return p.PublishEvent(topic, event.CategoryID, event)
-}
-
-func (p *Producer) PublishFoodfolioCategoryUpdated(topic string, event FoodfolioCategoryUpdatedEvent) error {
-	return p.PublishEvent(topic, event.CategoryID, event)
-}
-
-func (p *Producer) PublishFoodfolioCategoryDeleted(topic string, event FoodfolioCategoryDeletedEvent) error {
-	return p.PublishEvent(topic, event.CategoryID, event)
-}
-
-// Foodfolio Company Event Publishers
-func (p *Producer) PublishFoodfolioCompanyCreated(topic string, event FoodfolioCompanyCreatedEvent) error {
-	return p.PublishEvent(topic, event.CompanyID, event)
-}
-
-func (p *Producer) PublishFoodfolioCompanyUpdated(topic string, event FoodfolioCompanyUpdatedEvent) error {
-	return p.PublishEvent(topic, event.CompanyID, event)
-}
-
-func (p *Producer) PublishFoodfolioCompanyDeleted(topic string, event FoodfolioCompanyDeletedEvent) error {
-	return p.PublishEvent(topic, event.CompanyID, event)
-}
-
-// Foodfolio Item Event Publishers
-func (p *Producer) PublishFoodfolioItemCreated(topic string, event FoodfolioItemCreatedEvent) error {
-	return p.PublishEvent(topic, event.ItemID, event)
-}
-
-func (p *Producer) PublishFoodfolioItemUpdated(topic string, event FoodfolioItemUpdatedEvent) error {
-	return p.PublishEvent(topic, event.ItemID, event)
-}
-
-func (p *Producer) PublishFoodfolioItemDeleted(topic string, event FoodfolioItemDeletedEvent) error {
-	return p.PublishEvent(topic, event.ItemID, event)
-}
-
-// Foodfolio Item Variant Event Publishers
-func (p *Producer) PublishFoodfolioVariantCreated(topic string, event FoodfolioVariantCreatedEvent) error {
-	return p.PublishEvent(topic, event.VariantID, event)
-}
-
-func (p *Producer) PublishFoodfolioVariantUpdated(topic string, event FoodfolioVariantUpdatedEvent) error {
-	return p.PublishEvent(topic, event.VariantID, event)
-}
-
-func (p *Producer) PublishFoodfolioVariantDeleted(topic string, event FoodfolioVariantDeletedEvent) error {
-	return p.PublishEvent(topic, event.VariantID, event)
-}
-
-func (p *Producer) PublishFoodfolioVariantStockLow(topic string, event FoodfolioVariantStockLowEvent) error {
-	return p.PublishEvent(topic, event.VariantID, event)
-}
-
-func (p *Producer) PublishFoodfolioVariantStockEmpty(topic string, event FoodfolioVariantStockEmptyEvent) error {
-	return p.PublishEvent(topic, event.VariantID, event)
-}
-
-// Foodfolio Item Detail Event Publishers
-func (p *Producer) PublishFoodfolioDetailCreated(topic string, event FoodfolioDetailCreatedEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-func (p *Producer) PublishFoodfolioDetailOpened(topic string, event FoodfolioDetailOpenedEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-func (p *Producer) PublishFoodfolioDetailExpired(topic string, event FoodfolioDetailExpiredEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-func (p *Producer) PublishFoodfolioDetailExpiringSoon(topic string, event FoodfolioDetailExpiringSoonEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-func (p *Producer) PublishFoodfolioDetailConsumed(topic string, event FoodfolioDetailConsumedEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-func (p *Producer) PublishFoodfolioDetailMoved(topic string, event FoodfolioDetailMovedEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-func (p *Producer) PublishFoodfolioDetailFrozen(topic string, event FoodfolioDetailFrozenEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-func (p *Producer) PublishFoodfolioDetailThawed(topic string, event FoodfolioDetailThawedEvent) error {
-	return p.PublishEvent(topic, event.DetailID, event)
-}
-
-// Foodfolio Location Event Publishers
-func (p *Producer) PublishFoodfolioLocationCreated(topic string, event FoodfolioLocationCreatedEvent) error {
-	return p.PublishEvent(topic, event.LocationID, event)
-}
-
-func (p *Producer) PublishFoodfolioLocationUpdated(topic string, event FoodfolioLocationUpdatedEvent) error {
-	return p.PublishEvent(topic, event.LocationID, event)
-}
-
-func (p *Producer) PublishFoodfolioLocationDeleted(topic string, event FoodfolioLocationDeletedEvent) error {
-	return p.PublishEvent(topic, event.LocationID, event)
-}
-
-// Foodfolio Warehouse Event Publishers
-func (p *Producer) PublishFoodfolioWarehouseCreated(topic string, event FoodfolioWarehouseCreatedEvent) error {
-	return p.PublishEvent(topic, event.WarehouseID, event)
-}
-
-func (p *Producer) PublishFoodfolioWarehouseUpdated(topic string, event FoodfolioWarehouseUpdatedEvent) error {
-	return p.PublishEvent(topic, event.WarehouseID, event)
-}
-
-func (p *Producer) PublishFoodfolioWarehouseDeleted(topic string, event FoodfolioWarehouseDeletedEvent) error {
-	return p.PublishEvent(topic, event.WarehouseID, event)
-}
-
-// Foodfolio Receipt Event Publishers
-func (p *Producer) PublishFoodfolioReceiptCreated(topic string, event FoodfolioReceiptCreatedEvent) error {
-	return p.PublishEvent(topic, event.ReceiptID, event)
-}
-
-func (p *Producer) PublishFoodfolioReceiptScanned(topic string, event FoodfolioReceiptScannedEvent) error {
-	return p.PublishEvent(topic, event.ReceiptID, event)
-}
-
-func (p *Producer) PublishFoodfolioReceiptDeleted(topic string, event FoodfolioReceiptDeletedEvent) error {
-	return p.PublishEvent(topic, event.ReceiptID, event)
-}
-
-// Foodfolio Shopping List Event Publishers
-func (p *Producer) PublishFoodfolioShoppinglistCreated(topic string, event FoodfolioShoppinglistCreatedEvent) error {
-	return p.PublishEvent(topic, event.ShoppinglistID, event)
-}
-
-func (p *Producer) PublishFoodfolioShoppinglistUpdated(topic string, event FoodfolioShoppinglistUpdatedEvent) error {
-	return p.PublishEvent(topic, event.ShoppinglistID, event)
-}
-
-func (p *Producer) PublishFoodfolioShoppinglistDeleted(topic string, event FoodfolioShoppinglistDeletedEvent) error {
-	return p.PublishEvent(topic, event.ShoppinglistID, event)
-}
-
-func (p *Producer) PublishFoodfolioShoppinglistItemAdded(topic string, event FoodfolioShoppinglistItemAddedEvent) error {
-	return p.PublishEvent(topic, event.ItemID, event)
-}
-
-func (p *Producer) PublishFoodfolioShoppinglistItemRemoved(topic string, event FoodfolioShoppinglistItemRemovedEvent) error {
-	return p.PublishEvent(topic, event.ItemID, event)
-}
-
-func (p *Producer) PublishFoodfolioShoppinglistItemPurchased(topic string, event FoodfolioShoppinglistItemPurchasedEvent) error {
-	return p.PublishEvent(topic, event.ItemID, event)
-}
-
-// Warcraft Character Event Publishers
-func (p *Producer) PublishWarcraftCharacterCreated(topic string, event WarcraftCharacterCreatedEvent) error {
-	return p.PublishEvent(topic, event.CharacterID, event)
-}
-
-func (p *Producer) PublishWarcraftCharacterSynced(topic string, event WarcraftCharacterSyncedEvent) error {
-	return p.PublishEvent(topic, event.CharacterID, event)
-}
-
-func (p *Producer) PublishWarcraftCharacterDeleted(topic string, event WarcraftCharacterDeletedEvent) error {
-	return p.PublishEvent(topic, event.CharacterID, event)
-}
-
-func (p *Producer) PublishWarcraftCharacterEquipmentUpdated(topic string, event WarcraftCharacterEquipmentUpdatedEvent) error {
-	return p.PublishEvent(topic, event.CharacterID, event)
-}
-
-func (p *Producer) PublishWarcraftCharacterStatsUpdated(topic string, event WarcraftCharacterStatsUpdatedEvent) error {
-	return p.PublishEvent(topic, event.CharacterID, event)
-}
-
-// Warcraft Guild Event Publishers
-func (p *Producer) PublishWarcraftGuildCreated(topic string, event WarcraftGuildCreatedEvent) error {
-	return p.PublishEvent(topic, event.GuildID, event)
-}
-
-func (p *Producer) PublishWarcraftGuildSynced(topic string, event WarcraftGuildSyncedEvent) error {
-	return p.PublishEvent(topic, event.GuildID, event)
-}
-
-func (p *Producer) PublishWarcraftGuildDeleted(topic string, event WarcraftGuildDeletedEvent) error {
-	return p.PublishEvent(topic, event.GuildID, event)
-}
-
-// Twitchbot Stream Event Publishers
-func (p *Producer) PublishTwitchbotStreamStarted(topic string, event TwitchbotStreamStartedEvent) error {
-	return p.PublishEvent(topic, event.StreamID, event)
-}
-
-func (p *Producer) PublishTwitchbotStreamEnded(topic string, event TwitchbotStreamEndedEvent) error {
-	return p.PublishEvent(topic, event.StreamID, event)
-}
-
-func (p *Producer) PublishTwitchbotStreamUpdated(topic string, event TwitchbotStreamUpdatedEvent) error {
-	return p.PublishEvent(topic, event.StreamID, event)
-}
-
-// Twitchbot Message Event Publishers
-func (p *Producer) PublishTwitchbotMessageReceived(topic string, event TwitchbotMessageReceivedEvent) error {
-	return p.PublishEvent(topic, event.MessageID, event)
-}
-
-func (p *Producer) PublishTwitchbotMessageDeleted(topic string, event TwitchbotMessageDeletedEvent) error {
-	return p.PublishEvent(topic, event.MessageID, event)
-}
-
-func (p *Producer) PublishTwitchbotMessageTimeout(topic string, event TwitchbotMessageTimeoutEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
-}
-
-// Twitchbot Viewer Event Publishers
-func (p *Producer) PublishTwitchbotViewerJoined(topic string, event TwitchbotViewerJoinedEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
-}
-
-func (p *Producer) PublishTwitchbotViewerLeft(topic string, event TwitchbotViewerLeftEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
-}
-
-func (p *Producer) PublishTwitchbotViewerBanned(topic string, event TwitchbotViewerBannedEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
-}
-
-func (p *Producer) PublishTwitchbotViewerUnbanned(topic string, event TwitchbotViewerUnbannedEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
-}
-
-func (p *Producer) PublishTwitchbotViewerModAdded(topic string, event TwitchbotViewerModAddedEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
-}
+	msg := &sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.StringEncoder(key),
+		Value:   sarama.ByteEncoder(payload),
+		Headers: recordHeaders(topic, headers),
+	}
 
-func (p *Producer) PublishTwitchbotViewerModRemoved(topic string, event TwitchbotViewerModRemovedEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
-}
+	partition, offset, err := p.producer.SendMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to send message to Kafka: %w", err)
+	}
 
-func (p *Producer) PublishTwitchbotViewerVipAdded(topic string, event TwitchbotViewerVipAddedEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
+	log.Printf("Message published to topic %s (partition: %d, offset: %d)", topic, partition, offset)
+	return nil
 }
 
-func (p *Producer) PublishTwitchbotViewerVipRemoved(topic string, event TwitchbotViewerVipRemovedEvent) error {
-	return p.PublishEvent(topic, event.UserID, event)
+// recordHeaders converts headers to Kafka record headers
+// The legacy event_type and timestamp headers are kept for consumers not reading CloudEvents yet
+func recordHeaders(topic string, headers map[string]string) []sarama.RecordHeader {
+	records := []sarama.RecordHeader{
+		{
+			Key:   []byte("event_type"),
+			Value: []byte(topic),
+		},
+		{
+			Key:   []byte("timestamp"),
+			Value: []byte(time.Now().Format(time.RFC3339)),
+		},
+	}
+	for k, v := range headers {
+		records = append(records, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	return records
 }
 
-// Twitchbot Clip Event Publishers
-func (p *Producer) PublishTwitchbotClipCreated(topic string, event TwitchbotClipCreatedEvent) error {
-	return p.PublishEvent(topic, event.ClipID, event)
+// withDefaults applies the default source, content mode and schema registry
+func (o ProducerOptions) withDefaults() (ProducerOptions, error) {
+	if o.Source == "" {
+		o.Source = defaultEventSource
+	}
+	if o.Mode == "" {
+		o.Mode = ContentModeBinary
+	}
+	if o.Schemas == nil {
+		registry, err := EventSchemas()
+		if err != nil {
+			return o, fmt.Errorf("failed to load event schemas: %w", err)
+		}
+		o.Schemas = registry
+	}
+	if o.Async.Enabled {
+		o.Async = o.Async.withDefaults()
+	}
+	return o, nil
 }
 
-func (p *Producer) PublishTwitchbotClipUpdated(topic string, event TwitchbotClipUpdatedEvent) error {
-	return p.PublishEvent(topic, event.ClipID, event)
-}
+// newCloudEvent wraps an event in a CloudEvent after validating it against its schema
+func (o ProducerOptions) newCloudEvent(ctx context.Context, topic string, key string, event interface{}) (*CloudEvent, error) {
+	cloudEvent, err := NewCloudEvent(o.Source, topic, key, event)
+	if err != nil {
+		return nil, err
+	}
 
-func (p *Producer) PublishTwitchbotClipDeleted(topic string, event TwitchbotClipDeletedEvent) error {
-	return p.PublishEvent(topic, event.ClipID, event)
-}
+	// Refuse payloads that would break consumers of the event type
+	if cloudEvent.DataSchema, err = validateEvent(o.Schemas, SchemaSubject(event), cloudEvent.Data); err != nil {
+		return nil, err
+	}
 
-// Twitchbot Command Event Publishers
-func (p *Producer) PublishTwitchbotCommandCreated(topic string, event TwitchbotCommandCreatedEvent) error {
-	return p.PublishEvent(topic, event.CommandID, event)
+	return cloudEvent.WithContext(ctx), nil
 }
 
-func (p *Producer) PublishTwitchbotCommandUpdated(topic string, event TwitchbotCommandUpdatedEvent) error {
-	return p.PublishEvent(topic, event.CommandID, event)
-}
+// encode encodes a CloudEvent in the configured content mode, filling in the source
+func (o ProducerOptions) encode(event *CloudEvent) ([]byte, map[string]string, error) {
+	if event.Source == "" {
+		event.Source = o.Source
+	}
 
-func (p *Producer) PublishTwitchbotCommandDeleted(topic string, event TwitchbotCommandDeletedEvent) error {
-	return p.PublishEvent(topic, event.CommandID, event)
+	value, headers, err := event.Encode(o.Mode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return value, headers, nil
 }
 
-func (p *Producer) PublishTwitchbotCommandExecuted(topic string, event TwitchbotCommandExecutedEvent) error {
-	return p.PublishEvent(topic, event.CommandID, event)
-}
+// withSourceHeader sets the source of binary mode CloudEvents headers without one
+func (o ProducerOptions) withSourceHeader(headers map[string]string) map[string]string {
+	if _, ok := headers[HeaderCloudEventSpecVersion]; !ok || headers[HeaderCloudEventSource] != "" {
+		return headers
+	}
 
-// Blog Service Publishers
-func (p *Producer) PublishBlogPostScheduledPublished(topic string, event BlogPostScheduledPublishedEvent) error {
-	return p.PublishEvent(topic, event.PostID, event)
+	withSource := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		withSource[k] = v
+	}
+	withSource[HeaderCloudEventSource] = o.Source
+	return withSource
 }
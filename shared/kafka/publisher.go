@@ -0,0 +1,574 @@
+package kafka
+
+import "context"
+
+// Publisher publishes events; implemented by Producer and MemoryBroker
+// Services depend on Publisher, so handlers can be tested against a MemoryBroker
+type Publisher interface {
+	// PublishEvent publishes an event to a topic as a CloudEvent
+	PublishEvent(topic string, key string, event interface{}) error
+
+	// PublishEventContext publishes an event carrying the context's correlation and causation IDs
+	PublishEventContext(ctx context.Context, topic string, key string, event interface{}) error
+
+	// PublishCloudEvent publishes a CloudEvent to a topic
+	PublishCloudEvent(topic string, key string, event *CloudEvent) error
+
+	// PublishMessage publishes an already encoded payload with custom headers
+	PublishMessage(topic string, key string, payload []byte, headers map[string]string) error
+
+	// Close releases the publisher's resources
+	Close() error
+
+	// Typed helpers for the events in events.go
+	// Services should pass their configured topic names
+	PublishCategoryCreated(topic string, event CategoryCreatedEvent) error
+	PublishCategoryUpdated(topic string, event CategoryUpdatedEvent) error
+	PublishCategoryDeleted(topic string, event CategoryDeletedEvent) error
+	PublishPostCreated(topic string, event PostCreatedEvent) error
+	PublishPostUpdated(topic string, event PostUpdatedEvent) error
+	PublishPostPublished(topic string, event PostPublishedEvent) error
+	PublishPostDeleted(topic string, event PostDeletedEvent) error
+	PublishTagCreated(topic string, event TagCreatedEvent) error
+	PublishTagUpdated(topic string, event TagUpdatedEvent) error
+	PublishTagDeleted(topic string, event TagDeletedEvent) error
+	PublishCommentCreated(topic string, event CommentCreatedEvent) error
+	PublishCommentModerated(topic string, event CommentModeratedEvent) error
+	PublishCommentDeleted(topic string, event CommentDeletedEvent) error
+	PublishCommentApproved(topic string, event CommentApprovedEvent) error
+	PublishCommentRejected(topic string, event CommentRejectedEvent) error
+	PublishMediaUploaded(topic string, event MediaUploadedEvent) error
+	PublishMediaDeleted(topic string, event MediaDeletedEvent) error
+	PublishMediaThumbnailGenerated(topic string, event MediaThumbnailGeneratedEvent) error
+	PublishLinkCreated(topic string, event LinkCreatedEvent) error
+	PublishLinkUpdated(topic string, event LinkUpdatedEvent) error
+	PublishLinkDeleted(topic string, event LinkDeletedEvent) error
+	PublishLinkActivated(topic string, event LinkActivatedEvent) error
+	PublishLinkDeactivated(topic string, event LinkDeactivatedEvent) error
+	PublishLinkExpired(topic string, event LinkExpiredEvent) error
+	PublishLinkClicked(topic string, event LinkClickedEvent) error
+	PublishLinkClickFraudDetected(topic string, event LinkClickFraudDetectedEvent) error
+
+	// Foodfolio Category Event Publishers
+	PublishFoodfolioCategoryCreated(topic string, event FoodfolioCategoryCreatedEvent) error
+	PublishFoodfolioCategoryUpdated(topic string, event FoodfolioCategoryUpdatedEvent) error
+	PublishFoodfolioCategoryDeleted(topic string, event FoodfolioCategoryDeletedEvent) error
+
+	// Foodfolio Company Event Publishers
+	PublishFoodfolioCompanyCreated(topic string, event FoodfolioCompanyCreatedEvent) error
+	PublishFoodfolioCompanyUpdated(topic string, event FoodfolioCompanyUpdatedEvent) error
+	PublishFoodfolioCompanyDeleted(topic string, event FoodfolioCompanyDeletedEvent) error
+
+	// Foodfolio Item Event Publishers
+	PublishFoodfolioItemCreated(topic string, event FoodfolioItemCreatedEvent) error
+	PublishFoodfolioItemUpdated(topic string, event FoodfolioItemUpdatedEvent) error
+	PublishFoodfolioItemDeleted(topic string, event FoodfolioItemDeletedEvent) error
+
+	// Foodfolio Item Variant Event Publishers
+	PublishFoodfolioVariantCreated(topic string, event FoodfolioVariantCreatedEvent) error
+	PublishFoodfolioVariantUpdated(topic string, event FoodfolioVariantUpdatedEvent) error
+	PublishFoodfolioVariantDeleted(topic string, event FoodfolioVariantDeletedEvent) error
+	PublishFoodfolioVariantStockLow(topic string, event FoodfolioVariantStockLowEvent) error
+	PublishFoodfolioVariantStockEmpty(topic string, event FoodfolioVariantStockEmptyEvent) error
+
+	// Foodfolio Item Detail Event Publishers
+	PublishFoodfolioDetailCreated(topic string, event FoodfolioDetailCreatedEvent) error
+	PublishFoodfolioDetailOpened(topic string, event FoodfolioDetailOpenedEvent) error
+	PublishFoodfolioDetailExpired(topic string, event FoodfolioDetailExpiredEvent) error
+	PublishFoodfolioDetailExpiringSoon(topic string, event FoodfolioDetailExpiringSoonEvent) error
+	PublishFoodfolioDetailConsumed(topic string, event FoodfolioDetailConsumedEvent) error
+	PublishFoodfolioDetailMoved(topic string, event FoodfolioDetailMovedEvent) error
+	PublishFoodfolioDetailFrozen(topic string, event FoodfolioDetailFrozenEvent) error
+	PublishFoodfolioDetailThawed(topic string, event FoodfolioDetailThawedEvent) error
+
+	// Foodfolio Location Event Publishers
+	PublishFoodfolioLocationCreated(topic string, event FoodfolioLocationCreatedEvent) error
+	PublishFoodfolioLocationUpdated(topic string, event FoodfolioLocationUpdatedEvent) error
+	PublishFoodfolioLocationDeleted(topic string, event FoodfolioLocationDeletedEvent) error
+
+	// Foodfolio Warehouse Event Publishers
+	PublishFoodfolioWarehouseCreated(topic string, event FoodfolioWarehouseCreatedEvent) error
+	PublishFoodfolioWarehouseUpdated(topic string, event FoodfolioWarehouseUpdatedEvent) error
+	PublishFoodfolioWarehouseDeleted(topic string, event FoodfolioWarehouseDeletedEvent) error
+
+	// Foodfolio Receipt Event Publishers
+	PublishFoodfolioReceiptCreated(topic string, event FoodfolioReceiptCreatedEvent) error
+	PublishFoodfolioReceiptScanned(topic string, event FoodfolioReceiptScannedEvent) error
+	PublishFoodfolioReceiptDeleted(topic string, event FoodfolioReceiptDeletedEvent) error
+
+	// Foodfolio Shopping List Event Publishers
+	PublishFoodfolioShoppinglistCreated(topic string, event FoodfolioShoppinglistCreatedEvent) error
+	PublishFoodfolioShoppinglistUpdated(topic string, event FoodfolioShoppinglistUpdatedEvent) error
+	PublishFoodfolioShoppinglistDeleted(topic string, event FoodfolioShoppinglistDeletedEvent) error
+	PublishFoodfolioShoppinglistItemAdded(topic string, event FoodfolioShoppinglistItemAddedEvent) error
+	PublishFoodfolioShoppinglistItemRemoved(topic string, event FoodfolioShoppinglistItemRemovedEvent) error
+	PublishFoodfolioShoppinglistItemPurchased(topic string, event FoodfolioShoppinglistItemPurchasedEvent) error
+
+	// Warcraft Character Event Publishers
+	PublishWarcraftCharacterCreated(topic string, event WarcraftCharacterCreatedEvent) error
+	PublishWarcraftCharacterSynced(topic string, event WarcraftCharacterSyncedEvent) error
+	PublishWarcraftCharacterDeleted(topic string, event WarcraftCharacterDeletedEvent) error
+	PublishWarcraftCharacterEquipmentUpdated(topic string, event WarcraftCharacterEquipmentUpdatedEvent) error
+	PublishWarcraftCharacterStatsUpdated(topic string, event WarcraftCharacterStatsUpdatedEvent) error
+
+	// Warcraft Guild Event Publishers
+	PublishWarcraftGuildCreated(topic string, event WarcraftGuildCreatedEvent) error
+	PublishWarcraftGuildSynced(topic string, event WarcraftGuildSyncedEvent) error
+	PublishWarcraftGuildDeleted(topic string, event WarcraftGuildDeletedEvent) error
+
+	// Twitchbot Stream Event Publishers
+	PublishTwitchbotStreamStarted(topic string, event TwitchbotStreamStartedEvent) error
+	PublishTwitchbotStreamEnded(topic string, event TwitchbotStreamEndedEvent) error
+	PublishTwitchbotStreamUpdated(topic string, event TwitchbotStreamUpdatedEvent) error
+
+	// Twitchbot Message Event Publishers
+	PublishTwitchbotMessageReceived(topic string, event TwitchbotMessageReceivedEvent) error
+	PublishTwitchbotMessageDeleted(topic string, event TwitchbotMessageDeletedEvent) error
+	PublishTwitchbotMessageTimeout(topic string, event TwitchbotMessageTimeoutEvent) error
+
+	// Twitchbot Viewer Event Publishers
+	PublishTwitchbotViewerJoined(topic string, event TwitchbotViewerJoinedEvent) error
+	PublishTwitchbotViewerLeft(topic string, event TwitchbotViewerLeftEvent) error
+	PublishTwitchbotViewerBanned(topic string, event TwitchbotViewerBannedEvent) error
+	PublishTwitchbotViewerUnbanned(topic string, event TwitchbotViewerUnbannedEvent) error
+	PublishTwitchbotViewerModAdded(topic string, event TwitchbotViewerModAddedEvent) error
+	PublishTwitchbotViewerModRemoved(topic string, event TwitchbotViewerModRemovedEvent) error
+	PublishTwitchbotViewerVipAdded(topic string, event TwitchbotViewerVipAddedEvent) error
+	PublishTwitchbotViewerVipRemoved(topic string, event TwitchbotViewerVipRemovedEvent) error
+
+	// Twitchbot Clip Event Publishers
+	PublishTwitchbotClipCreated(topic string, event TwitchbotClipCreatedEvent) error
+	PublishTwitchbotClipUpdated(topic string, event TwitchbotClipUpdatedEvent) error
+	PublishTwitchbotClipDeleted(topic string, event TwitchbotClipDeletedEvent) error
+
+	// Twitchbot Command Event Publishers
+	PublishTwitchbotCommandCreated(topic string, event TwitchbotCommandCreatedEvent) error
+	PublishTwitchbotCommandUpdated(topic string, event TwitchbotCommandUpdatedEvent) error
+	PublishTwitchbotCommandDeleted(topic string, event TwitchbotCommandDeletedEvent) error
+	PublishTwitchbotCommandExecuted(topic string, event TwitchbotCommandExecutedEvent) error
+
+	// Blog Service Publishers
+	PublishBlogPostScheduledPublished(topic string, event BlogPostScheduledPublishedEvent) error
+}
+
+// eventHelpers implements the typed publish helpers on top of PublishEvent
+type eventHelpers struct {
+	publisher interface {
+		PublishEvent(topic string, key string, event interface{}) error
+	}
+}
+
+// PublishCategoryCreated publishes a category created event
+func (h eventHelpers) PublishCategoryCreated(topic string, event CategoryCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CategoryID, event)
+}
+
+// PublishCategoryUpdated publishes a category updated event
+func (h eventHelpers) PublishCategoryUpdated(topic string, event CategoryUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CategoryID, event)
+}
+
+// PublishCategoryDeleted publishes a category deleted event
+func (h eventHelpers) PublishCategoryDeleted(topic string, event CategoryDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CategoryID, event)
+}
+
+// PublishPostCreated publishes a post created event
+func (h eventHelpers) PublishPostCreated(topic string, event PostCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.PostID, event)
+}
+
+// PublishPostUpdated publishes a post updated event
+func (h eventHelpers) PublishPostUpdated(topic string, event PostUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.PostID, event)
+}
+
+// PublishPostPublished publishes a post published event
+func (h eventHelpers) PublishPostPublished(topic string, event PostPublishedEvent) error {
+	return h.publisher.PublishEvent(topic, event.PostID, event)
+}
+
+// PublishPostDeleted publishes a post deleted event
+func (h eventHelpers) PublishPostDeleted(topic string, event PostDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.PostID, event)
+}
+
+// PublishTagCreated publishes a tag created event
+func (h eventHelpers) PublishTagCreated(topic string, event TagCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.TagID, event)
+}
+
+// PublishTagUpdated publishes a tag updated event
+func (h eventHelpers) PublishTagUpdated(topic string, event TagUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.TagID, event)
+}
+
+// PublishTagDeleted publishes a tag deleted event
+func (h eventHelpers) PublishTagDeleted(topic string, event TagDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.TagID, event)
+}
+
+// PublishCommentCreated publishes a comment created event
+func (h eventHelpers) PublishCommentCreated(topic string, event CommentCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommentID, event)
+}
+
+// PublishCommentModerated publishes a comment moderated event
+func (h eventHelpers) PublishCommentModerated(topic string, event CommentModeratedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommentID, event)
+}
+
+// PublishCommentDeleted publishes a comment deleted event
+func (h eventHelpers) PublishCommentDeleted(topic string, event CommentDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommentID, event)
+}
+
+// PublishCommentApproved publishes a comment approved event
+func (h eventHelpers) PublishCommentApproved(topic string, event CommentApprovedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommentID, event)
+}
+
+// PublishCommentRejected publishes a comment rejected event
+func (h eventHelpers) PublishCommentRejected(topic string, event CommentRejectedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommentID, event)
+}
+
+// PublishMediaUploaded publishes a media uploaded event
+func (h eventHelpers) PublishMediaUploaded(topic string, event MediaUploadedEvent) error {
+	return h.publisher.PublishEvent(topic, event.MediaID, event)
+}
+
+// PublishMediaDeleted publishes a media deleted event
+func (h eventHelpers) PublishMediaDeleted(topic string, event MediaDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.MediaID, event)
+}
+
+// PublishMediaThumbnailGenerated publishes a media thumbnail generated event
+func (h eventHelpers) PublishMediaThumbnailGenerated(topic string, event MediaThumbnailGeneratedEvent) error {
+	return h.publisher.PublishEvent(topic, event.MediaID, event)
+}
+
+// PublishLinkCreated publishes a link created event
+func (h eventHelpers) PublishLinkCreated(topic string, event LinkCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LinkID, event)
+}
+
+// PublishLinkUpdated publishes a link updated event
+func (h eventHelpers) PublishLinkUpdated(topic string, event LinkUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LinkID, event)
+}
+
+// PublishLinkDeleted publishes a link deleted event
+func (h eventHelpers) PublishLinkDeleted(topic string, event LinkDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LinkID, event)
+}
+
+// PublishLinkActivated publishes a link activated event
+func (h eventHelpers) PublishLinkActivated(topic string, event LinkActivatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LinkID, event)
+}
+
+// PublishLinkDeactivated publishes a link deactivated event
+func (h eventHelpers) PublishLinkDeactivated(topic string, event LinkDeactivatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LinkID, event)
+}
+
+// PublishLinkExpired publishes a link expired event
+func (h eventHelpers) PublishLinkExpired(topic string, event LinkExpiredEvent) error {
+	return h.publisher.PublishEvent(topic, event.LinkID, event)
+}
+
+// PublishLinkClicked publishes a link clicked event
+func (h eventHelpers) PublishLinkClicked(topic string, event LinkClickedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ClickID, event)
+}
+
+// PublishLinkClickFraudDetected publishes a link click fraud detected event
+func (h eventHelpers) PublishLinkClickFraudDetected(topic string, event LinkClickFraudDetectedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ClickID, event)
+}
+
+// Foodfolio Category Event Publishers
+func (h eventHelpers) PublishFoodfolioCategoryCreated(topic string, event FoodfolioCategoryCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CategoryID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioCategoryUpdated(topic string, event FoodfolioCategoryUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CategoryID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioCategoryDeleted(topic string, event FoodfolioCategoryDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CategoryID, event)
+}
+
+// Foodfolio Company Event Publishers
+func (h eventHelpers) PublishFoodfolioCompanyCreated(topic string, event FoodfolioCompanyCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CompanyID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioCompanyUpdated(topic string, event FoodfolioCompanyUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CompanyID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioCompanyDeleted(topic string, event FoodfolioCompanyDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CompanyID, event)
+}
+
+// Foodfolio Item Event Publishers
+func (h eventHelpers) PublishFoodfolioItemCreated(topic string, event FoodfolioItemCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ItemID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioItemUpdated(topic string, event FoodfolioItemUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ItemID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioItemDeleted(topic string, event FoodfolioItemDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ItemID, event)
+}
+
+// Foodfolio Item Variant Event Publishers
+func (h eventHelpers) PublishFoodfolioVariantCreated(topic string, event FoodfolioVariantCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.VariantID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioVariantUpdated(topic string, event FoodfolioVariantUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.VariantID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioVariantDeleted(topic string, event FoodfolioVariantDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.VariantID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioVariantStockLow(topic string, event FoodfolioVariantStockLowEvent) error {
+	return h.publisher.PublishEvent(topic, event.VariantID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioVariantStockEmpty(topic string, event FoodfolioVariantStockEmptyEvent) error {
+	return h.publisher.PublishEvent(topic, event.VariantID, event)
+}
+
+// Foodfolio Item Detail Event Publishers
+func (h eventHelpers) PublishFoodfolioDetailCreated(topic string, event FoodfolioDetailCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioDetailOpened(topic string, event FoodfolioDetailOpenedEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioDetailExpired(topic string, event FoodfolioDetailExpiredEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioDetailExpiringSoon(topic string, event FoodfolioDetailExpiringSoonEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioDetailConsumed(topic string, event FoodfolioDetailConsumedEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioDetailMoved(topic string, event FoodfolioDetailMovedEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioDetailFrozen(topic string, event FoodfolioDetailFrozenEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioDetailThawed(topic string, event FoodfolioDetailThawedEvent) error {
+	return h.publisher.PublishEvent(topic, event.DetailID, event)
+}
+
+// Foodfolio Location Event Publishers
+func (h eventHelpers) PublishFoodfolioLocationCreated(topic string, event FoodfolioLocationCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LocationID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioLocationUpdated(topic string, event FoodfolioLocationUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LocationID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioLocationDeleted(topic string, event FoodfolioLocationDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.LocationID, event)
+}
+
+// Foodfolio Warehouse Event Publishers
+func (h eventHelpers) PublishFoodfolioWarehouseCreated(topic string, event FoodfolioWarehouseCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.WarehouseID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioWarehouseUpdated(topic string, event FoodfolioWarehouseUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.WarehouseID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioWarehouseDeleted(topic string, event FoodfolioWarehouseDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.WarehouseID, event)
+}
+
+// Foodfolio Receipt Event Publishers
+func (h eventHelpers) PublishFoodfolioReceiptCreated(topic string, event FoodfolioReceiptCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ReceiptID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioReceiptScanned(topic string, event FoodfolioReceiptScannedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ReceiptID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioReceiptDeleted(topic string, event FoodfolioReceiptDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ReceiptID, event)
+}
+
+// Foodfolio Shopping List Event Publishers
+func (h eventHelpers) PublishFoodfolioShoppinglistCreated(topic string, event FoodfolioShoppinglistCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ShoppinglistID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioShoppinglistUpdated(topic string, event FoodfolioShoppinglistUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ShoppinglistID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioShoppinglistDeleted(topic string, event FoodfolioShoppinglistDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ShoppinglistID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioShoppinglistItemAdded(topic string, event FoodfolioShoppinglistItemAddedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ItemID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioShoppinglistItemRemoved(topic string, event FoodfolioShoppinglistItemRemovedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ItemID, event)
+}
+
+func (h eventHelpers) PublishFoodfolioShoppinglistItemPurchased(topic string, event FoodfolioShoppinglistItemPurchasedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ItemID, event)
+}
+
+// Warcraft Character Event Publishers
+func (h eventHelpers) PublishWarcraftCharacterCreated(topic string, event WarcraftCharacterCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CharacterID, event)
+}
+
+func (h eventHelpers) PublishWarcraftCharacterSynced(topic string, event WarcraftCharacterSyncedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CharacterID, event)
+}
+
+func (h eventHelpers) PublishWarcraftCharacterDeleted(topic string, event WarcraftCharacterDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CharacterID, event)
+}
+
+func (h eventHelpers) PublishWarcraftCharacterEquipmentUpdated(topic string, event WarcraftCharacterEquipmentUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CharacterID, event)
+}
+
+func (h eventHelpers) PublishWarcraftCharacterStatsUpdated(topic string, event WarcraftCharacterStatsUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CharacterID, event)
+}
+
+// Warcraft Guild Event Publishers
+func (h eventHelpers) PublishWarcraftGuildCreated(topic string, event WarcraftGuildCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.GuildID, event)
+}
+
+func (h eventHelpers) PublishWarcraftGuildSynced(topic string, event WarcraftGuildSyncedEvent) error {
+	return h.publisher.PublishEvent(topic, event.GuildID, event)
+}
+
+func (h eventHelpers) PublishWarcraftGuildDeleted(topic string, event WarcraftGuildDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.GuildID, event)
+}
+
+// Twitchbot Stream Event Publishers
+func (h eventHelpers) PublishTwitchbotStreamStarted(topic string, event TwitchbotStreamStartedEvent) error {
+	return h.publisher.PublishEvent(topic, event.StreamID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotStreamEnded(topic string, event TwitchbotStreamEndedEvent) error {
+	return h.publisher.PublishEvent(topic, event.StreamID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotStreamUpdated(topic string, event TwitchbotStreamUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.StreamID, event)
+}
+
+// Twitchbot Message Event Publishers
+func (h eventHelpers) PublishTwitchbotMessageReceived(topic string, event TwitchbotMessageReceivedEvent) error {
+	return h.publisher.PublishEvent(topic, event.MessageID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotMessageDeleted(topic string, event TwitchbotMessageDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.MessageID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotMessageTimeout(topic string, event TwitchbotMessageTimeoutEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+// Twitchbot Viewer Event Publishers
+func (h eventHelpers) PublishTwitchbotViewerJoined(topic string, event TwitchbotViewerJoinedEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotViewerLeft(topic string, event TwitchbotViewerLeftEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotViewerBanned(topic string, event TwitchbotViewerBannedEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotViewerUnbanned(topic string, event TwitchbotViewerUnbannedEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotViewerModAdded(topic string, event TwitchbotViewerModAddedEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotViewerModRemoved(topic string, event TwitchbotViewerModRemovedEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotViewerVipAdded(topic string, event TwitchbotViewerVipAddedEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotViewerVipRemoved(topic string, event TwitchbotViewerVipRemovedEvent) error {
+	return h.publisher.PublishEvent(topic, event.UserID, event)
+}
+
+// Twitchbot Clip Event Publishers
+func (h eventHelpers) PublishTwitchbotClipCreated(topic string, event TwitchbotClipCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ClipID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotClipUpdated(topic string, event TwitchbotClipUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ClipID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotClipDeleted(topic string, event TwitchbotClipDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.ClipID, event)
+}
+
+// Twitchbot Command Event Publishers
+func (h eventHelpers) PublishTwitchbotCommandCreated(topic string, event TwitchbotCommandCreatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommandID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotCommandUpdated(topic string, event TwitchbotCommandUpdatedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommandID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotCommandDeleted(topic string, event TwitchbotCommandDeletedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommandID, event)
+}
+
+func (h eventHelpers) PublishTwitchbotCommandExecuted(topic string, event TwitchbotCommandExecutedEvent) error {
+	return h.publisher.PublishEvent(topic, event.CommandID, event)
+}
+
+// Blog Service Publishers
+func (h eventHelpers) PublishBlogPostScheduledPublished(topic string, event BlogPostScheduledPublishedEvent) error {
+	return h.publisher.PublishEvent(topic, event.PostID, event)
+}
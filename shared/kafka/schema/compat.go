@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Equal reports whether two schemas describe the same payload
+func Equal(a, b *Schema) bool {
+	return reflect.DeepEqual(comparable(a), comparable(b))
+}
+
+// comparable returns a copy without annotations
+func comparable(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Draft = ""
+	c.Title = ""
+	return &c
+}
+
+// BreakingChanges lists the changes of next that break consumers written against previous
+//
+// Payloads are open content, so adding properties is compatible. Removing a property,
+// required or optional, changing its type, making a required property optional or
+// allowing null where it wasn't allowed before is breaking
+func BreakingChanges(previous, next *Schema) []string {
+	var changes []string
+	breakingChanges("$", previous, next, &changes)
+	return changes
+}
+
+func breakingChanges(path string, previous, next *Schema, changes *[]string) {
+	if len(previous.Type) > 0 {
+		if len(next.Type) == 0 {
+			*changes = append(*changes, fmt.Sprintf("%s: type constraint %v removed", path, previous.Type))
+		}
+		for _, jsonType := range next.Type {
+			if !previous.Type.has(jsonType) && !(jsonType == "integer" && previous.Type.has("number")) {
+				*changes = append(*changes, fmt.Sprintf("%s: type %s not allowed before", path, jsonType))
+			}
+		}
+	}
+
+	if previous.Format != "" && next.Format != previous.Format {
+		*changes = append(*changes, fmt.Sprintf("%s: format changed from %q to %q", path, previous.Format, next.Format))
+	}
+
+	required := make(map[string]bool, len(next.Required))
+	for _, name := range next.Required {
+		required[name] = true
+	}
+	for _, name := range previous.Required {
+		if !required[name] {
+			*changes = append(*changes, fmt.Sprintf("%s: required property %q removed or made optional", path, name))
+		}
+	}
+
+	names := make([]string, 0, len(previous.Properties))
+	for name := range previous.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := next.Properties[name]
+		if !ok {
+			// Consumers may still read an optional property, so dropping it breaks them too
+			*changes = append(*changes, fmt.Sprintf("%s: property %q removed", path, name))
+			continue
+		}
+		breakingChanges(path+"."+name, previous.Properties[name], property, changes)
+	}
+
+	if previous.Items != nil {
+		if next.Items == nil {
+			*changes = append(*changes, fmt.Sprintf("%s: item schema removed", path))
+		} else {
+			breakingChanges(path+"[]", previous.Items, next.Items, changes)
+		}
+	}
+
+	if previous.AdditionalProperties != nil {
+		if next.AdditionalProperties == nil {
+			*changes = append(*changes, fmt.Sprintf("%s: value schema removed", path))
+		} else {
+			breakingChanges(path+".*", previous.AdditionalProperties, next.AdditionalProperties, changes)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// uriPrefix prefixes the dataschema URIs of registered schemas
+const uriPrefix = "urn:toxictoast:schema:"
+
+var (
+	// ErrUnknownSubject is returned when no schema is registered for a subject
+	ErrUnknownSubject = errors.New("unknown schema subject")
+
+	// ErrInvalidURI is returned when a dataschema URI doesn't reference this registry
+	ErrInvalidURI = errors.New("invalid schema URI")
+
+	// ErrIncompatible is returned when registering a schema with breaking changes
+	ErrIncompatible = errors.New("incompatible schema")
+)
+
+// Registry holds the versions of every subject's schema
+//
+// A file registry is a directory with one folder per subject holding the versions
+// as v1.json, v2.json, ...; every version has to be compatible with the previous one
+type Registry struct {
+	mu       sync.RWMutex
+	subjects map[string][]*Schema
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{subjects: make(map[string][]*Schema)}
+}
+
+// LoadRegistry loads a registry from a file system, e.g. an embedded schema directory
+func LoadRegistry(fsys fs.FS) (*Registry, error) {
+	r := NewRegistry()
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema registry: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subject := entry.Name()
+
+		files, err := fs.ReadDir(fsys, subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schemas of %s: %w", subject, err)
+		}
+
+		versions := make(map[int]*Schema)
+		for _, file := range files {
+			version, ok := parseFileName(file.Name())
+			if !ok {
+				continue
+			}
+
+			data, err := fs.ReadFile(fsys, path.Join(subject, file.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read schema %s/%s: %w", subject, file.Name(), err)
+			}
+
+			var s Schema
+			if err := json.Unmarshal(data, &s); err != nil {
+				return nil, fmt.Errorf("failed to parse schema %s/%s: %w", subject, file.Name(), err)
+			}
+			versions[version] = &s
+		}
+
+		for version := 1; version <= len(versions); version++ {
+			s, ok := versions[version]
+			if !ok {
+				return nil, fmt.Errorf("schema %s is missing version %d", subject, version)
+			}
+			r.subjects[subject] = append(r.subjects[subject], s)
+		}
+	}
+
+	return r, nil
+}
+
+// LoadFileRegistry loads a registry from a directory
+func LoadFileRegistry(dir string) (*Registry, error) {
+	return LoadRegistry(os.DirFS(dir))
+}
+
+// Subjects returns the registered subjects in alphabetical order
+func (r *Registry) Subjects() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subjects := make([]string, 0, len(r.subjects))
+	for subject := range r.subjects {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// Latest returns the latest schema of a subject and its version
+func (r *Registry) Latest(subject string) (*Schema, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.subjects[subject]
+	if len(versions) == 0 {
+		return nil, 0, fmt.Errorf("%w: %s", ErrUnknownSubject, subject)
+	}
+	return versions[len(versions)-1], len(versions), nil
+}
+
+// Get returns a version of a subject's schema
+func (r *Registry) Get(subject string, version int) (*Schema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.subjects[subject]
+	if version < 1 || version > len(versions) {
+		return nil, fmt.Errorf("%w: %s version %d", ErrUnknownSubject, subject, version)
+	}
+	return versions[version-1], nil
+}
+
+// Register adds a schema as the next version of a subject
+// It returns the existing version when the schema didn't change, and fails with
+// ErrIncompatible when it breaks consumers of the latest version
+func (r *Registry) Register(subject string, s *Schema) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := r.subjects[subject]
+	if len(versions) > 0 {
+		latest := versions[len(versions)-1]
+		if Equal(latest, s) {
+			return len(versions), nil
+		}
+		if changes := BreakingChanges(latest, s); len(changes) > 0 {
+			return 0, fmt.Errorf("%w: %s: %s", ErrIncompatible, subject, strings.Join(changes, "; "))
+		}
+	}
+
+	r.subjects[subject] = append(versions, s)
+	return len(r.subjects[subject]), nil
+}
+
+// Resolve returns the schema a dataschema URI references
+// Versions newer than the registry knows fall back to the latest known version,
+// which every newer version is compatible with
+func (r *Registry) Resolve(uri string) (*Schema, error) {
+	subject, version, err := ParseURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, latestVersion, err := r.Latest(subject)
+	if err != nil {
+		return nil, err
+	}
+	if version >= latestVersion {
+		return latest, nil
+	}
+	return r.Get(subject, version)
+}
+
+// Validate validates a payload against the latest schema of a subject
+func (r *Registry) Validate(subject string, data []byte) error {
+	s, _, err := r.Latest(subject)
+	if err != nil {
+		return err
+	}
+	return s.Validate(data)
+}
+
+// URI returns the dataschema URI of a subject version
+func URI(subject string, version int) string {
+	return fmt.Sprintf("%s%s:%d", uriPrefix, subject, version)
+}
+
+// ParseURI parses a dataschema URI into subject and version
+func ParseURI(uri string) (string, int, error) {
+	rest, ok := strings.CutPrefix(uri, uriPrefix)
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidURI, uri)
+	}
+
+	subject, rawVersion, ok := strings.Cut(rest, ":")
+	version, err := strconv.Atoi(rawVersion)
+	if !ok || subject == "" || err != nil || version < 1 {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidURI, uri)
+	}
+	return subject, version, nil
+}
+
+// FileName returns the file name of a schema version in a file registry
+func FileName(version int) string {
+	return fmt.Sprintf("v%d.json", version)
+}
+
+// parseFileName parses a schema file name into its version
+func parseFileName(name string) (int, bool) {
+	rawVersion, ok := strings.CutPrefix(strings.TrimSuffix(name, ".json"), "v")
+	if !ok || !strings.HasSuffix(name, ".json") {
+		return 0, false
+	}
+	version, err := strconv.Atoi(rawVersion)
+	return version, err == nil && version > 0
+}
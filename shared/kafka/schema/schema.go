@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema generated from Go structs
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Types is the list of JSON types a value may have; empty allows any value
+// It is encoded as a single string when it has one type
+type Types []string
+
+// MarshalJSON encodes a single type as a string
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON accepts a string or a list of strings
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("invalid schema type: %w", err)
+	}
+	*t = list
+	return nil
+}
+
+// has reports whether the type list allows a JSON type
+func (t Types) has(jsonType string) bool {
+	for _, candidate := range t {
+		if candidate == jsonType {
+			return true
+		}
+	}
+	return false
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generate generates the schema of the JSON encoding of v
+// Fields without omitempty are required; pointers, slices and maps are nullable
+func Generate(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	s := generate(t)
+	s.Draft = Draft
+	s.Title = t.Name()
+	return s
+}
+
+// generate generates the schema of a Go type
+func generate(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := generate(t.Elem())
+		return nullable(s)
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a base64 string
+			return &Schema{Type: Types{"string", "null"}}
+		}
+		return &Schema{Type: Types{"array", "null"}, Items: generate(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: generate(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object", "null"}, AdditionalProperties: generate(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema)}
+		addFields(s, t)
+		sort.Strings(s.Required)
+		return s
+	default:
+		// interface{} and other dynamic values accept anything
+		return &Schema{}
+	}
+}
+
+// addFields adds the exported fields of a struct, flattening embedded structs
+func addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addFields(s, field.Type)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		s.Properties[name] = generate(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// nullable allows null in addition to the schema's types
+func nullable(s *Schema) *Schema {
+	if len(s.Type) == 0 || s.Type.has("null") {
+		return s
+	}
+	s.Type = append(append(Types{}, s.Type...), "null")
+	return s
+}
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type orderV1 struct {
+	OrderID  string    `json:"order_id"`
+	Amount   int       `json:"amount"`
+	Note     *string   `json:"note,omitempty"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+type orderAddedField struct {
+	OrderID  string    `json:"order_id"`
+	Amount   int       `json:"amount"`
+	Note     *string   `json:"note,omitempty"`
+	PlacedAt time.Time `json:"placed_at"`
+	Currency string    `json:"currency"`
+}
+
+type orderRemovedField struct {
+	OrderID  string    `json:"order_id"`
+	Note     *string   `json:"note,omitempty"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+type orderRemovedOptionalField struct {
+	OrderID  string    `json:"order_id"`
+	Amount   int       `json:"amount"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+type orderRetyped struct {
+	OrderID  string    `json:"order_id"`
+	Amount   string    `json:"amount"`
+	Note     *string   `json:"note,omitempty"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+type orderNullable struct {
+	OrderID  string    `json:"order_id"`
+	Amount   *int      `json:"amount"`
+	Note     *string   `json:"note,omitempty"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name          string
+		next          interface{}
+		expectVersion int
+		expectErr     error
+	}{
+		{name: "unchanged schema keeps its version", next: orderV1{}, expectVersion: 1},
+		{name: "added field is compatible", next: orderAddedField{}, expectVersion: 2},
+		{name: "removed required field breaks", next: orderRemovedField{}, expectErr: ErrIncompatible},
+		{name: "removed optional field breaks", next: orderRemovedOptionalField{}, expectErr: ErrIncompatible},
+		{name: "changed type breaks", next: orderRetyped{}, expectErr: ErrIncompatible},
+		{name: "nullable field breaks", next: orderNullable{}, expectErr: ErrIncompatible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			if _, err := registry.Register("order", Generate(orderV1{})); err != nil {
+				t.Fatalf("Register failed: %v", err)
+			}
+
+			version, err := registry.Register("order", Generate(tt.next))
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+			if version != tt.expectVersion {
+				t.Errorf("Expected version %d, got %d", tt.expectVersion, version)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := Generate(orderV1{})
+
+	tests := []struct {
+		name      string
+		data      string
+		expectErr bool
+	}{
+		{name: "valid", data: `{"order_id":"o-1","amount":3,"placed_at":"2024-01-01T10:00:00Z"}`},
+		{name: "optional null", data: `{"order_id":"o-1","amount":3,"note":null,"placed_at":"2024-01-01T10:00:00Z"}`},
+		{name: "unknown fields are allowed", data: `{"order_id":"o-1","amount":3,"placed_at":"2024-01-01T10:00:00Z","extra":true}`},
+		{name: "missing required", data: `{"order_id":"o-1","placed_at":"2024-01-01T10:00:00Z"}`, expectErr: true},
+		{name: "fractional integer", data: `{"order_id":"o-1","amount":1.5,"placed_at":"2024-01-01T10:00:00Z"}`, expectErr: true},
+		{name: "invalid date-time", data: `{"order_id":"o-1","amount":3,"placed_at":"yesterday"}`, expectErr: true},
+		{name: "not an object", data: `[]`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate([]byte(tt.data))
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Expected error %v, got %v", tt.expectErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected ErrValidation, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrValidation is returned when a payload doesn't match its schema
+var ErrValidation = errors.New("schema validation failed")
+
+// Validate validates a JSON document against the schema
+func (s *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w: invalid JSON: %v", ErrValidation, err)
+	}
+
+	var problems []string
+	s.validate("$", value, &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrValidation, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validate collects the problems of a decoded value at path
+func (s *Schema) validate(path string, value interface{}, problems *[]string) {
+	jsonType := typeOf(value)
+	if len(s.Type) > 0 && !s.Type.has(jsonType) && !(jsonType == "integer" && s.Type.has("number")) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), jsonType))
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				*problems = append(*problems, fmt.Sprintf("%s: invalid date-time %q", path, v))
+			}
+		}
+
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*problems = append(*problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				property = s.AdditionalProperties
+			}
+			if property != nil {
+				property.validate(path+"."+name, v[name], problems)
+			}
+		}
+	}
+}
+
+// typeOf returns the JSON type of a value decoded with UseNumber
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
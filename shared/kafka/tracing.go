@@ -0,0 +1,75 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// W3C trace context headers
+// The CloudEvents distributed tracing extension is carried in the native W3C headers,
+// so OpenTelemetry propagators of any consumer can read them
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+var tracer = otel.Tracer("github.com/toxictoast/toxictoastgo/shared/kafka")
+
+// InjectTraceContext writes the trace context of ctx into message headers
+// Headers already carrying a trace context, e.g. of a stored outbox message, are kept
+func InjectTraceContext(ctx context.Context, headers map[string]string) {
+	if _, ok := headers[HeaderTraceParent]; ok {
+		return
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+}
+
+// ExtractTraceContext returns ctx continuing the trace of message headers
+func ExtractTraceContext(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
+
+// startPublishSpan starts a producer span for publishing to topic
+func startPublishSpan(ctx context.Context, topic string, key string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "send "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingKafkaMessageKey(key),
+		),
+	)
+}
+
+// startProcessSpan starts a consumer span continuing the trace of the message's producer
+func startProcessSpan(ctx context.Context, msg *Message, groupID string) (context.Context, trace.Span) {
+	ctx = ExtractTraceContext(ctx, msg.Headers)
+	return tracer.Start(ctx, "process "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(msg.Partition))),
+			semconv.MessagingConsumerGroupName(groupID),
+			semconv.MessagingKafkaOffset(int(msg.Offset)),
+			semconv.MessagingKafkaMessageKey(msg.Key),
+		),
+	)
+}
+
+// endSpan records the outcome of a span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,119 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	provider := sdktrace.NewTracerProvider()
+	defer provider.Shutdown(context.Background())
+
+	tests := []struct {
+		name string
+		mode ContentMode
+	}{
+		{name: "binary mode", mode: ContentModeBinary},
+		{name: "structured mode", mode: ContentModeStructured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+			defer span.End()
+
+			broker := NewMemoryBrokerWithOptions(ProducerOptions{Source: "link-service", Mode: tt.mode})
+
+			var received trace.SpanContext
+			broker.Subscribe(HandlerFunc(func(ctx context.Context, msg *Message) error {
+				received = trace.SpanContextFromContext(ctx)
+				return nil
+			}), "link.created")
+
+			if err := broker.PublishEventContext(ctx, "link.created", "link-1", LinkCreatedEvent{LinkID: "link-1"}); err != nil {
+				t.Fatalf("Publish failed: %v", err)
+			}
+
+			if received.TraceID() != span.SpanContext().TraceID() {
+				t.Errorf("Expected trace ID %s, got %s", span.SpanContext().TraceID(), received.TraceID())
+			}
+			if !received.IsRemote() {
+				t.Error("Expected the subscriber context to continue a remote span")
+			}
+		})
+	}
+}
+
+func TestPublishAndProcessSpans(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	otel.SetTracerProvider(provider)
+
+	// The producer span is carried in the message headers to the consumer span
+	ctx, publish := startPublishSpan(context.Background(), "link.created", "link-1")
+	headers := map[string]string{}
+	InjectTraceContext(ctx, headers)
+	endSpan(publish, nil)
+
+	msg := &Message{Topic: "link.created", Partition: 2, Offset: 42, Key: "link-1", Headers: headers}
+	_, process := startProcessSpan(context.Background(), msg, "link-consumers")
+	endSpan(process, errors.New("handler failed"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	producer, consumer := spans[0], spans[1]
+
+	tests := []struct {
+		span     sdktrace.ReadOnlySpan
+		name     string
+		kind     trace.SpanKind
+		status   codes.Code
+		expected attribute.KeyValue
+	}{
+		{span: producer, name: "send link.created", kind: trace.SpanKindProducer, status: codes.Unset, expected: attribute.String("messaging.kafka.message.key", "link-1")},
+		{span: consumer, name: "process link.created", kind: trace.SpanKindConsumer, status: codes.Error, expected: attribute.String("messaging.consumer.group.name", "link-consumers")},
+	}
+	for _, tt := range tests {
+		if tt.span.Name() != tt.name || tt.span.SpanKind() != tt.kind {
+			t.Errorf("Expected %s span %q, got %s span %q", tt.kind, tt.name, tt.span.SpanKind(), tt.span.Name())
+		}
+		if tt.span.Status().Code != tt.status {
+			t.Errorf("%s: Expected status %v, got %v", tt.name, tt.status, tt.span.Status().Code)
+		}
+		if !hasAttribute(tt.span, tt.expected) {
+			t.Errorf("%s: Expected attribute %s=%s, got %v", tt.name, tt.expected.Key, tt.expected.Value.Emit(), tt.span.Attributes())
+		}
+	}
+
+	if consumer.Parent().SpanID() != producer.SpanContext().SpanID() || !consumer.Parent().IsRemote() {
+		t.Errorf("Expected consumer span to continue the remote producer span %s, got parent %s", producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+	}
+}
+
+// hasAttribute reports whether a span carries the attribute
+func hasAttribute(span sdktrace.ReadOnlySpan, expected attribute.KeyValue) bool {
+	for _, attr := range span.Attributes() {
+		if attr == expected {
+			return true
+		}
+	}
+	return false
+}
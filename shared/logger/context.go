@@ -0,0 +1,57 @@
+package logger
+
+import "context"
+
+// contextKey is a custom type for context keys
+type contextKey string
+
+// fieldsKey is the context key of the correlation fields
+const fieldsKey contextKey = "log_fields"
+
+// fields are the correlation fields added to every record logged with a context
+type fields struct {
+	service   string
+	requestID string
+	userID    string
+}
+
+// fieldsFrom returns the correlation fields of ctx
+func fieldsFrom(ctx context.Context) fields {
+	if ctx == nil {
+		return fields{}
+	}
+	f, _ := ctx.Value(fieldsKey).(fields)
+	return f
+}
+
+// WithRequestID returns a context whose log records carry the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.requestID = requestID
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// WithUserID returns a context whose log records carry the user ID
+func WithUserID(ctx context.Context, userID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.userID = userID
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// WithService returns a context whose log records carry another service name than the
+// one the logger was set up with, e.g. for work done on behalf of another service
+func WithService(ctx context.Context, service string) context.Context {
+	f := fieldsFrom(ctx)
+	f.service = service
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// RequestIDFromContext returns the request ID stored with WithRequestID
+func RequestIDFromContext(ctx context.Context) string {
+	return fieldsFrom(ctx).requestID
+}
+
+// UserIDFromContext returns the user ID stored with WithUserID
+func UserIDFromContext(ctx context.Context) string {
+	return fieldsFrom(ctx).userID
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
+)
+
+// redacted replaces the values of sensitive attributes
+const redacted = "[REDACTED]"
+
+// DefaultRedactKeys are redacted wherever they appear in an attribute key, case insensitive
+var DefaultRedactKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"api_key",
+	"apikey",
+	"private_key",
+	"cookie",
+	"credential",
+}
+
+// Secret is a string that is never logged, e.g. a webhook secret passed under an innocent key
+type Secret string
+
+// LogValue hides the secret from slog
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// String hides the secret from fmt and log.Printf
+func (s Secret) String() string {
+	return redacted
+}
+
+// contextHandler adds the service and the correlation fields of the context to every
+// record, redacts sensitive attributes and filters records by component level
+type contextHandler struct {
+	next       slog.Handler
+	service    string
+	component  string
+	levels     *Levels
+	redactKeys []string
+}
+
+// Enabled reports whether the component logs at level
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.component)
+}
+
+// Handle redacts the record's attributes and adds the correlation fields
+// Fields already set on the record (e.g. request_id in the gRPC access log) are kept
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	seen := make(map[string]bool, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		seen[a.Key] = true
+		record.AddAttrs(h.redact(a))
+		return true
+	})
+
+	add := func(key, value string) {
+		if value != "" && !seen[key] {
+			record.AddAttrs(slog.String(key, value))
+		}
+	}
+
+	f := fieldsFrom(ctx)
+	if f.service != "" {
+		add("service", f.service)
+	} else {
+		add("service", h.service)
+	}
+	add("request_id", f.requestID)
+	add("user_id", f.userID)
+	if ctx != nil {
+		add("trace_id", tracing.TraceID(ctx))
+		add("span_id", tracing.SpanID(ctx))
+	}
+
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler adding redacted attributes to every record
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redactedAttrs[i] = h.redact(a)
+	}
+	clone := *h
+	clone.next = h.next.WithAttrs(redactedAttrs)
+	return &clone
+}
+
+// WithGroup returns a handler nesting the following attributes in a group
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+// withComponent returns a handler filtering by the level of a component
+func (h *contextHandler) withComponent(component string) *contextHandler {
+	clone := *h
+	clone.component = component
+	clone.next = h.next.WithAttrs([]slog.Attr{slog.String("component", component)})
+	return &clone
+}
+
+// redact replaces the values of sensitive attributes, also inside groups
+func (h *contextHandler) redact(a slog.Attr) slog.Attr {
+	if h.sensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+
+	group := a.Value.Group()
+	redactedGroup := make([]slog.Attr, len(group))
+	for i, ga := range group {
+		redactedGroup[i] = h.redact(ga)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redactedGroup...)}
+}
+
+// sensitive reports whether an attribute key contains one of the redact keys
+func (h *contextHandler) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range h.redactKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
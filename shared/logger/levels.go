@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidLevel is returned for unknown log level names
+var ErrInvalidLevel = errors.New("invalid log level")
+
+// ParseLevel parses debug, info, warn or error, optionally with an offset like "debug-4"
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidLevel, s)
+	}
+	return level, nil
+}
+
+// Levels holds the default log level and per-component overrides, changeable at runtime
+type Levels struct {
+	mu         sync.RWMutex
+	defaultLvl slog.Level
+	components map[string]slog.Level
+}
+
+// NewLevels creates levels with a default level and no overrides
+func NewLevels(defaultLevel slog.Level) *Levels {
+	return &Levels{
+		defaultLvl: defaultLevel,
+		components: make(map[string]slog.Level),
+	}
+}
+
+// Level returns the level of a component: its own override, the closest parent's
+// ("twitchbot" for "twitchbot.chat") or the default level
+func (l *Levels) Level(component string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for name := component; name != ""; {
+		if level, ok := l.components[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.defaultLvl
+}
+
+// SetDefault changes the level of components without override
+func (l *Levels) SetDefault(level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.defaultLvl = level
+}
+
+// Set overrides the level of a component and its children
+func (l *Levels) Set(component string, level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.components[component] = level
+}
+
+// Reset removes the override of a component
+func (l *Levels) Reset(component string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.components, component)
+}
+
+// levelsResponse is the JSON representation of Levels served by the admin endpoint
+type levelsResponse struct {
+	Default    string            `json:"default"`
+	Components map[string]string `json:"components"`
+}
+
+// levelRequest changes the level of a component, or the default level without component
+type levelRequest struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+}
+
+// Handler serves the admin endpoint for changing log levels at runtime
+//
+//	GET    returns the default level and all component overrides
+//	PUT    {"component": "twitchbot.chat", "level": "debug"} sets a level; without component the default
+//	DELETE ?component=twitchbot.chat removes an override
+func (l *Levels) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+			level, err := ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if req.Component == "" {
+				l.SetDefault(level)
+			} else {
+				l.Set(req.Component, level)
+			}
+			slog.Info("log level changed", "log_component", req.Component, "level", level.String())
+		case http.MethodDelete:
+			component := r.URL.Query().Get("component")
+			if component == "" {
+				http.Error(w, "missing component", http.StatusBadRequest)
+				return
+			}
+			l.Reset(component)
+			slog.Info("log level reset", "log_component", component)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(l.snapshot())
+	})
+}
+
+// snapshot returns the current levels for the admin endpoint
+func (l *Levels) snapshot() levelsResponse {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	resp := levelsResponse{
+		Default:    l.defaultLvl.String(),
+		Components: make(map[string]string, len(l.components)),
+	}
+	for name, level := range l.components {
+		resp.Components[name] = level.String()
+	}
+	return resp
+}
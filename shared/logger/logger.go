@@ -1,9 +1,14 @@
+// Package logger provides structured logging with request, trace and user correlation
+// based on log/slog, plus the deprecated line loggers it replaces
 package logger
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"os"
+	"log/slog"
+
+	"github.com/toxictoast/toxictoastgo/shared/tracing"
 )
 
 var (
@@ -19,11 +24,13 @@ type Logger struct {
 }
 
 // NewLogger creates a new logger instance for a service
+// After Setup, it writes through the structured logger
 func NewLogger(serviceName string) *Logger {
+	infoLogger, errorLogger := logLoggers(fmt.Sprintf("[%s] ", serviceName))
 	return &Logger{
 		serviceName: serviceName,
-		infoLogger:  log.New(os.Stdout, fmt.Sprintf("INFO: [%s] ", serviceName), log.Ldate|log.Ltime|log.Lshortfile),
-		errorLogger: log.New(os.Stderr, fmt.Sprintf("ERROR: [%s] ", serviceName), log.Ldate|log.Ltime|log.Lshortfile),
+		infoLogger:  infoLogger,
+		errorLogger: errorLogger,
 	}
 }
 
@@ -37,15 +44,42 @@ func (l *Logger) Error(message string) {
 	l.errorLogger.Println(message)
 }
 
+// InfoContext logs an info message with the correlation fields of ctx
+func (l *Logger) InfoContext(ctx context.Context, message string) {
+	logContext(ctx, l.infoLogger, slog.LevelInfo, message)
+}
+
+// ErrorContext logs an error message with the correlation fields of ctx
+func (l *Logger) ErrorContext(ctx context.Context, message string) {
+	logContext(ctx, l.errorLogger, slog.LevelError, message)
+}
+
+// logContext logs through the structured logger after Setup, otherwise with the trace ID appended
+func logContext(ctx context.Context, fallback *log.Logger, level slog.Level, message string) {
+	if root != nil {
+		slog.New(root).Log(ctx, level, message)
+		return
+	}
+	fallback.Println(withTrace(ctx, message))
+}
+
+// withTrace appends the trace and span ID of ctx, so log lines can be found from a trace
+func withTrace(ctx context.Context, message string) string {
+	traceID := tracing.TraceID(ctx)
+	if traceID == "" {
+		return message
+	}
+	return fmt.Sprintf("%s trace_id=%s span_id=%s", message, traceID, tracing.SpanID(ctx))
+}
+
 // Fatal logs a fatal message and exits
 func (l *Logger) Fatal(message string) {
 	l.errorLogger.Fatal(message)
 }
 
-// Deprecated: Use NewLogger instead
+// Deprecated: Use Setup instead
 func Init() {
-	InfoLogger = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+	InfoLogger, ErrorLogger = logLoggers("")
 }
 
 // Deprecated: Use Logger.Info instead
@@ -57,3 +91,13 @@ func Info(v ...interface{}) {
 func Error(v ...interface{}) {
 	ErrorLogger.Println(v...)
 }
+
+// InfoContext logs an info message with the correlation fields of ctx to the package-level logger
+func InfoContext(ctx context.Context, v ...interface{}) {
+	logContext(ctx, InfoLogger, slog.LevelInfo, fmt.Sprint(v...))
+}
+
+// ErrorContext logs an error message with the correlation fields of ctx to the package-level logger
+func ErrorContext(ctx context.Context, v ...interface{}) {
+	logContext(ctx, ErrorLogger, slog.LevelError, fmt.Sprint(v...))
+}
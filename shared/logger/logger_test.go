@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeRecords parses the JSON lines written by a logger
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected JSON record, got %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestContextFieldsAndRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(Options{ServiceName: "test-service", Output: &buf})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := WithUserID(WithRequestID(context.Background(), "req-1"), "user-1")
+	l.With("api_key", "key-1").InfoContext(ctx, "webhook created",
+		"webhook_id", "wh-1",
+		"secret", "s3cr3t",
+		slog.Group("user", "Password", "hunter2", "name", "alice"),
+		"signing", Secret("sig"),
+	)
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{key: "service", want: "test-service"},
+		{key: "request_id", want: "req-1"},
+		{key: "user_id", want: "user-1"},
+		{key: "webhook_id", want: "wh-1"},
+		{key: "secret", want: redacted},
+		{key: "api_key", want: redacted},
+		{key: "signing", want: redacted},
+	}
+	for _, tt := range tests {
+		if record[tt.key] != tt.want {
+			t.Errorf("%s: Expected %v, got %v", tt.key, tt.want, record[tt.key])
+		}
+	}
+
+	user, _ := record["user"].(map[string]interface{})
+	if user["Password"] != redacted || user["name"] != "alice" {
+		t.Errorf("Expected password in group to be redacted, got %v", user)
+	}
+}
+
+func TestComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevels(slog.LevelInfo)
+	levels.Set("twitchbot", slog.LevelWarn)
+	levels.Set("kafka", slog.LevelDebug)
+
+	h, err := newContextHandler(Options{Levels: levels, Output: &buf})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		component string
+		level     slog.Level
+		want      bool
+	}{
+		{component: "", level: slog.LevelInfo, want: true},
+		{component: "", level: slog.LevelDebug, want: false},
+		{component: "kafka", level: slog.LevelDebug, want: true},
+		{component: "twitchbot", level: slog.LevelInfo, want: false},
+		{component: "twitchbot.chat", level: slog.LevelInfo, want: false},
+		{component: "twitchbot.chat", level: slog.LevelWarn, want: true},
+	}
+	for _, tt := range tests {
+		if got := h.withComponent(tt.component).Enabled(context.Background(), tt.level); got != tt.want {
+			t.Errorf("%s at %v: Expected %v, got %v", tt.component, tt.level, tt.want, got)
+		}
+	}
+
+	// Levels change at runtime through the admin endpoint
+	req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"component":"twitchbot.chat","level":"debug"}`))
+	rec := httptest.NewRecorder()
+	levels.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if level := levels.Level("twitchbot.chat"); level != slog.LevelDebug {
+		t.Errorf("Expected DEBUG, got %v", level)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"verbose"}`))
+	rec = httptest.NewRecorder()
+	levels.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for unknown level, got %d", rec.Code)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	base, err := New(Options{Output: &buf})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	now := time.Unix(0, 0)
+	l := Sampled(base, Sampling{First: 2, Thereafter: 3, Tick: time.Second})
+	l.Handler().(*samplingHandler).state.now = func() time.Time { return now }
+
+	for i := 0; i < 8; i++ {
+		l.Info("chat message received", "n", i)
+	}
+	l.Info("other message")
+
+	// The next tick starts counting again
+	now = now.Add(time.Second)
+	l.Info("chat message received", "n", 8)
+
+	var got []float64
+	for _, record := range decodeRecords(t, &buf) {
+		if record["msg"] == "chat message received" {
+			got = append(got, record["n"].(float64))
+		}
+	}
+
+	// First 2, then every 3rd: records 0, 1, 4, 7, then 8 in the new tick
+	want := []float64{0, 1, 4, 7, 8}
+	if len(got) != len(want) {
+		t.Fatalf("Expected records %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected records %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name   string
+		token  Secret
+		header string
+		want   int
+	}{
+		{name: "disabled without token", token: "", header: "Bearer ", want: http.StatusForbidden},
+		{name: "missing header", token: "t0ken", header: "", want: http.StatusUnauthorized},
+		{name: "wrong token", token: "t0ken", header: "Bearer other", want: http.StatusUnauthorized},
+		{name: "not a bearer token", token: "t0ken", header: "t0ken", want: http.StatusUnauthorized},
+		{name: "matching token", token: "t0ken", header: "Bearer t0ken", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin/log-level", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		RequireToken(tt.token, ok).ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: Expected status %d, got %d", tt.name, tt.want, rec.Code)
+		}
+	}
+}
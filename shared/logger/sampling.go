@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Sampling limits how often the same message is logged on hot paths
+// Per Tick, the first First records of a message and level are logged, then every
+// Thereafter-th; with Thereafter 0 the rest of the tick is dropped
+type Sampling struct {
+	First      int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// samplingState counts records per message and level of the current tick
+// It is shared by all handlers derived from one sampled logger
+type samplingState struct {
+	mu      sync.Mutex
+	resetAt time.Time
+	counts  map[string]int
+	now     func() time.Time
+}
+
+// sample counts a record and reports whether it's logged
+func (s *samplingState) sample(key string, cfg Sampling) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !now.Before(s.resetAt) {
+		s.counts = make(map[string]int)
+		s.resetAt = now.Add(cfg.Tick)
+	}
+
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= cfg.First {
+		return true
+	}
+	return cfg.Thereafter > 0 && (n-cfg.First)%cfg.Thereafter == 0
+}
+
+// samplingHandler drops records exceeding the sampling rate
+type samplingHandler struct {
+	next  slog.Handler
+	cfg   Sampling
+	state *samplingState
+}
+
+// Enabled reports whether the wrapped handler logs at level
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record on if the sampling rate allows it
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.state.sample(r.Level.String()+" "+r.Message, h.cfg) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler sampling together with h
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), cfg: h.cfg, state: h.state}
+}
+
+// WithGroup returns a handler sampling together with h
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), cfg: h.cfg, state: h.state}
+}
+
+// Sampled returns a logger that samples the records of l, e.g. for every chat message
+// Sampling is keyed by message, so messages should be constant and details go in attributes
+func Sampled(l *slog.Logger, cfg Sampling) *slog.Logger {
+	if cfg.Tick <= 0 {
+		cfg.Tick = time.Second
+	}
+	return slog.New(&samplingHandler{
+		next:  l.Handler(),
+		cfg:   cfg,
+		state: &samplingState{now: time.Now},
+	})
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/toxictoast/toxictoastgo/shared/config"
+)
+
+var (
+	// levels are the levels of the logger installed by Setup, changed through LevelHandler
+	levels = NewLevels(slog.LevelInfo)
+
+	// root is the handler installed by Setup; nil until then
+	root *contextHandler
+
+	// adminToken guards LevelHandler; the endpoint is disabled while it is empty
+	adminToken Secret
+)
+
+// Options configures a structured logger
+type Options struct {
+	// ServiceName is added as service to every record without a service in its context
+	ServiceName string
+
+	// Levels holds the default and per-component levels; defaults to info for all components
+	Levels *Levels
+
+	// Format is "json" (default) or "text"
+	Format string
+
+	// Output defaults to os.Stdout
+	Output io.Writer
+
+	// RedactKeys are redacted in addition to DefaultRedactKeys
+	RedactKeys []string
+}
+
+// New creates a structured logger adding service, request ID, trace ID and user ID of the
+// context to every record and redacting secrets
+func New(opts Options) (*slog.Logger, error) {
+	h, err := newContextHandler(opts)
+	if err != nil {
+		return nil, err
+	}
+	return slog.New(h), nil
+}
+
+// newContextHandler creates the handler behind New and Setup
+func newContextHandler(opts Options) (*contextHandler, error) {
+	if opts.Levels == nil {
+		opts.Levels = NewLevels(slog.LevelInfo)
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+
+	// Levels are checked by the context handler, so the output handler logs everything
+	handlerOpts := &slog.HandlerOptions{Level: slog.Level(-100)}
+
+	var next slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "json":
+		next = slog.NewJSONHandler(opts.Output, handlerOpts)
+	case "text":
+		next = slog.NewTextHandler(opts.Output, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", opts.Format)
+	}
+
+	redactKeys := append([]string{}, DefaultRedactKeys...)
+	for _, k := range opts.RedactKeys {
+		redactKeys = append(redactKeys, strings.ToLower(k))
+	}
+
+	return &contextHandler{
+		next:       next,
+		service:    opts.ServiceName,
+		levels:     opts.Levels,
+		redactKeys: redactKeys,
+	}, nil
+}
+
+// Setup installs the structured logger as slog default
+// log.Printf, the package-level functions and Logger instances created afterwards write
+// through it as well, so existing log lines become structured records of the service
+func Setup(cfg config.LoggingConfig) error {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	levels.SetDefault(level)
+
+	for _, entry := range cfg.ComponentLevels {
+		component, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("%w: %q is not component=level", ErrInvalidLevel, entry)
+		}
+		componentLevel, err := ParseLevel(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		levels.Set(strings.TrimSpace(component), componentLevel)
+	}
+
+	h, err := newContextHandler(Options{
+		ServiceName: cfg.ServiceName,
+		Levels:      levels,
+		Format:      cfg.Format,
+	})
+	if err != nil {
+		return err
+	}
+
+	root = h
+	adminToken = Secret(cfg.AdminToken)
+	slog.SetDefault(slog.New(h))
+	InfoLogger = slog.NewLogLogger(h, slog.LevelInfo)
+	ErrorLogger = slog.NewLogLogger(h, slog.LevelError)
+	return nil
+}
+
+// Component returns the default logger for a component, e.g. "kafka" or "twitchbot.chat"
+// Its level can be changed at runtime through LevelHandler; dotted names inherit the level
+// of their parent component
+func Component(name string) *slog.Logger {
+	if h, ok := slog.Default().Handler().(*contextHandler); ok {
+		return slog.New(h.withComponent(name))
+	}
+	return slog.Default().With("component", name)
+}
+
+// LevelHandler serves the admin endpoint changing the levels of the logger installed by Setup
+// Requests need the configured admin token as bearer token; without one the endpoint is disabled
+func LevelHandler() http.Handler {
+	return RequireToken(adminToken, levels.Handler())
+}
+
+// RequireToken rejects requests without token as bearer token
+// An empty token rejects every request, so admin endpoints are closed unless configured
+func RequireToken(token Secret, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.Error(w, "admin endpoint disabled", http.StatusForbidden)
+			return
+		}
+
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logLoggers returns the info and error loggers behind Logger and the package-level functions
+func logLoggers(prefix string) (*log.Logger, *log.Logger) {
+	if root != nil {
+		return slog.NewLogLogger(root, slog.LevelInfo), slog.NewLogLogger(root, slog.LevelError)
+	}
+	return log.New(os.Stdout, "INFO: "+prefix, log.Ldate|log.Ltime|log.Lshortfile),
+		log.New(os.Stderr, "ERROR: "+prefix, log.Ldate|log.Ltime|log.Lshortfile)
+}
@@ -1,8 +1,11 @@
 package metrics
 
 import (
+	"net/http"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Metrics holds all Prometheus metrics for a service
@@ -94,3 +97,8 @@ func New(serviceName string) *Metrics {
 func (m *Metrics) Registry() *prometheus.Registry {
 	return m.registry
 }
+
+// Handler returns an HTTP handler exposing the service registry
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -3,11 +3,15 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/toxictoast/toxictoastgo/shared/auth"
 	"github.com/toxictoast/toxictoastgo/shared/jwt"
+	"github.com/toxictoast/toxictoastgo/shared/logger"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -18,23 +22,92 @@ const (
 	ClaimsContextKey contextKey = "jwt_claims"
 )
 
+// AuthOptions configures the authentication middleware
+type AuthOptions struct {
+	// RevocationStore is consulted for every authenticated request; defaults to an
+	// in-memory store, which only sees revocations made by the same process
+	RevocationStore auth.RevocationStore
+}
+
 // AuthMiddleware provides JWT authentication middleware for HTTP handlers
 type AuthMiddleware struct {
-	jwtHelper      *jwt.JWTHelper
-	tokenBlacklist *auth.TokenBlacklist
+	jwtHelper   *jwt.JWTHelper
+	revocations auth.RevocationStore
 }
 
 // NewAuthMiddleware creates a new authentication middleware
 func NewAuthMiddleware(jwtHelper *jwt.JWTHelper) *AuthMiddleware {
+	return NewAuthMiddlewareWithOptions(jwtHelper, AuthOptions{})
+}
+
+// NewAuthMiddlewareWithOptions creates a new authentication middleware with a custom revocation store
+func NewAuthMiddlewareWithOptions(jwtHelper *jwt.JWTHelper, opts AuthOptions) *AuthMiddleware {
+	if opts.RevocationStore == nil {
+		opts.RevocationStore = auth.NewMemoryRevocationStore()
+	}
 	return &AuthMiddleware{
-		jwtHelper:      jwtHelper,
-		tokenBlacklist: auth.NewTokenBlacklist(),
+		jwtHelper:   jwtHelper,
+		revocations: opts.RevocationStore,
 	}
 }
 
-// GetTokenBlacklist returns the token blacklist instance
-func (m *AuthMiddleware) GetTokenBlacklist() *auth.TokenBlacklist {
-	return m.tokenBlacklist
+// GetRevocationStore returns the revocation store
+func (m *AuthMiddleware) GetRevocationStore() auth.RevocationStore {
+	return m.revocations
+}
+
+// ValidateToken validates a token and checks that it hasn't been revoked
+// Revoked tokens fail with auth.ErrTokenRevoked, store failures with auth.ErrRevocationUnavailable
+func (m *AuthMiddleware) ValidateToken(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	claims, err := m.jwtHelper.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature is checked first, so forged tokens never cost a store lookup
+	revoked, err := m.revocations.IsRevoked(ctx, tokenRef(claims))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", auth.ErrRevocationUnavailable, err)
+	}
+	if revoked {
+		return nil, auth.ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// RevokeSession logs out the session of a token
+// Tokens without a session are revoked individually. The revocation lasts as long as
+// a refresh token, so the session can't be refreshed either
+func (m *AuthMiddleware) RevokeSession(ctx context.Context, claims *jwt.Claims) error {
+	if claims.SessionID != "" {
+		return m.revocations.RevokeSession(ctx, claims.SessionID, time.Now().Add(m.jwtHelper.GetRefreshTokenDuration()))
+	}
+
+	expiresAt := time.Now().Add(m.jwtHelper.GetAccessTokenDuration())
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	return m.revocations.RevokeToken(ctx, claims.ID, expiresAt)
+}
+
+// RevokeUser logs a user out everywhere by revoking all tokens issued until now
+func (m *AuthMiddleware) RevokeUser(ctx context.Context, userID string) error {
+	now := time.Now()
+	return m.revocations.RevokeUser(ctx, userID, now, now.Add(m.jwtHelper.GetRefreshTokenDuration()))
+}
+
+// tokenRef returns the revocation identifiers of a token
+func tokenRef(claims *jwt.Claims) auth.TokenRef {
+	ref := auth.TokenRef{
+		ID:        claims.ID,
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+	}
+	if claims.IssuedAt != nil {
+		ref.IssuedAt = claims.IssuedAt.Time
+	}
+	return ref
 }
 
 // Authenticate is a middleware that validates JWT tokens from the Authorization header
@@ -57,21 +130,28 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 
 		tokenString := parts[1]
 
-		// Check if token is revoked
-		if m.tokenBlacklist.IsRevoked(tokenString) {
-			writeJSONError(w, "Token has been revoked", http.StatusUnauthorized)
+		// Validate token and check revocation
+		claims, err := m.jwtHelper.ValidateToken(tokenString)
+		if err != nil {
+			writeJSONError(w, "Invalid or expired token: "+err.Error(), http.StatusUnauthorized)
 			return
 		}
 
-		// Validate token
-		claims, err := m.jwtHelper.ValidateToken(tokenString)
+		revoked, err := m.revocations.IsRevoked(r.Context(), tokenRef(claims))
 		if err != nil {
-			writeJSONError(w, "Invalid or expired token: "+err.Error(), http.StatusUnauthorized)
+			// Fail closed: a revoked token must not pass while the store is unavailable
+			log.Printf("Failed to check token revocation: %v", err)
+			writeJSONError(w, "Token revocation check unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if revoked {
+			writeJSONError(w, "Token has been revoked", http.StatusUnauthorized)
 			return
 		}
 
 		// Store claims in context
 		ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
+		ctx = logger.WithUserID(ctx, claims.UserID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -98,16 +178,17 @@ func (m *AuthMiddleware) AuthenticateOptional(next http.Handler) http.Handler {
 
 		tokenString := parts[1]
 
-		// Validate token
-		claims, err := m.jwtHelper.ValidateToken(tokenString)
+		// Validate token and check revocation
+		claims, err := m.ValidateToken(r.Context(), tokenString)
 		if err != nil {
-			// Invalid token - continue without authentication
+			// Invalid or revoked token - continue without authentication
 			next.ServeHTTP(w, r)
 			return
 		}
 
 		// Store claims in context
 		ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
+		ctx = logger.WithUserID(ctx, claims.UserID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
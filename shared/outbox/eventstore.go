@@ -0,0 +1,169 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
+)
+
+// TopicRouter maps a stored event to its Kafka topic
+// Returning an empty topic keeps the event out of the outbox
+type TopicRouter func(event *eventstore.EventEnvelope) string
+
+// EventTypeTopic publishes every event to a topic named after its event type (e.g. "user.created")
+func EventTypeTopic(event *eventstore.EventEnvelope) string {
+	return event.EventType
+}
+
+// PayloadMapper maps a stored event to the payload published for it
+// Domain events often hold data that must not leave the service, like credential hashes
+type PayloadMapper func(event *eventstore.EventEnvelope) (json.RawMessage, error)
+
+// OmitFields returns a payload mapper removing top-level fields from every payload
+func OmitFields(fields ...string) PayloadMapper {
+	return func(event *eventstore.EventEnvelope) (json.RawMessage, error) {
+		return omitFields(event.Data, fields)
+	}
+}
+
+// OmitPII returns a payload mapper removing the personal data fields registered for each event type
+// They are removed whether stored encrypted or not: a shredded key can't erase copies in Kafka,
+// so published events carry identifiers only and consumers look up personal data from its owner
+func OmitPII(registry *eventstore.PIIRegistry) PayloadMapper {
+	return func(event *eventstore.EventEnvelope) (json.RawMessage, error) {
+		registered := registry.Fields(event.EventType)
+		if len(registered) == 0 {
+			return event.Data, nil
+		}
+
+		fields := make([]string, 0, len(registered))
+		for field := range registered {
+			fields = append(fields, field)
+		}
+		return omitFields(event.Data, fields)
+	}
+}
+
+// ChainMappers returns a payload mapper applying mappers in order, each to the previous payload
+func ChainMappers(mappers ...PayloadMapper) PayloadMapper {
+	return func(event *eventstore.EventEnvelope) (json.RawMessage, error) {
+		mapped := *event
+		for _, mapper := range mappers {
+			payload, err := mapper(&mapped)
+			if err != nil {
+				return nil, err
+			}
+			mapped.Data = payload
+		}
+		return mapped.Data, nil
+	}
+}
+
+// omitFields removes top-level fields from a payload
+func omitFields(data json.RawMessage, fields []string) (json.RawMessage, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+	}
+
+	omitted := false
+	for _, field := range fields {
+		if _, ok := payload[field]; ok {
+			delete(payload, field)
+			omitted = true
+		}
+	}
+
+	if !omitted {
+		return data, nil
+	}
+	return json.Marshal(payload)
+}
+
+// HookOptions configures the outbox append hook
+type HookOptions struct {
+	// Router maps events to topics
+	Router TopicRouter
+
+	// Mapper maps events to published payloads; events are published as stored when nil
+	Mapper PayloadMapper
+}
+
+// EventStoreHook returns an append hook that records every saved event in the outbox
+// within the same transaction as the event store insert
+// Events are published as binary mode CloudEvents; the relay's producer sets the source
+//
+//	eventStore.AddAppendHook(outbox.EventStoreHook(outbox.EventTypeTopic))
+func EventStoreHook(router TopicRouter) eventstore.AppendHook {
+	return EventStoreHookWithOptions(HookOptions{Router: router})
+}
+
+// EventStoreHookWithOptions returns an append hook publishing payloads mapped by opts.Mapper
+//
+//	eventStore.AddAppendHook(outbox.EventStoreHookWithOptions(outbox.HookOptions{
+//		Router: outbox.EventTypeTopic,
+//		Mapper: outbox.ChainMappers(outbox.OmitFields("password_hash"), outbox.OmitPII(piiRegistry)),
+//	}))
+func EventStoreHookWithOptions(opts HookOptions) eventstore.AppendHook {
+	return func(ctx context.Context, tx *sql.Tx, events []*eventstore.EventEnvelope) error {
+		messages := make([]*Message, 0, len(events))
+
+		for _, event := range events {
+			topic := opts.Router(event)
+			if topic == "" {
+				continue
+			}
+
+			payload := event.Data
+			if opts.Mapper != nil {
+				var err error
+				if payload, err = opts.Mapper(event); err != nil {
+					return fmt.Errorf("failed to map event %s: %w", event.EventID, err)
+				}
+			}
+
+			headers := cloudEventOf(event).Headers()
+			headers["event_id"] = event.EventID
+			headers["aggregate_id"] = event.AggregateID
+			headers["aggregate_type"] = event.AggregateType
+			headers["version"] = strconv.FormatInt(event.Version, 10)
+
+			messages = append(messages, &Message{
+				MessageID: event.EventID,
+				Topic:     topic,
+				Key:       event.AggregateID,
+				Payload:   payload,
+				Headers:   headers,
+				CreatedAt: event.Timestamp,
+			})
+		}
+
+		return Enqueue(ctx, tx, messages...)
+	}
+}
+
+// cloudEventOf maps a stored event onto CloudEvents attributes
+func cloudEventOf(event *eventstore.EventEnvelope) *kafka.CloudEvent {
+	cloudEvent := &kafka.CloudEvent{
+		SpecVersion:     kafka.CloudEventsSpecVersion,
+		ID:              event.EventID,
+		Type:            event.EventType,
+		Subject:         event.AggregateID,
+		Time:            event.Timestamp,
+		DataContentType: kafka.ContentTypeJSON,
+	}
+
+	if id, ok := event.Metadata[eventstore.MetadataCorrelationID].(string); ok {
+		cloudEvent.CorrelationID = id
+	}
+	if id, ok := event.Metadata[eventstore.MetadataCausationID].(string); ok {
+		cloudEvent.CausationID = id
+	}
+
+	return cloudEvent
+}
@@ -0,0 +1,187 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/toxictoast/toxictoastgo/shared/eventstore"
+)
+
+func TestOmitFields(t *testing.T) {
+	mapper := OmitFields("password_hash", "new_password_hash")
+
+	tests := []struct {
+		name string
+		data string
+		want map[string]interface{}
+	}{
+		{
+			name: "removes credential hash",
+			data: `{"email":"a@example.com","password_hash":"$2a$10$abc"}`,
+			want: map[string]interface{}{"email": "a@example.com"},
+		},
+		{
+			name: "removes every listed field",
+			data: `{"user_id":"u-1","new_password_hash":"$2a$10$def","password_hash":"$2a$10$abc"}`,
+			want: map[string]interface{}{"user_id": "u-1"},
+		},
+		{
+			name: "keeps payload without listed fields",
+			data: `{"user_id":"u-1","reason":"spam"}`,
+			want: map[string]interface{}{"user_id": "u-1", "reason": "spam"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := mapper(&eventstore.EventEnvelope{EventID: "e-1", Data: json.RawMessage(tt.data)})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(payload, &got); err != nil {
+				t.Fatalf("Expected JSON payload, got %q: %v", payload, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("Expected %s to be %v, got %v", key, value, got[key])
+				}
+			}
+		})
+	}
+}
+
+func TestOmitFields_InvalidPayload(t *testing.T) {
+	_, err := OmitFields("password_hash")(&eventstore.EventEnvelope{Data: json.RawMessage(`not json`)})
+	if err == nil {
+		t.Error("Expected error for invalid payload, got nil")
+	}
+}
+
+// profileEvent is a payload with personal data, encrypted when stored
+type profileEvent struct {
+	UserID       string `json:"user_id"`
+	Email        string `json:"email" pii:"email"`
+	DisplayName  string `json:"display_name,omitempty" pii:"empty"`
+	PasswordHash string `json:"password_hash" pii:"token"`
+	Status       string `json:"status"`
+}
+
+// newEncryptingStore wraps inner with encryption of the profileEvent personal data
+func newEncryptingStore(t *testing.T, inner eventstore.EventStore) (*eventstore.EncryptingEventStore, *eventstore.PIIRegistry) {
+	t.Helper()
+	registry := eventstore.NewPIIRegistry()
+	if err := registry.Register("profile.created", profileEvent{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return eventstore.NewEncryptingEventStore(inner, eventstore.NewMemoryKeyStore(), registry), registry
+}
+
+// saveProfile saves a profile.created event for a new aggregate and returns its ID
+func saveProfile(t *testing.T, store eventstore.EventStore) string {
+	t.Helper()
+	id := uuid.New().String()
+	event, err := eventstore.NewEventEnvelope("profile", id, "profile.created", 1, profileEvent{
+		UserID: id, Email: "jane@example.com", DisplayName: "Jane", PasswordHash: "$2a$10$abc", Status: "active",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	if err := store.SaveEvents(context.Background(), id, -1, []*eventstore.EventEnvelope{event}); err != nil {
+		t.Fatalf("SaveEvents failed: %v", err)
+	}
+	return id
+}
+
+// assertPublicProfile checks that a published payload keeps only the non-personal fields
+func assertPublicProfile(t *testing.T, payload []byte, id string) {
+	t.Helper()
+	if strings.Contains(string(payload), "pii:v1:") {
+		t.Errorf("Expected no ciphertext in published payload, got %s", payload)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("Expected JSON payload, got %q: %v", payload, err)
+	}
+	want := map[string]interface{}{"user_id": id, "status": "active"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, got[key])
+		}
+	}
+}
+
+func TestOmitPII_EncryptedEvents(t *testing.T) {
+	inner := eventstore.NewMemoryEventStore()
+	store, registry := newEncryptingStore(t, inner)
+	id := saveProfile(t, store)
+
+	// Append hooks see the payload as stored by the inner store, with encrypted fields
+	events, err := inner.GetEvents(context.Background(), "profile", id)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Expected 1 stored event, got %d (%v)", len(events), err)
+	}
+	if !strings.Contains(string(events[0].Data), "pii:v1:") {
+		t.Fatalf("Expected encrypted payload, got %s", events[0].Data)
+	}
+
+	payload, err := ChainMappers(OmitFields("password_hash"), OmitPII(registry))(events[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertPublicProfile(t, payload, id)
+
+	// Event types without personal data are published as stored
+	plain := &eventstore.EventEnvelope{EventType: "profile.deleted", Data: json.RawMessage(`{"user_id":"u-1"}`)}
+	if payload, err := OmitPII(registry)(plain); err != nil || string(payload) != `{"user_id":"u-1"}` {
+		t.Errorf("Expected payload to be kept, got %s (%v)", payload, err)
+	}
+}
+
+// payloadPublisher records published payloads
+type payloadPublisher struct {
+	payloads [][]byte
+}
+
+func (p *payloadPublisher) PublishMessage(topic string, key string, payload []byte, headers map[string]string) error {
+	p.payloads = append(p.payloads, payload)
+	return nil
+}
+
+func TestEventStoreHook_EncryptionEnabled(t *testing.T) {
+	db := openTestDB(t)
+
+	// The hook runs in the inner store, below the encryption, as in user-service
+	postgresStore, err := eventstore.NewPostgresEventStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create event store: %v", err)
+	}
+	store, registry := newEncryptingStore(t, postgresStore)
+	postgresStore.AddAppendHook(EventStoreHookWithOptions(HookOptions{
+		Router: EventTypeTopic,
+		Mapper: ChainMappers(OmitFields("password_hash"), OmitPII(registry)),
+	}))
+
+	id := saveProfile(t, store)
+
+	publisher := &payloadPublisher{}
+	if _, err := NewRelay(db, publisher, RelayOptions{}).RelayOnce(context.Background()); err != nil {
+		t.Fatalf("RelayOnce failed: %v", err)
+	}
+	if len(publisher.payloads) != 1 {
+		t.Fatalf("Expected 1 published message, got %d", len(publisher.payloads))
+	}
+	assertPublicProfile(t, publisher.payloads[0], id)
+}
@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/toxictoast/toxictoastgo/shared/kafka"
+)
+
+// Message is a pending message recorded in the outbox table
+// It is published by the Relay after the surrounding transaction commits
+type Message struct {
+	// ID is the outbox row position, assigned on insert
+	ID int64
+
+	// MessageID uniquely identifies the message so consumers can deduplicate redeliveries
+	MessageID string
+
+	// Topic is the Kafka topic to publish to
+	Topic string
+
+	// Key is the Kafka message key; messages sharing a key are published in insert order
+	Key string
+
+	// Payload is the encoded message body
+	Payload []byte
+
+	// Headers are added to the Kafka message
+	Headers map[string]string
+
+	// CreatedAt is when the message was recorded
+	CreatedAt time.Time
+
+	// Attempts is how often the relay tried to publish the message
+	Attempts int
+}
+
+// NewMessage creates an outbox message with a JSON encoded payload
+func NewMessage(topic, key string, payload interface{}) (*Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	return &Message{
+		MessageID: uuid.New().String(),
+		Topic:     topic,
+		Key:       key,
+		Payload:   data,
+		Headers:   make(map[string]string),
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// Execer is satisfied by *sql.DB, *sql.Tx and gorm connection pools
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// CreateTable creates the outbox table if it doesn't exist
+func CreateTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS event_outbox (
+		id BIGSERIAL PRIMARY KEY,
+		message_id VARCHAR(36) NOT NULL UNIQUE,
+		topic VARCHAR(255) NOT NULL,
+		message_key VARCHAR(255) NOT NULL,
+		payload BYTEA NOT NULL,
+		headers JSONB,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		published_at TIMESTAMP,
+		attempts INT NOT NULL DEFAULT 0,
+		last_error TEXT,
+		next_attempt_at TIMESTAMP,
+		dead_at TIMESTAMP
+	);
+
+	-- Added with retry backoff; tables created before need the columns
+	ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP;
+	ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP;
+
+	CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox(id) WHERE published_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_event_outbox_pending_key ON event_outbox(message_key, id) WHERE published_at IS NULL AND dead_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_event_outbox_published_at ON event_outbox(published_at);
+	`
+
+	_, err := db.Exec(query)
+	return err
+}
+
+// Enqueue records messages in the outbox using the given executor
+// Pass the transaction that writes the related state so both commit or roll back together
+func Enqueue(ctx context.Context, exec Execer, messages ...*Message) error {
+	for _, msg := range messages {
+		if msg.MessageID == "" {
+			msg.MessageID = uuid.New().String()
+		}
+		if msg.CreatedAt.IsZero() {
+			msg.CreatedAt = time.Now().UTC()
+		}
+
+		// Keep the trace of the request that wrote the message, the relay publishes it later
+		if msg.Headers == nil {
+			msg.Headers = make(map[string]string)
+		}
+		kafka.InjectTraceContext(ctx, msg.Headers)
+
+		headersJSON, err := json.Marshal(msg.Headers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox headers: %w", err)
+		}
+
+		_, err = exec.ExecContext(ctx, `
+			INSERT INTO event_outbox (message_id, topic, message_key, payload, headers, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`,
+			msg.MessageID,
+			msg.Topic,
+			msg.Key,
+			msg.Payload,
+			headersJSON,
+			msg.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// EnqueueGorm records messages in the outbox from inside a GORM transaction
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//		if err := tx.Create(&post).Error; err != nil {
+//			return err
+//		}
+//		return outbox.EnqueueGorm(tx, msg)
+//	})
+func EnqueueGorm(tx *gorm.DB, messages ...*Message) error {
+	ctx := tx.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Enqueue(ctx, tx.Statement.ConnPool, messages...)
+}
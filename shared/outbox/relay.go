@@ -0,0 +1,351 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/toxictoast/toxictoastgo/shared/metrics"
+)
+
+const (
+	// relayLockID is the advisory lock key that elects a single active relay,
+	// which keeps per-key ordering intact when several replicas run a relay
+	relayLockID = 727_100_002
+
+	defaultRelayPollInterval  = 500 * time.Millisecond
+	defaultRelayBatchSize     = 100
+	defaultRelayRetention     = 7 * 24 * time.Hour
+	defaultRelayMaxAttempts   = 20
+	defaultRelayRetryDelay    = time.Second
+	defaultRelayMaxRetryDelay = 10 * time.Minute
+)
+
+// Publisher sends an encoded message to a topic
+// *kafka.Producer satisfies this interface
+type Publisher interface {
+	PublishMessage(topic string, key string, payload []byte, headers map[string]string) error
+}
+
+// RelayOptions configures the outbox relay
+type RelayOptions struct {
+	// PollInterval is how long to wait between relay rounds once the outbox is drained
+	PollInterval time.Duration
+
+	// BatchSize is the number of messages published per round
+	BatchSize int
+
+	// Retention is how long published messages are kept before being deleted
+	Retention time.Duration
+
+	// MaxAttempts is how often a message is tried before it is marked dead
+	// With the default delays a message is given up after about two hours of failures
+	MaxAttempts int
+
+	// RetryDelay is the wait before the first retry; it doubles per attempt up to MaxRetryDelay
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	// Metrics enables lag and failure metrics when set
+	Metrics *metrics.Metrics
+
+	// ServiceName is used as the service label on metrics
+	ServiceName string
+}
+
+// Relay publishes pending outbox messages with at-least-once delivery
+// Messages sharing a key are published in insert order; a failing message is retried
+// with exponential backoff and holds back later messages with the same key until it
+// succeeds or is marked dead after MaxAttempts. Messages with other keys keep flowing
+type Relay struct {
+	db        *sql.DB
+	publisher Publisher
+	opts      RelayOptions
+
+	published *prometheus.CounterVec
+	failures  *prometheus.CounterVec
+	dead      *prometheus.CounterVec
+	pending   *prometheus.GaugeVec
+	lag       *prometheus.GaugeVec
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(db *sql.DB, publisher Publisher, opts RelayOptions) *Relay {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultRelayPollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultRelayBatchSize
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = defaultRelayRetention
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultRelayMaxAttempts
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultRelayRetryDelay
+	}
+	if opts.MaxRetryDelay <= 0 {
+		opts.MaxRetryDelay = defaultRelayMaxRetryDelay
+	}
+
+	r := &Relay{
+		db:        db,
+		publisher: publisher,
+		opts:      opts,
+	}
+
+	if opts.Metrics != nil {
+		r.published = opts.Metrics.NewCounter("outbox_published_total", "Total number of outbox messages published", []string{"service", "topic"})
+		r.failures = opts.Metrics.NewCounter("outbox_publish_failures_total", "Total number of failed outbox publish attempts", []string{"service", "topic"})
+		r.dead = opts.Metrics.NewCounter("outbox_dead_messages_total", "Total number of outbox messages given up after MaxAttempts", []string{"service", "topic"})
+		r.pending = opts.Metrics.NewGauge("outbox_pending_messages", "Number of outbox messages waiting to be published", []string{"service"})
+		r.lag = opts.Metrics.NewGauge("outbox_lag_seconds", "Age of the oldest unpublished outbox message in seconds", []string{"service"})
+	}
+
+	return r
+}
+
+// Start runs the relay until ctx is cancelled
+func (r *Relay) Start(ctx context.Context) {
+	go func() {
+		cleanupTicker := time.NewTicker(time.Hour)
+		defer cleanupTicker.Stop()
+
+		for {
+			published, err := r.RelayOnce(ctx)
+			if err != nil {
+				log.Printf("Outbox relay error: %v", err)
+			}
+
+			if err := r.recordBacklog(ctx); err != nil {
+				log.Printf("Outbox relay failed to measure backlog: %v", err)
+			}
+
+			// Keep draining while full batches are coming back
+			if err == nil && published == r.opts.BatchSize {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				log.Println("Stopping outbox relay")
+				return
+			case <-cleanupTicker.C:
+				if err := r.Cleanup(ctx); err != nil {
+					log.Printf("Outbox cleanup failed: %v", err)
+				}
+			case <-time.After(r.opts.PollInterval):
+			}
+		}
+	}()
+}
+
+// RelayOnce publishes one batch of pending messages and returns how many were published
+// It does nothing if another relay currently holds the relay lock
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var acquired bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, relayLockID).Scan(&acquired); err != nil {
+		return 0, fmt.Errorf("failed to acquire relay lock: %w", err)
+	}
+	if !acquired {
+		return 0, nil
+	}
+
+	messages, err := r.fetchPending(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool)
+
+	for _, msg := range messages {
+		if blocked[msg.Key] {
+			continue
+		}
+
+		if err := r.publisher.PublishMessage(msg.Topic, msg.Key, msg.Payload, msg.Headers); err != nil {
+			// Hold back later messages for this key to preserve ordering
+			blocked[msg.Key] = true
+			if err := r.recordFailure(ctx, tx, msg, err); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE event_outbox SET published_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1
+		`, msg.ID); err != nil {
+			return published, fmt.Errorf("failed to mark outbox message as published: %w", err)
+		}
+
+		published++
+		if r.published != nil {
+			r.published.WithLabelValues(r.opts.ServiceName, msg.Topic).Inc()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		// Messages were sent but not marked, they will be delivered again
+		return 0, fmt.Errorf("failed to commit relay transaction: %w", err)
+	}
+
+	return published, nil
+}
+
+// recordFailure schedules the next attempt of a message, or marks it dead once
+// MaxAttempts is reached so later messages with its key are published again
+func (r *Relay) recordFailure(ctx context.Context, tx *sql.Tx, msg *Message, cause error) error {
+	attempts := msg.Attempts + 1
+	dead := attempts >= r.opts.MaxAttempts
+
+	if r.failures != nil {
+		r.failures.WithLabelValues(r.opts.ServiceName, msg.Topic).Inc()
+	}
+	if dead {
+		log.Printf("Outbox message %s (%s) dead after %d attempts: %v", msg.MessageID, msg.Topic, attempts, cause)
+		if r.dead != nil {
+			r.dead.WithLabelValues(r.opts.ServiceName, msg.Topic).Inc()
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET attempts = attempts + 1,
+			last_error = $2,
+			next_attempt_at = NOW() + make_interval(secs => $3),
+			dead_at = CASE WHEN $4::boolean THEN NOW() END
+		WHERE id = $1
+	`, msg.ID, cause.Error(), r.retryDelay(attempts).Seconds(), dead); err != nil {
+		return fmt.Errorf("failed to record outbox failure: %w", err)
+	}
+	return nil
+}
+
+// retryDelay returns the backoff after the given number of failed attempts
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	delay := r.opts.RetryDelay
+	for i := 1; i < attempts && delay < r.opts.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.opts.MaxRetryDelay {
+		delay = r.opts.MaxRetryDelay
+	}
+	return delay
+}
+
+// fetchPending loads the next batch of due messages in insert order
+// Keys whose earliest pending message is waiting for a retry are skipped in SQL, so a
+// failing key never fills the batch and holds back other keys
+func (r *Relay) fetchPending(ctx context.Context, tx *sql.Tx) ([]*Message, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT o.id, o.message_id, o.topic, o.message_key, o.payload, o.headers, o.created_at, o.attempts
+		FROM event_outbox o
+		WHERE o.published_at IS NULL
+			AND o.dead_at IS NULL
+			AND (o.next_attempt_at IS NULL OR o.next_attempt_at <= NOW())
+			AND NOT EXISTS (
+				SELECT 1 FROM event_outbox w
+				WHERE w.message_key = o.message_key
+					AND w.id < o.id
+					AND w.published_at IS NULL
+					AND w.dead_at IS NULL
+					AND w.next_attempt_at > NOW()
+			)
+		ORDER BY o.id ASC
+		LIMIT $1
+	`, r.opts.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*Message
+	for rows.Next() {
+		var msg Message
+		var headersJSON []byte
+
+		if err := rows.Scan(&msg.ID, &msg.MessageID, &msg.Topic, &msg.Key, &msg.Payload, &headersJSON, &msg.CreatedAt, &msg.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+
+		if len(headersJSON) > 0 {
+			if err := json.Unmarshal(headersJSON, &msg.Headers); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal outbox headers: %w", err)
+			}
+		}
+		if msg.Headers == nil {
+			msg.Headers = make(map[string]string)
+		}
+		msg.Headers["message_id"] = msg.MessageID
+
+		messages = append(messages, &msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox rows: %w", err)
+	}
+
+	return messages, nil
+}
+
+// recordBacklog updates the pending and lag gauges
+func (r *Relay) recordBacklog(ctx context.Context) error {
+	if r.pending == nil {
+		return nil
+	}
+
+	var pending int64
+	var lagSeconds sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), EXTRACT(EPOCH FROM NOW() - MIN(created_at))
+		FROM event_outbox
+		WHERE published_at IS NULL AND dead_at IS NULL
+	`).Scan(&pending, &lagSeconds)
+	if err != nil {
+		return err
+	}
+
+	r.pending.WithLabelValues(r.opts.ServiceName).Set(float64(pending))
+	r.lag.WithLabelValues(r.opts.ServiceName).Set(lagSeconds.Float64)
+	return nil
+}
+
+// Cleanup deletes published messages older than the retention period
+func (r *Relay) Cleanup(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM event_outbox
+		WHERE published_at IS NOT NULL AND published_at < NOW() - make_interval(secs => $1)
+	`, r.opts.Retention.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to clean up outbox: %w", err)
+	}
+	return nil
+}
+
+// RequeueDead makes dead messages pending again, e.g. after fixing the cause, and
+// returns how many were requeued
+func (r *Relay) RequeueDead(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET dead_at = NULL, attempts = 0, next_attempt_at = NULL
+		WHERE published_at IS NULL AND dead_at IS NOT NULL
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue dead outbox messages: %w", err)
+	}
+	return result.RowsAffected()
+}
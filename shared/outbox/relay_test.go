@@ -0,0 +1,186 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// recordingPublisher records published messages and fails for the keys in failing
+type recordingPublisher struct {
+	failing   map[string]bool
+	published []string
+}
+
+func (p *recordingPublisher) PublishMessage(topic string, key string, payload []byte, headers map[string]string) error {
+	if p.failing[key] {
+		return errors.New("broker unavailable")
+	}
+
+	var name string
+	if err := json.Unmarshal(payload, &name); err != nil {
+		return err
+	}
+	p.published = append(p.published, name)
+	return nil
+}
+
+// openTestDB connects to the database in OUTBOX_TEST_DATABASE_URL and empties the outbox
+// The tests truncate event_outbox, so never point this at real data
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("OUTBOX_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("OUTBOX_TEST_DATABASE_URL not set, skipping Postgres relay tests")
+	}
+	if testing.Short() {
+		t.Skip("Skipping Postgres relay tests in short mode")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := CreateTable(db); err != nil {
+		t.Fatalf("Failed to create outbox table: %v", err)
+	}
+	if _, err := db.Exec(`TRUNCATE event_outbox`); err != nil {
+		t.Fatalf("Failed to truncate outbox: %v", err)
+	}
+
+	return db
+}
+
+// enqueue records messages named after their payload, e.g. "a1" with key "a"
+func enqueue(t *testing.T, db *sql.DB, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		msg, err := NewMessage("test.topic", name[:1], name)
+		if err != nil {
+			t.Fatalf("Failed to create message: %v", err)
+		}
+		if err := Enqueue(context.Background(), db, msg); err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+}
+
+// relayOnce runs one relay round and checks what it published
+func relayOnce(t *testing.T, relay *Relay, publisher *recordingPublisher, expected ...string) {
+	t.Helper()
+	publisher.published = nil
+
+	if _, err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatalf("RelayOnce failed: %v", err)
+	}
+
+	if len(publisher.published) != len(expected) {
+		t.Fatalf("Expected published %v, got %v", expected, publisher.published)
+	}
+	for i := range expected {
+		if publisher.published[i] != expected[i] {
+			t.Fatalf("Expected published %v, got %v", expected, publisher.published)
+		}
+	}
+}
+
+func TestRelay_PublishesInOrder(t *testing.T) {
+	db := openTestDB(t)
+	publisher := &recordingPublisher{}
+	relay := NewRelay(db, publisher, RelayOptions{})
+
+	enqueue(t, db, "a1", "b1", "a2", "b2")
+	relayOnce(t, relay, publisher, "a1", "b1", "a2", "b2")
+	relayOnce(t, relay, publisher)
+}
+
+func TestRelay_RetriesFailingKeyWithoutBlockingOthers(t *testing.T) {
+	db := openTestDB(t)
+	publisher := &recordingPublisher{failing: map[string]bool{"a": true}}
+	relay := NewRelay(db, publisher, RelayOptions{BatchSize: 1, RetryDelay: time.Hour})
+
+	// a1 fails; a2 waits behind it
+	enqueue(t, db, "a1", "a2", "b1", "b2")
+	relayOnce(t, relay, publisher)
+
+	// The waiting key is skipped in SQL, so even a batch of one reaches the other keys
+	relayOnce(t, relay, publisher, "b1")
+	relayOnce(t, relay, publisher, "b2")
+	relayOnce(t, relay, publisher)
+
+	var attempts int
+	var lastError sql.NullString
+	if err := db.QueryRow(`SELECT attempts, last_error FROM event_outbox WHERE message_key = 'a' ORDER BY id LIMIT 1`).Scan(&attempts, &lastError); err != nil {
+		t.Fatalf("Failed to read failed message: %v", err)
+	}
+	if attempts != 1 || lastError.String != "broker unavailable" {
+		t.Errorf("Expected 1 attempt with error, got %d attempts and %q", attempts, lastError.String)
+	}
+
+	// Once the retry is due, the key is published in order
+	publisher.failing = nil
+	if _, err := db.Exec(`UPDATE event_outbox SET next_attempt_at = NOW() - INTERVAL '1 second' WHERE next_attempt_at IS NOT NULL`); err != nil {
+		t.Fatalf("Failed to expire retry delay: %v", err)
+	}
+	relayOnce(t, relay, publisher, "a1")
+	relayOnce(t, relay, publisher, "a2")
+}
+
+func TestRelay_DeadMessagesReleaseTheirKey(t *testing.T) {
+	db := openTestDB(t)
+	publisher := &recordingPublisher{failing: map[string]bool{"a": true}}
+	relay := NewRelay(db, publisher, RelayOptions{MaxAttempts: 1})
+
+	// a1 is given up after its only attempt; a2 is held back in the same round
+	enqueue(t, db, "a1", "a2", "b1")
+	relayOnce(t, relay, publisher, "b1")
+
+	var dead int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM event_outbox WHERE dead_at IS NOT NULL`).Scan(&dead); err != nil {
+		t.Fatalf("Failed to count dead messages: %v", err)
+	}
+	if dead != 1 {
+		t.Fatalf("Expected 1 dead message, got %d", dead)
+	}
+
+	// The dead message no longer holds back its key
+	publisher.failing = nil
+	relayOnce(t, relay, publisher, "a2")
+
+	requeued, err := relay.RequeueDead(context.Background())
+	if err != nil {
+		t.Fatalf("RequeueDead failed: %v", err)
+	}
+	if requeued != 1 {
+		t.Errorf("Expected 1 requeued message, got %d", requeued)
+	}
+	relayOnce(t, relay, publisher, "a1")
+}
+
+func TestRelay_RetryDelay(t *testing.T) {
+	relay := NewRelay(nil, &recordingPublisher{}, RelayOptions{RetryDelay: time.Second, MaxRetryDelay: 10 * time.Second})
+
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Second},
+		{attempts: 2, expected: 2 * time.Second},
+		{attempts: 4, expected: 8 * time.Second},
+		{attempts: 5, expected: 10 * time.Second},
+		{attempts: 50, expected: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if delay := relay.retryDelay(tt.attempts); delay != tt.expected {
+			t.Errorf("attempts %d: Expected %v, got %v", tt.attempts, tt.expected, delay)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/toxictoast/toxictoastgo/shared/middleware"
+)
+
+// HeaderAPIKey is the request header carrying an API key
+const HeaderAPIKey = "X-API-Key"
+
+// Limit is a middleware that enforces the policies
+// Responses carry RateLimit-* headers; denied requests get 429 and Retry-After
+func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decision := rl.Check(r.Context(), Request{
+			IP:     rl.clientIP(r),
+			UserID: rl.opts.HTTPUserID(r),
+			APIKey: r.Header.Get(HeaderAPIKey),
+			Route:  r.URL.Path,
+			Method: r.Method,
+		})
+
+		for name, value := range decision.Headers() {
+			w.Header().Set(name, value)
+		}
+
+		if !decision.Allowed() {
+			http.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// claimsUserID returns the user ID of the JWT claims in the request context
+func claimsUserID(r *http.Request) string {
+	if claims := middleware.GetClaims(r.Context()); claims != nil {
+		return claims.UserID
+	}
+	return ""
+}
+
+// clientIP returns the client IP of a request
+// Forwarding headers are only trusted from Options.TrustedProxies; otherwise it's RemoteAddr
+func (rl *RateLimiter) clientIP(r *http.Request) string {
+	return rl.resolveIP(hostOf(r.RemoteAddr), r.Header.Get("X-Forwarded-For"), r.Header.Get("X-Real-IP"))
+}
@@ -0,0 +1,121 @@
+// Package ratelimit implements GCRA rate limiting with per-IP, per-user, per-route
+// and per-API-key policies, shared between replicas through Redis
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Rate requests per Period with bursts of up to Burst requests
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// interval returns the time one request uses up
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// burst returns the burst size, which defaults to Rate
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed bool
+
+	// Limit is the burst size of the limit
+	Limit int
+
+	// Remaining is the number of requests that may be made right now
+	Remaining int
+
+	// ResetAfter is the time until the limit is fully replenished
+	ResetAfter time.Duration
+
+	// RetryAfter is the time until the next request is allowed; zero when allowed
+	RetryAfter time.Duration
+}
+
+// Limiter checks and consumes rate limits
+type Limiter interface {
+	// Allow consumes one request of key's limit if the limit allows it
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// gcra applies the generic cell rate algorithm
+// tat is the theoretical arrival time stored for the key; the new tat is only stored when allowed
+func gcra(tat time.Time, now time.Time, limit Limit) (time.Time, *Result) {
+	interval := limit.interval()
+	tolerance := interval * time.Duration(limit.burst())
+
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-tolerance)
+
+	result := &Result{Limit: limit.burst()}
+	if now.Before(allowAt) {
+		result.RetryAfter = allowAt.Sub(now)
+		result.ResetAfter = tat.Sub(now)
+		return tat, result
+	}
+
+	result.Allowed = true
+	result.Remaining = int(math.Floor(float64(now.Sub(allowAt)) / float64(interval)))
+	result.ResetAfter = newTat.Sub(now)
+	return newTat, result
+}
+
+// sweepInterval is how often the memory limiter drops replenished keys
+const sweepInterval = time.Minute
+
+// MemoryLimiter is a process-local Limiter intended for tests and single-replica setups
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryLimiter creates a new in-memory limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		tats: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+// Allow consumes one request of key's limit if the limit allows it
+func (m *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	// Drop replenished keys, so the map doesn't grow with every client ever seen
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		for k, tat := range m.tats {
+			if tat.Before(now) {
+				delete(m.tats, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	tat, result := gcra(m.tats[key], now, limit)
+	if result.Allowed {
+		m.tats[key] = tat
+	}
+	return result, nil
+}
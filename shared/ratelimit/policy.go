@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// KeyType selects what a policy counts requests by
+type KeyType string
+
+const (
+	// KeyIP counts requests per client IP
+	KeyIP KeyType = "ip"
+
+	// KeyUser counts requests per authenticated user; anonymous requests are not limited
+	KeyUser KeyType = "user"
+
+	// KeyRoute counts all requests to a route together
+	KeyRoute KeyType = "route"
+
+	// KeyAPIKey counts requests per API key; requests without one are not limited
+	KeyAPIKey KeyType = "api_key"
+)
+
+// ErrInvalidPolicy is returned for policies that can't be enforced
+var ErrInvalidPolicy = errors.New("invalid rate limit policy")
+
+// Duration is a time.Duration encoded as a string like "1m" in config files
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON encodes a duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Policy limits the requests matching its routes and methods
+type Policy struct {
+	// Name identifies the policy in limiter keys and the RateLimit-Policy header
+	Name string `json:"name"`
+
+	// Key selects what requests are counted by
+	Key KeyType `json:"key"`
+
+	// Routes are HTTP path or gRPC full method prefixes; empty matches every route
+	Routes []string `json:"routes,omitempty"`
+
+	// Methods are HTTP methods; empty matches every method (ignored for gRPC)
+	Methods []string `json:"methods,omitempty"`
+
+	// Rate requests are allowed per Period, in bursts of up to Burst (default Rate)
+	Rate   int      `json:"rate"`
+	Period Duration `json:"period"`
+	Burst  int      `json:"burst,omitempty"`
+}
+
+// Limit returns the policy's limit
+func (p Policy) Limit() Limit {
+	return Limit{Rate: p.Rate, Period: time.Duration(p.Period), Burst: p.Burst}
+}
+
+// Validate checks that the policy can be enforced
+func (p Policy) Validate() error {
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("%w: missing name", ErrInvalidPolicy)
+	case p.Rate <= 0 || p.Period <= 0:
+		return fmt.Errorf("%w: %s: rate and period must be positive", ErrInvalidPolicy, p.Name)
+	case time.Duration(p.Period)/time.Duration(p.Rate) < time.Microsecond:
+		return fmt.Errorf("%w: %s: rate too high for period", ErrInvalidPolicy, p.Name)
+	}
+
+	switch p.Key {
+	case KeyIP, KeyUser, KeyRoute, KeyAPIKey:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s: unknown key %q", ErrInvalidPolicy, p.Name, p.Key)
+	}
+}
+
+// match returns the matched route prefix, or false when the policy doesn't apply
+func (p Policy) match(route string, method string) (string, bool) {
+	if len(p.Methods) > 0 && method != "" {
+		matched := false
+		for _, m := range p.Methods {
+			if strings.EqualFold(m, method) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", false
+		}
+	}
+
+	if len(p.Routes) == 0 {
+		return "*", true
+	}
+	for _, prefix := range p.Routes {
+		if strings.HasPrefix(route, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// Config is the rate limit configuration file
+type Config struct {
+	Policies []Policy `json:"policies"`
+}
+
+// LoadConfig loads and validates a JSON rate limit configuration file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit config: %w", err)
+	}
+
+	names := make(map[string]bool, len(cfg.Policies))
+	for _, policy := range cfg.Policies {
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+		if names[policy.Name] {
+			return nil, fmt.Errorf("%w: duplicate name %s", ErrInvalidPolicy, policy.Name)
+		}
+		names[policy.Name] = true
+	}
+
+	return &cfg, nil
+}
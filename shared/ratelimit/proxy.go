@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// ParseTrustedProxies parses proxy addresses and CIDR ranges, e.g. "10.0.0.0/8" or "172.18.0.5"
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+			}
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		addr = addr.Unmap()
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
+
+// trusted reports whether ip belongs to a trusted proxy
+func (rl *RateLimiter) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range rl.opts.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveIP returns the client IP of a connection from remote, given its forwarding headers
+// The headers are only read when remote is a trusted proxy. X-Forwarded-For is walked from
+// the right, skipping trusted proxies, since a client can put anything in the left of it
+func (rl *RateLimiter) resolveIP(remote, forwardedFor, realIP string) string {
+	if !rl.trusted(remote) {
+		return remote
+	}
+
+	if forwardedFor != "" {
+		hops := strings.Split(forwardedFor, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop == "" {
+				continue
+			}
+			if !rl.trusted(hop) || i == 0 {
+				return hop
+			}
+		}
+	}
+
+	if realIP = strings.TrimSpace(realIP); realIP != "" {
+		return realIP
+	}
+	return remote
+}
+
+// hostOf strips the port from an address
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+)
+
+// Request identifies the client and route of a request
+type Request struct {
+	IP     string
+	UserID string
+	APIKey string
+	Route  string
+	Method string
+}
+
+// Decision is the outcome of checking a request against all matching policies
+type Decision struct {
+	// Policy is the most restrictive matching policy, or nil when none matched
+	Policy *Policy
+
+	// Result is the policy's result
+	Result *Result
+}
+
+// Allowed reports whether the request may proceed
+func (d *Decision) Allowed() bool {
+	return d.Result == nil || d.Result.Allowed
+}
+
+// Headers returns the RateLimit-* and Retry-After headers of the decision
+// Names are lower case, so they can be used as gRPC metadata as well
+func (d *Decision) Headers() map[string]string {
+	if d.Result == nil {
+		return nil
+	}
+
+	headers := map[string]string{
+		"ratelimit-limit":     strconv.Itoa(d.Result.Limit),
+		"ratelimit-remaining": strconv.Itoa(d.Result.Remaining),
+		"ratelimit-reset":     strconv.Itoa(seconds(d.Result.ResetAfter)),
+		"ratelimit-policy":    fmt.Sprintf("%d;w=%d;name=%q", d.Result.Limit, seconds(time.Duration(d.Policy.Period)), d.Policy.Name),
+	}
+	if !d.Result.Allowed {
+		headers["retry-after"] = strconv.Itoa(seconds(d.Result.RetryAfter))
+	}
+	return headers
+}
+
+// seconds rounds a duration up to whole seconds
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// Options configures how a RateLimiter identifies clients
+type Options struct {
+	// HTTPUserID resolves the user of an HTTP request; defaults to the JWT claims
+	// stored by middleware.AuthMiddleware, which requires the limiter to run after it
+	HTTPUserID func(r *http.Request) string
+
+	// TrustedProxies are the proxies whose X-Forwarded-For and X-Real-IP headers are
+	// believed; without them the IP of a request is its remote address
+	TrustedProxies []netip.Prefix
+}
+
+// RateLimiter enforces rate limit policies with a Limiter
+type RateLimiter struct {
+	limiter  Limiter
+	policies []Policy
+	opts     Options
+}
+
+// New creates a rate limiter enforcing the policies of a config
+func New(limiter Limiter, cfg *Config) *RateLimiter {
+	return NewWithOptions(limiter, cfg, Options{})
+}
+
+// NewWithOptions creates a rate limiter with custom client identification
+func NewWithOptions(limiter Limiter, cfg *Config, opts Options) *RateLimiter {
+	if opts.HTTPUserID == nil {
+		opts.HTTPUserID = claimsUserID
+	}
+	return &RateLimiter{limiter: limiter, policies: cfg.Policies, opts: opts}
+}
+
+// Check consumes a request from every matching policy
+// A request is denied when any policy denies it. Limiter errors fail open, so an
+// unavailable Redis doesn't take the API down with it
+func (rl *RateLimiter) Check(ctx context.Context, req Request) *Decision {
+	decision := &Decision{}
+
+	for i := range rl.policies {
+		policy := &rl.policies[i]
+
+		route, ok := policy.match(req.Route, req.Method)
+		if !ok {
+			continue
+		}
+
+		subject, ok := subjectOf(policy.Key, req, route)
+		if !ok {
+			continue
+		}
+
+		result, err := rl.limiter.Allow(ctx, policy.Name+":"+subject, policy.Limit())
+		if err != nil {
+			log.Printf("Warning: Rate limit policy %s not enforced: %v", policy.Name, err)
+			continue
+		}
+
+		if decision.Result == nil || moreRestrictive(result, decision.Result) {
+			decision.Policy = policy
+			decision.Result = result
+		}
+	}
+
+	return decision
+}
+
+// subjectOf returns the value a policy counts requests by
+func subjectOf(key KeyType, req Request, route string) (string, bool) {
+	switch key {
+	case KeyIP:
+		return req.IP, req.IP != ""
+	case KeyUser:
+		return req.UserID, req.UserID != ""
+	case KeyAPIKey:
+		return req.APIKey, req.APIKey != ""
+	case KeyRoute:
+		return route, true
+	default:
+		return "", false
+	}
+}
+
+// moreRestrictive reports whether a limits the client more than b
+func moreRestrictive(a, b *Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
@@ -0,0 +1,240 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/toxictoast/toxictoastgo/shared/auth"
+	sharedgrpc "github.com/toxictoast/toxictoastgo/shared/grpc"
+)
+
+func TestMemoryLimiterGCRA(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 2, Period: time.Second, Burst: 3}
+
+	tests := []struct {
+		name          string
+		advance       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration
+	}{
+		{name: "first request", wantAllowed: true, wantRemaining: 2},
+		{name: "second request", wantAllowed: true, wantRemaining: 1},
+		{name: "burst exhausted", wantAllowed: true, wantRemaining: 0},
+		{name: "denied", wantAllowed: false, wantRetry: 500 * time.Millisecond},
+		{name: "replenished one", advance: 500 * time.Millisecond, wantAllowed: true, wantRemaining: 0},
+		{name: "fully replenished", advance: 2 * time.Second, wantAllowed: true, wantRemaining: 2},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		result, err := limiter.Allow(context.Background(), "client", limit)
+		if err != nil {
+			t.Fatalf("%s: Allow failed: %v", tt.name, err)
+		}
+		if result.Allowed != tt.wantAllowed || result.Remaining != tt.wantRemaining || result.RetryAfter != tt.wantRetry {
+			t.Errorf("%s: Expected allowed=%v remaining=%d retry=%v, got allowed=%v remaining=%d retry=%v",
+				tt.name, tt.wantAllowed, tt.wantRemaining, tt.wantRetry, result.Allowed, result.Remaining, result.RetryAfter)
+		}
+	}
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	cfg := &Config{Policies: []Policy{
+		{Name: "login", Key: KeyIP, Routes: []string{"/api/auth/login"}, Methods: []string{"POST"}, Rate: 1, Period: Duration(time.Minute)},
+		{Name: "api-keys", Key: KeyAPIKey, Routes: []string{"/api/"}, Rate: 2, Period: Duration(time.Minute)},
+	}}
+	rl := New(NewMemoryLimiter(), cfg)
+	handler := rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		ip         string
+		apiKey     string
+		wantStatus int
+		wantPolicy bool
+	}{
+		{name: "login allowed", method: "POST", path: "/api/auth/login", ip: "10.0.0.1", wantStatus: http.StatusOK, wantPolicy: true},
+		{name: "login denied", method: "POST", path: "/api/auth/login", ip: "10.0.0.1", wantStatus: http.StatusTooManyRequests, wantPolicy: true},
+		{name: "other ip allowed", method: "POST", path: "/api/auth/login", ip: "10.0.0.2", wantStatus: http.StatusOK, wantPolicy: true},
+		{name: "other method not limited", method: "GET", path: "/api/auth/login", ip: "10.0.0.1", wantStatus: http.StatusOK},
+		{name: "api key allowed", method: "GET", path: "/api/blog/posts", apiKey: "key-1", wantStatus: http.StatusOK, wantPolicy: true},
+		{name: "api key allowed again", method: "GET", path: "/api/blog/posts", apiKey: "key-1", wantStatus: http.StatusOK, wantPolicy: true},
+		{name: "api key denied", method: "GET", path: "/api/links", apiKey: "key-1", wantStatus: http.StatusTooManyRequests, wantPolicy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = tt.ip + ":1234"
+			if tt.apiKey != "" {
+				req.Header.Set(HeaderAPIKey, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("RateLimit-Policy") != ""; got != tt.wantPolicy {
+				t.Errorf("Expected RateLimit-Policy header %v, got %q", tt.wantPolicy, rec.Header().Get("RateLimit-Policy"))
+			}
+			if tt.wantStatus == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+				t.Error("Expected Retry-After header")
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid", content: `{"policies": [{"name": "default", "key": "ip", "rate": 100, "period": "1m", "burst": 200}]}`},
+		{name: "unknown key", content: `{"policies": [{"name": "default", "key": "session", "rate": 100, "period": "1m"}]}`, wantErr: true},
+		{name: "missing rate", content: `{"policies": [{"name": "default", "key": "ip", "period": "1m"}]}`, wantErr: true},
+		{name: "invalid period", content: `{"policies": [{"name": "default", "key": "ip", "rate": 1, "period": "often"}]}`, wantErr: true},
+		{name: "duplicate name", content: `{"policies": [{"name": "a", "key": "ip", "rate": 1, "period": "1s"}, {"name": "a", "key": "user", "rate": 1, "period": "1s"}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ratelimit.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && cfg.Policies[0].Limit().Period != time.Minute {
+				t.Errorf("Expected period 1m, got %v", cfg.Policies[0].Limit().Period)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.5"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rl := NewWithOptions(NewMemoryLimiter(), &Config{}, Options{TrustedProxies: proxies})
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		realIP       string
+		expectedIP   string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.7:1234", expectedIP: "203.0.113.7"},
+		{name: "spoofed header from untrusted client", remoteAddr: "203.0.113.7:1234", forwardedFor: "1.2.3.4", realIP: "5.6.7.8", expectedIP: "203.0.113.7"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.2:1234", forwardedFor: "198.51.100.1", expectedIP: "198.51.100.1"},
+		{name: "spoofed entry left of the client", remoteAddr: "10.0.0.2:1234", forwardedFor: "1.2.3.4, 198.51.100.1", expectedIP: "198.51.100.1"},
+		{name: "chain of trusted proxies", remoteAddr: "192.168.1.5:1234", forwardedFor: "1.2.3.4, 198.51.100.1, 10.1.2.3", expectedIP: "198.51.100.1"},
+		{name: "only trusted proxies", remoteAddr: "10.0.0.2:1234", forwardedFor: "10.0.0.3, 10.0.0.4", expectedIP: "10.0.0.3"},
+		{name: "real ip from trusted proxy", remoteAddr: "10.0.0.2:1234", realIP: "198.51.100.1", expectedIP: "198.51.100.1"},
+		{name: "trusted proxy without headers", remoteAddr: "10.0.0.2:1234", expectedIP: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if ip := rl.clientIP(req); ip != tt.expectedIP {
+				t.Errorf("Expected IP %s, got %s", tt.expectedIP, ip)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		wantErr bool
+	}{
+		{name: "cidr and address", values: []string{"10.0.0.0/8", " 172.18.0.5 ", "::1"}},
+		{name: "empty entries", values: []string{"", " "}},
+		{name: "invalid address", values: []string{"gateway"}, wantErr: true},
+		{name: "invalid cidr", values: []string{"10.0.0.0/33"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTrustedProxies(tt.values); (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGRPCUserID(t *testing.T) {
+	forwarded := metadata.Pairs(sharedgrpc.MetadataKeyUserID, "user-1")
+	verified := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}},
+	})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{name: "anonymous", ctx: context.Background(), expected: ""},
+		{name: "plain metadata", ctx: withForwardedUser(t, context.Background(), forwarded), expected: ""},
+		{name: "raw metadata", ctx: metadata.NewIncomingContext(context.Background(), forwarded), expected: ""},
+		{name: "metadata from verified peer", ctx: withForwardedUser(t, verified, forwarded), expected: "user-1"},
+		{name: "keycloak user", ctx: auth.WithUserContext(context.Background(), &auth.UserContext{UserID: "user-2"}), expected: "user-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grpcUserID(tt.ctx); got != tt.expected {
+				t.Errorf("Expected user %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// withForwardedUser returns the handler context of a call carrying md, after the auth interceptor
+func withForwardedUser(t *testing.T, ctx context.Context, md metadata.MD) context.Context {
+	t.Helper()
+	var handlerCtx context.Context
+	_, err := sharedgrpc.AuthInterceptor(metadata.NewIncomingContext(ctx, md), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCtx = ctx
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("AuthInterceptor failed: %v", err)
+	}
+	return handlerCtx
+}
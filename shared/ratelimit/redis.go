@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript applies the GCRA atomically with Redis time, so all replicas share one clock
+// KEYS[1] holds the theoretical arrival time in microseconds
+// ARGV: interval and tolerance in microseconds
+// Returns allowed (0/1), remaining, reset after and retry after in microseconds
+var gcraScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local interval = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - tolerance
+
+if now < allow_at then
+	return {0, 0, tat - now, allow_at - now}
+end
+
+local ttl = math.max(1, math.ceil((new_tat - now) / 1000))
+redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", string.format("%.0f", ttl))
+return {1, math.floor((now - allow_at) / interval), new_tat - now, 0}
+`)
+
+// RedisLimiter is a Limiter sharing its state between replicas through Redis
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLimiter creates a limiter storing its state under "ratelimit:" keys
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: "ratelimit:"}
+}
+
+// Allow consumes one request of key's limit if the limit allows it
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	interval := limit.interval().Microseconds()
+	tolerance := interval * int64(limit.burst())
+
+	values, err := gcraScript.Run(ctx, l.client, []string{l.prefix + key}, interval, tolerance).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("failed to check rate limit: unexpected script result %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.burst(),
+		Remaining:  int(values[1]),
+		ResetAfter: time.Duration(values[2]) * time.Microsecond,
+		RetryAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
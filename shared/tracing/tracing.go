@@ -0,0 +1,106 @@
+// Package tracing sets up OpenTelemetry tracing: an OTLP exporter, W3C trace context
+// propagation and helpers to correlate logs with traces
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/toxictoast/toxictoastgo/shared/config"
+)
+
+// ErrInvalidEndpoint is returned for an OTLP endpoint that isn't an http(s) URL
+var ErrInvalidEndpoint = errors.New("invalid OTLP endpoint")
+
+// ShutdownFunc flushes pending spans and stops the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// Init installs the global tracer provider and propagator
+// The propagator is always installed, so services without a collector still pass
+// the trace context of their callers on to gRPC, HTTP and Kafka
+func Init(ctx context.Context, cfg config.TracingConfig) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Endpoint == "" {
+		log.Println("Tracing: no OTLP endpoint configured, spans are not exported")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	host, insecure, err := parseEndpoint(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(host)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	log.Printf("Tracing: exporting spans of %s to %s", cfg.ServiceName, cfg.Endpoint)
+	return provider.Shutdown, nil
+}
+
+// parseEndpoint returns the host and port of an OTLP endpoint URL, e.g. "http://jaeger:4317"
+// The scheme selects the transport: http sends spans in plain text, https uses TLS
+func parseEndpoint(endpoint string) (string, bool, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, fmt.Errorf("%w %q: %v", ErrInvalidEndpoint, endpoint, err)
+	}
+	if u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false, fmt.Errorf("%w %q: expected a URL like http://jaeger:4317", ErrInvalidEndpoint, endpoint)
+	}
+	return u.Host, u.Scheme == "http", nil
+}
+
+// TraceID returns the trace ID of the span in ctx, or "" without a valid span
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// SpanID returns the span ID of the span in ctx, or "" without a valid span
+func SpanID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasSpanID() {
+		return ""
+	}
+	return spanContext.SpanID().String()
+}
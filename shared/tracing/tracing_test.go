@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/toxictoast/toxictoastgo/shared/config"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		endpoint     string
+		wantHost     string
+		wantInsecure bool
+		wantErr      bool
+	}{
+		{name: "http", endpoint: "http://jaeger:4317", wantHost: "jaeger:4317", wantInsecure: true},
+		{name: "https", endpoint: "https://otel.example.com:4317", wantHost: "otel.example.com:4317"},
+		{name: "trailing slash", endpoint: "http://jaeger:4317/", wantHost: "jaeger:4317", wantInsecure: true},
+		{name: "bare host and port", endpoint: "jaeger:4317", wantErr: true},
+		{name: "unsupported scheme", endpoint: "grpc://jaeger:4317", wantErr: true},
+		{name: "missing host", endpoint: "http://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, insecure, err := parseEndpoint(tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidEndpoint) {
+					t.Errorf("Expected ErrInvalidEndpoint, got %v", err)
+				}
+				return
+			}
+			if host != tt.wantHost || insecure != tt.wantInsecure {
+				t.Errorf("Expected %s (insecure %v), got %s (insecure %v)", tt.wantHost, tt.wantInsecure, host, insecure)
+			}
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	if _, err := Init(context.Background(), config.TracingConfig{ServiceName: "test-service", Endpoint: "jaeger:4317"}); !errors.Is(err, ErrInvalidEndpoint) {
+		t.Errorf("Expected ErrInvalidEndpoint for a bare endpoint, got %v", err)
+	}
+
+	// Without an endpoint spans aren't exported, but the trace context is still propagated
+	shutdown, err := Init(context.Background(), config.TracingConfig{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer shutdown(context.Background())
+
+	provider := sdktrace.NewTracerProvider()
+	defer provider.Shutdown(context.Background())
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if !strings.Contains(carrier.Get("traceparent"), span.SpanContext().TraceID().String()) {
+		t.Errorf("Expected traceparent with trace ID %s, got %q", span.SpanContext().TraceID(), carrier.Get("traceparent"))
+	}
+}
+
+func TestTraceAndSpanID(t *testing.T) {
+	if TraceID(context.Background()) != "" || SpanID(context.Background()) != "" {
+		t.Error("Expected empty IDs without a span")
+	}
+
+	provider := sdktrace.NewTracerProvider()
+	defer provider.Shutdown(context.Background())
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	if got := TraceID(ctx); got != span.SpanContext().TraceID().String() {
+		t.Errorf("Expected trace ID %s, got %s", span.SpanContext().TraceID(), got)
+	}
+	if got := SpanID(ctx); got != span.SpanContext().SpanID().String() {
+		t.Errorf("Expected span ID %s, got %s", span.SpanContext().SpanID(), got)
+	}
+}